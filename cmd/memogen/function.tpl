@@ -10,12 +10,30 @@ import (
 )
 
 {{range .Functions}}
-    {{ .SignatureWithPrefix "memoized" }} {
-        hash := "{{ .Name }}" {{range .Params}} + ":" + fmt.Sprint({{.Name}}) {{end}}
+    {{- /* a leading ctx parameter is passed through but is not part of the memoized arguments */}}
+    {{- $ctx := and .Params (eq (index .Params 0).Name "ctx")}}
+    func init() {
+        protocolstate.RegisterMemoized[{{.ResultFirstFieldType}}]("{{ .SourcePackage }}.{{ .Name }}", {{if $ctx}}{{len (slice .Params 1)}}{{else}}{{len .Params}}{{end}})
+    }
 
+    {{ .SignatureWithPrefix "memoized" }} {
+        hash := "{{ .SourcePackage }}.{{ .Name }}" {{range .Params}}{{if ne .Name "ctx"}} + ":" + fmt.Sprint({{.Name}}){{end}} {{end}}
+    {{- /* @nodisk is replaced by memogen with the functions annotated with @memo(nodisk) */}}
+    {{- if eq .Name "" @nodisk}}
+        // results are only memoized in memory (nodisk)
         v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
             return {{.Name}}({{.ParamsNames}})
         })
+    {{- else}}
+        // execution id is not part of the on-disk key so results can be reused across runs
+        diskKey := "{{ .SourcePackage }}.{{ .Name }}" {{range .Params}}{{if and (ne .Name "executionId") (ne .Name "ctx")}} + ":" + fmt.Sprint({{.Name}}){{end}} {{end}}
+
+        v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+            return protocolstate.MemoizeOnDisk(executionId, diskKey, func() ({{.ResultFirstFieldType}}, error) {
+                return {{.Name}}({{.ParamsNames}})
+            })
+        })
+    {{- end}}
         if err != nil {
             return {{.ResultFirstFieldDefaultValue}}, err
         }
@@ -2,19 +2,38 @@
 // func(x,y) => result, error
 // it works by creating a new memoized version of the functions in the same path as memo.original.file.go
 // some parts are specific for nuclei and hardcoded within the template
+//
+// functions annotated with @memo(nodisk) are only memoized in memory and never
+// written to the on-disk cache (ex: timings or sensitive results). the on-disk
+// cache of the execution requires the other functions to have an executionId parameter.
+// a leading ctx parameter (ex: to cancel probes) is passed through to the function
+// but is not part of the memoized arguments, calls sharing a result share its errors
+// (ex: a cancelled context) which are never cached
 package main
 
 import (
 	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/projectdiscovery/utils/memoize"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
+const (
+	memoAnnotation    = "// @memo"
+	nodiskAnnotation  = "// @memo(nodisk)"
+	nodiskPlaceholder = "@nodisk"
+)
+
 var (
 	srcPath = flag.String("src", "", "nuclei source path")
 	tplPath = flag.String("tpl", "function.tpl", "template path")
@@ -51,6 +70,10 @@ func walk(path string, info fs.FileInfo, err error) error {
 	if !stringsutil.EqualFoldAny(ext, ".go") {
 		return nil
 	}
+	// generated files mention the annotations in comments
+	if strings.HasPrefix(base, "memo.") {
+		return nil
+	}
 
 	basePath := filepath.Dir(path)
 	outPath := filepath.Join(basePath, "memo."+base)
@@ -64,7 +87,14 @@ func walk(path string, info fs.FileInfo, err error) error {
 		return nil
 	}
 	log.Println("processing:", path)
-	out, err := memoize.Src(string(tplSrc), path, data, "")
+	nodisk, err := nodiskFunctions(path, data)
+	if err != nil {
+		return err
+	}
+	// the memoize generator only handles the plain annotation
+	src := strings.ReplaceAll(string(data), nodiskAnnotation, memoAnnotation)
+	tpl := strings.ReplaceAll(string(tplSrc), nodiskPlaceholder, strings.Join(nodisk, " "))
+	out, err := memoize.Src(tpl, path, []byte(src), "")
 	if err != nil {
 		return err
 	}
@@ -75,3 +105,43 @@ func walk(path string, info fs.FileInfo, err error) error {
 
 	return nil
 }
+
+// nodiskFunctions returns the quoted names of the functions of the source
+// annotated with @memo(nodisk) and checks that the functions cached on disk
+// take the execution id selecting the cache directory
+func nodiskFunctions(path string, data []byte) ([]string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), path, data, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var nodisk []string
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Doc == nil {
+			continue
+		}
+		for _, comment := range fn.Doc.List {
+			switch comment.Text {
+			case nodiskAnnotation:
+				nodisk = append(nodisk, strconv.Quote(fn.Name.Name))
+			case memoAnnotation:
+				if !hasParam(fn, "executionId") {
+					return nil, fmt.Errorf("%s: %s has no executionId parameter, use %s", path, fn.Name.Name, nodiskAnnotation)
+				}
+			}
+		}
+	}
+	return nodisk, nil
+}
+
+// hasParam returns true if fn has a parameter with the given name
+func hasParam(fn *ast.FuncDecl, name string) bool {
+	for _, param := range fn.Type.Params.List {
+		for _, ident := range param.Names {
+			if ident.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
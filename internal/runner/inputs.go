@@ -2,7 +2,6 @@ package runner
 
 import (
 	"context"
-	"fmt"
 	"sync/atomic"
 	"time"
 
@@ -39,9 +38,9 @@ func (r *Runner) initializeTemplatesHTTPInput() (*hybrid.HybridMap, error) {
 	httpxOptions.RetryMax = r.options.Retries
 	httpxOptions.Timeout = time.Duration(r.options.Timeout) * time.Second
 
-	dialers := protocolstate.GetDialersWithId(r.options.ExecutionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", r.options.ExecutionId)
+	dialers, err := protocolstate.GetDialersOrError(r.options.ExecutionId)
+	if err != nil {
+		return nil, err
 	}
 
 	httpxOptions.NetworkPolicy = dialers.NetworkPolicy
@@ -278,6 +278,7 @@ type NetworkConfig struct {
 	Interface             string   // Interface to use for network scan
 	InternalResolversList []string // Use a list of resolver
 	LeaveDefaultPorts     bool     // Leave default ports for http/https
+	LocalPortRange        string   // LocalPortRange restricts the local ports of outbound tcp connections (ex: 40000-40999)
 	MaxHostError          int      // Maximum number of host errors to allow before skipping that host
 	Retries               int      // Number of retries
 	SourceIP              string   // SourceIP sets custom source IP address for network requests
@@ -311,6 +312,7 @@ func WithNetworkConfig(opts NetworkConfig) NucleiSDKOptions {
 		e.opts.LeaveDefaultPorts = opts.LeaveDefaultPorts
 		e.opts.Interface = opts.Interface
 		e.opts.SourceIP = opts.SourceIP
+		e.opts.LocalPortRange = opts.LocalPortRange
 		e.opts.SystemResolvers = opts.SystemResolvers
 		e.opts.InternalResolversList = opts.InternalResolversList
 		return nil
@@ -0,0 +1,100 @@
+package compiler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// rdpResponder answers every connection with a rdp connection confirm
+func rdpResponder(t *testing.T) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				if _, err := conn.Read(make([]byte, 1024)); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{
+					0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+					0x02, 0x1f, 0x08, 0x00, 0x08, 0x00, 0x00, 0x00,
+				})
+			}()
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// TestOmittedOptions checks that module functions whose trailing options
+// argument was added later can still be called without it by existing templates
+func TestOmittedOptions(t *testing.T) {
+	executionId := jstest.Init(t)
+
+	host, port := rdpResponder(t)
+	tlsServer := httptest.NewTLSServer(nil)
+	t.Cleanup(tlsServer.Close)
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "rdp.IsRDP",
+			source: fmt.Sprintf(`require('nuclei/rdp').IsRDP('%s', %d).IsRDP ? 'ok' : 'not rdp'`, host, port),
+		},
+		{
+			// the responder does not speak nla so the call only has to reach the library
+			name: "rdp.CheckRDPAuth",
+			source: fmt.Sprintf(`
+				let result = 'ok';
+				try { require('nuclei/rdp').CheckRDPAuth('%s', %d); } catch (e) { if (String(e).includes('reflect')) { result = String(e); } }
+				result;
+			`, host, port),
+		},
+		{
+			name:   "net.Open",
+			source: fmt.Sprintf(`require('nuclei/net').Open('tcp', '%s:%d').Close(); 'ok'`, host, port),
+		},
+		{
+			name:   "net.OpenTLS",
+			source: fmt.Sprintf(`require('nuclei/net').OpenTLS('tcp', '%s').Close(); 'ok'`, tlsServer.Listener.Addr().String()),
+		},
+	}
+	compiler := New()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := SourceAutoMode(test.source, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := compiler.ExecuteWithOptions(p, NewExecuteArgs(), &ExecuteOptions{
+				ExecutionId:     executionId,
+				Context:         context.Background(),
+				TimeoutVariants: &types.Timeouts{JsCompilerExecutionTimeout: time.Duration(20) * time.Second},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(result["response"]); !strings.EqualFold(got, "ok") {
+				t.Fatalf("expected call without options to succeed, got=%v", got)
+			}
+		})
+	}
+}
@@ -2,12 +2,17 @@ package compiler
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/gologger/levels"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
@@ -46,3 +51,91 @@ func (n *noopWriter) Write(data []byte, level levels.Level) {
 		n.Callback(data, level)
 	}
 }
+
+func TestModules(t *testing.T) {
+	var rdp *gojs.ModuleInfo
+	modules := Modules()
+	for i := range modules {
+		if modules[i].Name == "nuclei/rdp" {
+			rdp = &modules[i]
+		}
+	}
+	if rdp == nil {
+		t.Fatalf("nuclei/rdp not found in %d modules", len(modules))
+	}
+
+	arity := map[string]int{}
+	for _, fn := range rdp.Functions {
+		arity[fn.Name] = fn.Arity
+	}
+	// IsRDP(host, port, opts) and CheckRDPAuth(host, port, opts)
+	for name, want := range map[string]int{"IsRDP": 3, "CheckRDPAuth": 3} {
+		got, ok := arity[name]
+		if !ok {
+			t.Fatalf("%s not found in %+v", name, rdp.Functions)
+		}
+		if got != want {
+			t.Fatalf("expected %s arity %d, got=%d", name, want, got)
+		}
+	}
+	if !slices.Contains(rdp.Classes, "IsRDPResponse") {
+		t.Fatalf("IsRDPResponse not found in %v", rdp.Classes)
+	}
+}
+
+func TestExecuteStreamCallback(t *testing.T) {
+	executionId := jstest.Init(t)
+
+	// closed ports fail fast and are still reported to the callback
+	var hosts []string
+	for i := 0; i < 5; i++ {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		hosts = append(hosts, fmt.Sprintf("'%s'", ln.Addr().String()))
+		_ = ln.Close()
+	}
+	source := fmt.Sprintf(`
+		const rdp = require('nuclei/rdp');
+		const seen = [];
+		rdp.IsRDPStream([%s], 3389, (result) => {
+			if (result.Response.PortOpen || result.Error === '') { throw new Error('expected closed port'); }
+			seen.push(result.Host + ':' + result.Port);
+		}, { Concurrency: 2 });
+		seen.length;
+	`, strings.Join(hosts, ", "))
+
+	compiler := New()
+	p, err := SourceAutoMode(source, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := compiler.ExecuteWithOptions(p, NewExecuteArgs(), &ExecuteOptions{
+		ExecutionId:     executionId,
+		Context:         context.Background(),
+		TimeoutVariants: &types.Timeouts{JsCompilerExecutionTimeout: time.Duration(20) * time.Second},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result["response"]; got != int64(len(hosts)) {
+		t.Fatalf("expected callback once per host (%d), got=%v", len(hosts), got)
+	}
+
+	// exceptions thrown by the callback stop the stream (options omitted)
+	p, err = SourceAutoMode(`
+		const rdp = require('nuclei/rdp');
+		rdp.IsRDPStream(['127.0.0.1:1'], 3389, (result) => { throw new Error('stop'); });
+	`, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := compiler.ExecuteWithOptions(p, NewExecuteArgs(), &ExecuteOptions{
+		ExecutionId:     executionId,
+		Context:         context.Background(),
+		TimeoutVariants: &types.Timeouts{JsCompilerExecutionTimeout: time.Duration(20) * time.Second},
+	}); err == nil || !strings.Contains(err.Error(), "stop") {
+		t.Fatalf("expected callback exception to be returned, got %v", err)
+	}
+}
@@ -12,26 +12,54 @@ import (
 	"github.com/Mzack9999/goja_nodejs/require"
 	"github.com/kitabisa/go-ci"
 	"github.com/projectdiscovery/gologger"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libacme"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libajp"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libauth"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libbytes"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libcwmp"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libdhcp"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libdns"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libdoh"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libenip"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libfox"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libfs"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libgopher"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libikev2"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libjdwp"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libkerberos"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libldap"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmail"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmdns"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libminecraft"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmqtt"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmssql"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libmysql"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libnet"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libntlm"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libopcua"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/liboracle"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libpop3"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libpostgres"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libprobe"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libproxy"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libquic"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libradius"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/librdp"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libredis"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/librmi"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/librsync"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsip"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsmb"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsmi"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsmtp"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsnmp"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libsocks"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libssdp"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libssh"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libstructs"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libtelnet"
 	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libvnc"
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/generated/go/libwebsocket"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/global"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/goconsole"
@@ -203,6 +231,12 @@ func InternalGetGeneratorRuntime() *goja.Runtime {
 	return runtime
 }
 
+// Modules returns the nuclei/* js libraries available in the runtime
+// along with their exported function signatures (ex: for tooling and auto-completion)
+func Modules() []gojs.ModuleInfo {
+	return gojs.Modules()
+}
+
 func getRegistry() *require.Registry {
 	lazyRegistryInit()
 	return r
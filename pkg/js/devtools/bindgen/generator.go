@@ -258,8 +258,13 @@ func identifyGenDecl(node ast.Node, decl *ast.GenDecl, data *TemplateData) {
 			if len(spec.Values) == 0 {
 				continue
 			}
+			// only constant literals are exposed (ex: sentinel errors are skipped)
+			value, ok := spec.Values[0].(*ast.BasicLit)
+			if !ok {
+				continue
+			}
 			data.PackageVars[spec.Names[0].Name] = spec.Names[0].Name
-			data.PackageVarsValues[spec.Names[0].Name] = spec.Values[0].(*ast.BasicLit).Value
+			data.PackageVarsValues[spec.Names[0].Name] = value.Value
 		case *ast.TypeSpec:
 			if !spec.Name.IsExported() {
 				continue
@@ -26,12 +26,39 @@ func exprToString(expr ast.Expr) string {
 		return "interface{}"
 	case *ast.MapType:
 		return "Record<" + toTsTypes(exprToString(t.Key)) + ", " + toTsTypes(exprToString(t.Value)) + ">"
+	case *ast.FuncType:
+		return funcTypeToString(t)
 	// Add more cases to handle other types
 	default:
 		return fmt.Sprintf("%T", expr)
 	}
 }
 
+// funcTypeToString converts a function type (ex: a javascript callback) to a
+// typescript function type. error results are reported as exceptions
+func funcTypeToString(t *ast.FuncType) string {
+	var params []string
+	for i, field := range t.Params.List {
+		typ := toTsTypes(exprToString(field.Type))
+		if len(field.Names) == 0 {
+			params = append(params, fmt.Sprintf("arg%d: %s", i, typ))
+		}
+		for _, name := range field.Names {
+			params = append(params, name.Name+": "+typ)
+		}
+	}
+	result := "void"
+	if t.Results != nil {
+		for _, field := range t.Results.List {
+			if typ := exprToString(field.Type); typ != "error" {
+				result = toTsTypes(typ)
+				break
+			}
+		}
+	}
+	return "(" + strings.Join(params, ", ") + ") => " + result
+}
+
 // toTsTypes converts Go types to TypeScript types
 func toTsTypes(t string) string {
 	if strings.Contains(t, "interface{}") {
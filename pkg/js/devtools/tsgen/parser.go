@@ -492,12 +492,17 @@ func (p *EntityParser) extractVarsNConstants() {
 					if len(spec.Values) == 0 {
 						continue
 					}
+					// only constant literals are exposed (ex: sentinel errors are skipped)
+					value, ok := spec.Values[0].(*ast.BasicLit)
+					if !ok {
+						continue
+					}
 					// get comments or description
 					p.vars = append(p.vars, Entity{
 						Name:        spec.Names[0].Name,
 						Type:        "const",
 						Description: strings.TrimSpace(spec.Comment.Text()),
-						Value:       spec.Values[0].(*ast.BasicLit).Value,
+						Value:       value.Value,
 					})
 				}
 			}
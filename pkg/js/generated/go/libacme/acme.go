@@ -0,0 +1,30 @@
+package acme
+
+import (
+	lib_acme "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/acme"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/acme")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"CheckChallengeEndpoint": lib_acme.CheckChallengeEndpoint,
+
+			// Var and consts
+
+			// Objects / Classes
+			"ChallengeEndpointResponse": gojs.GetClassConstructor[lib_acme.ChallengeEndpointResponse](&lib_acme.ChallengeEndpointResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
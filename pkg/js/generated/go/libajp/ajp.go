@@ -0,0 +1,30 @@
+package ajp
+
+import (
+	lib_ajp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/ajp"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/ajp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsAJP": lib_ajp.IsAJP,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsAJPResponse": gojs.GetClassConstructor[lib_ajp.IsAJPResponse](&lib_ajp.IsAJPResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
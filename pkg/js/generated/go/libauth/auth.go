@@ -0,0 +1,32 @@
+package auth
+
+import (
+	lib_auth "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/auth"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/auth")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"BruteForce": lib_auth.BruteForce,
+
+			// Var and consts
+
+			// Objects / Classes
+			"BruteForceOptions":  gojs.GetClassConstructor[lib_auth.BruteForceOptions](&lib_auth.BruteForceOptions{}),
+			"BruteForceResponse": gojs.GetClassConstructor[lib_auth.BruteForceResponse](&lib_auth.BruteForceResponse{}),
+			"Credential":         gojs.GetClassConstructor[lib_auth.Credential](&lib_auth.Credential{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -15,7 +15,22 @@ func init() {
 	module.Set(
 		gojs.Objects{
 			// Functions
-			"NewBuffer": lib_bytes.NewBuffer,
+			"CRC16CCITT":       lib_bytes.CRC16CCITT,
+			"CRC16DNP":         lib_bytes.CRC16DNP,
+			"CRC16Modbus":      lib_bytes.CRC16Modbus,
+			"CRC32":            lib_bytes.CRC32,
+			"CRC32C":           lib_bytes.CRC32C,
+			"FromString":       lib_bytes.FromString,
+			"InternetChecksum": lib_bytes.InternetChecksum,
+			"NewBuffer":        lib_bytes.NewBuffer,
+			"PackUint16BE":     lib_bytes.PackUint16BE,
+			"PackUint16LE":     lib_bytes.PackUint16LE,
+			"PackUint32BE":     lib_bytes.PackUint32BE,
+			"PackUint32LE":     lib_bytes.PackUint32LE,
+			"UnpackUint16BE":   lib_bytes.UnpackUint16BE,
+			"UnpackUint16LE":   lib_bytes.UnpackUint16LE,
+			"UnpackUint32BE":   lib_bytes.UnpackUint32BE,
+			"UnpackUint32LE":   lib_bytes.UnpackUint32LE,
 
 			// Var and consts
 
@@ -0,0 +1,30 @@
+package cwmp
+
+import (
+	lib_cwmp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/cwmp"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/cwmp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"Detect": lib_cwmp.Detect,
+
+			// Var and consts
+
+			// Objects / Classes
+			"DetectResponse": gojs.GetClassConstructor[lib_cwmp.DetectResponse](&lib_cwmp.DetectResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,30 @@
+package dhcp
+
+import (
+	lib_dhcp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/dhcp"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/dhcp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"Discover": lib_dhcp.Discover,
+
+			// Var and consts
+
+			// Objects / Classes
+			"DHCPOffer": gojs.GetClassConstructor[lib_dhcp.DHCPOffer](&lib_dhcp.DHCPOffer{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
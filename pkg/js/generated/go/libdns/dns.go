@@ -0,0 +1,32 @@
+package dns
+
+import (
+	lib_dns "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/dns"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/dns")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"CachedAnswers": lib_dns.CachedAnswers,
+			"Resolve":       lib_dns.Resolve,
+			"ReversePTR":    lib_dns.ReversePTR,
+
+			// Var and consts
+
+			// Objects / Classes
+			"CachedAnswersResponse": gojs.GetClassConstructor[lib_dns.CachedAnswersResponse](&lib_dns.CachedAnswersResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,32 @@
+package doh
+
+import (
+	lib_doh "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/doh"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/doh")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsDoH": lib_doh.IsDoH,
+			"IsDoT": lib_doh.IsDoT,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsDoHResponse": gojs.GetClassConstructor[lib_doh.IsDoHResponse](&lib_doh.IsDoHResponse{}),
+			"IsDoTResponse": gojs.GetClassConstructor[lib_doh.IsDoTResponse](&lib_doh.IsDoTResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
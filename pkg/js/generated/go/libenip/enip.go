@@ -0,0 +1,31 @@
+package enip
+
+import (
+	lib_enip "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/enip"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/enip")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"Discover":    lib_enip.Discover,
+			"GetIdentity": lib_enip.GetIdentity,
+
+			// Var and consts
+
+			// Objects / Classes
+			"Identity": gojs.GetClassConstructor[lib_enip.Identity](&lib_enip.Identity{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
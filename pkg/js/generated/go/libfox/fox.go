@@ -0,0 +1,30 @@
+package fox
+
+import (
+	lib_fox "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/fox"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/fox")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"GetInfo": lib_fox.GetInfo,
+
+			// Var and consts
+
+			// Objects / Classes
+			"GetInfoResponse": gojs.GetClassConstructor[lib_fox.GetInfoResponse](&lib_fox.GetInfoResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,31 @@
+package gopher
+
+import (
+	lib_gopher "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/gopher"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/gopher")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"Fetch": lib_gopher.Fetch,
+
+			// Var and consts
+
+			// Objects / Classes
+			"FetchResponse": gojs.GetClassConstructor[lib_gopher.FetchResponse](&lib_gopher.FetchResponse{}),
+			"Item":          gojs.GetClassConstructor[lib_gopher.Item](&lib_gopher.Item{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
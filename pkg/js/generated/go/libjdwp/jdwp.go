@@ -0,0 +1,30 @@
+package jdwp
+
+import (
+	lib_jdwp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/jdwp"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/jdwp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsJDWP": lib_jdwp.IsJDWP,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsJDWPResponse": gojs.GetClassConstructor[lib_jdwp.IsJDWPResponse](&lib_jdwp.IsJDWPResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,36 @@
+package mail
+
+import (
+	lib_mail "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mail"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/mail")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"CheckStartTLSStripping": lib_mail.CheckStartTLSStripping,
+			"DetectTLSMode":          lib_mail.DetectTLSMode,
+
+			// Var and consts
+			"TLSModeImplicit":  lib_mail.TLSModeImplicit,
+			"TLSModePlaintext": lib_mail.TLSModePlaintext,
+			"TLSModeStartTLS":  lib_mail.TLSModeStartTLS,
+			"TLSModeUnknown":   lib_mail.TLSModeUnknown,
+
+			// Objects / Classes
+			"CheckStartTLSStrippingResponse": gojs.GetClassConstructor[lib_mail.CheckStartTLSStrippingResponse](&lib_mail.CheckStartTLSStrippingResponse{}),
+			"DetectTLSModeResponse":          gojs.GetClassConstructor[lib_mail.DetectTLSModeResponse](&lib_mail.DetectTLSModeResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,32 @@
+package mdns
+
+import (
+	lib_mdns "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mdns"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/mdns")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"QueryServices": lib_mdns.QueryServices,
+			"ResolveName":   lib_mdns.ResolveName,
+
+			// Var and consts
+
+			// Objects / Classes
+			"QueryServicesResponse": gojs.GetClassConstructor[lib_mdns.QueryServicesResponse](&lib_mdns.QueryServicesResponse{}),
+			"ServiceInstance":       gojs.GetClassConstructor[lib_mdns.ServiceInstance](&lib_mdns.ServiceInstance{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
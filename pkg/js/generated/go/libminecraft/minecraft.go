@@ -0,0 +1,30 @@
+package minecraft
+
+import (
+	lib_minecraft "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/minecraft"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/minecraft")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"ServerListPing": lib_minecraft.ServerListPing,
+
+			// Var and consts
+
+			// Objects / Classes
+			"ServerListPingResponse": gojs.GetClassConstructor[lib_minecraft.ServerListPingResponse](&lib_minecraft.ServerListPingResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
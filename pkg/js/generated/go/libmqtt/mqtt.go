@@ -0,0 +1,31 @@
+package mqtt
+
+import (
+	lib_mqtt "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mqtt"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/mqtt")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"SampleTopics": lib_mqtt.SampleTopics,
+
+			// Var and consts
+
+			// Objects / Classes
+			"SampleTopicsOptions":  gojs.GetClassConstructor[lib_mqtt.SampleTopicsOptions](&lib_mqtt.SampleTopicsOptions{}),
+			"SampleTopicsResponse": gojs.GetClassConstructor[lib_mqtt.SampleTopicsResponse](&lib_mqtt.SampleTopicsResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -15,13 +15,36 @@ func init() {
 	module.Set(
 		gojs.Objects{
 			// Functions
-			"Open":    lib_net.Open,
-			"OpenTLS": lib_net.OpenTLS,
+			"ConnInfo":             lib_net.ConnInfo,
+			"Expect":               lib_net.Expect,
+			"MeasureAmplification": lib_net.MeasureAmplification,
+			"Open":                 lib_net.Open,
+			"OpenTLS":              lib_net.OpenTLS,
+			"Probe":                lib_net.Probe,
+			"ScanPorts":            lib_net.ScanPorts,
 
 			// Var and consts
+			"ProbeFiltered":           lib_net.ProbeFiltered,
+			"ProbeOpenImmediateClose": lib_net.ProbeOpenImmediateClose,
+			"ProbeOpenImmediateRST":   lib_net.ProbeOpenImmediateRST,
+			"ProbeOpenResponsive":     lib_net.ProbeOpenResponsive,
+			"ProbeOpenSilent":         lib_net.ProbeOpenSilent,
+			"ProbeRefused":            lib_net.ProbeRefused,
 
 			// Objects / Classes
-			"NetConn": gojs.GetClassConstructor[lib_net.NetConn](&lib_net.NetConn{}),
+			"AmplificationResponse": gojs.GetClassConstructor[lib_net.AmplificationResponse](&lib_net.AmplificationResponse{}),
+			"ConnInfoResponse":      gojs.GetClassConstructor[lib_net.ConnInfoResponse](&lib_net.ConnInfoResponse{}),
+			"DecodedData":           gojs.GetClassConstructor[lib_net.DecodedData](&lib_net.DecodedData{}),
+			"ExpectOptions":         gojs.GetClassConstructor[lib_net.ExpectOptions](&lib_net.ExpectOptions{}),
+			"ExpectResponse":        gojs.GetClassConstructor[lib_net.ExpectResponse](&lib_net.ExpectResponse{}),
+			"ExpectStep":            gojs.GetClassConstructor[lib_net.ExpectStep](&lib_net.ExpectStep{}),
+			"NetConn":               gojs.GetClassConstructor[lib_net.NetConn](&lib_net.NetConn{}),
+			"OpenOptions":           gojs.GetClassConstructor[lib_net.OpenOptions](&lib_net.OpenOptions{}),
+			"OpenTLSOptions":        gojs.GetClassConstructor[lib_net.OpenTLSOptions](&lib_net.OpenTLSOptions{}),
+			"PortResult":            gojs.GetClassConstructor[lib_net.PortResult](&lib_net.PortResult{}),
+			"ProbeOptions":          gojs.GetClassConstructor[lib_net.ProbeOptions](&lib_net.ProbeOptions{}),
+			"ProbeResponse":         gojs.GetClassConstructor[lib_net.ProbeResponse](&lib_net.ProbeResponse{}),
+			"ScanPortsOptions":      gojs.GetClassConstructor[lib_net.ScanPortsOptions](&lib_net.ScanPortsOptions{}),
 		},
 	).Register()
 }
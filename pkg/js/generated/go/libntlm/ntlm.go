@@ -0,0 +1,30 @@
+package ntlm
+
+import (
+	lib_ntlm "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/ntlm"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/ntlm")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"GetInfo": lib_ntlm.GetInfo,
+
+			// Var and consts
+
+			// Objects / Classes
+			"GetInfoResponse": gojs.GetClassConstructor[lib_ntlm.GetInfoResponse](&lib_ntlm.GetInfoResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
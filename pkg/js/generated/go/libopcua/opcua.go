@@ -0,0 +1,31 @@
+package opcua
+
+import (
+	lib_opcua "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/opcua"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/opcua")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"GetEndpoints": lib_opcua.GetEndpoints,
+
+			// Var and consts
+
+			// Objects / Classes
+			"Endpoint":             gojs.GetClassConstructor[lib_opcua.Endpoint](&lib_opcua.Endpoint{}),
+			"GetEndpointsResponse": gojs.GetClassConstructor[lib_opcua.GetEndpointsResponse](&lib_opcua.GetEndpointsResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -15,11 +15,13 @@ func init() {
 	module.Set(
 		gojs.Objects{
 			// Functions
+			"DetectEngine": lib_postgres.DetectEngine,
 
 			// Var and consts
 
 			// Objects / Classes
-			"PGClient": gojs.GetClassConstructor[lib_postgres.PGClient](&lib_postgres.PGClient{}),
+			"DetectEngineResponse": gojs.GetClassConstructor[lib_postgres.DetectEngineResponse](&lib_postgres.DetectEngineResponse{}),
+			"PGClient":             gojs.GetClassConstructor[lib_postgres.PGClient](&lib_postgres.PGClient{}),
 		},
 	).Register()
 }
@@ -0,0 +1,38 @@
+package probe
+
+import (
+	lib_probe "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/probe"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/probe")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"HoneypotScore": lib_probe.HoneypotScore,
+			"Identify":      lib_probe.Identify,
+			"Probes":        lib_probe.Probes,
+
+			// Var and consts
+			"HoneypotAnswersGarbage":     lib_probe.HoneypotAnswersGarbage,
+			"HoneypotIdenticalBanners":   lib_probe.HoneypotIdenticalBanners,
+			"HoneypotImplausibleLatency": lib_probe.HoneypotImplausibleLatency,
+			"HoneypotMultipleProtocols":  lib_probe.HoneypotMultipleProtocols,
+
+			// Objects / Classes
+			"HoneypotScoreOptions":  gojs.GetClassConstructor[lib_probe.HoneypotScoreOptions](&lib_probe.HoneypotScoreOptions{}),
+			"HoneypotScoreResponse": gojs.GetClassConstructor[lib_probe.HoneypotScoreResponse](&lib_probe.HoneypotScoreResponse{}),
+			"IdentifyResponse":      gojs.GetClassConstructor[lib_probe.IdentifyResponse](&lib_probe.IdentifyResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
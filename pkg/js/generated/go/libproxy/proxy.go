@@ -0,0 +1,30 @@
+package proxy
+
+import (
+	lib_proxy "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/proxy"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/proxy")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsOpenHTTPProxy": lib_proxy.IsOpenHTTPProxy,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsOpenHTTPProxyResponse": gojs.GetClassConstructor[lib_proxy.IsOpenHTTPProxyResponse](&lib_proxy.IsOpenHTTPProxyResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
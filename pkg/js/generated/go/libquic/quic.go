@@ -0,0 +1,30 @@
+package quic
+
+import (
+	lib_quic "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/quic"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/quic")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsQUIC": lib_quic.IsQUIC,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsQUICResponse": gojs.GetClassConstructor[lib_quic.IsQUICResponse](&lib_quic.IsQUICResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
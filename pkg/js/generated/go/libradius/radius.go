@@ -0,0 +1,30 @@
+package radius
+
+import (
+	lib_radius "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/radius"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/radius")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"CheckAuth": lib_radius.CheckAuth,
+
+			// Var and consts
+
+			// Objects / Classes
+			"CheckAuthResponse": gojs.GetClassConstructor[lib_radius.CheckAuthResponse](&lib_radius.CheckAuthResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -15,14 +15,26 @@ func init() {
 	module.Set(
 		gojs.Objects{
 			// Functions
-			"CheckRDPAuth": lib_rdp.CheckRDPAuth,
-			"IsRDP":        lib_rdp.IsRDP,
+			"CheckRDPAuth":    lib_rdp.CheckRDPAuth,
+			"GetRDWebVersion": lib_rdp.GetRDWebVersion,
+			"IsRDP":           lib_rdp.IsRDP,
+			"IsRDPAllAddrs":   lib_rdp.IsRDPAllAddrs,
+			"IsRDPStream":     lib_rdp.IsRDPStream,
 
 			// Var and consts
 
 			// Objects / Classes
+			"CheckRDPAuthOptions":  gojs.GetClassConstructor[lib_rdp.CheckRDPAuthOptions](&lib_rdp.CheckRDPAuthOptions{}),
 			"CheckRDPAuthResponse": gojs.GetClassConstructor[lib_rdp.CheckRDPAuthResponse](&lib_rdp.CheckRDPAuthResponse{}),
+			"IsRDPAddrResult":      gojs.GetClassConstructor[lib_rdp.IsRDPAddrResult](&lib_rdp.IsRDPAddrResult{}),
+			"IsRDPAllAddrsOptions": gojs.GetClassConstructor[lib_rdp.IsRDPAllAddrsOptions](&lib_rdp.IsRDPAllAddrsOptions{}),
+			"IsRDPOptions":         gojs.GetClassConstructor[lib_rdp.IsRDPOptions](&lib_rdp.IsRDPOptions{}),
 			"IsRDPResponse":        gojs.GetClassConstructor[lib_rdp.IsRDPResponse](&lib_rdp.IsRDPResponse{}),
+			"IsRDPStreamOptions":   gojs.GetClassConstructor[lib_rdp.IsRDPStreamOptions](&lib_rdp.IsRDPStreamOptions{}),
+			"IsRDPStreamResult":    gojs.GetClassConstructor[lib_rdp.IsRDPStreamResult](&lib_rdp.IsRDPStreamResult{}),
+			"RDWebVersionResponse": gojs.GetClassConstructor[lib_rdp.RDWebVersionResponse](&lib_rdp.RDWebVersionResponse{}),
+			"RawExchange":          gojs.GetClassConstructor[lib_rdp.RawExchange](&lib_rdp.RawExchange{}),
+			"ServerInfo":           gojs.GetClassConstructor[lib_rdp.ServerInfo](&lib_rdp.ServerInfo{}),
 		},
 	).Register()
 }
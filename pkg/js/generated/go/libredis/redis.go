@@ -18,13 +18,14 @@ func init() {
 			"Connect":           lib_redis.Connect,
 			"GetServerInfo":     lib_redis.GetServerInfo,
 			"GetServerInfoAuth": lib_redis.GetServerInfoAuth,
+			"GetTopology":       lib_redis.GetTopology,
 			"IsAuthenticated":   lib_redis.IsAuthenticated,
 			"RunLuaScript":      lib_redis.RunLuaScript,
 
 			// Var and consts
 
 			// Objects / Classes
-
+			"TopologyResponse": gojs.GetClassConstructor[lib_redis.TopologyResponse](&lib_redis.TopologyResponse{}),
 		},
 	).Register()
 }
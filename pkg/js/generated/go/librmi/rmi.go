@@ -0,0 +1,31 @@
+package rmi
+
+import (
+	lib_rmi "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/rmi"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/rmi")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsRMI":          lib_rmi.IsRMI,
+			"ListBoundNames": lib_rmi.ListBoundNames,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsRMIResponse": gojs.GetClassConstructor[lib_rmi.IsRMIResponse](&lib_rmi.IsRMIResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,31 @@
+package sip
+
+import (
+	lib_sip "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/sip"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/sip")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"CheckRegister": lib_sip.CheckRegister,
+
+			// Var and consts
+
+			// Objects / Classes
+			"CheckRegisterOptions":  gojs.GetClassConstructor[lib_sip.CheckRegisterOptions](&lib_sip.CheckRegisterOptions{}),
+			"CheckRegisterResponse": gojs.GetClassConstructor[lib_sip.CheckRegisterResponse](&lib_sip.CheckRegisterResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -19,7 +19,14 @@ func init() {
 			// Var and consts
 
 			// Objects / Classes
-			"SMBClient": gojs.GetClassConstructor[lib_smb.SMBClient](&lib_smb.SMBClient{}),
+			"CompressionSupport":     gojs.GetClassConstructor[lib_smb.CompressionSupport](&lib_smb.CompressionSupport{}),
+			"ListSharesInfoOptions":  gojs.GetClassConstructor[lib_smb.ListSharesInfoOptions](&lib_smb.ListSharesInfoOptions{}),
+			"ListSharesInfoResponse": gojs.GetClassConstructor[lib_smb.ListSharesInfoResponse](&lib_smb.ListSharesInfoResponse{}),
+			"ReadFileOptions":        gojs.GetClassConstructor[lib_smb.ReadFileOptions](&lib_smb.ReadFileOptions{}),
+			"ReadFileResponse":       gojs.GetClassConstructor[lib_smb.ReadFileResponse](&lib_smb.ReadFileResponse{}),
+			"SMBClient":              gojs.GetClassConstructor[lib_smb.SMBClient](&lib_smb.SMBClient{}),
+			"SecurityPolicy":         gojs.GetClassConstructor[lib_smb.SecurityPolicy](&lib_smb.SecurityPolicy{}),
+			"ShareInfo":              gojs.GetClassConstructor[lib_smb.ShareInfo](&lib_smb.ShareInfo{}),
 		},
 	).Register()
 }
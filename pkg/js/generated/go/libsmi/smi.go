@@ -0,0 +1,30 @@
+package smi
+
+import (
+	lib_smi "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/smi"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/smi")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsSmartInstall": lib_smi.IsSmartInstall,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsSmartInstallResponse": gojs.GetClassConstructor[lib_smi.IsSmartInstallResponse](&lib_smi.IsSmartInstallResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
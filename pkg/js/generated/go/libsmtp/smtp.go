@@ -15,14 +15,17 @@ func init() {
 	module.Set(
 		gojs.Objects{
 			// Functions
-			"NewSMTPClient": lib_smtp.NewSMTPClient,
+			"CheckOpenRelay": lib_smtp.CheckOpenRelay,
+			"NewSMTPClient":  lib_smtp.NewSMTPClient,
 
 			// Var and consts
 
 			// Objects / Classes
-			"Client":       lib_smtp.NewSMTPClient,
-			"SMTPMessage":  gojs.GetClassConstructor[lib_smtp.SMTPMessage](&lib_smtp.SMTPMessage{}),
-			"SMTPResponse": gojs.GetClassConstructor[lib_smtp.SMTPResponse](&lib_smtp.SMTPResponse{}),
+			"CheckOpenRelayOptions":  gojs.GetClassConstructor[lib_smtp.CheckOpenRelayOptions](&lib_smtp.CheckOpenRelayOptions{}),
+			"CheckOpenRelayResponse": gojs.GetClassConstructor[lib_smtp.CheckOpenRelayResponse](&lib_smtp.CheckOpenRelayResponse{}),
+			"Client":                 lib_smtp.NewSMTPClient,
+			"SMTPMessage":            gojs.GetClassConstructor[lib_smtp.SMTPMessage](&lib_smtp.SMTPMessage{}),
+			"SMTPResponse":           gojs.GetClassConstructor[lib_smtp.SMTPResponse](&lib_smtp.SMTPResponse{}),
 		},
 	).Register()
 }
@@ -0,0 +1,32 @@
+package snmp
+
+import (
+	lib_snmp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/snmp"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/snmp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"CheckV3User": lib_snmp.CheckV3User,
+			"GetEngineID": lib_snmp.GetEngineID,
+
+			// Var and consts
+
+			// Objects / Classes
+			"CheckV3UserResponse": gojs.GetClassConstructor[lib_snmp.CheckV3UserResponse](&lib_snmp.CheckV3UserResponse{}),
+			"EngineIDResponse":    gojs.GetClassConstructor[lib_snmp.EngineIDResponse](&lib_snmp.EngineIDResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
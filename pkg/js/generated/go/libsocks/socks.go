@@ -0,0 +1,30 @@
+package socks
+
+import (
+	lib_socks "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/socks"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/socks")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"IsOpenProxy": lib_socks.IsOpenProxy,
+
+			// Var and consts
+
+			// Objects / Classes
+			"IsOpenProxyResponse": gojs.GetClassConstructor[lib_socks.IsOpenProxyResponse](&lib_socks.IsOpenProxyResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,32 @@
+package ssdp
+
+import (
+	lib_ssdp "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/ssdp"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/ssdp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"Discover":             lib_ssdp.Discover,
+			"GetDeviceDescription": lib_ssdp.GetDeviceDescription,
+
+			// Var and consts
+
+			// Objects / Classes
+			"DeviceDescription": gojs.GetClassConstructor[lib_ssdp.DeviceDescription](&lib_ssdp.DeviceDescription{}),
+			"SSDPResponse":      gojs.GetClassConstructor[lib_ssdp.SSDPResponse](&lib_ssdp.SSDPResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -15,11 +15,13 @@ func init() {
 	module.Set(
 		gojs.Objects{
 			// Functions
+			"VerifyHostKey": lib_ssh.VerifyHostKey,
 
 			// Var and consts
 
 			// Objects / Classes
-			"SSHClient": gojs.GetClassConstructor[lib_ssh.SSHClient](&lib_ssh.SSHClient{}),
+			"SSHClient":             gojs.GetClassConstructor[lib_ssh.SSHClient](&lib_ssh.SSHClient{}),
+			"VerifyHostKeyResponse": gojs.GetClassConstructor[lib_ssh.VerifyHostKeyResponse](&lib_ssh.VerifyHostKeyResponse{}),
 		},
 	).Register()
 }
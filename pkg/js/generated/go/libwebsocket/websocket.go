@@ -0,0 +1,33 @@
+package websocket
+
+import (
+	lib_websocket "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/websocket"
+
+	"github.com/Mzack9999/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("nuclei/websocket")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+			"Connect":   lib_websocket.Connect,
+			"Handshake": lib_websocket.Handshake,
+
+			// Var and consts
+
+			// Objects / Classes
+			"Conn":              gojs.GetClassConstructor[lib_websocket.Conn](&lib_websocket.Conn{}),
+			"ConnectOptions":    gojs.GetClassConstructor[lib_websocket.ConnectOptions](&lib_websocket.ConnectOptions{}),
+			"HandshakeResponse": gojs.GetClassConstructor[lib_websocket.HandshakeResponse](&lib_websocket.HandshakeResponse{}),
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,64 @@
+
+
+/**
+ * CheckChallengeEndpoint requests the acme http-01 challenge directory
+ * (/.well-known/acme-challenge/) to detect directory listings exposing
+ * leftover challenge tokens. A missing directory (404/410) is reported as
+ * Clean while a listing is reported as DirectoryListing with the token filenames.
+ * HTTPS is tried first and plaintext HTTP is used as fallback.
+ * @example
+ * ```javascript
+ * const acme = require('nuclei/acme');
+ * const resp = acme.CheckChallengeEndpoint('acme.com', 80);
+ * if (resp.DirectoryListing) { log('exposed challenge tokens', resp.Tokens); }
+ * ```
+ */
+export function CheckChallengeEndpoint(host: string, port: number): ChallengeEndpointResponse | null {
+    return null;
+}
+
+
+
+/**
+ * ChallengeEndpointResponse is the response from the CheckChallengeEndpoint function.
+ * this is returned by CheckChallengeEndpoint function.
+ * @example
+ * ```javascript
+ * const acme = require('nuclei/acme');
+ * const resp = acme.CheckChallengeEndpoint('acme.com', 80);
+ * log(toJSON(resp));
+ * ```
+ */
+export interface ChallengeEndpointResponse {
+    
+    /**
+    * Clean is true if the challenge directory is not found (404 or 410)
+    */
+    
+    Clean?: boolean,
+    
+    /**
+    * DirectoryListing is true if the challenge directory is listed
+    */
+    
+    DirectoryListing?: boolean,
+    
+    /**
+    * Tokens are the challenge token filenames found in the directory listing
+    */
+    
+    Tokens?: string[],
+    
+    /**
+    * StatusCode is the http status code of the response
+    */
+    
+    StatusCode?: number,
+    
+    /**
+    * TLS is true if the endpoint was reached over https
+    */
+    
+    TLS?: boolean,
+}
+
@@ -0,0 +1,78 @@
+
+
+/**
+ * IsAJP checks if the given host and port are running an apache jserv
+ * protocol (ajp13) connector by sending a cping and waiting for the cpong.
+ * On success a minimal forward request (GET /) is sent over the same
+ * connection to read the server headers and to check if the connector
+ * appears to be a tomcat connector exploitable without a secret.
+ * @example
+ * ```javascript
+ * const ajp = require('nuclei/ajp');
+ * const isAJP = ajp.IsAJP('acme.com', 8009);
+ * log(isAJP.IsAJP, isAJP.Exploitable);
+ * ```
+ */
+export function IsAJP(host: string, port: number): IsAJPResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsAJPResponse is the response from the IsAJP function.
+ * this is returned by IsAJP function.
+ * @example
+ * ```javascript
+ * const ajp = require('nuclei/ajp');
+ * const isAJP = ajp.IsAJP('acme.com', 8009);
+ * log(toJSON(isAJP));
+ * ```
+ */
+export interface IsAJPResponse {
+    
+    /**
+    * IsAJP is true if the endpoint answered the cping with a cpong
+    */
+    
+    IsAJP?: boolean,
+    
+    /**
+    * StatusCode is the http status code of the forwarded request (0 if not answered)
+    */
+    
+    StatusCode?: number,
+    
+    /**
+    * Server is the Server (or Servlet-Engine) header of the forwarded request
+    */
+    
+    Server?: string,
+    
+    /**
+    * Tomcat is true if the container was identified as apache tomcat
+    */
+    
+    Tomcat?: boolean,
+    
+    /**
+    * Version is the tomcat version found in the response (if any)
+    */
+    
+    Version?: string,
+    
+    /**
+    * SecretRequired is true if the forwarded request was rejected (403)
+    * which is the behavior of connectors configured with a secret
+    */
+    
+    SecretRequired?: boolean,
+    
+    /**
+    * Exploitable is true if the tomcat connector accepted the forwarded
+    * request without a secret (ex: ghostcat CVE-2020-1938)
+    */
+    
+    Exploitable?: boolean,
+}
+
@@ -0,0 +1,119 @@
+
+
+/**
+ * BruteForce tries the credentials against the auth function of the given
+ * protocol library (ssh, mysql, mssql, postgres, redis) with bounded concurrency
+ * and stops at the first valid credential unless ContinueOnSuccess is set.
+ * Usernames returning an explicit lockout error (ex: account locked) or the same
+ * auth error LockoutThreshold times in a row are skipped to avoid locking accounts.
+ * Network errors (ex: timeouts, refused connections) are not lockout signals.
+ * No attempt is started past the scan deadline or the javascript execution timeout.
+ * Results are not memoized since credentials differ between calls.
+ * @example
+ * ```javascript
+ * const auth = require('nuclei/auth');
+ * const creds = [{ Username: 'root', Password: 'root' }, { Username: 'root', Password: 'toor' }];
+ * const result = auth.BruteForce('ssh', 'acme.com', 22, creds, { Concurrency: 2, Delay: 500 });
+ * if (result.Found) { log(toJSON(result.Valid)); }
+ * ```
+ */
+export function BruteForce(protocol: string, host: string, port: number, creds: Credential[], opts: BruteForceOptions): BruteForceResponse | null {
+    return null;
+}
+
+
+
+/**
+ * BruteForceOptions contains options for BruteForce function.
+ * @example
+ * ```javascript
+ * const auth = require('nuclei/auth');
+ * const options = new auth.BruteForceOptions();
+ * options.Concurrency = 2;
+ * options.Delay = 1000;
+ * ```
+ */
+export interface BruteForceOptions {
+    
+    /**
+    * Concurrency is the maximum number of concurrent attempts (default: 5, max: 25)
+    */
+    
+    Concurrency?: number,
+    
+    /**
+    * Delay is the minimum time in milliseconds between two attempts for the same username
+    */
+    
+    Delay?: number,
+    
+    /**
+    * LockoutThreshold is the number of consecutive identical auth errors (network
+    * errors excluded) after which a username is considered locked out and skipped (default: 3)
+    */
+    
+    LockoutThreshold?: number,
+    
+    /**
+    * ContinueOnSuccess keeps trying remaining credentials after a valid one is found
+    */
+    
+    ContinueOnSuccess?: boolean,
+}
+
+
+
+/**
+ * BruteForceResponse is the result of a BruteForce run.
+ * this is returned by BruteForce function.
+ * @example
+ * ```javascript
+ * const auth = require('nuclei/auth');
+ * const result = auth.BruteForce('ssh', 'acme.com', 22, [{ Username: 'root', Password: 'toor' }]);
+ * log(toJSON(result));
+ * ```
+ */
+export interface BruteForceResponse {
+    
+    /**
+    * Found is true if valid credentials were found
+    */
+    
+    Found?: boolean,
+    
+    /**
+    * Valid contains the valid credentials in the order they were found
+    */
+    
+    Valid?: Credential[],
+    
+    /**
+    * Attempts is the number of credentials tried
+    */
+    
+    Attempts?: number,
+    
+    /**
+    * LockedOut contains the usernames skipped after a lockout signal
+    */
+    
+    LockedOut?: string[],
+}
+
+
+
+/**
+ * Credential is a username and password pair tried by BruteForce.
+ * @example
+ * ```javascript
+ * const auth = require('nuclei/auth');
+ * const creds = [{ Username: 'root', Password: 'toor' }, { Username: 'admin', Password: 'admin' }];
+ * ```
+ */
+export interface Credential {
+    
+    Username?: string,
+    
+    Password?: string,
+}
+
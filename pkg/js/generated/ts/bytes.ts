@@ -1,5 +1,221 @@
 
 
+/**
+ * CRC16CCITT returns the CRC-16/CCITT-FALSE checksum of data
+ * (polynomial 0x1021 and initial value 0xffff).
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const crc = bytes.CRC16CCITT(bytes.FromString('123456789')); // 0x29b1
+ * ```
+ */
+export function CRC16CCITT(data: Uint8Array): number {
+    return 0;
+}
+
+
+
+/**
+ * CRC16DNP returns the CRC-16/DNP checksum of data.
+ * the checksum is sent in little endian order after each dnp3 block.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const crc = bytes.CRC16DNP([0x05, 0x64, 0x05, 0xc0, 0x01, 0x00, 0x00, 0x04]); // 0x21e9
+ * ```
+ */
+export function CRC16DNP(data: Uint8Array): number {
+    return 0;
+}
+
+
+
+/**
+ * CRC16Modbus returns the CRC-16/MODBUS checksum of data.
+ * the checksum is sent in little endian order at the end of modbus rtu frames.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const frame = [0x01, 0x03, 0x00, 0x00, 0x00, 0x0a];
+ * const crc = bytes.CRC16Modbus(frame); // 0xcdc5
+ * const packet = frame.concat(Array.from(bytes.PackUint16LE(crc)));
+ * ```
+ */
+export function CRC16Modbus(data: Uint8Array): number {
+    return 0;
+}
+
+
+
+/**
+ * CRC32 returns the CRC-32 (ieee) checksum of data.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const crc = bytes.CRC32(bytes.FromString('123456789')); // 0xcbf43926
+ * ```
+ */
+export function CRC32(data: Uint8Array): number {
+    return 0;
+}
+
+
+
+/**
+ * CRC32C returns the CRC-32C (castagnoli) checksum of data.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const crc = bytes.CRC32C(bytes.FromString('123456789')); // 0xe3069283
+ * ```
+ */
+export function CRC32C(data: Uint8Array): number {
+    return 0;
+}
+
+
+
+/**
+ * FromString returns the bytes of the given string.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const data = bytes.FromString('hello');
+ * ```
+ */
+export function FromString(data: string): Uint8Array {
+    return new Uint8Array(8);
+}
+
+
+
+/**
+ * InternetChecksum returns the 16 bit ones' complement checksum of data
+ * used by ip, icmp, tcp and udp headers (rfc 1071).
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const checksum = bytes.InternetChecksum([0x08, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x01]);
+ * ```
+ */
+export function InternetChecksum(data: Uint8Array): number {
+    return 0;
+}
+
+
+
+/**
+ * PackUint16BE packs value as a big endian unsigned 16 bit integer.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const data = bytes.PackUint16BE(502); // [0x01, 0xf6]
+ * ```
+ */
+export function PackUint16BE(value: number): Uint8Array | null {
+    return null;
+}
+
+
+
+/**
+ * PackUint16LE packs value as a little endian unsigned 16 bit integer.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const data = bytes.PackUint16LE(502); // [0xf6, 0x01]
+ * ```
+ */
+export function PackUint16LE(value: number): Uint8Array | null {
+    return null;
+}
+
+
+
+/**
+ * PackUint32BE packs value as a big endian unsigned 32 bit integer.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const data = bytes.PackUint32BE(0xcbf43926);
+ * ```
+ */
+export function PackUint32BE(value: number): Uint8Array | null {
+    return null;
+}
+
+
+
+/**
+ * PackUint32LE packs value as a little endian unsigned 32 bit integer.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const data = bytes.PackUint32LE(0xcbf43926);
+ * ```
+ */
+export function PackUint32LE(value: number): Uint8Array | null {
+    return null;
+}
+
+
+
+/**
+ * UnpackUint16BE unpacks the big endian unsigned 16 bit integer at offset of data.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const length = bytes.UnpackUint16BE([0x00, 0x00, 0x00, 0x06], 2); // 6
+ * ```
+ */
+export function UnpackUint16BE(data: Uint8Array, offset: number): number | null {
+    return null;
+}
+
+
+
+/**
+ * UnpackUint16LE unpacks the little endian unsigned 16 bit integer at offset of data.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const crc = bytes.UnpackUint16LE([0xc5, 0xcd], 0); // 0xcdc5
+ * ```
+ */
+export function UnpackUint16LE(data: Uint8Array, offset: number): number | null {
+    return null;
+}
+
+
+
+/**
+ * UnpackUint32BE unpacks the big endian unsigned 32 bit integer at offset of data.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const value = bytes.UnpackUint32BE([0xcb, 0xf4, 0x39, 0x26], 0); // 0xcbf43926
+ * ```
+ */
+export function UnpackUint32BE(data: Uint8Array, offset: number): number | null {
+    return null;
+}
+
+
+
+/**
+ * UnpackUint32LE unpacks the little endian unsigned 32 bit integer at offset of data.
+ * @example
+ * ```javascript
+ * const bytes = require('nuclei/bytes');
+ * const value = bytes.UnpackUint32LE([0x26, 0x39, 0xf4, 0xcb], 0); // 0xcbf43926
+ * ```
+ */
+export function UnpackUint32LE(data: Uint8Array, offset: number): number | null {
+    return null;
+}
+
+
+
 /**
  * Buffer is a bytes/Uint8Array type in javascript
  * @example
@@ -0,0 +1,75 @@
+
+
+/**
+ * Detect sends a TR-069 (CWMP) GetRPCMethods SOAP request to the given host,
+ * port and path and parses the response to confirm a CWMP ACS/CPE endpoint and
+ * report supported rpc methods. HTTPS is tried first and plaintext HTTP is used
+ * as fallback. Endpoints requiring authentication are reported with AuthRequired.
+ * @example
+ * ```javascript
+ * const cwmp = require('nuclei/cwmp');
+ * const result = cwmp.Detect('acme.com', 7547, '/');
+ * log(result.IsCWMP, result.Methods);
+ * ```
+ */
+export function Detect(host: string, port: number, path: string): DetectResponse | null {
+    return null;
+}
+
+
+
+/**
+ * DetectResponse is the response from the Detect function.
+ * this is returned by Detect function.
+ * @example
+ * ```javascript
+ * const cwmp = require('nuclei/cwmp');
+ * const result = cwmp.Detect('acme.com', 7547, '/');
+ * log(toJSON(result));
+ * ```
+ */
+export interface DetectResponse {
+    
+    /**
+    * IsCWMP is true if the endpoint responded with a cwmp soap envelope
+    */
+    
+    IsCWMP?: boolean,
+    
+    /**
+    * AuthRequired is true if the endpoint requires authentication (401)
+    */
+    
+    AuthRequired?: boolean,
+    
+    /**
+    * StatusCode is the http status code of the response
+    */
+    
+    StatusCode?: number,
+    
+    /**
+    * Server is the value of Server header (if any)
+    */
+    
+    Server?: string,
+    
+    /**
+    * Methods contains rpc methods supported by the endpoint (GetRPCMethodsResponse)
+    */
+    
+    Methods?: string[],
+    
+    /**
+    * Fault is the soap/cwmp fault string (if any)
+    */
+    
+    Fault?: string,
+    
+    /**
+    * TLS is true if the endpoint was reached over https
+    */
+    
+    TLS?: boolean,
+}
+
@@ -0,0 +1,72 @@
+
+
+/**
+ * Discover broadcasts a DHCPDISCOVER and collects DHCPOFFER responses
+ * until the read timeout of the execution is reached. The argument can either be
+ * a network interface name (ex: eth0) through which the request is broadcast using its
+ * hardware address or a host/ip to which the DHCPDISCOVER is sent directly instead of broadcast.
+ * This can be used to detect rogue dhcp servers on internal networks.
+ * Note: this function binds to udp port 68 and sends broadcast datagrams which
+ * requires root privileges (or CAP_NET_BIND_SERVICE and CAP_NET_RAW on linux).
+ * @example
+ * ```javascript
+ * const dhcp = require('nuclei/dhcp');
+ * const offers = dhcp.Discover('eth0');
+ * log(toJSON(offers));
+ * ```
+ */
+export function Discover(ifaceOrHost: string): DHCPOffer[] | null {
+    return null;
+}
+
+
+
+/**
+ * DHCPOffer is a DHCPOFFER received in response to a DHCPDISCOVER.
+ * this is returned by Discover function.
+ * @example
+ * ```javascript
+ * const dhcp = require('nuclei/dhcp');
+ * const offers = dhcp.Discover('eth0');
+ * log(toJSON(offers));
+ * ```
+ */
+export interface DHCPOffer {
+    
+    /**
+    * OfferedIP is the ip address offered to the client (yiaddr)
+    */
+    
+    OfferedIP?: string,
+    
+    /**
+    * SubnetMask is the offered subnet mask
+    */
+    
+    SubnetMask?: string,
+    
+    /**
+    * Gateway is the first router advertised in the offer
+    */
+    
+    Gateway?: string,
+    
+    /**
+    * DNS is the list of advertised dns servers
+    */
+    
+    DNS?: string[],
+    
+    /**
+    * ServerIdentifier is the address of the dhcp server that sent the offer
+    */
+    
+    ServerIdentifier?: string,
+    
+    /**
+    * LeaseTime is the offered lease time in seconds
+    */
+    
+    LeaseTime?: number,
+}
+
@@ -0,0 +1,102 @@
+
+
+/**
+ * CachedAnswers returns the A, AAAA and CNAME answers cached for host by the
+ * dialer of the execution, that is the addresses network connections to host
+ * (ex: rdp.IsRDP, net.Open) actually used. No query is sent: Cached is false
+ * and the records are empty if host was not dialed during the execution.
+ * @example
+ * ```javascript
+ * const dns = require('nuclei/dns');
+ * const rdp = require('nuclei/rdp');
+ * const isRDP = rdp.IsRDP('acme.com', 3389);
+ * const answers = dns.CachedAnswers('acme.com');
+ * log(toJSON(answers.A), toJSON(answers.CNAME));
+ * ```
+ */
+export function CachedAnswers(host: string): CachedAnswersResponse | null {
+    return null;
+}
+
+
+
+/**
+ * Resolve resolves the records of given type (A, AAAA, CNAME, MX, TXT, NS)
+ * of host using the resolvers configured for the execution (-r, -system-resolvers).
+ * Results are memoized so repeated lookups of the same name are not sent again.
+ * An empty list is returned for names without records of the given type.
+ * @example
+ * ```javascript
+ * const dns = require('nuclei/dns');
+ * const ips = dns.Resolve('acme.com', 'A');
+ * const txt = dns.Resolve('acme.com', 'TXT');
+ * log(toJSON(ips), toJSON(txt));
+ * ```
+ */
+export function Resolve(host: string, recordType: string): string[] | null {
+    return null;
+}
+
+
+
+/**
+ * ReversePTR resolves the hostnames of given ipv4 or ipv6 address with a PTR
+ * lookup (in-addr.arpa / ip6.arpa) using the resolvers configured for the execution.
+ * An empty list is returned when the address has no PTR record while an error
+ * is returned if the lookup itself fails (ex: SERVFAIL or timeout).
+ * @example
+ * ```javascript
+ * const dns = require('nuclei/dns');
+ * const names = dns.ReversePTR('93.184.216.34');
+ * log(toJSON(names));
+ * ```
+ */
+export function ReversePTR(ip: string): string[] | null {
+    return null;
+}
+
+
+
+/**
+ * CachedAnswersResponse is the response from the CachedAnswers function.
+ * this is returned by CachedAnswers function.
+ * @example
+ * ```javascript
+ * const dns = require('nuclei/dns');
+ * const answers = dns.CachedAnswers('acme.com');
+ * log(toJSON(answers));
+ * ```
+ */
+export interface CachedAnswersResponse {
+    
+    /**
+    * Host is the hostname of the answers
+    */
+    
+    Host?: string,
+    
+    /**
+    * Cached is true if the host was resolved during the execution
+    */
+    
+    Cached?: boolean,
+    
+    /**
+    * A are the ipv4 addresses of the host
+    */
+    
+    A?: string[],
+    
+    /**
+    * AAAA are the ipv6 addresses of the host
+    */
+    
+    AAAA?: string[],
+    
+    /**
+    * CNAME is the cname chain of the host in the order of the answers
+    */
+    
+    CNAME?: string[],
+}
+
@@ -0,0 +1,100 @@
+
+
+/**
+ * IsDoH checks if the given host and port expose a DNS-over-HTTPS endpoint
+ * by sending a rfc 8484 POST query (root NS) to path (default: /dns-query)
+ * and validating the dns message of the response body.
+ * @example
+ * ```javascript
+ * const doh = require('nuclei/doh');
+ * const resp = doh.IsDoH('dns.acme.com', 443, '/dns-query');
+ * log(resp.IsDoH, resp.Rcode);
+ * ```
+ */
+export function IsDoH(host: string, port: number, path: string): IsDoHResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsDoT checks if the given host and port expose a DNS-over-TLS server
+ * (rfc 7858, usually on port 853) by sending a query (root NS) over tls
+ * and validating the dns message of the response.
+ * @example
+ * ```javascript
+ * const doh = require('nuclei/doh');
+ * const resp = doh.IsDoT('dns.acme.com', 853);
+ * log(resp.IsDoT, resp.Rcode);
+ * ```
+ */
+export function IsDoT(host: string, port: number): IsDoTResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsDoHResponse is the response from the IsDoH function.
+ * this is returned by IsDoH function.
+ * @example
+ * ```javascript
+ * const doh = require('nuclei/doh');
+ * const resp = doh.IsDoH('dns.acme.com', 443, '/dns-query');
+ * log(toJSON(resp));
+ * ```
+ */
+export interface IsDoHResponse {
+    
+    /**
+    * IsDoH is true if the endpoint answered the query with a valid dns message
+    */
+    
+    IsDoH?: boolean,
+    
+    /**
+    * StatusCode is the http status code of the response
+    */
+    
+    StatusCode?: number,
+    
+    /**
+    * ContentType is the content type of the response
+    */
+    
+    ContentType?: string,
+    
+    /**
+    * Rcode is the response code of the dns message (ex: NOERROR, REFUSED)
+    */
+    
+    Rcode?: string,
+}
+
+
+
+/**
+ * IsDoTResponse is the response from the IsDoT function.
+ * this is returned by IsDoT function.
+ * @example
+ * ```javascript
+ * const doh = require('nuclei/doh');
+ * const resp = doh.IsDoT('dns.acme.com', 853);
+ * log(toJSON(resp));
+ * ```
+ */
+export interface IsDoTResponse {
+    
+    /**
+    * IsDoT is true if the server answered the query with a valid dns message
+    */
+    
+    IsDoT?: boolean,
+    
+    /**
+    * Rcode is the response code of the dns message (ex: NOERROR, REFUSED)
+    */
+    
+    Rcode?: string,
+}
+
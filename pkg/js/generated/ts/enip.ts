@@ -0,0 +1,115 @@
+
+
+/**
+ * Discover sends an EtherNet/IP List Identity request over UDP and collects
+ * responses until the read timeout of the execution is reached. If host is empty
+ * the request is broadcast (255.255.255.255) on the local network.
+ * Note: sending broadcast datagrams may require elevated privileges on some platforms.
+ * @example
+ * ```javascript
+ * const enip = require('nuclei/enip');
+ * const devices = enip.Discover('192.168.1.10');
+ * log(toJSON(devices));
+ * ```
+ */
+export function Discover(host: string): Identity[] | null {
+    return null;
+}
+
+
+
+/**
+ * GetIdentity sends an EtherNet/IP List Identity request over TCP to the given
+ * host and port and returns the parsed CIP identity of the device.
+ * @example
+ * ```javascript
+ * const enip = require('nuclei/enip');
+ * const identity = enip.GetIdentity('acme.com', 44818);
+ * log(identity.ProductName, identity.SerialNumber);
+ * ```
+ */
+export function GetIdentity(host: string, port: number): Identity | null {
+    return null;
+}
+
+
+
+/**
+ * Identity is the CIP identity returned in a List Identity response.
+ * this is returned by GetIdentity and Discover functions.
+ * @example
+ * ```javascript
+ * const enip = require('nuclei/enip');
+ * const identity = enip.GetIdentity('acme.com', 44818);
+ * log(toJSON(identity));
+ * ```
+ */
+export interface Identity {
+    
+    /**
+    * IsENIP is true if a valid list identity response was received
+    */
+    
+    IsENIP?: boolean,
+    
+    /**
+    * Address is the address of the responding device
+    */
+    
+    Address?: string,
+    
+    /**
+    * VendorID is the cip vendor id
+    */
+    
+    VendorID?: number,
+    
+    /**
+    * Vendor is the name of the vendor (for well known vendor ids)
+    */
+    
+    Vendor?: string,
+    
+    /**
+    * DeviceType is the cip device type
+    */
+    
+    DeviceType?: number,
+    
+    /**
+    * ProductCode is the vendor specific product code
+    */
+    
+    ProductCode?: number,
+    
+    /**
+    * Revision is the major.minor revision of the device
+    */
+    
+    Revision?: string,
+    
+    /**
+    * Status is the device status word
+    */
+    
+    Status?: number,
+    
+    /**
+    * SerialNumber is the serial number of the device (hex)
+    */
+    
+    SerialNumber?: string,
+    
+    /**
+    * ProductName is the product name of the device
+    */
+    
+    ProductName?: string,
+    
+    /**
+    * State is the device state
+    */
+    
+    State?: number,
+}
+
@@ -0,0 +1,73 @@
+
+
+/**
+ * GetInfo sends a Niagara Fox hello to the given host and port and parses
+ * the returned properties (host name, addresses, versions, station name).
+ * Once the hello is confirmed no error is returned, properties are left
+ * empty if the response is cut short (ex: read timeout).
+ * @example
+ * ```javascript
+ * const fox = require('nuclei/fox');
+ * const info = fox.GetInfo('acme.com', 1911);
+ * log(info.StationName, info.HostAddress);
+ * ```
+ */
+export function GetInfo(host: string, port: number): GetInfoResponse | null {
+    return null;
+}
+
+
+
+/**
+ * GetInfoResponse is the response from the GetInfo function.
+ * this is returned by GetInfo function.
+ * @example
+ * ```javascript
+ * const fox = require('nuclei/fox');
+ * const info = fox.GetInfo('acme.com', 1911);
+ * log(toJSON(info));
+ * ```
+ */
+export interface GetInfoResponse {
+    
+    /**
+    * IsFox is true if the service responded with a fox hello
+    */
+    
+    IsFox?: boolean,
+    
+    FoxVersion?: string,
+    
+    HostName?: string,
+    
+    HostAddress?: string,
+    
+    HostID?: string,
+    
+    AppName?: string,
+    
+    AppVersion?: string,
+    
+    VMName?: string,
+    
+    VMVersion?: string,
+    
+    OSName?: string,
+    
+    OSVersion?: string,
+    
+    StationName?: string,
+    
+    BrandID?: string,
+    
+    Language?: string,
+    
+    TimeZone?: string,
+    
+    /**
+    * Properties contains all key/value pairs of the hello response
+    */
+    
+    Properties?: Record<string, string>,
+}
+
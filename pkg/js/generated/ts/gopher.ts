@@ -0,0 +1,101 @@
+
+
+/**
+ * Fetch sends the selector to the gopher server at the given host and port
+ * and returns its response. Menus are parsed into items and other responses
+ * are returned as text with control characters removed.
+ * An empty selector fetches the root menu.
+ * @example
+ * ```javascript
+ * const gopher = require('nuclei/gopher');
+ * const resp = gopher.Fetch('acme.com', 70, '/');
+ * for (const item of resp.Items) { log(item.Type, item.Selector); }
+ * ```
+ */
+export function Fetch(host: string, port: number, selector: string): FetchResponse | null {
+    return null;
+}
+
+
+
+/**
+ * FetchResponse is the response from the Fetch function.
+ * this is returned by Fetch function.
+ * @example
+ * ```javascript
+ * const gopher = require('nuclei/gopher');
+ * const resp = gopher.Fetch('acme.com', 70, '');
+ * log(toJSON(resp));
+ * ```
+ */
+export interface FetchResponse {
+    
+    /**
+    * IsMenu is true if the response was parsed as a gopher menu
+    */
+    
+    IsMenu?: boolean,
+    
+    /**
+    * Items are the items of the menu (if IsMenu)
+    */
+    
+    Items?: Item[],
+    
+    /**
+    * Text is the response with control characters removed (if not IsMenu)
+    */
+    
+    Text?: string,
+    
+    /**
+    * Truncated is true if the response exceeded the read limit
+    */
+    
+    Truncated?: boolean,
+}
+
+
+
+/**
+ * Item is an item of a gopher menu.
+ * @example
+ * ```javascript
+ * const gopher = require('nuclei/gopher');
+ * const resp = gopher.Fetch('acme.com', 70, '');
+ * log(resp.Items[0].Display);
+ * ```
+ */
+export interface Item {
+    
+    /**
+    * Type is the item type (ex: 0 for text, 1 for menu, i for info)
+    */
+    
+    Type?: string,
+    
+    /**
+    * Display is the user visible name of the item
+    */
+    
+    Display?: string,
+    
+    /**
+    * Selector is the selector of the item
+    */
+    
+    Selector?: string,
+    
+    /**
+    * Host is the host serving the item
+    */
+    
+    Host?: string,
+    
+    /**
+    * Port is the port serving the item
+    */
+    
+    Port?: number,
+}
+
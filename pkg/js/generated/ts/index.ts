@@ -1,21 +1,49 @@
+export * as acme from './acme';
+export * as ajp from './ajp';
+export * as auth from './auth';
 export * as bytes from './bytes';
+export * as cwmp from './cwmp';
+export * as dhcp from './dhcp';
+export * as dns from './dns';
+export * as doh from './doh';
+export * as enip from './enip';
+export * as fox from './fox';
 export * as fs from './fs';
 export * as goconsole from './goconsole';
+export * as gopher from './gopher';
 export * as ikev2 from './ikev2';
+export * as jdwp from './jdwp';
 export * as kerberos from './kerberos';
 export * as ldap from './ldap';
+export * as mail from './mail';
+export * as mdns from './mdns';
+export * as minecraft from './minecraft';
+export * as mqtt from './mqtt';
 export * as mssql from './mssql';
 export * as mysql from './mysql';
 export * as net from './net';
+export * as ntlm from './ntlm';
+export * as opcua from './opcua';
 export * as oracle from './oracle';
 export * as pop3 from './pop3';
 export * as postgres from './postgres';
+export * as probe from './probe';
+export * as proxy from './proxy';
+export * as quic from './quic';
+export * as radius from './radius';
 export * as rdp from './rdp';
 export * as redis from './redis';
+export * as rmi from './rmi';
 export * as rsync from './rsync';
+export * as sip from './sip';
 export * as smb from './smb';
+export * as smi from './smi';
 export * as smtp from './smtp';
+export * as snmp from './snmp';
+export * as socks from './socks';
+export * as ssdp from './ssdp';
 export * as ssh from './ssh';
 export * as structs from './structs';
 export * as telnet from './telnet';
 export * as vnc from './vnc';
+export * as websocket from './websocket';
@@ -0,0 +1,70 @@
+
+
+/**
+ * IsJDWP checks if the given host and port are running a java debug wire protocol
+ * (jdwp) endpoint by performing the jdwp handshake and on success issues
+ * VirtualMachine.Version command to return the jvm version and description.
+ * Once the handshake succeeded no error is returned, the version is left
+ * empty if the command fails (ex: read timeout).
+ * @example
+ * ```javascript
+ * const jdwp = require('nuclei/jdwp');
+ * const isJDWP = jdwp.IsJDWP('acme.com', 8000);
+ * log(toJSON(isJDWP));
+ * ```
+ */
+export function IsJDWP(host: string, port: number): IsJDWPResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsJDWPResponse is the response from the IsJDWP function.
+ * this is returned by IsJDWP function.
+ * @example
+ * ```javascript
+ * const jdwp = require('nuclei/jdwp');
+ * const isJDWP = jdwp.IsJDWP('acme.com', 8000);
+ * log(toJSON(isJDWP));
+ * ```
+ */
+export interface IsJDWPResponse {
+    
+    /**
+    * IsJDWP is true if the endpoint completed the jdwp handshake
+    */
+    
+    IsJDWP?: boolean,
+    
+    /**
+    * Description is the jvm description returned by VirtualMachine.Version
+    */
+    
+    Description?: string,
+    
+    /**
+    * JDWPMajor is the major version of jdwp protocol
+    */
+    
+    JDWPMajor?: number,
+    
+    /**
+    * JDWPMinor is the minor version of jdwp protocol
+    */
+    
+    JDWPMinor?: number,
+    
+    /**
+    * VMVersion is the version of the jvm (ex: 17.0.2)
+    */
+    
+    VMVersion?: string,
+    
+    /**
+    * VMName is the name of the jvm (ex: OpenJDK 64-Bit Server VM)
+    */
+    
+    VMName?: string,
+}
+
@@ -0,0 +1,131 @@
+
+
+
+export const TLSModeImplicit = "implicit";
+
+
+export const TLSModePlaintext = "plaintext";
+
+
+export const TLSModeStartTLS = "starttls";
+
+
+export const TLSModeUnknown = "unknown";
+
+/**
+ * CheckStartTLSStripping checks whether a smtp, imap or pop3 service advertising
+ * STARTTLS (see DetectTLSMode) still permits authentication over plaintext, the
+ * precondition of STARTTLS stripping attacks.
+ * Authentication is started without credentials and cancelled right away: smtp
+ * AUTH and pop3 USER/AUTH must be accepted, while imap LOGIN is permitted unless
+ * LOGINDISABLED is advertised.
+ * PlaintextAuth is only checked for starttls services (implicit tls cannot be
+ * stripped and plaintext only services never upgrade).
+ * @example
+ * ```javascript
+ * const mail = require('nuclei/mail');
+ * const resp = mail.CheckStartTLSStripping('acme.com', 587);
+ * if (resp.PlaintextAuth) { log('plaintext auth allowed before starttls'); }
+ * ```
+ */
+export function CheckStartTLSStripping(host: string, port: number): CheckStartTLSStrippingResponse | null {
+    return null;
+}
+
+
+
+/**
+ * DetectTLSMode determines whether a smtp, imap or pop3 service speaks
+ * implicit tls, supports STARTTLS or is plaintext only.
+ * An implicit tls handshake is tried first, then the plaintext greeting
+ * is read and the capabilities of the server are checked for STARTTLS.
+ * @example
+ * ```javascript
+ * const mail = require('nuclei/mail');
+ * const resp = mail.DetectTLSMode('acme.com', 2525);
+ * if (resp.Mode == mail.TLSModeStartTLS) { log('starttls supported'); }
+ * ```
+ */
+export function DetectTLSMode(host: string, port: number): DetectTLSModeResponse | null {
+    return null;
+}
+
+
+
+/**
+ * CheckStartTLSStrippingResponse is the response from the CheckStartTLSStripping function.
+ * this is returned by CheckStartTLSStripping function.
+ * @example
+ * ```javascript
+ * const mail = require('nuclei/mail');
+ * const resp = mail.CheckStartTLSStripping('acme.com', 587);
+ * log(toJSON(resp));
+ * ```
+ */
+export interface CheckStartTLSStrippingResponse {
+    
+    /**
+    * Mode is the tls mode of the service (see DetectTLSMode)
+    */
+    
+    Mode?: string,
+    
+    /**
+    * Protocol is the mail protocol identified from the greeting (smtp, imap or pop3)
+    */
+    
+    Protocol?: string,
+    
+    /**
+    * Banner is the greeting sent by the server
+    */
+    
+    Banner?: string,
+    
+    /**
+    * Mechanisms are the authentication mechanisms advertised before tls
+    */
+    
+    Mechanisms?: string[],
+    
+    /**
+    * PlaintextAuth is true if a STARTTLS service accepts authentication
+    * before tls which allows a mitm to strip STARTTLS and capture credentials
+    */
+    
+    PlaintextAuth?: boolean,
+}
+
+
+
+/**
+ * DetectTLSModeResponse is the response from the DetectTLSMode function.
+ * this is returned by DetectTLSMode function.
+ * @example
+ * ```javascript
+ * const mail = require('nuclei/mail');
+ * const resp = mail.DetectTLSMode('acme.com', 587);
+ * log(toJSON(resp));
+ * ```
+ */
+export interface DetectTLSModeResponse {
+    
+    /**
+    * Mode is one of TLSModeImplicit, TLSModeStartTLS, TLSModePlaintext or TLSModeUnknown
+    */
+    
+    Mode?: string,
+    
+    /**
+    * Protocol is the mail protocol identified from the greeting (smtp, imap or pop3)
+    */
+    
+    Protocol?: string,
+    
+    /**
+    * Banner is the greeting sent by the server
+    */
+    
+    Banner?: string,
+}
+
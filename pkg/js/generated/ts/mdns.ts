@@ -0,0 +1,87 @@
+
+
+/**
+ * QueryServices sends a DNS-SD service enumeration query (PTR _services._dns-sd._udp.local)
+ * to the given host over mDNS and returns the advertised service types and their instances.
+ * If host is empty the query is sent to the mDNS multicast group (224.0.0.251) and
+ * responses from all responders are collected until the read timeout is reached.
+ * @example
+ * ```javascript
+ * const mdns = require('nuclei/mdns');
+ * const services = mdns.QueryServices('192.168.1.10');
+ * log(toJSON(services));
+ * ```
+ */
+export function QueryServices(host: string): QueryServicesResponse | null {
+    return null;
+}
+
+
+
+/**
+ * ResolveName resolves the given .local name using mDNS multicast
+ * A and AAAA queries and returns the resolved addresses.
+ * @example
+ * ```javascript
+ * const mdns = require('nuclei/mdns');
+ * const addrs = mdns.ResolveName('printer.local');
+ * log(addrs);
+ * ```
+ */
+export function ResolveName(name: string): string[] | null {
+    return null;
+}
+
+
+
+/**
+ * QueryServicesResponse is the response from the QueryServices function.
+ * this is returned by QueryServices function.
+ * @example
+ * ```javascript
+ * const mdns = require('nuclei/mdns');
+ * const services = mdns.QueryServices('192.168.1.10');
+ * log(toJSON(services));
+ * ```
+ */
+export interface QueryServicesResponse {
+    
+    /**
+    * Services contains advertised service types (ex: _http._tcp.local)
+    */
+    
+    Services?: string[],
+    
+    /**
+    * Instances contains advertised service instances
+    */
+    
+    Instances?: ServiceInstance[],
+}
+
+
+
+/**
+ * ServiceInstance is a DNS-SD service instance advertised over mDNS.
+ * @example
+ * ```javascript
+ * const mdns = require('nuclei/mdns');
+ * const services = mdns.QueryServices('192.168.1.10');
+ * for (const instance of services.Instances) {
+ *   log(instance.Name, instance.Target, instance.Port);
+ * }
+ * ```
+ */
+export interface ServiceInstance {
+    
+    Name?: string,
+    
+    Service?: string,
+    
+    Target?: string,
+    
+    Port?: number,
+    
+    TXT?: string[],
+}
+
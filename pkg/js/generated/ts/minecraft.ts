@@ -0,0 +1,72 @@
+
+
+/**
+ * ServerListPing performs a server list ping (handshake followed by a status
+ * request) on the given host and port and returns the server status
+ * (version, protocol, MOTD and player counts).
+ * @example
+ * ```javascript
+ * const minecraft = require('nuclei/minecraft');
+ * const status = minecraft.ServerListPing('acme.com', 25565);
+ * log(status.Version, status.MOTD, status.OnlinePlayers);
+ * ```
+ */
+export function ServerListPing(host: string, port: number): ServerListPingResponse | null {
+    return null;
+}
+
+
+
+/**
+ * ServerListPingResponse is the response from the ServerListPing function.
+ * this is returned by ServerListPing function.
+ * @example
+ * ```javascript
+ * const minecraft = require('nuclei/minecraft');
+ * const status = minecraft.ServerListPing('acme.com', 25565);
+ * log(toJSON(status));
+ * ```
+ */
+export interface ServerListPingResponse {
+    
+    /**
+    * IsMinecraft is true if the service responded with a status
+    */
+    
+    IsMinecraft?: boolean,
+    
+    /**
+    * Version is the name of the server version (ex: 1.20.4)
+    */
+    
+    Version?: string,
+    
+    /**
+    * Protocol is the protocol version number of the server
+    */
+    
+    Protocol?: number,
+    
+    /**
+    * MOTD is the message of the day without formatting codes
+    */
+    
+    MOTD?: string,
+    
+    OnlinePlayers?: number,
+    
+    MaxPlayers?: number,
+    
+    /**
+    * Players contains the names of the player sample if any
+    */
+    
+    Players?: string[],
+    
+    /**
+    * Raw is the json status returned by server
+    */
+    
+    Raw?: string,
+}
+
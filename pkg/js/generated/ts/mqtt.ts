@@ -0,0 +1,140 @@
+
+
+/**
+ * SampleTopics connects to the mqtt broker (3.1.1) at the given host and port,
+ * subscribes to the topic filter (default: #) and collects the topic names of
+ * messages published during a bounded window. Open brokers leaking data can be
+ * detected with it. The number of topics, bytes and the window are capped and
+ * the result is not memoized unless Memoize is set. Sampling cut short by the
+ * scan deadline returns the topics collected so far with TimedOut set.
+ * @example
+ * ```javascript
+ * const mqtt = require('nuclei/mqtt');
+ * const resp = mqtt.SampleTopics('acme.com', 1883, { Duration: 5 });
+ * if (resp.Topics.length > 0) { log(resp.Topics); }
+ * ```
+ */
+export function SampleTopics(host: string, port: number, opts: SampleTopicsOptions): SampleTopicsResponse | null {
+    return null;
+}
+
+
+
+/**
+ * SampleTopicsOptions contains options for SampleTopics function.
+ * @example
+ * ```javascript
+ * const mqtt = require('nuclei/mqtt');
+ * const resp = mqtt.SampleTopics('acme.com', 1883, { Topic: 'devices/#', Duration: 10, MaxPayload: 64 });
+ * ```
+ */
+export interface SampleTopicsOptions {
+    
+    /**
+    * Topic is the topic filter to subscribe to (default: #)
+    */
+    
+    Topic?: string,
+    
+    /**
+    * Duration is the sampling window in seconds (default: 5, max: 60)
+    */
+    
+    Duration?: number,
+    
+    /**
+    * MaxTopics is the maximum number of collected topics (default: 100, max: 1000)
+    */
+    
+    MaxTopics?: number,
+    
+    /**
+    * MaxPayload is the number of payload bytes sampled per topic (default: 0, max: 1024)
+    */
+    
+    MaxPayload?: number,
+    
+    /**
+    * Username is the username used to connect (optional)
+    */
+    
+    Username?: string,
+    
+    /**
+    * Password is the password used to connect (optional)
+    */
+    
+    Password?: string,
+    
+    /**
+    * Memoize returns the memoized result of a previous sampling of the
+    * same target and options instead of sampling again
+    */
+    
+    Memoize?: boolean,
+}
+
+
+
+/**
+ * SampleTopicsResponse is the response from the SampleTopics function.
+ * this is returned by SampleTopics function.
+ * @example
+ * ```javascript
+ * const mqtt = require('nuclei/mqtt');
+ * const resp = mqtt.SampleTopics('acme.com', 1883);
+ * log(toJSON(resp));
+ * ```
+ */
+export interface SampleTopicsResponse {
+    
+    /**
+    * Connected is true if the broker accepted the connection
+    */
+    
+    Connected?: boolean,
+    
+    /**
+    * ReturnCode is the connack return code (ex: 5 for not authorized)
+    */
+    
+    ReturnCode?: number,
+    
+    /**
+    * Subscribed is true if the broker accepted the subscription
+    */
+    
+    Subscribed?: boolean,
+    
+    /**
+    * Topics are the topic names observed during the window
+    */
+    
+    Topics?: string[],
+    
+    /**
+    * Samples contains the truncated first payload of each topic (if MaxPayload is set)
+    */
+    
+    Samples?: Record<string, string>,
+    
+    /**
+    * Messages is the number of received messages
+    */
+    
+    Messages?: number,
+    
+    /**
+    * Truncated is true if collection stopped due to the topic or byte limits
+    */
+    
+    Truncated?: boolean,
+    
+    /**
+    * TimedOut is true if the scan deadline ended the sampling before the
+    * window, the collected topics are returned anyway
+    */
+    
+    TimedOut?: boolean,
+}
+
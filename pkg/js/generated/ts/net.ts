@@ -1,30 +1,153 @@
 
 
+
+export const ProbeFiltered = "FILTERED";
+
+
+export const ProbeOpenImmediateClose = "OPEN_IMMEDIATE_CLOSE";
+
+
+export const ProbeOpenImmediateRST = "OPEN_IMMEDIATE_RST";
+
+
+export const ProbeOpenResponsive = "OPEN_RESPONSIVE";
+
+
+export const ProbeOpenSilent = "OPEN_SILENT";
+
+
+export const ProbeRefused = "REFUSED";
+
+/**
+ * ConnInfo opens a tcp connection to the host and port and returns the
+ * maximum segment size negotiated with the target along with the addresses
+ * of the connection. The mss is read from the socket options and an error
+ * is returned on platforms not exposing it (ex: windows).
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const info = net.ConnInfo('acme.com', 443);
+ * if (info.MSS < 1460) { log('mss clamped to', info.MSS); }
+ * ```
+ */
+export function ConnInfo(host: string, port: number): ConnInfoResponse | null {
+    return null;
+}
+
+
+
+/**
+ * Expect connects to the given host and port and executes given steps in
+ * order over a single connection. Each step optionally sends data and then
+ * waits until the received data matches its Expect regex or its timeout expires.
+ * Execution stops at the first step whose expectation did not match.
+ * NoGreetingWait sends the first data right away to services which only
+ * respond to requests; skipped steps have an empty output and are not completed.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const resp = net.Expect('acme.com', 25, [{ Expect: '^220 ' }, { Send: 'EHLO nuclei\r\n', Expect: '(?m)^250 ' }, { Send: 'QUIT\r\n' }], { Timeout: 5 });
+ * log(resp.Matched, toJSON(resp.Outputs));
+ * ```
+ */
+export function Expect(host: string, port: number, steps: ExpectStep[], opts: ExpectOptions): ExpectResponse | null {
+    return null;
+}
+
+
+
+/**
+ * MeasureAmplification sends a single udp probe (hex encoded) to the host and
+ * port and measures the response to request byte ratio of the udp payloads
+ * (ex: ntp monlist, memcached stats, dns any queries, ssdp m-search).
+ * Datagrams are read until no more data is received for a second or the
+ * response exceeds 1MB. Hosts not answering within 5 seconds or rejecting the
+ * probe with an icmp port unreachable return a Factor of 0.
+ * Only a single small probe is sent and responses are only received by the
+ * scanner, but the probe still triggers the amplified response of the host
+ * so it should only be used against hosts in scope and not in tight loops.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * // ntp mode 7 monlist request
+ * const result = net.MeasureAmplification('acme.com', 123, '1700032a' + '00'.repeat(4));
+ * if (result.Factor > 10) { log('amplification factor', result.Factor); }
+ * ```
+ */
+export function MeasureAmplification(host: string, port: number, probe: string): AmplificationResponse | null {
+    return null;
+}
+
+
+
 /**
  * Open opens a new connection to the address with a timeout.
- * supported protocols: tcp, udp
+ * supported protocols: tcp, udp, unix
+ * unix sockets can also be opened using unix:///path/to/app.sock addresses
+ * and require local file access (-lfa).
+ * Interface pins the connection to a network interface (ex: eth1).
  * @example
  * ```javascript
  * const net = require('nuclei/net');
  * const conn = net.Open('tcp', 'acme.com:80');
+ * const local = net.Open('unix', 'unix:///var/run/app.sock');
  * ```
  */
-export function Open(protocol: string): NetConn | null {
+export function Open(protocol: string, opts: OpenOptions): NetConn | null {
     return null;
 }
 
 
 
 /**
- * Open opens a new connection to the address with a timeout.
- * supported protocols: tcp, udp
+ * OpenTLS opens a new tls connection to the address with a timeout.
+ * supported protocols: tcp (tls over udp is not supported)
+ * The certificate of the server is only verified if Verify is set.
  * @example
  * ```javascript
  * const net = require('nuclei/net');
  * const conn = net.OpenTLS('tcp', 'acme.com:443');
+ * const trusted = net.OpenTLS('tcp', 'acme.com:443', { Verify: true });
  * ```
  */
-export function OpenTLS(protocol: string): NetConn | null {
+export function OpenTLS(protocol: string, opts: OpenTLSOptions): NetConn | null {
+    return null;
+}
+
+
+
+/**
+ * Probe connects to the host and port and classifies the port based on the
+ * connect result and a short read after connect. Unlike ScanPorts it tells
+ * apart services which accept connections to reset or close them right away
+ * (ex: tcpwrappers, fail2ban) from silent and responsive services.
+ * The host is resolved beforehand so that Latency only covers the tcp connect,
+ * and results are not memoized so that every call measures it.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const result = net.Probe('acme.com', 22);
+ * if (result.State === 'OPEN_IMMEDIATE_RST') { log('connection dropped by host filtering'); }
+ * ```
+ */
+export function Probe(host: string, port: number, opts: ProbeOptions): ProbeResponse | null {
+    return null;
+}
+
+
+
+/**
+ * ScanPorts tries to establish a tcp connection to each given port of the host
+ * with bounded concurrency and returns whether the connection succeeded along with
+ * the connect latency. Results are returned in the same order as given ports.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const results = net.ScanPorts('acme.com', [22, 80, 443, 3389], { Timeout: 2, Threads: 10 });
+ * log(toJSON(results.filter(r => r.Open)));
+ * ```
+ */
+export function ScanPorts(host: string, ports: number[], opts: ScanPortsOptions): PortResult[] | null {
     return null;
 }
 
@@ -58,6 +181,22 @@ export class NetConn {
     }
     
 
+    /**
+    * NegotiatedProtocol returns the application protocol (alpn) selected by the
+    * server during the tls handshake. An empty string is returned for plain
+    * connections or if the server did not select any of the advertised protocols.
+    * @example
+    * ```javascript
+    * const net = require('nuclei/net');
+    * const conn = net.OpenTLS('tcp', 'acme.com:443', { ALPN: ['h2', 'http/1.1'] });
+    * log(conn.NegotiatedProtocol());
+    * ```
+    */
+    public NegotiatedProtocol(): string {
+        return "";
+    }
+    
+
     /**
     * SetTimeout sets read/write timeout for the connection (in seconds).
     * @example
@@ -181,6 +320,61 @@ export class NetConn {
     }
     
 
+    /**
+    * ReadUntil receives data until delimiter (ex: \r\n or \r\n.\r\n for multiline
+    * smtp and pop3 responses) and returns the received bytes including the delimiter.
+    * ErrDelimiterNotFound is returned if the delimiter is not received within
+    * maxBytes (default 64KB) and timeout seconds (default: timeout of the connection)
+    * or before the server closes the connection. Bytes received after the delimiter
+    * (or before a failure) are returned by the next reads.
+    * @example
+    * ```javascript
+    * const net = require('nuclei/net');
+    * const conn = net.Open('tcp', 'acme.com:110');
+    * const banner = conn.ReadUntil('\r\n', 1024, 5);
+    * conn.Send('CAPA\r\n');
+    * const capabilities = conn.ReadUntil('\r\n.\r\n', 4096, 5);
+    * ```
+    */
+    public ReadUntil(delimiter: string, maxBytes: number, timeout: number): Uint8Array | null {
+        return null;
+    }
+    
+
+    /**
+    * RecvFullDecoded is similar to RecvFullString but transcodes the received data
+    * from the given charset (ex: shift_jis, latin1, windows-1252) to utf-8.
+    * An empty charset keeps the data as is. Raw bytes are always returned.
+    * @example
+    * ```javascript
+    * const net = require('nuclei/net');
+    * const conn = net.Open('tcp', 'acme.com:23');
+    * const banner = conn.RecvFullDecoded(1024, 'latin1');
+    * log(banner.Data, banner.Raw.length);
+    * ```
+    */
+    public RecvFullDecoded(N: number, charset: string): DecodedData | null {
+        return null;
+    }
+    
+
+    /**
+    * RecvDecoded is similar to RecvString but transcodes the received data
+    * from the given charset (ex: shift_jis, latin1, windows-1252) to utf-8.
+    * An empty charset keeps the data as is. Raw bytes are always returned.
+    * @example
+    * ```javascript
+    * const net = require('nuclei/net');
+    * const conn = net.Open('tcp', 'acme.com:21');
+    * const banner = conn.RecvDecoded(1024, 'shift_jis');
+    * log(banner.Data);
+    * ```
+    */
+    public RecvDecoded(N: number, charset: string): DecodedData | null {
+        return null;
+    }
+    
+
     /**
     * RecvFullHex receives data from the connection with a timeout
     * in hex format.
@@ -217,3 +411,400 @@ export class NetConn {
 
 }
 
+
+
+/**
+ * AmplificationResponse is the amplification measured for a udp probe.
+ * this is returned by MeasureAmplification function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const result = net.MeasureAmplification('acme.com', 11211, '000000000001000073746174730d0a');
+ * log(result.Factor, result.ResponseSize);
+ * ```
+ */
+export interface AmplificationResponse {
+    
+    /**
+    * Factor is the ratio of response bytes to probe bytes (0 without response)
+    */
+    
+    Factor?: number,
+    
+    /**
+    * RequestSize is the size of the probe in bytes
+    */
+    
+    RequestSize?: number,
+    
+    /**
+    * ResponseSize is the total size of the response datagrams in bytes
+    */
+    
+    ResponseSize?: number,
+    
+    /**
+    * Datagrams is the number of response datagrams received
+    */
+    
+    Datagrams?: number,
+    
+    /**
+    * Truncated is true if the response exceeded the read limit (1MB)
+    */
+    
+    Truncated?: boolean,
+}
+
+
+
+/**
+ * ConnInfoResponse contains socket level information of a tcp connection.
+ * this is returned by ConnInfo function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const info = net.ConnInfo('acme.com', 443);
+ * log(info.MSS, info.RemoteAddress);
+ * ```
+ */
+export interface ConnInfoResponse {
+    
+    /**
+    * MSS is the maximum segment size negotiated with the target (ex: 1460)
+    */
+    
+    MSS?: number,
+    
+    /**
+    * LocalAddress is the local address (ip:port) of the connection
+    */
+    
+    LocalAddress?: string,
+    
+    /**
+    * RemoteAddress is the remote address (ip:port) of the connection
+    */
+    
+    RemoteAddress?: string,
+}
+
+
+
+/**
+ * DecodedData contains data received from the connection decoded to utf-8.
+ * this is returned by RecvDecoded and RecvFullDecoded functions.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const conn = net.Open('tcp', 'acme.com:21');
+ * const banner = conn.RecvDecoded(1024, 'shift_jis');
+ * log(banner.Data);
+ * ```
+ */
+export interface DecodedData {
+    
+    /**
+    * Data is the received data decoded to utf-8
+    */
+    
+    Data?: string,
+    
+    /**
+    * Raw contains the received bytes as is
+    */
+    
+    Raw?: Uint8Array,
+}
+
+
+
+/**
+ * ExpectOptions contains options for Expect function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const options = new net.ExpectOptions();
+ * options.TLS = true;
+ * options.Timeout = 10;
+ * ```
+ */
+export interface ExpectOptions {
+    
+    TLS?: boolean,
+    
+    Timeout?: number,
+    
+    ALPN?: string[],
+    
+    /**
+    * NoGreetingWait skips the leading steps without Send (server greeting) instead of
+    * waiting for them. a greeting sent anyway is received by the next step
+    */
+    
+    NoGreetingWait?: boolean,
+}
+
+
+
+/**
+ * ExpectResponse is the result of an Expect interaction.
+ * this is returned by Expect function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const resp = net.Expect('acme.com', 25, [{ Expect: '^220 ' }], {});
+ * log(resp.Matched, resp.Outputs);
+ * ```
+ */
+export interface ExpectResponse {
+    
+    /**
+    * Matched is true if the expectations of all steps matched
+    */
+    
+    Matched?: boolean,
+    
+    /**
+    * Completed is the number of steps whose expectation matched
+    */
+    
+    Completed?: number,
+    
+    /**
+    * Outputs contains the data received during each executed step
+    */
+    
+    Outputs?: string[],
+    
+    /**
+    * NegotiatedProtocol is the application protocol (alpn) selected by the server
+    * in the tls handshake (if any)
+    */
+    
+    NegotiatedProtocol?: string,
+}
+
+
+
+/**
+ * ExpectStep is a single step of an Expect interaction.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const step = { Send: 'EHLO nuclei\r\n', Expect: '^250[ -]', Timeout: 5 };
+ * ```
+ */
+export interface ExpectStep {
+    
+    /**
+    * Send is the data sent at the start of the step (optional)
+    */
+    
+    Send?: string,
+    
+    /**
+    * Expect is a regex matched against data received during the step (optional).
+    * data up to the end of the match is consumed, the rest is kept for next steps.
+    */
+    
+    Expect?: string,
+    
+    /**
+    * Timeout is the time in seconds to wait for Expect to match (default: ExpectOptions.Timeout)
+    */
+    
+    Timeout?: number,
+    
+    /**
+    * StartTLS upgrades the connection to tls before sending data of the step
+    */
+    
+    StartTLS?: boolean,
+}
+
+
+
+/**
+ * OpenOptions contains options for Open function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const conn = net.Open('tcp', 'acme.com:3389', { ProxyProtocol: 2 });
+ * const pinned = net.Open('tcp', 'acme.com:80', { Interface: 'eth1' });
+ * ```
+ */
+export interface OpenOptions {
+    
+    /**
+    * Interface binds the connection to the address of the given network
+    * interface (ex: eth1) on multi-homed scanners
+    */
+    
+    Interface?: string,
+    
+    /**
+    * ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+    * before any data (ex: backends behind load balancers)
+    */
+    
+    ProxyProtocol?: number,
+    
+    /**
+    * ProxySource is the client address (ip:port) announced in the PROXY header
+    * (default: local address of the connection)
+    */
+    
+    ProxySource?: string,
+    
+    /**
+    * ProxyDestination is the server address (ip:port) announced in the PROXY header
+    * (default: remote address of the connection)
+    */
+    
+    ProxyDestination?: string,
+}
+
+
+
+/**
+ * OpenTLSOptions contains options for OpenTLS function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const conn = net.OpenTLS('tcp', 'acme.com:443', { IP: '93.184.216.34' });
+ * ```
+ */
+export interface OpenTLSOptions {
+    
+    /**
+    * IP is dialed instead of resolving the host of the address which
+    * is still used as sni (ex: resolved by a previous dns step)
+    */
+    
+    IP?: string,
+    
+    /**
+    * ALPN are the application protocols advertised in the handshake (ex: h2, http/1.1).
+    * the protocol selected by the server is returned by NegotiatedProtocol
+    */
+    
+    ALPN?: string[],
+    
+    /**
+    * Verify verifies the certificate chain and hostname of the server against the
+    * system roots and fails with ErrCertUntrusted otherwise (disabled by default
+    * since scanned services often use self signed certificates)
+    */
+    
+    Verify?: boolean,
+    
+    /**
+    * ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+    * before any data (ex: backends behind load balancers)
+    */
+    
+    ProxyProtocol?: number,
+    
+    /**
+    * ProxySource is the client address (ip:port) announced in the PROXY header
+    * (default: local address of the connection)
+    */
+    
+    ProxySource?: string,
+    
+    /**
+    * ProxyDestination is the server address (ip:port) announced in the PROXY header
+    * (default: remote address of the connection)
+    */
+    
+    ProxyDestination?: string,
+}
+
+
+
+/**
+ * PortResult is the result of a single port probe.
+ * this is returned by ScanPorts function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const results = net.ScanPorts('acme.com', [22, 80, 443], {});
+ * for (const result of results) {
+ *   log(result.Port, result.Open, result.Latency);
+ * }
+ * ```
+ */
+export interface PortResult {
+    
+    Port?: number,
+    
+    Open?: boolean,
+    
+    Latency?: number,
+}
+
+
+
+/**
+ * ProbeOptions contains options for Probe function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const result = net.Probe('acme.com', 22, { Timeout: 3, ReadTimeout: 1 });
+ * ```
+ */
+export interface ProbeOptions {
+    
+    Timeout?: number,
+    
+    ReadTimeout?: number,
+}
+
+
+
+/**
+ * ProbeResponse is the classification of a tcp port.
+ * this is returned by Probe function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const result = net.Probe('acme.com', 22);
+ * log(result.State, result.Latency);
+ * ```
+ */
+export interface ProbeResponse {
+    
+    /**
+    * State is the state of the port (OPEN_RESPONSIVE, OPEN_SILENT, OPEN_IMMEDIATE_RST,
+    * OPEN_IMMEDIATE_CLOSE, REFUSED or FILTERED)
+    */
+    
+    State?: string,
+    
+    /**
+    * Latency is the tcp connect latency in milliseconds (open ports only)
+    */
+    
+    Latency?: number,
+}
+
+
+
+/**
+ * ScanPortsOptions contains options for ScanPorts function.
+ * @example
+ * ```javascript
+ * const net = require('nuclei/net');
+ * const options = new net.ScanPortsOptions();
+ * options.Timeout = 2;
+ * options.Threads = 50;
+ * ```
+ */
+export interface ScanPortsOptions {
+    
+    Timeout?: number,
+    
+    Threads?: number,
+}
+
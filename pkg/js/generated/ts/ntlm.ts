@@ -0,0 +1,88 @@
+
+
+/**
+ * GetInfo sends a NTLM NEGOTIATE message in the Authorization header of a HTTP
+ * request to given host, port and path and parses the NTLM CHALLENGE returned in
+ * WWW-Authenticate header. This leaks internal names and os version of windows
+ * endpoints like OWA, Exchange, RDWeb, WinRM etc. HTTPS is tried first and
+ * plaintext HTTP is used as fallback.
+ * @example
+ * ```javascript
+ * const ntlm = require('nuclei/ntlm');
+ * const info = ntlm.GetInfo('acme.com', 443, '/EWS/');
+ * log(info.DNSDomainName);
+ * ```
+ */
+export function GetInfo(host: string, port: number, path: string): GetInfoResponse | null {
+    return null;
+}
+
+
+
+/**
+ * GetInfoResponse is the response from the GetInfo function.
+ * this is returned by GetInfo function.
+ * @example
+ * ```javascript
+ * const ntlm = require('nuclei/ntlm');
+ * const info = ntlm.GetInfo('acme.com', 443, '/EWS/');
+ * log(toJSON(info));
+ * ```
+ */
+export interface GetInfoResponse {
+    
+    /**
+    * TargetName is the target name from challenge message
+    */
+    
+    TargetName?: string,
+    
+    /**
+    * NetBIOSComputerName is the netbios name of server
+    */
+    
+    NetBIOSComputerName?: string,
+    
+    /**
+    * NetBIOSDomainName is the netbios name of domain
+    */
+    
+    NetBIOSDomainName?: string,
+    
+    /**
+    * DNSComputerName is the fqdn of server
+    */
+    
+    DNSComputerName?: string,
+    
+    /**
+    * DNSDomainName is the fqdn of domain
+    */
+    
+    DNSDomainName?: string,
+    
+    /**
+    * ForestName is the fqdn of forest
+    */
+    
+    ForestName?: string,
+    
+    /**
+    * OSVersion is the version of server os (ex: 10.0.17763)
+    */
+    
+    OSVersion?: string,
+    
+    /**
+    * Timestamp is the server time in unix seconds (if present)
+    */
+    
+    Timestamp?: number,
+    
+    /**
+    * TLS is true if the endpoint was reached over TLS
+    */
+    
+    TLS?: boolean,
+}
+
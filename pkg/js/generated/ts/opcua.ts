@@ -0,0 +1,113 @@
+
+
+/**
+ * GetEndpoints performs the OPC UA Hello/Acknowledge handshake, opens an
+ * unsecured secure channel (SecurityPolicy#None) and calls the GetEndpoints
+ * discovery service returning advertised endpoints, security policies and
+ * message security modes.
+ * @example
+ * ```javascript
+ * const opcua = require('nuclei/opcua');
+ * const result = opcua.GetEndpoints('acme.com', 4840);
+ * log(result.AllowsNone);
+ * ```
+ */
+export function GetEndpoints(host: string, port: number): GetEndpointsResponse | null {
+    return null;
+}
+
+
+
+/**
+ * Endpoint is an endpoint description returned by GetEndpoints service.
+ * @example
+ * ```javascript
+ * const opcua = require('nuclei/opcua');
+ * const result = opcua.GetEndpoints('acme.com', 4840);
+ * log(result.Endpoints[0].SecurityPolicyURI);
+ * ```
+ */
+export interface Endpoint {
+    
+    /**
+    * EndpointURL is the url of the endpoint
+    */
+    
+    EndpointURL?: string,
+    
+    /**
+    * SecurityMode is the message security mode (None, Sign, SignAndEncrypt)
+    */
+    
+    SecurityMode?: string,
+    
+    /**
+    * SecurityPolicyURI is the uri of the security policy
+    */
+    
+    SecurityPolicyURI?: string,
+    
+    /**
+    * SecurityLevel is the relative security level assigned by server
+    */
+    
+    SecurityLevel?: number,
+    
+    /**
+    * UserTokenTypes contains accepted user token types (Anonymous, UserName, Certificate, IssuedToken)
+    */
+    
+    UserTokenTypes?: string[],
+    
+    /**
+    * ApplicationURI is the application uri of the server
+    */
+    
+    ApplicationURI?: string,
+    
+    /**
+    * ProductURI is the product uri of the server
+    */
+    
+    ProductURI?: string,
+    
+    /**
+    * ApplicationName is the application name of the server
+    */
+    
+    ApplicationName?: string,
+}
+
+
+
+/**
+ * GetEndpointsResponse is the response from the GetEndpoints function.
+ * this is returned by GetEndpoints function.
+ * @example
+ * ```javascript
+ * const opcua = require('nuclei/opcua');
+ * const result = opcua.GetEndpoints('acme.com', 4840);
+ * log(toJSON(result));
+ * ```
+ */
+export interface GetEndpointsResponse {
+    
+    /**
+    * Endpoints contains endpoints advertised by the server
+    */
+    
+    Endpoints?: Endpoint[],
+    
+    /**
+    * AllowsNone is true if any endpoint uses None security mode or policy
+    */
+    
+    AllowsNone?: boolean,
+    
+    /**
+    * AllowsAnonymous is true if any endpoint accepts anonymous user tokens
+    */
+    
+    AllowsAnonymous?: boolean,
+}
+
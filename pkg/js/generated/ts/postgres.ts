@@ -1,5 +1,25 @@
 
 
+/**
+ * DetectEngine distinguishes real PostgreSQL from wire compatible engines
+ * (CockroachDB, YugabyteDB, Greenplum) using the server_version and engine
+ * specific parameter status fields sent after the startup handshake.
+ * Parameters are only sent to unauthenticated clients by servers using trust
+ * authentication, otherwise engine specific error messages are used if any.
+ * The result is memoized on host and port.
+ * @example
+ * ```javascript
+ * const postgres = require('nuclei/postgres');
+ * const engine = postgres.DetectEngine('acme.com', 5432);
+ * if (engine.Engine == 'CockroachDB') { log(engine.Version); }
+ * ```
+ */
+export function DetectEngine(host: string, port: number): DetectEngineResponse | null {
+    return null;
+}
+
+
+
 /**
  * PGClient is a client for Postgres database.
  * Internally client uses go-pg/pg driver.
@@ -84,6 +104,47 @@ export class PGClient {
 
 
 
+/**
+ * DetectEngineResponse is the response from the DetectEngine function.
+ * this is returned by DetectEngine function.
+ * @example
+ * ```javascript
+ * const postgres = require('nuclei/postgres');
+ * const engine = postgres.DetectEngine('acme.com', 5432);
+ * log(toJSON(engine));
+ * ```
+ */
+export interface DetectEngineResponse {
+    
+    /**
+    * IsPostgres is true if the server speaks the postgres wire protocol
+    */
+    
+    IsPostgres?: boolean,
+    
+    /**
+    * Engine is the identified engine (PostgreSQL, CockroachDB, YugabyteDB or Greenplum)
+    * and is empty if the server did not reveal it (ex: authentication required)
+    */
+    
+    Engine?: string,
+    
+    /**
+    * Version is the version of the engine (ex: 23.1.11 for CockroachDB)
+    */
+    
+    Version?: string,
+    
+    /**
+    * AuthRequired is true if the server requested authentication before
+    * sending its parameters
+    */
+    
+    AuthRequired?: boolean,
+}
+
+
+
 /**
  * SQLResult Interface
  */
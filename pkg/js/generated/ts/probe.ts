@@ -0,0 +1,156 @@
+
+
+
+export const HoneypotAnswersGarbage = "answers_garbage";
+
+
+export const HoneypotIdenticalBanners = "identical_banners";
+
+
+export const HoneypotImplausibleLatency = "implausible_latency";
+
+
+export const HoneypotMultipleProtocols = "multiple_protocols";
+
+/**
+ * HoneypotScore runs probes giving contradictory or implausible results on
+ * real services against the host and port and returns a heuristic likelihood
+ * of the target being a honeypot along with the triggered indicators:
+ *   - multiple unrelated protocol probes succeed on the same port
+ *   - other ports of the host (Ports option) send the same banner
+ *   - the port answers faster than half of the tcp connect round trip
+ *   - the port answers random binary data (weak, also done by ex: http servers)
+ * The score is meant to de-prioritize deception infrastructure and is not
+ * a proof. Probes waiting for a banner take their full timeout against
+ * honeypots which never send one.
+ * @example
+ * ```javascript
+ * const probe = require('nuclei/probe');
+ * const result = probe.HoneypotScore('acme.com', 22, { Ports: [23, 2222] });
+ * if (result.Score >= 50) { log('likely honeypot', result.Indicators); }
+ * ```
+ */
+export function HoneypotScore(host: string, port: number, opts: HoneypotScoreOptions): HoneypotScoreResponse | null {
+    return null;
+}
+
+
+
+/**
+ * Identify runs the given protocol probes concurrently against the same host
+ * and port and returns the first positive identification. At most as many probes
+ * as the template concurrency (-c) run at once. Once a probe matches, probes that
+ * have not started yet are skipped and in-flight probes are cancelled (their
+ * connections are closed) and their results are discarded. If no probes are
+ * given all available probes are used.
+ * @example
+ * ```javascript
+ * const probe = require('nuclei/probe');
+ * const result = probe.Identify('acme.com', 8443, ['rdp', 'ssh', 'smb']);
+ * log(result.Matched, result.Protocol);
+ * ```
+ */
+export function Identify(host: string, port: number, probes: string[]): IdentifyResponse | null {
+    return null;
+}
+
+
+
+/**
+ * Probes returns the names of the available protocol probes.
+ * @example
+ * ```javascript
+ * const probe = require('nuclei/probe');
+ * log(probe.Probes());
+ * ```
+ */
+export function Probes(): string[] {
+    return [];
+}
+
+
+
+/**
+ * HoneypotScoreOptions contains options for HoneypotScore function.
+ * @example
+ * ```javascript
+ * const probe = require('nuclei/probe');
+ * const result = probe.HoneypotScore('acme.com', 22, { Probes: ['ssh', 'rdp', 'telnet'], Ports: [23, 2222] });
+ * ```
+ */
+export interface HoneypotScoreOptions {
+    
+    /**
+    * Probes are the protocol probes run against the port (default: all probes)
+    */
+    
+    Probes?: string[],
+    
+    /**
+    * Ports are other ports of the host whose banner is compared to the banner of the port
+    */
+    
+    Ports?: number[],
+}
+
+
+
+/**
+ * HoneypotScoreResponse is the response from the HoneypotScore function.
+ * this is returned by HoneypotScore function.
+ * @example
+ * ```javascript
+ * const probe = require('nuclei/probe');
+ * const result = probe.HoneypotScore('acme.com', 22);
+ * log(result.Score, result.Indicators);
+ * ```
+ */
+export interface HoneypotScoreResponse {
+    
+    /**
+    * Score is the heuristic likelihood (0-100) of the target being a honeypot
+    */
+    
+    Score?: number,
+    
+    /**
+    * Indicators are the triggered indicators (multiple_protocols, identical_banners,
+    * implausible_latency, answers_garbage)
+    */
+    
+    Indicators?: string[],
+    
+    /**
+    * Protocols are the protocols identified on the port
+    */
+    
+    Protocols?: string[],
+}
+
+
+
+/**
+ * IdentifyResponse is the response from the Identify function.
+ * this is returned by Identify function.
+ * @example
+ * ```javascript
+ * const probe = require('nuclei/probe');
+ * const result = probe.Identify('acme.com', 8443, ['rdp', 'ssh', 'smb']);
+ * log(toJSON(result));
+ * ```
+ */
+export interface IdentifyResponse {
+    
+    /**
+    * Matched is true if one of the probes identified the service
+    */
+    
+    Matched?: boolean,
+    
+    /**
+    * Protocol is the name of the matching probe
+    */
+    
+    Protocol?: string,
+}
+
@@ -0,0 +1,62 @@
+
+
+/**
+ * IsOpenHTTPProxy checks if the given host and port are running an open HTTP forward proxy
+ * by sending a CONNECT request to a benign endpoint. If the server does not speak
+ * plaintext HTTP, the request is retried over TLS. No data is sent through the tunnel.
+ * @example
+ * ```javascript
+ * const proxy = require('nuclei/proxy');
+ * const result = proxy.IsOpenHTTPProxy('acme.com', 3128);
+ * log(toJSON(result));
+ * ```
+ */
+export function IsOpenHTTPProxy(host: string, port: number): IsOpenHTTPProxyResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsOpenHTTPProxyResponse is the response from the IsOpenHTTPProxy function.
+ * this is returned by IsOpenHTTPProxy function.
+ * @example
+ * ```javascript
+ * const proxy = require('nuclei/proxy');
+ * const result = proxy.IsOpenHTTPProxy('acme.com', 3128);
+ * log(toJSON(result));
+ * ```
+ */
+export interface IsOpenHTTPProxyResponse {
+    
+    /**
+    * OpenProxy is true if the server established the CONNECT tunnel (200 response)
+    */
+    
+    OpenProxy?: boolean,
+    
+    /**
+    * AuthRequired is true if the server responded with 407 Proxy Authentication Required
+    */
+    
+    AuthRequired?: boolean,
+    
+    /**
+    * StatusCode is the status code of the CONNECT response
+    */
+    
+    StatusCode?: number,
+    
+    /**
+    * Server is the value of Server header (if any)
+    */
+    
+    Server?: string,
+    
+    /**
+    * TLS is true if the proxy is fronted by TLS
+    */
+    
+    TLS?: boolean,
+}
+
@@ -0,0 +1,81 @@
+
+
+/**
+ * IsQUIC sends a quic initial packet to the given host and port (udp, usually 443)
+ * and performs the quic and tls handshakes offering the h3, h3-29, doq and
+ * hq-interop application protocols. HTTP3 is set if the server selects h3.
+ * Servers rejecting the handshake (ex: unsupported application protocol) are
+ * still reported as quic with the reason in Error, while version negotiation
+ * packets are returned in SupportedVersions. Hosts not answering within 5
+ * seconds are not quic and are not reported as an error.
+ * The certificate of the server is not verified.
+ * @example
+ * ```javascript
+ * const quic = require('nuclei/quic');
+ * const isQUIC = quic.IsQUIC('acme.com', 443);
+ * if (isQUIC.HTTP3) { log('http/3 exposed', isQUIC.Version); }
+ * ```
+ */
+export function IsQUIC(host: string, port: number): IsQUICResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsQUICResponse is the response from the IsQUIC function.
+ * this is returned by IsQUIC function.
+ * @example
+ * ```javascript
+ * const quic = require('nuclei/quic');
+ * const isQUIC = quic.IsQUIC('acme.com', 443);
+ * log(toJSON(isQUIC));
+ * ```
+ */
+export interface IsQUICResponse {
+    
+    /**
+    * IsQUIC is true if the endpoint answered the quic initial packet
+    * (completed handshake, connection close or version negotiation)
+    */
+    
+    IsQUIC?: boolean,
+    
+    /**
+    * HandshakeComplete is true if the quic and tls handshakes completed
+    */
+    
+    HandshakeComplete?: boolean,
+    
+    /**
+    * Version is the negotiated quic version (ex: v1, v2)
+    */
+    
+    Version?: string,
+    
+    /**
+    * ALPN is the application protocol selected by the server (ex: h3)
+    */
+    
+    ALPN?: string,
+    
+    /**
+    * HTTP3 is true if the server selected http/3 (h3)
+    */
+    
+    HTTP3?: boolean,
+    
+    /**
+    * SupportedVersions are the versions offered by the server in a version
+    * negotiation packet if none of the client versions is supported
+    */
+    
+    SupportedVersions?: string[],
+    
+    /**
+    * Error is the reason the handshake did not complete (if IsQUIC is true)
+    */
+    
+    Error?: string,
+}
+
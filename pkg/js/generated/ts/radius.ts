@@ -0,0 +1,63 @@
+
+
+/**
+ * CheckAuth sends a RADIUS Access-Request with PAP authentication using given
+ * shared secret, username and password and reports whether the server accepted
+ * or rejected the credentials. Since servers silently drop requests with a wrong
+ * shared secret, a missing or garbled response is returned as an error.
+ * @example
+ * ```javascript
+ * const radius = require('nuclei/radius');
+ * const result = radius.CheckAuth('acme.com', 1812, 'testing123', 'admin', 'admin');
+ * log(result.Accepted);
+ * ```
+ */
+export function CheckAuth(host: string, port: number, secret: string, username: string, password: string): CheckAuthResponse | null {
+    return null;
+}
+
+
+
+/**
+ * CheckAuthResponse is the response from the CheckAuth function.
+ * this is returned by CheckAuth function.
+ * @example
+ * ```javascript
+ * const radius = require('nuclei/radius');
+ * const result = radius.CheckAuth('acme.com', 1812, 'testing123', 'admin', 'admin');
+ * log(toJSON(result));
+ * ```
+ */
+export interface CheckAuthResponse {
+    
+    /**
+    * Accepted is true if server responded with Access-Accept
+    */
+    
+    Accepted?: boolean,
+    
+    /**
+    * Rejected is true if server responded with Access-Reject
+    */
+    
+    Rejected?: boolean,
+    
+    /**
+    * Challenge is true if server responded with Access-Challenge
+    */
+    
+    Challenge?: boolean,
+    
+    /**
+    * Code is the radius code of the response
+    */
+    
+    Code?: number,
+    
+    /**
+    * ReplyMessage is the Reply-Message attribute of the response (if any)
+    */
+    
+    ReplyMessage?: string,
+}
+
@@ -4,6 +4,7 @@
  * CheckRDPAuth checks if the given host and port are running rdp server
  * with authentication and returns their metadata.
  * If connection is successful, it returns true.
+ * Truncated or invalid ntlm challenges return ErrMalformedResponse.
  * @example
  * ```javascript
  * const rdp = require('nuclei/rdp');
@@ -11,7 +12,26 @@
  * log(toJSON(checkRDPAuth));
  * ```
  */
-export function CheckRDPAuth(host: string, port: number): CheckRDPAuthResponse | null {
+export function CheckRDPAuth(host: string, port: number, opts: CheckRDPAuthOptions): CheckRDPAuthResponse | null {
+    return null;
+}
+
+
+
+/**
+ * GetRDWebVersion fetches the rd web access login page at path
+ * (default: /RDWeb/Pages/en-US/login.aspx) and extracts the rds version
+ * from the versioned static assets it references.
+ * HTTPS is tried first and plaintext HTTP is used as fallback.
+ * Pages that are not rd web access portals return IsRDWeb false.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const rdweb = rdp.GetRDWebVersion('acme.com', 443, '');
+ * log(rdweb.IsRDWeb, rdweb.Version, rdweb.Product);
+ * ```
+ */
+export function GetRDWebVersion(host: string, port: number, path: string): RDWebVersionResponse | null {
     return null;
 }
 
@@ -22,19 +42,135 @@ export function CheckRDPAuth(host: string, port: number): CheckRDPAuthResponse |
  * If connection is successful, it returns true.
  * If connection is unsuccessful, it returns false and error.
  * The Name of the OS is also returned if the connection is successful.
+ * PortOpen is true whenever the tcp connection succeeded, even if the
+ * service could not be identified as rdp.
+ * Detection failures after the connection succeeded (ex: timeouts, truncated
+ * or invalid negotiation responses) return IsRDP false without an error.
+ * Confidence can be used to accept partial matches (ex: non windows servers).
+ * CaptureRaw can be used to inspect the handshake of unexpected responders.
+ * NegotiateTLS returns the tls version and cipher suite chosen by the server.
  * @example
  * ```javascript
  * const rdp = require('nuclei/rdp');
  * const isRDP = rdp.IsRDP('acme.com', 3389);
- * log(toJSON(isRDP));
+ * if (isRDP.Confidence >= 50) { log(toJSON(isRDP)); }
+ * ```
+ */
+export function IsRDP(host: string, port: number, opts: IsRDPOptions): IsRDPResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsRDPAllAddrs resolves every A and AAAA record of host and checks if each
+ * address is running rdp server, returning the result of every address in
+ * the order of the records (ex: all backends of a round-robin or cdn
+ * hostname). A failing address does not stop the probing of the others,
+ * only resolution errors are returned.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const results = rdp.IsRDPAllAddrs('acme.com', 3389);
+ * const confirmed = results.every((result) => result.Response.IsRDP);
  * ```
  */
-export function IsRDP(host: string, port: number): IsRDPResponse | null {
+export function IsRDPAllAddrs(host: string, port: number, opts: IsRDPAllAddrsOptions): IsRDPAddrResult[] | null {
     return null;
 }
 
 
 
+/**
+ * IsRDPStream checks if the given hosts are running rdp server and invokes
+ * callback with the result of each host as soon as its probe completes
+ * instead of buffering all the results. Hosts can be given as host:port to
+ * override port. Concurrency bounds the number of running probes and the
+ * callback is never invoked concurrently. An exception thrown by the
+ * callback stops the probing of remaining hosts.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * rdp.IsRDPStream(['acme.com', 'acme.org:3390'], 3389, (result) => {
+ *   if (result.Response.IsRDP) { log(result.Host, result.Response.OS); }
+ * }, { Concurrency: 20 });
+ * ```
+ */
+export function IsRDPStream(hosts: string[], port: number, callback: (arg0: IsRDPStreamResult) => void, opts: IsRDPStreamOptions): void {
+    return;
+}
+
+
+
+/**
+ * CheckRDPAuthOptions contains options for CheckRDPAuth function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const checkRDPAuth = rdp.CheckRDPAuth('acme.com', 3389, { KeepAlive: 10 });
+ * ```
+ */
+export interface CheckRDPAuthOptions {
+    
+    /**
+    * KeepAlive enables tcp keep-alive probes with the given interval in seconds.
+    * a negative value disables keep-alive
+    */
+    
+    KeepAlive?: number,
+    
+    /**
+    * NoDelay sets TCP_NODELAY on the connection (enabled by default)
+    */
+    
+    NoDelay?: boolean,
+    
+    /**
+    * IP is dialed instead of resolving host (ex: resolved by a previous dns step)
+    */
+    
+    IP?: string,
+    
+    /**
+    * Interface binds the connection to the address of the given network
+    * interface (ex: eth1) on multi-homed scanners
+    */
+    
+    Interface?: string,
+    
+    /**
+    * ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+    * before the rdp data (ex: backends behind load balancers)
+    */
+    
+    ProxyProtocol?: number,
+    
+    /**
+    * ProxySource is the client address (ip:port) announced in the PROXY header
+    * (default: local address of the connection)
+    */
+    
+    ProxySource?: string,
+    
+    /**
+    * ProxyDestination is the server address (ip:port) announced in the PROXY header
+    * (default: remote address of the connection)
+    */
+    
+    ProxyDestination?: string,
+    
+    /**
+    * NegotiateTLS sends a x.224 connection request and upgrades the connection
+    * to tls before credssp, as done by rdp clients, and returns the negotiated
+    * TLSVersion and CipherSuite. servers selecting legacy rdp security return
+    * no auth info (disabled by default).
+    */
+    
+    NegotiateTLS?: boolean,
+}
+
+
+
 /**
  * CheckRDPAuthResponse is the response from the CheckRDPAuth function.
  * this is returned by CheckRDPAuth function.
@@ -47,9 +183,167 @@ export function IsRDP(host: string, port: number): IsRDPResponse | null {
  */
 export interface CheckRDPAuthResponse {
     
-    PluginInfo?: ServiceRDP,
+    PluginInfo?: ServerInfo,
     
     Auth?: boolean,
+    
+    /**
+    * ResolvedIP is the ip address the host resolved to when probed
+    */
+    
+    ResolvedIP?: string,
+    
+    /**
+    * TLSVersion is the tls version negotiated before credssp (if NegotiateTLS is set)
+    */
+    
+    TLSVersion?: string,
+    
+    /**
+    * CipherSuite is the tls cipher suite chosen by the server (if NegotiateTLS is set)
+    */
+    
+    CipherSuite?: string,
+}
+
+
+
+/**
+ * IsRDPAddrResult is the result of an address probed by IsRDPAllAddrs.
+ * this is returned by IsRDPAllAddrs function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const results = rdp.IsRDPAllAddrs('acme.com', 3389);
+ * log(toJSON(results));
+ * ```
+ */
+export interface IsRDPAddrResult {
+    
+    /**
+    * IP is the probed address of the host
+    */
+    
+    IP?: string,
+    
+    /**
+    * Response is the result of IsRDP for the address
+    */
+    
+    Response?: IsRDPResponse,
+    
+    /**
+    * Error is the error of the probe (if any)
+    */
+    
+    Error?: string,
+}
+
+
+
+/**
+ * IsRDPAllAddrsOptions contains options for IsRDPAllAddrs function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const results = rdp.IsRDPAllAddrs('acme.com', 3389, { Concurrency: 5 });
+ * ```
+ */
+export interface IsRDPAllAddrsOptions {
+    
+    /**
+    * Concurrency is the number of addresses probed concurrently (default: 10, max: 100)
+    */
+    
+    Concurrency?: number,
+    
+    /**
+    * Options are the options used to probe every address (IP is ignored)
+    */
+    
+    Options?: IsRDPOptions,
+}
+
+
+
+/**
+ * IsRDPOptions contains options for IsRDP function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const isRDP = rdp.IsRDP('acme.com', 3389, { NoCache: true });
+ * ```
+ */
+export interface IsRDPOptions {
+    
+    /**
+    * NoCache performs a fresh probe instead of returning the memoized
+    * result of the execution. the memoized result is left intact.
+    */
+    
+    NoCache?: boolean,
+    
+    /**
+    * KeepAlive enables tcp keep-alive probes with the given interval in seconds.
+    * a negative value disables keep-alive
+    */
+    
+    KeepAlive?: number,
+    
+    /**
+    * NoDelay sets TCP_NODELAY on the connection (enabled by default)
+    */
+    
+    NoDelay?: boolean,
+    
+    /**
+    * IP is dialed instead of resolving host (ex: resolved by a previous dns step)
+    */
+    
+    IP?: string,
+    
+    /**
+    * Interface binds the connection to the address of the given network
+    * interface (ex: eth1) on multi-homed scanners
+    */
+    
+    Interface?: string,
+    
+    /**
+    * ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+    * before the rdp data (ex: backends behind load balancers)
+    */
+    
+    ProxyProtocol?: number,
+    
+    /**
+    * ProxySource is the client address (ip:port) announced in the PROXY header
+    * (default: local address of the connection)
+    */
+    
+    ProxySource?: string,
+    
+    /**
+    * ProxyDestination is the server address (ip:port) announced in the PROXY header
+    * (default: remote address of the connection)
+    */
+    
+    ProxyDestination?: string,
+    
+    /**
+    * CaptureRaw returns the raw bytes exchanged during detection in RawExchange
+    * for debugging (disabled by default)
+    */
+    
+    CaptureRaw?: boolean,
+    
+    /**
+    * NegotiateTLS upgrades the connection to tls when selected by the server and
+    * returns the negotiated TLSVersion and CipherSuite (disabled by default).
+    * legacy rdp security leaves them empty.
+    */
+    
+    NegotiateTLS?: boolean,
 }
 
 
@@ -69,29 +363,259 @@ export interface IsRDPResponse {
     IsRDP?: boolean,
     
     OS?: string,
+    
+    /**
+    * ResolvedIP is the ip address the host resolved to when probed
+    */
+    
+    ResolvedIP?: string,
+    
+    /**
+    * PortOpen is true if the tcp connection succeeded (even if the service is not rdp)
+    */
+    
+    PortOpen?: boolean,
+    
+    /**
+    * Confidence (0-100) reflects how strongly the handshake matched rdp:
+    *  - 100: rdp signature matched and the os was identified
+    *  - 80: rdp signature matched with unknown negotiation flags
+    *  - 50: connection confirm with rdp negotiation data but no rdp signature (IsRDP is false)
+    *  - 25: bare x.224 connection confirm, also used by other iso-tsap services (IsRDP is false)
+    *  - 0: not rdp or malformed response
+    */
+    
+    Confidence?: number,
+    
+    /**
+    * RawExchange contains the bytes exchanged during detection (if CaptureRaw is set)
+    */
+    
+    RawExchange?: RawExchange,
+    
+    /**
+    * TLSVersion is the tls version negotiated after the connection confirm (if NegotiateTLS
+    * is set and the server selected tls security, ex: TLS 1.2)
+    */
+    
+    TLSVersion?: string,
+    
+    /**
+    * CipherSuite is the tls cipher suite chosen by the server (if NegotiateTLS is set and
+    * the server selected tls security, ex: TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384)
+    */
+    
+    CipherSuite?: string,
+}
+
+
+
+/**
+ * IsRDPStreamOptions contains options for IsRDPStream function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * rdp.IsRDPStream(['acme.com', 'acme.org'], 3389, (result) => log(result.Host), { Concurrency: 5 });
+ * ```
+ */
+export interface IsRDPStreamOptions {
+    
+    /**
+    * Concurrency is the number of hosts probed concurrently (default: 10, max: 100)
+    */
+    
+    Concurrency?: number,
+    
+    /**
+    * Options are the options used to probe every host
+    */
+    
+    Options?: IsRDPOptions,
 }
 
 
 
 /**
- * ServiceRDP Interface
+ * IsRDPStreamResult is the result of a host probed by IsRDPStream.
+ * this is passed to the callback of IsRDPStream function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * rdp.IsRDPStream(['acme.com'], 3389, (result) => log(toJSON(result)));
+ * ```
  */
-export interface ServiceRDP {
+export interface IsRDPStreamResult {
     
-    ForestName?: string,
+    /**
+    * Host is the probed host
+    */
+    
+    Host?: string,
+    
+    /**
+    * Port is the probed port
+    */
+    
+    Port?: number,
+    
+    /**
+    * Response is the result of IsRDP for the host
+    */
+    
+    Response?: IsRDPResponse,
+    
+    /**
+    * Error is the error of the probe (if any)
+    */
+    
+    Error?: string,
+}
+
+
+
+/**
+ * RDWebVersionResponse is the response from the GetRDWebVersion function.
+ * this is returned by GetRDWebVersion function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const rdweb = rdp.GetRDWebVersion('acme.com', 443, '/RDWeb/Pages/en-US/login.aspx');
+ * log(toJSON(rdweb));
+ * ```
+ */
+export interface RDWebVersionResponse {
+    
+    /**
+    * IsRDWeb is true if the page is a rd web access portal
+    */
+    
+    IsRDWeb?: boolean,
+    
+    /**
+    * Version is the rds version found in the static assets (ex: 10.0.17763.1)
+    */
+    
+    Version?: string,
+    
+    /**
+    * Product is the windows server release of the version (if known)
+    */
+    
+    Product?: string,
+    
+    /**
+    * StatusCode is the http status code of the response
+    */
+    
+    StatusCode?: number,
+    
+    /**
+    * Server is the value of Server header (if any)
+    */
+    
+    Server?: string,
+    
+    /**
+    * TLS is true if the portal was reached over https
+    */
+    
+    TLS?: boolean,
+}
+
+
+
+/**
+ * RawExchange contains the raw bytes sent and received during a detection.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const isRDP = rdp.IsRDP('acme.com', 3389, { CaptureRaw: true });
+ * log(isRDP.RawExchange.Received);
+ * ```
+ */
+export interface RawExchange {
+    
+    /**
+    * Sent is the hex encoded data written to the server
+    */
+    
+    Sent?: string,
+    
+    /**
+    * Received is the hex encoded data read from the server
+    */
+    
+    Received?: string,
+    
+    /**
+    * Truncated is true if the exchange exceeded the capture limit (64KB per direction)
+    */
+    
+    Truncated?: boolean,
+}
+
+
+
+/**
+ * ServerInfo contains the metadata of a rdp server extracted from
+ * the ntlm challenge. field names are part of the template contract and
+ * do not depend on the underlying fingerprinting library.
+ * this is returned by CheckRDPAuth function.
+ * @example
+ * ```javascript
+ * const rdp = require('nuclei/rdp');
+ * const checkRDPAuth = rdp.CheckRDPAuth('acme.com', 3389);
+ * log(checkRDPAuth.PluginInfo.DNSDomainName);
+ * ```
+ */
+export interface ServerInfo {
+    
+    /**
+    * OSFingerprint is the os guessed from the os version (ex: Windows Server 2016 or 2019)
+    */
     
     OSFingerprint?: string,
     
+    /**
+    * OSVersion is the os version with build number (ex: 10.0.17763)
+    */
+    
     OSVersion?: string,
     
+    /**
+    * TargetName is the authentication realm (usually the domain name)
+    */
+    
     TargetName?: string,
     
+    /**
+    * NetBIOSComputerName is the netbios name of the server
+    */
+    
     NetBIOSComputerName?: string,
     
+    /**
+    * NetBIOSDomainName is the netbios name of the domain
+    */
+    
     NetBIOSDomainName?: string,
     
+    /**
+    * DNSComputerName is the fqdn of the server
+    */
+    
     DNSComputerName?: string,
     
+    /**
+    * DNSDomainName is the fqdn of the domain
+    */
+    
     DNSDomainName?: string,
+    
+    /**
+    * ForestName is the fqdn of the forest
+    */
+    
+    ForestName?: string,
 }
 
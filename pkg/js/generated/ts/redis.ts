@@ -42,6 +42,23 @@ export function GetServerInfoAuth(host: string, port: number, password: string):
 
 
 
+/**
+ * GetTopology returns the replication, cluster and sentinel topology of a redis node
+ * using INFO and CLUSTER INFO commands. This helps to identify nodes belonging to the
+ * same logical datastore. Empty password can be used for unauthenticated servers.
+ * @example
+ * ```javascript
+ * const redis = require('nuclei/redis');
+ * const topology = redis.GetTopology('acme.com', 6379, 'password');
+ * log(topology.Role, topology.ClusterEnabled);
+ * ```
+ */
+export function GetTopology(host: string, port: number, password: string): TopologyResponse | null {
+    return null;
+}
+
+
+
 /**
  * IsAuthenticated checks if the redis server requires authentication
  * @example
@@ -68,3 +85,91 @@ export function RunLuaScript(host: string, port: number, password: string, scrip
     return null;
 }
 
+
+
+/**
+ * TopologyResponse is the response from the GetTopology function.
+ * this is returned by GetTopology function.
+ * @example
+ * ```javascript
+ * const redis = require('nuclei/redis');
+ * const topology = redis.GetTopology('acme.com', 6379, 'password');
+ * log(toJSON(topology));
+ * ```
+ */
+export interface TopologyResponse {
+    
+    /**
+    * Mode is the redis mode (standalone, cluster or sentinel)
+    */
+    
+    Mode?: string,
+    
+    /**
+    * Role is the replication role of the node (master or slave)
+    */
+    
+    Role?: string,
+    
+    /**
+    * ReplicationID is the replication id shared by master and its replicas
+    * it can be used to identify nodes of the same logical datastore
+    */
+    
+    ReplicationID?: string,
+    
+    /**
+    * MasterHost is the host of the master (replicas only)
+    */
+    
+    MasterHost?: string,
+    
+    /**
+    * MasterPort is the port of the master (replicas only)
+    */
+    
+    MasterPort?: number,
+    
+    /**
+    * MasterLinkStatus is the status of link to the master (replicas only)
+    */
+    
+    MasterLinkStatus?: string,
+    
+    /**
+    * Replicas contains addresses (host:port) of connected replicas (masters only)
+    */
+    
+    Replicas?: string[],
+    
+    /**
+    * ClusterEnabled is true if cluster mode is enabled
+    */
+    
+    ClusterEnabled?: boolean,
+    
+    /**
+    * ClusterState is the state of the cluster (ok or fail)
+    */
+    
+    ClusterState?: string,
+    
+    /**
+    * ClusterKnownNodes is the number of nodes known to the cluster
+    */
+    
+    ClusterKnownNodes?: number,
+    
+    /**
+    * ClusterSize is the number of master nodes serving slots
+    */
+    
+    ClusterSize?: number,
+    
+    /**
+    * SentinelMasters contains addresses (host:port) of masters monitored by a sentinel
+    */
+    
+    SentinelMasters?: string[],
+}
+
@@ -0,0 +1,53 @@
+
+
+/**
+ * IsRMI checks if the given host and port are running a java rmi endpoint
+ * by performing the JRMP stream protocol handshake.
+ * @example
+ * ```javascript
+ * const rmi = require('nuclei/rmi');
+ * const isRMI = rmi.IsRMI('acme.com', 1099);
+ * log(toJSON(isRMI));
+ * ```
+ */
+export function IsRMI(host: string, port: number): IsRMIResponse | null {
+    return null;
+}
+
+
+
+/**
+ * ListBoundNames queries the rmi registry running on given host and port
+ * and returns the names of bound remote objects (Registry.list()).
+ * @example
+ * ```javascript
+ * const rmi = require('nuclei/rmi');
+ * const names = rmi.ListBoundNames('acme.com', 1099);
+ * log(names);
+ * ```
+ */
+export function ListBoundNames(host: string, port: number): string[] | null {
+    return null;
+}
+
+
+
+/**
+ * IsRMIResponse is the response from the IsRMI function.
+ * this is returned by IsRMI function.
+ * @example
+ * ```javascript
+ * const rmi = require('nuclei/rmi');
+ * const isRMI = rmi.IsRMI('acme.com', 1099);
+ * log(toJSON(isRMI));
+ * ```
+ */
+export interface IsRMIResponse {
+    
+    /**
+    * IsRMI is true if the endpoint acknowledged the jrmp handshake
+    */
+    
+    IsRMI?: boolean,
+}
+
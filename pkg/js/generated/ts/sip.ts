@@ -0,0 +1,86 @@
+
+
+/**
+ * CheckRegister sends a REGISTER request for user@domain and answers the
+ * digest challenge (401 or 407) of the registrar with given password.
+ * The registration is requested with a zero expiry so no binding is kept.
+ * 200 means valid credentials, a second challenge means invalid credentials
+ * while 403 and 404 are returned for forbidden and unknown extensions.
+ * @example
+ * ```javascript
+ * const sip = require('nuclei/sip');
+ * const resp = sip.CheckRegister('acme.com', 5060, 'acme.com', '1000', 'secret');
+ * log(resp.Valid, resp.Status, resp.Realm);
+ * ```
+ */
+export function CheckRegister(host: string, port: number, domain: string, user: string, password: string, opts: CheckRegisterOptions): CheckRegisterResponse | null {
+    return null;
+}
+
+
+
+/**
+ * CheckRegisterOptions contains options for CheckRegister function.
+ * @example
+ * ```javascript
+ * const sip = require('nuclei/sip');
+ * const resp = sip.CheckRegister('acme.com', 5060, 'acme.com', '1000', 'secret', { Transport: 'tcp' });
+ * ```
+ */
+export interface CheckRegisterOptions {
+    
+    /**
+    * Transport is udp (default) or tcp
+    */
+    
+    Transport?: string,
+}
+
+
+
+/**
+ * CheckRegisterResponse is the response from the CheckRegister function.
+ * this is returned by CheckRegister function.
+ * @example
+ * ```javascript
+ * const sip = require('nuclei/sip');
+ * const resp = sip.CheckRegister('acme.com', 5060, 'acme.com', '1000', 'secret');
+ * log(toJSON(resp));
+ * ```
+ */
+export interface CheckRegisterResponse {
+    
+    /**
+    * Valid is true if the registration was accepted with the credentials
+    */
+    
+    Valid?: boolean,
+    
+    /**
+    * Status classifies the final response, one of:
+    * valid, no_auth_required, invalid_credentials, forbidden, not_found, unexpected
+    */
+    
+    Status?: string,
+    
+    /**
+    * StatusCode and Reason are the status line of the final response
+    */
+    
+    StatusCode?: number,
+    
+    Reason?: string,
+    
+    /**
+    * Realm is the realm of the digest challenge
+    */
+    
+    Realm?: string,
+    
+    /**
+    * Server is the Server (or User-Agent) header of the response
+    */
+    
+    Server?: string,
+}
+
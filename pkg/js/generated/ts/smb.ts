@@ -71,6 +71,102 @@ export class SMBClient {
     }
     
 
+    /**
+    * SupportsCompression negotiates smb 3.1.1 with a preauth integrity and a
+    * compression negotiate context offering all compression algorithms and
+    * returns whether the server advertised compression along with the accepted
+    * algorithms. Compression support is a precondition of SMBGhost (CVE-2020-0796)
+    * and does not imply the server is vulnerable.
+    * @example
+    * ```javascript
+    * const smb = require('nuclei/smb');
+    * const client = new smb.SMBClient();
+    * const compression = client.SupportsCompression('acme.com', 445);
+    * if (compression.CompressionSupported) { log(compression.Algorithms); }
+    * ```
+    */
+    public SupportsCompression(host: string, port: number): CompressionSupport | null {
+        return null;
+    }
+    
+
+    /**
+    * ReadFile tries to connect to provided host and port, mounts the given share
+    * and reads the file at given path (relative to share root). Files larger than
+    * MaxSize option (default 10MB) are not read and an error is returned.
+    * null sessions can be tried by using empty username and password
+    * and guest sessions by using 'guest' username with empty password.
+    * File contents are not memoized, so they are never written to the
+    * -js-cache-dir cache.
+    * @example
+    * ```javascript
+    * const smb = require('nuclei/smb');
+    * const client = new smb.SMBClient();
+    * const file = client.ReadFile('acme.com', 445, 'Users', 'Public/config.ini', 'username', 'password', {});
+    * log(ToString(file.Data));
+    * ```
+    */
+    public ReadFile(host: string, port: number, share: string, path: string, user: string, password: string, opts: ReadFileOptions): ReadFileResponse | null {
+        return null;
+    }
+    
+
+    /**
+    * GetSecurityPolicy returns the signing, guest, null session and smb1
+    * policy of the smb server in a single set of handshakes.
+    * Signing flags are taken from the smb2 negotiate response (or the smb1
+    * one for smb1 only servers) and sessions are only attempted over smb2.
+    * @example
+    * ```javascript
+    * const smb = require('nuclei/smb');
+    * const client = new smb.SMBClient();
+    * const policy = client.GetSecurityPolicy('acme.com', 445);
+    * if (!policy.SigningRequired) { log('signing not required'); }
+    * ```
+    */
+    public GetSecurityPolicy(host: string, port: number): SecurityPolicy | null {
+        return null;
+    }
+    
+
+    /**
+    * ListSharesInfo tries to connect to provided host and port and enumerates
+    * shares using the SRVSVC named pipe (NetShareEnumAll) returning share names,
+    * types and remarks. null sessions can be tried by using empty username and password
+    * and guest sessions by using 'guest' username with empty password.
+    * @example
+    * ```javascript
+    * const smb = require('nuclei/smb');
+    * const client = new smb.SMBClient();
+    * const shares = client.ListSharesInfo('acme.com', 445, 'username', 'password');
+    * log(toJSON(shares));
+    * ```
+    */
+    public ListSharesInfo(host: string, port: number, user: string, password: string): ShareInfo[] | null {
+        return null;
+    }
+    
+
+    /**
+    * ListSharesInfoWithOptions is ListSharesInfo returning the shares in a response.
+    * With PartialOnTimeout, an enumeration exceeding its timeout (10 seconds after
+    * the session is established) returns the shares whose entries were received
+    * before the deadline with TimedOut set instead of discarding them. Such
+    * enumerations are not memoized since their result depends on timing.
+    * @example
+    * ```javascript
+    * const smb = require('nuclei/smb');
+    * const client = new smb.SMBClient();
+    * const resp = client.ListSharesInfoWithOptions('acme.com', 445, 'username', 'password', { PartialOnTimeout: true });
+    * if (resp.TimedOut) { log('partial share list'); }
+    * log(toJSON(resp.Shares));
+    * ```
+    */
+    public ListSharesInfoWithOptions(host: string, port: number, user: string, password: string, opts: ListSharesInfoOptions): ListSharesInfoResponse | null {
+        return null;
+    }
+    
+
     /**
     * DetectSMBGhost tries to detect SMBGhost vulnerability
     * by using SMBv3 compression feature.
@@ -90,20 +186,104 @@ export class SMBClient {
 
 
 
+/**
+ * CompressionSupport is the smb 3.1.1 compression support of a smb server.
+ * this is returned by SupportsCompression function.
+ * @example
+ * ```javascript
+ * const smb = require('nuclei/smb');
+ * const client = new smb.SMBClient();
+ * const compression = client.SupportsCompression('acme.com', 445);
+ * log(toJSON(compression));
+ * ```
+ */
+export interface CompressionSupport {
+    
+    /**
+    * CompressionSupported is true if the server answered with a compression negotiate context
+    */
+    
+    CompressionSupported?: boolean,
+    
+    /**
+    * Algorithms are the compression algorithms advertised by the server (ex: LZNT1)
+    */
+    
+    Algorithms?: string[],
+    
+    /**
+    * Dialect is the smb2 dialect selected by the server (ex: 3.1.1)
+    */
+    
+    Dialect?: string,
+}
+
+
+
 /**
  * HeaderLog Interface
  */
 export interface HeaderLog {
     
+    Credits?: number,
+    
+    Flags?: number,
+    
     ProtocolID?: Uint8Array,
     
     Status?: number,
     
     Command?: number,
+}
+
+
+
+/**
+ * ListSharesInfoOptions contains options for ListSharesInfoWithOptions function.
+ * @example
+ * ```javascript
+ * const smb = require('nuclei/smb');
+ * const client = new smb.SMBClient();
+ * const resp = client.ListSharesInfoWithOptions('acme.com', 445, 'username', 'password', { PartialOnTimeout: true });
+ * ```
+ */
+export interface ListSharesInfoOptions {
     
-    Credits?: number,
+    /**
+    * PartialOnTimeout returns the shares received before the enumeration
+    * timed out with TimedOut set instead of an error
+    */
     
-    Flags?: number,
+    PartialOnTimeout?: boolean,
+}
+
+
+
+/**
+ * ListSharesInfoResponse is the response from the ListSharesInfoWithOptions function.
+ * this is returned by ListSharesInfoWithOptions function.
+ * @example
+ * ```javascript
+ * const smb = require('nuclei/smb');
+ * const client = new smb.SMBClient();
+ * const resp = client.ListSharesInfoWithOptions('acme.com', 445, 'username', 'password', { PartialOnTimeout: true });
+ * log(resp.TimedOut, toJSON(resp.Shares));
+ * ```
+ */
+export interface ListSharesInfoResponse {
+    
+    /**
+    * Shares are the enumerated shares
+    */
+    
+    Shares?: ShareInfo[],
+    
+    /**
+    * TimedOut is true if the enumeration timed out and Shares only contains
+    * the shares received before (if PartialOnTimeout is set)
+    */
+    
+    TimedOut?: boolean,
 }
 
 
@@ -113,8 +293,6 @@ export interface HeaderLog {
  */
 export interface NegotiationLog {
     
-    SecurityMode?: number,
-    
     DialectRevision?: number,
     
     ServerGuid?: Uint8Array,
@@ -127,16 +305,69 @@ export interface NegotiationLog {
     
     AuthenticationTypes?: string[],
     
+    SecurityMode?: number,
+    
     HeaderLog?: HeaderLog,
 }
 
 
 
+/**
+ * ReadFileOptions contains options for the ReadFile function.
+ * @example
+ * ```javascript
+ * const smb = require('nuclei/smb');
+ * const client = new smb.SMBClient();
+ * const file = client.ReadFile('acme.com', 445, 'Users', 'Public/config.ini', 'username', 'password', { MaxSize: 1048576 });
+ * ```
+ */
+export interface ReadFileOptions {
+    
+    /**
+    * MaxSize is the maximum size of file (in bytes) that will be read
+    * defaults to 10MB.
+    */
+    
+    MaxSize?: number,
+}
+
+
+
+/**
+ * ReadFileResponse is the response from the ReadFile function.
+ * this is returned by ReadFile function.
+ * @example
+ * ```javascript
+ * const smb = require('nuclei/smb');
+ * const client = new smb.SMBClient();
+ * const file = client.ReadFile('acme.com', 445, 'Users', 'Public/config.ini', 'username', 'password', {});
+ * log(file.Size);
+ * ```
+ */
+export interface ReadFileResponse {
+    
+    /**
+    * Data contains the content of the file
+    */
+    
+    Data?: Uint8Array,
+    
+    /**
+    * Size is the size of the file in bytes
+    */
+    
+    Size?: number,
+}
+
+
+
 /**
  * SMBCapabilities Interface
  */
 export interface SMBCapabilities {
     
+    Encryption?: boolean,
+    
     DFSSupport?: boolean,
     
     Leasing?: boolean,
@@ -148,8 +379,6 @@ export interface SMBCapabilities {
     Persist?: boolean,
     
     DirLeasing?: boolean,
-    
-    Encryption?: boolean,
 }
 
 
@@ -159,23 +388,23 @@ export interface SMBCapabilities {
  */
 export interface SMBLog {
     
+    SupportV1?: boolean,
+    
+    NativeOs?: string,
+    
     NTLM?: string,
     
     GroupName?: string,
     
     HasNTLM?: boolean,
     
-    SupportV1?: boolean,
-    
-    NativeOs?: string,
-    
-    Version?: SMBVersions,
-    
     Capabilities?: SMBCapabilities,
     
     NegotiationLog?: NegotiationLog,
     
     SessionSetupLog?: SessionSetupLog,
+    
+    Version?: SMBVersions,
 }
 
 
@@ -196,13 +425,63 @@ export interface SMBVersions {
 
 
 
+/**
+ * SecurityPolicy is the security policy of a smb server.
+ * this is returned by GetSecurityPolicy function.
+ * @example
+ * ```javascript
+ * const smb = require('nuclei/smb');
+ * const client = new smb.SMBClient();
+ * const policy = client.GetSecurityPolicy('acme.com', 445);
+ * log(toJSON(policy));
+ * ```
+ */
+export interface SecurityPolicy {
+    
+    /**
+    * SigningEnabled is true if the server supports message signing
+    */
+    
+    SigningEnabled?: boolean,
+    
+    /**
+    * SigningRequired is true if the server requires message signing (not relayable)
+    */
+    
+    SigningRequired?: boolean,
+    
+    /**
+    * GuestAuthAllowed is true if a session can be established as guest with a blank password
+    */
+    
+    GuestAuthAllowed?: boolean,
+    
+    /**
+    * NullSessionAllowed is true if an anonymous session can be established
+    */
+    
+    NullSessionAllowed?: boolean,
+    
+    /**
+    * SMBv1Enabled is true if the server accepts the NT LM 0.12 (smb1) dialect
+    */
+    
+    SMBv1Enabled?: boolean,
+    
+    /**
+    * Dialect is the smb2 dialect selected by the server (ex: 3.1.1)
+    */
+    
+    Dialect?: string,
+}
+
+
+
 /**
  * ServiceSMB Interface
  */
 export interface ServiceSMB {
     
-    OSVersion?: string,
-    
     NetBIOSComputerName?: string,
     
     NetBIOSDomainName?: string,
@@ -216,6 +495,8 @@ export interface ServiceSMB {
     SigningEnabled?: boolean,
     
     SigningRequired?: boolean,
+    
+    OSVersion?: string,
 }
 
 
@@ -234,3 +515,43 @@ export interface SessionSetupLog {
     HeaderLog?: HeaderLog,
 }
 
+
+
+/**
+ * ShareInfo contains information about a share
+ * as returned by SRVSVC NetShareEnumAll (level 1).
+ * @example
+ * ```javascript
+ * const smb = require('nuclei/smb');
+ * const client = new smb.SMBClient();
+ * const shares = client.ListSharesInfo('acme.com', 445, 'username', 'password');
+ * log(toJSON(shares));
+ * ```
+ */
+export interface ShareInfo {
+    
+    /**
+    * Name is the name of the share
+    */
+    
+    Name?: string,
+    
+    /**
+    * Type is the type of the share (DISK, PRINTER, DEVICE, IPC)
+    */
+    
+    Type?: string,
+    
+    /**
+    * Special is true for administrative/special shares (ex: C$, IPC$)
+    */
+    
+    Special?: boolean,
+    
+    /**
+    * Remark is the comment associated with the share
+    */
+    
+    Remark?: string,
+}
+
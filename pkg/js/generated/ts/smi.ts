@@ -0,0 +1,38 @@
+
+
+/**
+ * IsSmartInstall checks if the given host and port are running an enabled
+ * Cisco Smart Install (SMI) client by sending the director hello message
+ * and validating the SMI header of the response.
+ * @example
+ * ```javascript
+ * const smi = require('nuclei/smi');
+ * const smartInstall = smi.IsSmartInstall('acme.com', 4786);
+ * log(toJSON(smartInstall));
+ * ```
+ */
+export function IsSmartInstall(host: string, port: number): IsSmartInstallResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsSmartInstallResponse is the response from the IsSmartInstall function.
+ * this is returned by IsSmartInstall function.
+ * @example
+ * ```javascript
+ * const smi = require('nuclei/smi');
+ * const smartInstall = smi.IsSmartInstall('acme.com', 4786);
+ * log(toJSON(smartInstall));
+ * ```
+ */
+export interface IsSmartInstallResponse {
+    
+    /**
+    * Enabled is true if the host runs an enabled smart install client
+    */
+    
+    Enabled?: boolean,
+}
+
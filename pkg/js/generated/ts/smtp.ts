@@ -1,5 +1,23 @@
 
 
+/**
+ * CheckOpenRelay checks if the smtp server accepts relaying a message from
+ * an external sender to an external recipient. Only MAIL FROM and RCPT TO are
+ * sent, the DATA phase is never started and the session is reset before QUIT.
+ * NoGreetingWait avoids waiting for the greeting on servers that never send one.
+ * @example
+ * ```javascript
+ * const smtp = require('nuclei/smtp');
+ * const resp = smtp.CheckOpenRelay('acme.com', 25, 'probe@example.com', 'probe@example.org');
+ * log(resp.IsOpenRelay, resp.Status);
+ * ```
+ */
+export function CheckOpenRelay(host: string, port: number, from: string, to: string, opts: CheckOpenRelayOptions): CheckOpenRelayResponse | null {
+    return null;
+}
+
+
+
 /**
  * Client is a minimal SMTP client for nuclei scripts.
  * @example
@@ -179,6 +197,73 @@ export class SMTPMessage {
 
 
 
+/**
+ * CheckOpenRelayOptions contains options for CheckOpenRelay function.
+ * @example
+ * ```javascript
+ * const smtp = require('nuclei/smtp');
+ * const resp = smtp.CheckOpenRelay('acme.com', 25, 'probe@example.com', 'probe@example.org', { NoGreetingWait: true });
+ * ```
+ */
+export interface CheckOpenRelayOptions {
+    
+    /**
+    * NoGreetingWait sends EHLO right after connecting instead of waiting for the
+    * greeting of the server (ex: servers only answering commands). a greeting
+    * received afterwards is still returned as Banner
+    */
+    
+    NoGreetingWait?: boolean,
+}
+
+
+
+/**
+ * CheckOpenRelayResponse is the response from the CheckOpenRelay function.
+ * this is returned by CheckOpenRelay function.
+ * @example
+ * ```javascript
+ * const smtp = require('nuclei/smtp');
+ * const resp = smtp.CheckOpenRelay('acme.com', 25, 'probe@example.com', 'probe@example.org');
+ * log(toJSON(resp));
+ * ```
+ */
+export interface CheckOpenRelayResponse {
+    
+    /**
+    * IsOpenRelay is true if the server accepted the external recipient
+    */
+    
+    IsOpenRelay?: boolean,
+    
+    /**
+    * Status classifies the server response, one of:
+    * accepted, relay_denied, sender_rejected, auth_required, temporary_failure
+    */
+    
+    Status?: string,
+    
+    Banner?: string,
+    
+    /**
+    * MailCode and MailMessage are the response to MAIL FROM
+    */
+    
+    MailCode?: number,
+    
+    MailMessage?: string,
+    
+    /**
+    * RcptCode and RcptMessage are the response to RCPT TO (if sent)
+    */
+    
+    RcptCode?: number,
+    
+    RcptMessage?: string,
+}
+
+
+
 /**
  * SMTPResponse is the response from the IsSMTP function.
  * @example
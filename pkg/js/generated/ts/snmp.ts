@@ -0,0 +1,102 @@
+
+
+/**
+ * CheckV3User checks whether the given usm user exists on the snmpv3 agent.
+ * After the engine discovery an authenticated request with an invalid digest
+ * is sent for the user and the usm report of the agent is returned:
+ * usmStatsUnknownUserNames for unknown users, usmStatsWrongDigests for users
+ * with authentication and usmStatsUnsupportedSecLevels for users without it.
+ * @example
+ * ```javascript
+ * const snmp = require('nuclei/snmp');
+ * const user = snmp.CheckV3User('acme.com', 161, 'admin');
+ * if (user.UserExists) { log('found snmpv3 user', user.Report); }
+ * ```
+ */
+export function CheckV3User(host: string, port: number, user: string): CheckV3UserResponse | null {
+    return null;
+}
+
+
+
+/**
+ * GetEngineID performs the snmpv3 engine discovery (rfc 3414 section 4)
+ * and returns the authoritative engine id along with engine boots and time
+ * from the report of the agent.
+ * @example
+ * ```javascript
+ * const snmp = require('nuclei/snmp');
+ * const engine = snmp.GetEngineID('acme.com', 161);
+ * log(engine.EngineID, engine.EngineBoots, engine.EngineTime);
+ * ```
+ */
+export function GetEngineID(host: string, port: number): EngineIDResponse | null {
+    return null;
+}
+
+
+
+/**
+ * CheckV3UserResponse is the response from the CheckV3User function.
+ * this is returned by CheckV3User function.
+ * @example
+ * ```javascript
+ * const snmp = require('nuclei/snmp');
+ * const user = snmp.CheckV3User('acme.com', 161, 'admin');
+ * log(toJSON(user));
+ * ```
+ */
+export interface CheckV3UserResponse {
+    
+    /**
+    * UserExists is true if the agent did not report the user as unknown
+    */
+    
+    UserExists?: boolean,
+    
+    /**
+    * AuthRequired is true if the agent rejected the authentication digest of the user
+    */
+    
+    AuthRequired?: boolean,
+    
+    /**
+    * Report is the usm report returned by the agent (ex: usmStatsUnknownUserNames)
+    */
+    
+    Report?: string,
+}
+
+
+
+/**
+ * EngineIDResponse is the response from the GetEngineID function.
+ * this is returned by GetEngineID function.
+ * @example
+ * ```javascript
+ * const snmp = require('nuclei/snmp');
+ * const engine = snmp.GetEngineID('acme.com', 161);
+ * log(toJSON(engine));
+ * ```
+ */
+export interface EngineIDResponse {
+    
+    /**
+    * EngineID is the hex encoded authoritative snmp engine id
+    */
+    
+    EngineID?: string,
+    
+    /**
+    * EngineBoots is the number of times the snmp engine was (re)initialized
+    */
+    
+    EngineBoots?: number,
+    
+    /**
+    * EngineTime is the number of seconds since the engine boots counter was last changed
+    */
+    
+    EngineTime?: number,
+}
+
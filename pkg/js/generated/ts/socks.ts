@@ -0,0 +1,57 @@
+
+
+/**
+ * IsOpenProxy checks if the given host and port are running an open SOCKS proxy.
+ * It performs a SOCKS5 handshake (falling back to SOCKS4a) without authentication
+ * and tries to connect through the proxy to a benign external endpoint.
+ * No data is sent through the established tunnel.
+ * @example
+ * ```javascript
+ * const socks = require('nuclei/socks');
+ * const proxy = socks.IsOpenProxy('acme.com', 1080);
+ * log(toJSON(proxy));
+ * ```
+ */
+export function IsOpenProxy(host: string, port: number): IsOpenProxyResponse | null {
+    return null;
+}
+
+
+
+/**
+ * IsOpenProxyResponse is the response from the IsOpenProxy function.
+ * this is returned by IsOpenProxy function.
+ * @example
+ * ```javascript
+ * const socks = require('nuclei/socks');
+ * const proxy = socks.IsOpenProxy('acme.com', 1080);
+ * log(toJSON(proxy));
+ * ```
+ */
+export interface IsOpenProxyResponse {
+    
+    /**
+    * IsSOCKS is true if the server speaks SOCKS4 or SOCKS5
+    */
+    
+    IsSOCKS?: boolean,
+    
+    /**
+    * Version is the SOCKS version (4 or 5) spoken by the server
+    */
+    
+    Version?: number,
+    
+    /**
+    * AuthRequired is true if the server requires authentication (or ident for SOCKS4)
+    */
+    
+    AuthRequired?: boolean,
+    
+    /**
+    * OpenProxy is true if the server forwarded a connection without authentication
+    */
+    
+    OpenProxy?: boolean,
+}
+
@@ -0,0 +1,121 @@
+
+
+/**
+ * Discover sends a SSDP M-SEARCH request (ssdp:all) to the given host and collects
+ * the responses until the read timeout of the execution is reached. If host is empty
+ * the request is sent to the SSDP multicast group (239.255.255.250).
+ * @example
+ * ```javascript
+ * const ssdp = require('nuclei/ssdp');
+ * const devices = ssdp.Discover('192.168.1.1');
+ * log(toJSON(devices));
+ * ```
+ */
+export function Discover(host: string): SSDPResponse[] | null {
+    return null;
+}
+
+
+
+/**
+ * GetDeviceDescription fetches the UPnP device description from given url
+ * (usually the Location of a SSDP response) and parses the device details.
+ * @example
+ * ```javascript
+ * const ssdp = require('nuclei/ssdp');
+ * const device = ssdp.GetDeviceDescription('http://192.168.1.1:49152/rootDesc.xml');
+ * log(toJSON(device));
+ * ```
+ */
+export function GetDeviceDescription(url: string): DeviceDescription | null {
+    return null;
+}
+
+
+
+/**
+ * DeviceDescription is the parsed UPnP device description.
+ * this is returned by GetDeviceDescription function.
+ * @example
+ * ```javascript
+ * const ssdp = require('nuclei/ssdp');
+ * const device = ssdp.GetDeviceDescription('http://192.168.1.1:49152/rootDesc.xml');
+ * log(device.Manufacturer, device.ModelName);
+ * ```
+ */
+export interface DeviceDescription {
+    
+    DeviceType?: string,
+    
+    FriendlyName?: string,
+    
+    Manufacturer?: string,
+    
+    ModelName?: string,
+    
+    ModelNumber?: string,
+    
+    ModelDescription?: string,
+    
+    SerialNumber?: string,
+    
+    UDN?: string,
+    
+    /**
+    * Services contains service types of the device and its embedded devices
+    */
+    
+    Services?: string[],
+    
+    /**
+    * IsIGD is true for internet gateway devices exposing port mapping services
+    */
+    
+    IsIGD?: boolean,
+}
+
+
+
+/**
+ * SSDPResponse is a response to a SSDP M-SEARCH request.
+ * this is returned by Discover function.
+ * @example
+ * ```javascript
+ * const ssdp = require('nuclei/ssdp');
+ * const devices = ssdp.Discover('192.168.1.1');
+ * log(toJSON(devices));
+ * ```
+ */
+export interface SSDPResponse {
+    
+    /**
+    * Address is the address of the responding device
+    */
+    
+    Address?: string,
+    
+    /**
+    * ST is the search target of the response
+    */
+    
+    ST?: string,
+    
+    /**
+    * USN is the unique service name of the device
+    */
+    
+    USN?: string,
+    
+    /**
+    * Server is the value of SERVER header (os, upnp version and product)
+    */
+    
+    Server?: string,
+    
+    /**
+    * Location is the url of the device description
+    */
+    
+    Location?: string,
+}
+
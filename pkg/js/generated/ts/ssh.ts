@@ -1,5 +1,24 @@
 
 
+/**
+ * VerifyHostKey performs a key exchange with the given host and port and
+ * checks if the host key matches the expected sha256 fingerprint. The
+ * fingerprint can be given in OpenSSH format (SHA256:base64), as plain
+ * base64 or as hex. The actual fingerprint is returned along with the result.
+ * No authentication is attempted.
+ * @example
+ * ```javascript
+ * const ssh = require('nuclei/ssh');
+ * const result = ssh.VerifyHostKey('acme.com', 22, 'SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8');
+ * log(result.Matched, result.Fingerprint);
+ * ```
+ */
+export function VerifyHostKey(host: string, port: number, expectedSHA256: string): VerifyHostKeyResponse | null {
+    return null;
+}
+
+
+
 /**
  * SSHClient is a client for SSH servers.
  * Internally client uses github.com/zmap/zgrab2/lib/ssh driver.
@@ -228,3 +247,36 @@ export interface KexInitMsg {
     FirstKexFollows?: boolean,
 }
 
+
+
+/**
+ * VerifyHostKeyResponse is the response from the VerifyHostKey function.
+ * this is returned by VerifyHostKey function.
+ * @example
+ * ```javascript
+ * const ssh = require('nuclei/ssh');
+ * const result = ssh.VerifyHostKey('acme.com', 22, 'SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8');
+ * log(toJSON(result));
+ * ```
+ */
+export interface VerifyHostKeyResponse {
+    
+    /**
+    * Matched is true if the host key matches the expected fingerprint
+    */
+    
+    Matched?: boolean,
+    
+    /**
+    * Fingerprint is the sha256 fingerprint of the host key (SHA256:base64)
+    */
+    
+    Fingerprint?: string,
+    
+    /**
+    * KeyType is the type of the host key (ex: ssh-ed25519)
+    */
+    
+    KeyType?: string,
+}
+
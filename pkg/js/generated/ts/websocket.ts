@@ -0,0 +1,236 @@
+
+
+/**
+ * Connect performs the websocket upgrade handshake with the given host,
+ * port and path and returns the connection. If the upgrade is accepted
+ * frames can be exchanged using Send and Recv. Unlike Handshake the
+ * result is not memoized.
+ * @example
+ * ```javascript
+ * const websocket = require('nuclei/websocket');
+ * const conn = websocket.Connect('acme.com', 443, '/ws', { TLS: true, Headers: { Origin: 'https://acme.com' } });
+ * if (conn.Accepted) { conn.Send('{"type":"ping"}'); log(conn.Recv()); }
+ * conn.Close();
+ * ```
+ */
+export function Connect(host: string, port: number, path: string, opts: ConnectOptions): Conn | null {
+    return null;
+}
+
+
+
+/**
+ * Handshake performs the websocket upgrade handshake with the given host,
+ * port and path and returns whether the server accepted the upgrade along
+ * with the negotiated subprotocol and response headers. The connection is
+ * closed after the handshake and the result is memoized on target and path.
+ * @example
+ * ```javascript
+ * const websocket = require('nuclei/websocket');
+ * const resp = websocket.Handshake('acme.com', 443, '/ws', { TLS: true });
+ * log(resp.Accepted, resp.Subprotocol);
+ * ```
+ */
+export function Handshake(host: string, port: number, path: string, opts: ConnectOptions): HandshakeResponse | null {
+    return null;
+}
+
+
+
+/**
+ * Conn is a websocket connection.
+ * this is returned by Connect function.
+ * @example
+ * ```javascript
+ * const websocket = require('nuclei/websocket');
+ * const conn = websocket.Connect('acme.com', 80, '/ws');
+ * if (conn.Accepted) { conn.Send('ping'); log(conn.Recv()); }
+ * ```
+ */
+export class Conn {
+    
+
+    
+    /**
+    * Accepted is true if the server switched protocols with a valid Sec-WebSocket-Accept
+    */
+    
+    public Accepted?: boolean;
+    
+
+    
+    /**
+    * StatusCode is the http status code of the upgrade response
+    */
+    
+    public StatusCode?: number;
+    
+
+    
+    /**
+    * Subprotocol is the subprotocol selected by the server (if any)
+    */
+    
+    public Subprotocol?: string;
+    
+
+    
+    /**
+    * Headers are the headers of the upgrade response
+    */
+    
+    public Headers?: Record<string, string>;
+    
+
+    // Constructor of Conn
+    constructor() {}
+    /**
+    * SetTimeout sets the timeout of frame operations in seconds.
+    * @example
+    * ```javascript
+    * const websocket = require('nuclei/websocket');
+    * const conn = websocket.Connect('acme.com', 80, '/ws');
+    * conn.SetTimeout(5);
+    * ```
+    */
+    public SetTimeout(value: number): void {
+        return;
+    }
+    
+
+    /**
+    * Send sends data as a text frame.
+    * @example
+    * ```javascript
+    * const websocket = require('nuclei/websocket');
+    * const conn = websocket.Connect('acme.com', 80, '/ws');
+    * conn.Send('hello');
+    * ```
+    */
+    public Send(data: string): void {
+        return;
+    }
+    
+
+    /**
+    * SendBinary sends data as a binary frame.
+    * @example
+    * ```javascript
+    * const websocket = require('nuclei/websocket');
+    * const conn = websocket.Connect('acme.com', 80, '/ws');
+    * conn.SendBinary([0x01, 0x02]);
+    * ```
+    */
+    public SendBinary(data: Uint8Array): void {
+        return;
+    }
+    
+
+    /**
+    * Recv receives the next text or binary message as a string.
+    * Control frames (ping, pong) are handled transparently.
+    * @example
+    * ```javascript
+    * const websocket = require('nuclei/websocket');
+    * const conn = websocket.Connect('acme.com', 80, '/ws');
+    * conn.Send('hello');
+    * const message = conn.Recv();
+    * ```
+    */
+    public Recv(): string | null {
+        return null;
+    }
+    
+
+    /**
+    * Close closes the connection sending a close frame if the upgrade was accepted.
+    * @example
+    * ```javascript
+    * const websocket = require('nuclei/websocket');
+    * const conn = websocket.Connect('acme.com', 80, '/ws');
+    * conn.Close();
+    * ```
+    */
+    public Close(): void {
+        return;
+    }
+    
+
+}
+
+
+
+/**
+ * ConnectOptions contains options for Handshake and Connect functions.
+ * @example
+ * ```javascript
+ * const websocket = require('nuclei/websocket');
+ * const conn = websocket.Connect('acme.com', 443, '/ws', { TLS: true, Subprotocols: ['graphql-ws'] });
+ * ```
+ */
+export interface ConnectOptions {
+    
+    /**
+    * TLS performs the handshake over tls (wss)
+    */
+    
+    TLS?: boolean,
+    
+    /**
+    * Headers are added to the upgrade request (ex: Origin, Cookie)
+    */
+    
+    Headers?: Record<string, string>,
+    
+    /**
+    * Subprotocols are offered in Sec-WebSocket-Protocol ordered by preference
+    */
+    
+    Subprotocols?: string[],
+    
+    /**
+    * Timeout is the timeout of the handshake and frame operations in seconds (default: 10)
+    */
+    
+    Timeout?: number,
+}
+
+
+
+/**
+ * HandshakeResponse is the result of the websocket upgrade handshake.
+ * this is returned by Handshake function.
+ * @example
+ * ```javascript
+ * const websocket = require('nuclei/websocket');
+ * const resp = websocket.Handshake('acme.com', 80, '/ws');
+ * log(toJSON(resp));
+ * ```
+ */
+export interface HandshakeResponse {
+    
+    /**
+    * Accepted is true if the server switched protocols with a valid Sec-WebSocket-Accept
+    */
+    
+    Accepted?: boolean,
+    
+    /**
+    * StatusCode is the http status code of the upgrade response
+    */
+    
+    StatusCode?: number,
+    
+    /**
+    * Subprotocol is the subprotocol selected by the server (if any)
+    */
+    
+    Subprotocol?: string,
+    
+    /**
+    * Headers are the headers of the upgrade response
+    */
+    
+    Headers?: Record<string, string>,
+}
+
@@ -4,6 +4,7 @@ import (
 	"context"
 	"maps"
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/Mzack9999/goja"
@@ -112,7 +113,37 @@ func (p *GojaModule) Require(runtime *goja.Runtime, module *goja.Object) {
 	o := module.Get("exports").(*goja.Object)
 
 	for k, v := range p.sets {
-		_ = o.Set(k, v)
+		_ = o.Set(k, padArguments(runtime, v))
+	}
+}
+
+// padArguments wraps functions receiving an injected context so that omitted
+// trailing arguments (ex: options) are passed as undefined and converted to
+// their zero value. the runtime zero fills them with the type of the previous
+// argument when the context is injected which makes the call panic
+func padArguments(runtime *goja.Runtime, value interface{}) interface{} {
+	fnType := reflect.TypeOf(value)
+	if fnType == nil || fnType.Kind() != reflect.Func || fnType.IsVariadic() || fnType.NumIn() < 2 || fnType.In(0) != contextType {
+		return value
+	}
+	fn, ok := goja.AssertFunction(runtime.ToValue(value))
+	if !ok {
+		return value
+	}
+	arity := fnType.NumIn() - 1
+	return func(call goja.FunctionCall) goja.Value {
+		args := call.Arguments
+		if len(args) < arity {
+			args = append(slices.Clone(args), make([]goja.Value, arity-len(args))...)
+			for i := len(call.Arguments); i < arity; i++ {
+				args[i] = goja.Undefined()
+			}
+		}
+		result, err := fn(call.This, args...)
+		if err != nil {
+			panic(err)
+		}
+		return result
 	}
 }
 
@@ -123,6 +154,7 @@ func (p *GojaModule) Enable(runtime Runtime) {
 func (p *GojaModule) Register() Module {
 	p.once.Do(func() {
 		require.RegisterNativeModule(p.Name(), p.Require)
+		registerModule(p)
 	})
 
 	return p
@@ -0,0 +1,125 @@
+package gojs
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Mzack9999/goja"
+)
+
+var (
+	registeredModules   = map[string]*GojaModule{}
+	registeredModulesMu sync.RWMutex
+
+	contextType     = reflect.TypeOf((*context.Context)(nil)).Elem()
+	constructorType = reflect.TypeOf(func(goja.ConstructorCall, *goja.Runtime) *goja.Object { return nil })
+)
+
+// ModuleInfo describes a module registered in the js runtime
+type ModuleInfo struct {
+	// Name is the name used to require the module (ex: nuclei/rdp)
+	Name string
+	// Functions are the exported functions of the module
+	Functions []FunctionInfo
+	// Classes are the exported classes (constructors) of the module
+	Classes []string
+}
+
+// FunctionInfo describes a function exported by a module
+type FunctionInfo struct {
+	// Name is the name of the function
+	Name string
+	// Signature is the go signature of the function as seen from js
+	// (ex: IsRDP(string, int, rdp.IsRDPOptions) (rdp.IsRDPResponse, error))
+	Signature string
+	// Arity is the number of arguments accepted from js
+	// (injected context is not counted)
+	Arity int
+	// Variadic is true if the last argument is variadic
+	Variadic bool
+}
+
+// registerModule records the module so it can be enumerated by Modules
+func registerModule(module *GojaModule) {
+	registeredModulesMu.Lock()
+	defer registeredModulesMu.Unlock()
+	registeredModules[module.name] = module
+}
+
+// Modules returns the modules registered in the js runtime sorted by name
+// along with their exported functions and classes
+func Modules() []ModuleInfo {
+	registeredModulesMu.RLock()
+	defer registeredModulesMu.RUnlock()
+
+	modules := make([]ModuleInfo, 0, len(registeredModules))
+	for _, module := range registeredModules {
+		modules = append(modules, module.info())
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+	return modules
+}
+
+// info returns the description of the module
+func (p *GojaModule) info() ModuleInfo {
+	info := ModuleInfo{Name: p.name}
+	for name, value := range p.sets {
+		fnType := reflect.TypeOf(value)
+		if fnType == nil || fnType.Kind() != reflect.Func {
+			continue
+		}
+		if fnType == constructorType {
+			info.Classes = append(info.Classes, name)
+			continue
+		}
+		info.Functions = append(info.Functions, newFunctionInfo(name, fnType))
+	}
+	sort.Slice(info.Functions, func(i, j int) bool {
+		return info.Functions[i].Name < info.Functions[j].Name
+	})
+	sort.Strings(info.Classes)
+	return info
+}
+
+// newFunctionInfo returns the description of a function with given type
+func newFunctionInfo(name string, fnType reflect.Type) FunctionInfo {
+	var in []string
+	for i := 0; i < fnType.NumIn(); i++ {
+		argType := fnType.In(i)
+		if i == 0 && argType == contextType {
+			// context is injected by the runtime
+			continue
+		}
+		if fnType.IsVariadic() && i == fnType.NumIn()-1 {
+			in = append(in, "..."+argType.Elem().String())
+			continue
+		}
+		in = append(in, argType.String())
+	}
+	var out []string
+	for i := 0; i < fnType.NumOut(); i++ {
+		out = append(out, fnType.Out(i).String())
+	}
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteString("(" + strings.Join(in, ", ") + ")")
+	switch len(out) {
+	case 0:
+	case 1:
+		sb.WriteString(" " + out[0])
+	default:
+		sb.WriteString(" (" + strings.Join(out, ", ") + ")")
+	}
+	return FunctionInfo{
+		Name:      name,
+		Signature: sb.String(),
+		Arity:     len(in),
+		Variadic:  fnType.IsVariadic(),
+	}
+}
@@ -0,0 +1,154 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the challenge endpoint request
+	requestTimeout = 10 * time.Second
+	// path of http-01 challenge tokens (rfc 8555 section 8.3)
+	challengePath = "/.well-known/acme-challenge/"
+)
+
+var (
+	// markers (lowercase) of directory listings of common web servers
+	listingMarkers = [][]byte{
+		[]byte("<title>index of /"),
+		[]byte("<h1>index of /"),
+		[]byte("directory listing for /"),
+		[]byte("[to parent directory]"),
+	}
+	// links to challenge tokens, tokens are base64url encoded (>= 128 bits of entropy)
+	tokenLink = regexp.MustCompile(`(?i)href=["']?(?:[^"'>\s]*/)?([A-Za-z0-9_-]{22,})["'>\s]`)
+)
+
+type (
+	// ChallengeEndpointResponse is the response from the CheckChallengeEndpoint function.
+	// this is returned by CheckChallengeEndpoint function.
+	// @example
+	// ```javascript
+	// const acme = require('nuclei/acme');
+	// const resp = acme.CheckChallengeEndpoint('acme.com', 80);
+	// log(toJSON(resp));
+	// ```
+	ChallengeEndpointResponse struct {
+		// Clean is true if the challenge directory is not found (404 or 410)
+		Clean bool
+		// DirectoryListing is true if the challenge directory is listed
+		DirectoryListing bool
+		// Tokens are the challenge token filenames found in the directory listing
+		Tokens []string
+		// StatusCode is the http status code of the response
+		StatusCode int
+		// TLS is true if the endpoint was reached over https
+		TLS bool
+	}
+)
+
+// CheckChallengeEndpoint requests the acme http-01 challenge directory
+// (/.well-known/acme-challenge/) to detect directory listings exposing
+// leftover challenge tokens. A missing directory (404/410) is reported as
+// Clean while a listing is reported as DirectoryListing with the token filenames.
+// HTTPS is tried first and plaintext HTTP is used as fallback.
+// @example
+// ```javascript
+// const acme = require('nuclei/acme');
+// const resp = acme.CheckChallengeEndpoint('acme.com', 80);
+// if (resp.DirectoryListing) { log('exposed challenge tokens', resp.Tokens); }
+// ```
+func CheckChallengeEndpoint(ctx context.Context, host string, port int) (ChallengeEndpointResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedcheckChallengeEndpoint(executionId, host, port)
+}
+
+// @memo
+func checkChallengeEndpoint(executionId string, host string, port int) (ChallengeEndpointResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return ChallengeEndpointResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	client, err := utils.NewHTTPClient(executionId, requestTimeout)
+	if err != nil {
+		return ChallengeEndpointResponse{}, err
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	resp, err := fetchChallengeDirectory(executionId, client, "https://"+address+challengePath)
+	if err == nil {
+		resp.TLS = true
+		return resp, nil
+	}
+	// fallback to plaintext http
+	return fetchChallengeDirectory(executionId, client, "http://"+address+challengePath)
+}
+
+// fetchChallengeDirectory fetches the challenge directory at url and classifies the response
+func fetchChallengeDirectory(executionId string, client *http.Client, url string) (ChallengeEndpointResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return ChallengeEndpointResponse{}, err
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return ChallengeEndpointResponse{}, err
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+
+	resp := ChallengeEndpointResponse{StatusCode: httpResp.StatusCode}
+	switch httpResp.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		resp.Clean = true
+		return resp, nil
+	case http.StatusOK:
+	default:
+		return resp, nil
+	}
+	body, err := utils.ReadAll(executionId, httpResp.Body)
+	if err != nil {
+		return resp, err
+	}
+	if !isDirectoryListing(body) {
+		return resp, nil
+	}
+	resp.DirectoryListing = true
+	resp.Tokens = challengeTokens(body)
+	return resp, nil
+}
+
+// isDirectoryListing checks if body contains any of the listing markers
+func isDirectoryListing(body []byte) bool {
+	body = bytes.ToLower(body)
+	for _, marker := range listingMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// challengeTokens returns the unique token filenames linked by a directory listing
+func challengeTokens(body []byte) []string {
+	tokens := []string{}
+	seen := map[string]struct{}{}
+	for _, match := range tokenLink.FindAllSubmatch(body, -1) {
+		token := string(match[1])
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
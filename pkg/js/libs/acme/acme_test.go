@@ -0,0 +1,93 @@
+package acme
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// nginxListing is a nginx autoindex page of the challenge directory
+const nginxListing = `<html>
+<head><title>Index of /.well-known/acme-challenge/</title></head>
+<body>
+<h1>Index of /.well-known/acme-challenge/</h1><hr><pre><a href="../">../</a>
+<a href="LoqXcYV8q5ONbJQxbmR7SCTNo3tiAXDfowyjxAjEuX0">LoqXcYV8q5ONbJQxbmR7SCTNo3tiAXDfowyjxAjEuX0</a> 14-Oct-2026 10:12  87
+<a href="evaGxfADs6pSRb2LAv9IZf17Dt3juxGJ-PCt92wr-oA">evaGxfADs6pSRb2LAv9IZf17Dt3juxGJ-PCt92wr-oA</a> 14-Oct-2026 10:12  87
+</pre><hr></body>
+</html>`
+
+func TestCheckChallengeEndpoint(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	server := func(t *testing.T, tls bool, handler http.HandlerFunc) (string, int) {
+		var s *httptest.Server
+		if tls {
+			s = httptest.NewTLSServer(handler)
+		} else {
+			s = httptest.NewServer(handler)
+		}
+		t.Cleanup(s.Close)
+		host, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+		portNum, _ := strconv.Atoi(port)
+		return host, portNum
+	}
+	tests := []struct {
+		name    string
+		tls     bool
+		handler http.HandlerFunc
+		want    ChallengeEndpointResponse
+	}{
+		{
+			name: "directory listing",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != challengePath {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(nginxListing))
+			},
+			want: ChallengeEndpointResponse{
+				DirectoryListing: true,
+				Tokens:           []string{"LoqXcYV8q5ONbJQxbmR7SCTNo3tiAXDfowyjxAjEuX0", "evaGxfADs6pSRb2LAv9IZf17Dt3juxGJ-PCt92wr-oA"},
+				StatusCode:       200,
+			},
+		},
+		{
+			name:    "not found over https",
+			tls:     true,
+			handler: http.NotFound,
+			want:    ChallengeEndpointResponse{Clean: true, StatusCode: 404, TLS: true},
+		},
+		{
+			name: "forbidden",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			want: ChallengeEndpointResponse{StatusCode: 403},
+		},
+		{
+			name: "page without listing",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("<html><title>Welcome</title></html>"))
+			},
+			want: ChallengeEndpointResponse{StatusCode: 200},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port := server(t, tt.tls, tt.handler)
+			got, err := CheckChallengeEndpoint(ctx, host, port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
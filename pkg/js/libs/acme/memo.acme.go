@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package acme
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[ChallengeEndpointResponse]("acme.checkChallengeEndpoint", 3)
+}
+
+func memoizedcheckChallengeEndpoint(executionId string, host string, port int) (ChallengeEndpointResponse, error) {
+	hash := "acme.checkChallengeEndpoint" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "acme.checkChallengeEndpoint" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (ChallengeEndpointResponse, error) {
+			return checkChallengeEndpoint(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return ChallengeEndpointResponse{}, err
+	}
+	if value, ok := v.(ChallengeEndpointResponse); ok {
+		return value, nil
+	}
+
+	return ChallengeEndpointResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,308 @@
+package ajp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of cping and forward request exchange
+	probeTimeout = 5 * time.Second
+
+	// maximum size of an ajp packet
+	maxPacketSize = 64 * 1024
+	// maximum number of response packets read for the forward request
+	maxResponsePackets = 32
+	// maximum size of the response body that is inspected
+	maxBodySize = 16 * 1024
+
+	// request packet types
+	typeForwardRequest = 0x02
+	typeCPing          = 0x0a
+	// response packet types
+	typeSendBodyChunk = 0x03
+	typeSendHeaders   = 0x04
+	typeEndResponse   = 0x05
+	typeGetBodyChunk  = 0x06
+	typeCPong         = 0x09
+
+	methodGet       = 0x02
+	headerHost      = 0xa00b
+	requestFinished = 0xff
+)
+
+var (
+	// magic of packets sent by the web server (client) and the container (server)
+	requestMagic  = []byte{0x12, 0x34}
+	responseMagic = []byte{'A', 'B'}
+
+	// coded response header names
+	responseHeaders = map[uint16]string{
+		0xa001: "Content-Type",
+		0xa002: "Content-Language",
+		0xa003: "Content-Length",
+		0xa004: "Date",
+		0xa005: "Last-Modified",
+		0xa006: "Location",
+		0xa007: "Set-Cookie",
+		0xa008: "Set-Cookie2",
+		0xa009: "Servlet-Engine",
+		0xa00a: "Status",
+		0xa00b: "WWW-Authenticate",
+	}
+
+	tomcatVersion = regexp.MustCompile(`Apache Tomcat/([0-9][0-9A-Za-z.\-]*)`)
+
+	errInvalidPacket = errors.New("invalid ajp packet")
+)
+
+type (
+	// IsAJPResponse is the response from the IsAJP function.
+	// this is returned by IsAJP function.
+	// @example
+	// ```javascript
+	// const ajp = require('nuclei/ajp');
+	// const isAJP = ajp.IsAJP('acme.com', 8009);
+	// log(toJSON(isAJP));
+	// ```
+	IsAJPResponse struct {
+		// IsAJP is true if the endpoint answered the cping with a cpong
+		IsAJP bool
+		// StatusCode is the http status code of the forwarded request (0 if not answered)
+		StatusCode int
+		// Server is the Server (or Servlet-Engine) header of the forwarded request
+		Server string
+		// Tomcat is true if the container was identified as apache tomcat
+		Tomcat bool
+		// Version is the tomcat version found in the response (if any)
+		Version string
+		// SecretRequired is true if the forwarded request was rejected (403)
+		// which is the behavior of connectors configured with a secret
+		SecretRequired bool
+		// Exploitable is true if the tomcat connector accepted the forwarded
+		// request without a secret (ex: ghostcat CVE-2020-1938)
+		Exploitable bool
+	}
+)
+
+// IsAJP checks if the given host and port are running an apache jserv
+// protocol (ajp13) connector by sending a cping and waiting for the cpong.
+// On success a minimal forward request (GET /) is sent over the same
+// connection to read the server headers and to check if the connector
+// appears to be a tomcat connector exploitable without a secret.
+// @example
+// ```javascript
+// const ajp = require('nuclei/ajp');
+// const isAJP = ajp.IsAJP('acme.com', 8009);
+// log(isAJP.IsAJP, isAJP.Exploitable);
+// ```
+func IsAJP(ctx context.Context, host string, port int) (IsAJPResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisAJP(executionId, host, port)
+}
+
+// @memo
+func isAJP(executionId string, host string, port int) (IsAJPResponse, error) {
+	resp := IsAJPResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	conn = utils.LimitConn(executionId, conn)
+
+	if _, err := conn.Write(newPacket([]byte{typeCPing})); err != nil {
+		return resp, err
+	}
+	data, err := readPacket(conn)
+	if err != nil {
+		if errors.Is(err, errInvalidPacket) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+			// closed, silent or non ajp responder
+			return resp, nil
+		}
+		return resp, err
+	}
+	if len(data) != 1 || data[0] != typeCPong {
+		return resp, nil
+	}
+	resp.IsAJP = true
+
+	if _, err := conn.Write(newForwardRequest(host, port)); err != nil {
+		return resp, nil
+	}
+	readForwardResponse(conn, &resp)
+	resp.Tomcat = resp.Tomcat || strings.Contains(resp.Server, "Tomcat")
+	resp.SecretRequired = resp.StatusCode == 403
+	resp.Exploitable = resp.Tomcat && resp.StatusCode != 0 && !resp.SecretRequired
+	return resp, nil
+}
+
+// newPacket frames payload as a web server to container packet
+func newPacket(payload []byte) []byte {
+	packet := append([]byte{}, requestMagic...)
+	packet = binary.BigEndian.AppendUint16(packet, uint16(len(payload)))
+	return append(packet, payload...)
+}
+
+// appendString appends an ajp string (length, data and null terminator)
+func appendString(data []byte, value string) []byte {
+	data = binary.BigEndian.AppendUint16(data, uint16(len(value)))
+	data = append(data, value...)
+	return append(data, 0x00)
+}
+
+// newForwardRequest returns a forward request packet for GET /
+func newForwardRequest(host string, port int) []byte {
+	payload := []byte{typeForwardRequest, methodGet}
+	payload = appendString(payload, "HTTP/1.1")
+	payload = appendString(payload, "/")
+	payload = appendString(payload, "127.0.0.1")
+	payload = appendString(payload, "localhost")
+	payload = appendString(payload, host)
+	payload = binary.BigEndian.AppendUint16(payload, uint16(port))
+	// is_ssl and a single host header
+	payload = append(payload, 0x00)
+	payload = binary.BigEndian.AppendUint16(payload, 1)
+	payload = binary.BigEndian.AppendUint16(payload, headerHost)
+	payload = appendString(payload, host)
+	payload = append(payload, requestFinished)
+	return newPacket(payload)
+}
+
+// readPacket reads a container to web server packet and returns its payload
+func readPacket(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:2], responseMagic) {
+		return nil, errInvalidPacket
+	}
+	length := int(binary.BigEndian.Uint16(header[2:4]))
+	if length == 0 || length > maxPacketSize {
+		return nil, errInvalidPacket
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readForwardResponse reads the response packets of the forward request
+// until the end of the response. errors leave resp partially filled
+func readForwardResponse(conn net.Conn, resp *IsAJPResponse) {
+	var body []byte
+	defer func() {
+		if match := tomcatVersion.FindSubmatch(body); match != nil {
+			resp.Tomcat = true
+			resp.Version = string(match[1])
+		}
+	}()
+	for i := 0; i < maxResponsePackets; i++ {
+		data, err := readPacket(conn)
+		if err != nil {
+			return
+		}
+		switch data[0] {
+		case typeSendHeaders:
+			if err := parseSendHeaders(data[1:], resp); err != nil {
+				return
+			}
+		case typeSendBodyChunk:
+			if len(data) < 3 {
+				return
+			}
+			chunk := data[3:]
+			if size := int(binary.BigEndian.Uint16(data[1:3])); size < len(chunk) {
+				chunk = chunk[:size]
+			}
+			if len(body) < maxBodySize {
+				body = append(body, chunk...)
+			}
+		case typeEndResponse, typeGetBodyChunk:
+			// request has no body so a body chunk request ends the exchange
+			return
+		default:
+			return
+		}
+	}
+}
+
+// parseSendHeaders parses the status and headers of a send headers packet
+func parseSendHeaders(data []byte, resp *IsAJPResponse) error {
+	readUint16 := func() (uint16, error) {
+		if len(data) < 2 {
+			return 0, errInvalidPacket
+		}
+		value := binary.BigEndian.Uint16(data[:2])
+		data = data[2:]
+		return value, nil
+	}
+	readString := func() (string, error) {
+		length, err := readUint16()
+		if err != nil {
+			return "", err
+		}
+		if length == 0xffff {
+			// null string
+			return "", nil
+		}
+		if int(length)+1 > len(data) {
+			return "", errInvalidPacket
+		}
+		value := string(data[:length])
+		data = data[length+1:]
+		return value, nil
+	}
+
+	status, err := readUint16()
+	if err != nil {
+		return err
+	}
+	if _, err := readString(); err != nil {
+		return err
+	}
+	resp.StatusCode = int(status)
+	count, err := readUint16()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < int(count); i++ {
+		var name string
+		if len(data) >= 2 && data[0] == 0xa0 {
+			code, _ := readUint16()
+			name = responseHeaders[code]
+		} else if name, err = readString(); err != nil {
+			return err
+		}
+		value, err := readString()
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.EqualFold(name, "Server"):
+			resp.Server = value
+		case strings.EqualFold(name, "Servlet-Engine") && resp.Server == "":
+			resp.Server = value
+		}
+	}
+	return nil
+}
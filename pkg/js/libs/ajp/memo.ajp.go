@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package ajp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsAJPResponse]("ajp.isAJP", 3)
+}
+
+func memoizedisAJP(executionId string, host string, port int) (IsAJPResponse, error) {
+	hash := "ajp.isAJP" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "ajp.isAJP" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsAJPResponse, error) {
+			return isAJP(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsAJPResponse{}, err
+	}
+	if value, ok := v.(IsAJPResponse); ok {
+		return value, nil
+	}
+
+	return IsAJPResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,349 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mssql"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mysql"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/postgres"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/redis"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/ssh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	defaultConcurrency      = 5
+	maxConcurrency          = 25
+	defaultLockoutThreshold = 3
+)
+
+var (
+	// lockoutMarkers (lowercase) are explicit account lockout signals of auth errors
+	lockoutMarkers = []string{"locked", "lockout", "too many", "blocked", "disabled"}
+
+	// transportMarkers (lowercase) are network errors of libraries returning
+	// errors as plain strings, they are not answers to the credentials
+	transportMarkers = []string{"i/o timeout", "connection refused", "connection reset", "broken pipe", "no route to host", "network is unreachable", "eof", "deadline exceeded"}
+
+	// protocols are the auth functions usable by BruteForce
+	protocols = map[string]authenticator{
+		"ssh": {
+			login: func(ctx context.Context, host string, port int, username, password string) (bool, error) {
+				return (&ssh.SSHClient{}).Connect(ctx, host, port, username, password)
+			},
+			rejected: []string{"unable to authenticate"},
+		},
+		"mysql": {
+			login: func(ctx context.Context, host string, port int, username, password string) (bool, error) {
+				return (&mysql.MySQLClient{}).Connect(ctx, host, port, username, password)
+			},
+			rejected: []string{"access denied for user"},
+		},
+		"mssql": {
+			login: func(ctx context.Context, host string, port int, username, password string) (bool, error) {
+				return (&mssql.MSSQLClient{}).Connect(ctx, host, port, username, password)
+			},
+		},
+		"postgres": {
+			login: func(ctx context.Context, host string, port int, username, password string) (bool, error) {
+				return (&postgres.PGClient{}).Connect(ctx, host, port, username, password)
+			},
+		},
+		"redis": {
+			// redis has no usernames (requirepass), only the password is used
+			login: func(ctx context.Context, host string, port int, username, password string) (bool, error) {
+				return redis.Connect(ctx, host, port, password)
+			},
+			rejected: []string{"wrongpass", "invalid password"},
+		},
+	}
+)
+
+// authenticator is the auth function of a protocol library
+type authenticator struct {
+	login func(ctx context.Context, host string, port int, username, password string) (bool, error)
+	// rejected (lowercase) are markers of errors returned for invalid credentials
+	// (libraries returning false without an error do not need any)
+	rejected []string
+}
+
+// isRejected checks if err is returned for invalid credentials
+func (a authenticator) isRejected(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, marker := range a.rejected {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+type (
+	// Credential is a username and password pair tried by BruteForce.
+	// @example
+	// ```javascript
+	// const auth = require('nuclei/auth');
+	// const creds = [{ Username: 'root', Password: 'toor' }, { Username: 'admin', Password: 'admin' }];
+	// ```
+	Credential struct {
+		Username string
+		Password string
+	}
+
+	// BruteForceOptions contains options for BruteForce function.
+	// @example
+	// ```javascript
+	// const auth = require('nuclei/auth');
+	// const options = new auth.BruteForceOptions();
+	// options.Concurrency = 2;
+	// options.Delay = 1000;
+	// ```
+	BruteForceOptions struct {
+		// Concurrency is the maximum number of concurrent attempts (default: 5, max: 25)
+		Concurrency int
+		// Delay is the minimum time in milliseconds between two attempts for the same username
+		Delay int
+		// LockoutThreshold is the number of consecutive identical auth errors (network
+		// errors excluded) after which a username is considered locked out and skipped (default: 3)
+		LockoutThreshold int
+		// ContinueOnSuccess keeps trying remaining credentials after a valid one is found
+		ContinueOnSuccess bool
+	}
+
+	// BruteForceResponse is the result of a BruteForce run.
+	// this is returned by BruteForce function.
+	// @example
+	// ```javascript
+	// const auth = require('nuclei/auth');
+	// const result = auth.BruteForce('ssh', 'acme.com', 22, [{ Username: 'root', Password: 'toor' }]);
+	// log(toJSON(result));
+	// ```
+	BruteForceResponse struct {
+		// Found is true if valid credentials were found
+		Found bool
+		// Valid contains the valid credentials in the order they were found
+		Valid []Credential
+		// Attempts is the number of credentials tried
+		Attempts int
+		// LockedOut contains the usernames skipped after a lockout signal
+		LockedOut []string
+	}
+)
+
+// BruteForce tries the credentials against the auth function of the given
+// protocol library (ssh, mysql, mssql, postgres, redis) with bounded concurrency
+// and stops at the first valid credential unless ContinueOnSuccess is set.
+// Usernames returning an explicit lockout error (ex: account locked) or the same
+// auth error LockoutThreshold times in a row are skipped to avoid locking accounts.
+// Network errors (ex: timeouts, refused connections) are not lockout signals.
+// No attempt is started past the scan deadline or the javascript execution timeout.
+// Results are not memoized since credentials differ between calls.
+// @example
+// ```javascript
+// const auth = require('nuclei/auth');
+// const creds = [{ Username: 'root', Password: 'root' }, { Username: 'root', Password: 'toor' }];
+// const result = auth.BruteForce('ssh', 'acme.com', 22, creds, { Concurrency: 2, Delay: 500 });
+// if (result.Found) { log(toJSON(result.Valid)); }
+// ```
+func BruteForce(ctx context.Context, protocol string, host string, port int, creds []Credential, opts BruteForceOptions) (BruteForceResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	auth, ok := protocols[strings.ToLower(protocol)]
+	if !ok {
+		return BruteForceResponse{}, fmt.Errorf("unsupported protocol %q (supported: %s)", protocol, strings.Join(supportedProtocols(), ", "))
+	}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return BruteForceResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	return bruteForce(ctx, auth, host, port, creds, opts), nil
+}
+
+// supportedProtocols returns the sorted names of supported protocols
+func supportedProtocols() []string {
+	names := make([]string, 0, len(protocols))
+	for name := range protocols {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// account is the brute force state of a username
+type account struct {
+	lastError   string
+	repeated    int
+	lockedOut   bool
+	nextAttempt time.Time
+}
+
+// bruteState is the state shared by brute force workers
+type bruteState struct {
+	sync.Mutex
+	opts     BruteForceOptions
+	accounts map[string]*account
+	resp     BruteForceResponse
+	stopped  bool
+}
+
+// bruteForce runs the credentials against auth and returns the response
+func bruteForce(ctx context.Context, auth authenticator, host string, port int, creds []Credential, opts BruteForceOptions) BruteForceResponse {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+	if opts.LockoutThreshold <= 0 {
+		opts.LockoutThreshold = defaultLockoutThreshold
+	}
+	state := &bruteState{opts: opts, accounts: map[string]*account{}}
+	executionId := ctx.Value("executionId").(string)
+	// the javascript execution is abandoned after its timeout, stop with it
+	deadline := protocolstate.GetDeadline(executionId, protocolstate.GetTimeouts(executionId).JsCompilerExecutionTimeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	work := make(chan Credential)
+	var wg sync.WaitGroup
+	for i := 0; i < min(opts.Concurrency, maxConcurrency, max(len(creds), 1)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cred := range work {
+				wait, ok := state.reserve(cred.Username)
+				if !ok {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					continue
+				case <-time.After(wait):
+				}
+				protocolstate.RateLimitTake(executionId)
+				if ctx.Err() != nil {
+					continue
+				}
+				valid, err := auth.login(ctx, host, port, cred.Username, cred.Password)
+				if err != nil && auth.isRejected(err) {
+					err = nil
+				}
+				state.record(cred, valid, err)
+			}
+		}()
+	}
+dispatch:
+	for _, cred := range creds {
+		if state.isStopped() {
+			break
+		}
+		select {
+		case work <- cred:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	resp := state.resp
+	slices.Sort(resp.LockedOut)
+	return resp
+}
+
+// isStopped checks if no more credentials should be tried
+func (s *bruteState) isStopped() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.stopped
+}
+
+// reserve returns the time to wait before trying a credential of username
+// (honoring the delay between attempts of the same username) or false if the
+// credential should be skipped
+func (s *bruteState) reserve(username string) (time.Duration, bool) {
+	s.Lock()
+	defer s.Unlock()
+	acc, ok := s.accounts[username]
+	if !ok {
+		acc = &account{}
+		s.accounts[username] = acc
+	}
+	if s.stopped || acc.lockedOut {
+		return 0, false
+	}
+	now := time.Now()
+	next := now
+	if acc.nextAttempt.After(now) {
+		next = acc.nextAttempt
+	}
+	acc.nextAttempt = next.Add(time.Duration(s.opts.Delay) * time.Millisecond)
+	return next.Sub(now), true
+}
+
+// record updates the state with the result of a credential
+func (s *bruteState) record(cred Credential, valid bool, err error) {
+	s.Lock()
+	defer s.Unlock()
+	acc := s.accounts[cred.Username]
+	s.resp.Attempts++
+	switch {
+	case valid:
+		acc.lastError, acc.repeated = "", 0
+		s.resp.Found = true
+		s.resp.Valid = append(s.resp.Valid, cred)
+		if !s.opts.ContinueOnSuccess {
+			s.stopped = true
+		}
+	case err == nil:
+		// rejected credentials reset the repeated error counter
+		acc.lastError, acc.repeated = "", 0
+	case isTransportError(err):
+		// the host is unreachable or slow, this says nothing about the account
+	default:
+		message := err.Error()
+		if message == acc.lastError {
+			acc.repeated++
+		} else {
+			acc.lastError, acc.repeated = message, 1
+		}
+		if acc.repeated >= s.opts.LockoutThreshold || isLockout(err) {
+			if !acc.lockedOut {
+				acc.lockedOut = true
+				s.resp.LockedOut = append(s.resp.LockedOut, cred.Username)
+			}
+		}
+	}
+}
+
+// isLockout checks if err is an explicit account lockout signal
+func isLockout(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, marker := range lockoutMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransportError checks if err is a network error (ex: timeout, refused
+// connection) rather than an answer of the server to the credentials
+func isTransportError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) || protocolstate.IsConnectionRefused(err) || protocolstate.IsConnectionReset(err) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, marker := range transportMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
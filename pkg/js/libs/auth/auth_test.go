@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// fakeService is an auth service with per user behavior
+type fakeService struct {
+	sync.Mutex
+	passwords map[string]string
+	// errors returned for every attempt of a user
+	errors   map[string]error
+	attempts map[string][]time.Time
+	running  atomic.Int32
+	peak     atomic.Int32
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		passwords: map[string]string{"admin": "secret", "root": "toor"},
+		errors: map[string]error{
+			"locked":   errors.New("login failed: account is locked"),
+			"flaky":    errors.New("read: connection reset by peer"),
+			"busy":     errors.New("auth: service unavailable"),
+			"rejected": errors.New("auth: invalid credentials"),
+		},
+		attempts: map[string][]time.Time{},
+	}
+}
+
+func (f *fakeService) authenticator() authenticator {
+	return authenticator{
+		login: func(ctx context.Context, host string, port int, username, password string) (bool, error) {
+			n := f.running.Add(1)
+			defer f.running.Add(-1)
+			for {
+				peak := f.peak.Load()
+				if n <= peak || f.peak.CompareAndSwap(peak, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+
+			f.Lock()
+			defer f.Unlock()
+			f.attempts[username] = append(f.attempts[username], time.Now())
+			if err, ok := f.errors[username]; ok {
+				return false, err
+			}
+			return f.passwords[username] == password, nil
+		},
+		rejected: []string{"invalid credentials"},
+	}
+}
+
+// credentials returns a credential per password of each user
+func credentials(users []string, passwords ...string) []Credential {
+	var creds []Credential
+	for _, password := range passwords {
+		for _, user := range users {
+			creds = append(creds, Credential{Username: user, Password: password})
+		}
+	}
+	return creds
+}
+
+func TestBruteForceEarlyStop(t *testing.T) {
+	ctx := jstest.Context(t)
+	service := newFakeService()
+
+	creds := credentials([]string{"admin"}, "admin", "password", "123456", "secret", "letmein", "qwerty")
+	resp := bruteForce(ctx, service.authenticator(), "127.0.0.1", 22, creds, BruteForceOptions{Concurrency: 1})
+	want := BruteForceResponse{Found: true, Valid: []Credential{{Username: "admin", Password: "secret"}}, Attempts: 4}
+	if !reflect.DeepEqual(resp, want) {
+		t.Fatalf("expected %+v, got %+v", want, resp)
+	}
+
+	resp = bruteForce(ctx, service.authenticator(), "127.0.0.1", 22, credentials([]string{"admin", "root"}, "secret", "toor"), BruteForceOptions{ContinueOnSuccess: true})
+	if !resp.Found || len(resp.Valid) != 2 || resp.Attempts != 4 {
+		t.Fatalf("expected both valid credentials with all attempts, got %+v", resp)
+	}
+}
+
+func TestBruteForceLockout(t *testing.T) {
+	ctx := jstest.Context(t)
+	service := newFakeService()
+
+	passwords := []string{"1", "2", "3", "4", "5", "6"}
+	creds := credentials([]string{"locked", "busy", "flaky", "rejected", "admin"}, passwords...)
+	resp := bruteForce(ctx, service.authenticator(), "127.0.0.1", 22, creds, BruteForceOptions{Concurrency: 1})
+	if resp.Found {
+		t.Fatalf("expected no valid credentials, got %+v", resp.Valid)
+	}
+	// network errors (flaky) are not lockout signals
+	if want := []string{"busy", "locked"}; !reflect.DeepEqual(resp.LockedOut, want) {
+		t.Fatalf("expected locked out users %v, got %v", want, resp.LockedOut)
+	}
+	for user, want := range map[string]int{"locked": 1, "busy": defaultLockoutThreshold, "flaky": len(passwords), "rejected": len(passwords), "admin": len(passwords)} {
+		if got := len(service.attempts[user]); got != want {
+			t.Fatalf("expected %d attempts for %s, got %d", want, user, got)
+		}
+	}
+	if want := 1 + defaultLockoutThreshold + 3*len(passwords); resp.Attempts != want {
+		t.Fatalf("expected %d attempts, got %d", want, resp.Attempts)
+	}
+}
+
+func TestBruteForceDelayAndConcurrency(t *testing.T) {
+	ctx := jstest.Context(t)
+	service := newFakeService()
+
+	delay := 30 * time.Millisecond
+	var users []string
+	for i := 0; i < 8; i++ {
+		users = append(users, fmt.Sprintf("user%d", i))
+	}
+	creds := credentials(users, "a", "b", "c")
+	resp := bruteForce(ctx, service.authenticator(), "127.0.0.1", 22, creds, BruteForceOptions{Concurrency: 4, Delay: int(delay / time.Millisecond)})
+	if resp.Attempts != len(creds) {
+		t.Fatalf("expected %d attempts, got %d", len(creds), resp.Attempts)
+	}
+	if got := service.peak.Load(); got > 4 {
+		t.Fatalf("expected at most 4 concurrent attempts, got %d", got)
+	}
+	for _, user := range users {
+		attempts := service.attempts[user]
+		for i := 1; i < len(attempts); i++ {
+			// allow for timer granularity
+			if gap := attempts[i].Sub(attempts[i-1]); gap < delay-5*time.Millisecond {
+				t.Fatalf("expected attempts of %s to be %s apart, got %s", user, delay, gap)
+			}
+		}
+	}
+}
+
+func TestBruteForceScanDeadline(t *testing.T) {
+	executionId := jstest.Init(t)
+	ctx := jstest.ExecutionContext(executionId)
+	service := newFakeService()
+
+	// a single user with a delay would take 10s to try every password
+	creds := credentials([]string{"rejected"}, "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11")
+	protocolstate.SetScanDeadline(executionId, time.Now().Add(300*time.Millisecond))
+	start := time.Now()
+	resp := bruteForce(ctx, service.authenticator(), "127.0.0.1", 22, creds, BruteForceOptions{Delay: 1000})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected brute force to stop at the scan deadline, took %s", elapsed)
+	}
+	if resp.Attempts != 1 || len(service.attempts["rejected"]) != 1 {
+		t.Fatalf("expected a single attempt before the deadline, got %+v", resp)
+	}
+}
+
+func TestBruteForceProtocol(t *testing.T) {
+	ctx := jstest.Context(t)
+	service := newFakeService()
+	protocols["fake"] = service.authenticator()
+	t.Cleanup(func() { delete(protocols, "fake") })
+
+	resp, err := BruteForce(ctx, "FAKE", "127.0.0.1", 22, credentials([]string{"root"}, "root", "toor"), BruteForceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Found || resp.Valid[0].Password != "toor" {
+		t.Fatalf("expected root:toor to be found, got %+v", resp)
+	}
+
+	_, err = BruteForce(ctx, "telnet", "127.0.0.1", 23, nil, BruteForceOptions{})
+	if err == nil || !strings.Contains(err.Error(), "supported: fake, mssql, mysql, postgres, redis, ssh") {
+		t.Fatalf("expected unsupported protocol error, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package bytes
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+)
+
+var (
+	// castagnoliTable is the crc-32c table (ex: iscsi, sctp)
+	castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// CRC16Modbus returns the CRC-16/MODBUS checksum of data.
+// the checksum is sent in little endian order at the end of modbus rtu frames.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const frame = [0x01, 0x03, 0x00, 0x00, 0x00, 0x0a];
+// const crc = bytes.CRC16Modbus(frame); // 0xcdc5
+// const packet = frame.concat(Array.from(bytes.PackUint16LE(crc)));
+// ```
+func CRC16Modbus(data []byte) int {
+	return int(crc16Reflected(data, 0xa001, 0xffff, 0x0000))
+}
+
+// CRC16DNP returns the CRC-16/DNP checksum of data.
+// the checksum is sent in little endian order after each dnp3 block.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const crc = bytes.CRC16DNP([0x05, 0x64, 0x05, 0xc0, 0x01, 0x00, 0x00, 0x04]); // 0x21e9
+// ```
+func CRC16DNP(data []byte) int {
+	return int(crc16Reflected(data, 0xa6bc, 0x0000, 0xffff))
+}
+
+// CRC16CCITT returns the CRC-16/CCITT-FALSE checksum of data
+// (polynomial 0x1021 and initial value 0xffff).
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const crc = bytes.CRC16CCITT(bytes.FromString('123456789')); // 0x29b1
+// ```
+func CRC16CCITT(data []byte) int {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return int(crc)
+}
+
+// CRC32 returns the CRC-32 (ieee) checksum of data.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const crc = bytes.CRC32(bytes.FromString('123456789')); // 0xcbf43926
+// ```
+func CRC32(data []byte) int {
+	return int(crc32.ChecksumIEEE(data))
+}
+
+// CRC32C returns the CRC-32C (castagnoli) checksum of data.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const crc = bytes.CRC32C(bytes.FromString('123456789')); // 0xe3069283
+// ```
+func CRC32C(data []byte) int {
+	return int(crc32.Checksum(data, castagnoliTable))
+}
+
+// InternetChecksum returns the 16 bit ones' complement checksum of data
+// used by ip, icmp, tcp and udp headers (rfc 1071).
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const checksum = bytes.InternetChecksum([0x08, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x01]);
+// ```
+func InternetChecksum(data []byte) int {
+	var sum uint32
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i : i+2]))
+	}
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	return int(^uint16(sum))
+}
+
+// FromString returns the bytes of the given string.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const data = bytes.FromString('hello');
+// ```
+func FromString(data string) []byte {
+	return []byte(data)
+}
+
+// PackUint16BE packs value as a big endian unsigned 16 bit integer.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const data = bytes.PackUint16BE(502); // [0x01, 0xf6]
+// ```
+func PackUint16BE(value int) ([]byte, error) {
+	if value < 0 || value > math.MaxUint16 {
+		return nil, outOfRange(value, 16)
+	}
+	return binary.BigEndian.AppendUint16(nil, uint16(value)), nil
+}
+
+// PackUint16LE packs value as a little endian unsigned 16 bit integer.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const data = bytes.PackUint16LE(502); // [0xf6, 0x01]
+// ```
+func PackUint16LE(value int) ([]byte, error) {
+	if value < 0 || value > math.MaxUint16 {
+		return nil, outOfRange(value, 16)
+	}
+	return binary.LittleEndian.AppendUint16(nil, uint16(value)), nil
+}
+
+// PackUint32BE packs value as a big endian unsigned 32 bit integer.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const data = bytes.PackUint32BE(0xcbf43926);
+// ```
+func PackUint32BE(value int) ([]byte, error) {
+	if value < 0 || value > math.MaxUint32 {
+		return nil, outOfRange(value, 32)
+	}
+	return binary.BigEndian.AppendUint32(nil, uint32(value)), nil
+}
+
+// PackUint32LE packs value as a little endian unsigned 32 bit integer.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const data = bytes.PackUint32LE(0xcbf43926);
+// ```
+func PackUint32LE(value int) ([]byte, error) {
+	if value < 0 || value > math.MaxUint32 {
+		return nil, outOfRange(value, 32)
+	}
+	return binary.LittleEndian.AppendUint32(nil, uint32(value)), nil
+}
+
+// UnpackUint16BE unpacks the big endian unsigned 16 bit integer at offset of data.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const length = bytes.UnpackUint16BE([0x00, 0x00, 0x00, 0x06], 2); // 6
+// ```
+func UnpackUint16BE(data []byte, offset int) (int, error) {
+	if err := checkBounds(data, offset, 2); err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(data[offset:])), nil
+}
+
+// UnpackUint16LE unpacks the little endian unsigned 16 bit integer at offset of data.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const crc = bytes.UnpackUint16LE([0xc5, 0xcd], 0); // 0xcdc5
+// ```
+func UnpackUint16LE(data []byte, offset int) (int, error) {
+	if err := checkBounds(data, offset, 2); err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint16(data[offset:])), nil
+}
+
+// UnpackUint32BE unpacks the big endian unsigned 32 bit integer at offset of data.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const value = bytes.UnpackUint32BE([0xcb, 0xf4, 0x39, 0x26], 0); // 0xcbf43926
+// ```
+func UnpackUint32BE(data []byte, offset int) (int, error) {
+	if err := checkBounds(data, offset, 4); err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(data[offset:])), nil
+}
+
+// UnpackUint32LE unpacks the little endian unsigned 32 bit integer at offset of data.
+// @example
+// ```javascript
+// const bytes = require('nuclei/bytes');
+// const value = bytes.UnpackUint32LE([0x26, 0x39, 0xf4, 0xcb], 0); // 0xcbf43926
+// ```
+func UnpackUint32LE(data []byte, offset int) (int, error) {
+	if err := checkBounds(data, offset, 4); err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint32(data[offset:])), nil
+}
+
+// crc16Reflected computes a reflected crc-16 with the reflected polynomial poly
+func crc16Reflected(data []byte, poly uint16, init uint16, xorOut uint16) uint16 {
+	crc := init
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&0x0001 != 0 {
+				crc = crc>>1 ^ poly
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc ^ xorOut
+}
+
+// outOfRange returns an error for a value not fitting in an unsigned integer of given bits
+func outOfRange(value int, bits int) error {
+	return fmt.Errorf("value %d out of range of uint%d", value, bits)
+}
+
+// checkBounds returns an error if size bytes at offset are not within data
+func checkBounds(data []byte, offset int, size int) error {
+	if offset < 0 || offset+size > len(data) {
+		return fmt.Errorf("cannot read %d bytes at offset %d of %d bytes", size, offset, len(data))
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package bytes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksums(t *testing.T) {
+	check := []byte("123456789")
+	modbusFrame := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0a}
+	dnpHeader := []byte{0x05, 0x64, 0x05, 0xc0, 0x01, 0x00, 0x00, 0x04}
+	// icmp echo request header with id and sequence 1
+	icmpHeader := []byte{0x08, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x01}
+
+	tests := []struct {
+		name     string
+		checksum func([]byte) int
+		data     []byte
+		expected int
+	}{
+		{"crc16-modbus check", CRC16Modbus, check, 0x4b37},
+		{"crc16-modbus frame", CRC16Modbus, modbusFrame, 0xcdc5},
+		{"crc16-modbus empty", CRC16Modbus, nil, 0xffff},
+		{"crc16-dnp check", CRC16DNP, check, 0xea82},
+		{"crc16-dnp header", CRC16DNP, dnpHeader, 0x21e9},
+		{"crc16-ccitt check", CRC16CCITT, check, 0x29b1},
+		{"crc16-ccitt empty", CRC16CCITT, nil, 0xffff},
+		{"crc32 check", CRC32, check, 0xcbf43926},
+		{"crc32 empty", CRC32, nil, 0},
+		{"crc32c check", CRC32C, check, 0xe3069283},
+		{"internet checksum icmp", InternetChecksum, icmpHeader, 0xf7fd},
+		{"internet checksum odd", InternetChecksum, []byte{0x01, 0x02, 0x03}, 0xfbfd},
+		{"internet checksum empty", InternetChecksum, nil, 0xffff},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.checksum(test.data))
+		})
+	}
+}
+
+func TestInternetChecksumVerifies(t *testing.T) {
+	// a header containing its checksum sums to zero
+	header := []byte{0x08, 0x00, 0x00, 0x00, 0x12, 0x34, 0x00, 0x2a}
+	checksum, err := PackUint16BE(InternetChecksum(header))
+	require.NoError(t, err)
+	copy(header[2:], checksum)
+	require.Equal(t, 0, InternetChecksum(header))
+}
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		name     string
+		pack     func(int) ([]byte, error)
+		value    int
+		expected []byte
+	}{
+		{"uint16 be", PackUint16BE, 0x01f6, []byte{0x01, 0xf6}},
+		{"uint16 le", PackUint16LE, 0x01f6, []byte{0xf6, 0x01}},
+		{"uint16 be max", PackUint16BE, 0xffff, []byte{0xff, 0xff}},
+		{"uint32 be", PackUint32BE, 0xcbf43926, []byte{0xcb, 0xf4, 0x39, 0x26}},
+		{"uint32 le", PackUint32LE, 0xcbf43926, []byte{0x26, 0x39, 0xf4, 0xcb}},
+		{"uint32 le zero", PackUint32LE, 0, []byte{0x00, 0x00, 0x00, 0x00}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.pack(test.value)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, got)
+		})
+	}
+
+	for _, value := range []int{-1, 0x10000} {
+		_, err := PackUint16BE(value)
+		require.Error(t, err)
+		_, err = PackUint16LE(value)
+		require.Error(t, err)
+	}
+	for _, value := range []int{-1, 0x100000000} {
+		_, err := PackUint32BE(value)
+		require.Error(t, err)
+		_, err = PackUint32LE(value)
+		require.Error(t, err)
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	data := []byte{0x00, 0xcb, 0xf4, 0x39, 0x26}
+	tests := []struct {
+		name     string
+		unpack   func([]byte, int) (int, error)
+		offset   int
+		expected int
+	}{
+		{"uint16 be", UnpackUint16BE, 1, 0xcbf4},
+		{"uint16 le", UnpackUint16LE, 1, 0xf4cb},
+		{"uint16 be end", UnpackUint16BE, 3, 0x3926},
+		{"uint32 be", UnpackUint32BE, 1, 0xcbf43926},
+		{"uint32 le", UnpackUint32LE, 1, 0x2639f4cb},
+		{"uint32 be start", UnpackUint32BE, 0, 0x00cbf439},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.unpack(data, test.offset)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, got)
+		})
+	}
+
+	for _, offset := range []int{-1, 4} {
+		_, err := UnpackUint16BE(data, offset)
+		require.Error(t, err)
+		_, err = UnpackUint16LE(data, offset)
+		require.Error(t, err)
+	}
+	for _, offset := range []int{-1, 2} {
+		_, err := UnpackUint32BE(data, offset)
+		require.Error(t, err)
+		_, err = UnpackUint32LE(data, offset)
+		require.Error(t, err)
+	}
+	_, err := UnpackUint32BE(nil, 0)
+	require.Error(t, err)
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	frame := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0a}
+	crc, err := PackUint16LE(CRC16Modbus(frame))
+	require.NoError(t, err)
+	packet := append(frame, crc...)
+	require.Equal(t, []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0a, 0xc5, 0xcd}, packet)
+
+	got, err := UnpackUint16LE(packet, len(frame))
+	require.NoError(t, err)
+	require.Equal(t, CRC16Modbus(frame), got)
+	// crc over a modbus frame including its crc is zero
+	require.Equal(t, 0, CRC16Modbus(packet))
+}
@@ -0,0 +1,175 @@
+package cwmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of each soap request
+	requestTimeout = 10 * time.Second
+
+	cwmpNamespacePrefix = "urn:dslforum-org:cwmp-"
+
+	getRPCMethodsEnvelope = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:cwmp="urn:dslforum-org:cwmp-1-0">
+<soap:Header><cwmp:ID soap:mustUnderstand="1">1</cwmp:ID></soap:Header>
+<soap:Body><cwmp:GetRPCMethods/></soap:Body>
+</soap:Envelope>`
+)
+
+type (
+	// DetectResponse is the response from the Detect function.
+	// this is returned by Detect function.
+	// @example
+	// ```javascript
+	// const cwmp = require('nuclei/cwmp');
+	// const result = cwmp.Detect('acme.com', 7547, '/');
+	// log(toJSON(result));
+	// ```
+	DetectResponse struct {
+		// IsCWMP is true if the endpoint responded with a cwmp soap envelope
+		IsCWMP bool
+		// AuthRequired is true if the endpoint requires authentication (401)
+		AuthRequired bool
+		// StatusCode is the http status code of the response
+		StatusCode int
+		// Server is the value of Server header (if any)
+		Server string
+		// Methods contains rpc methods supported by the endpoint (GetRPCMethodsResponse)
+		Methods []string
+		// Fault is the soap/cwmp fault string (if any)
+		Fault string
+		// TLS is true if the endpoint was reached over https
+		TLS bool
+	}
+)
+
+// Detect sends a TR-069 (CWMP) GetRPCMethods SOAP request to the given host,
+// port and path and parses the response to confirm a CWMP ACS/CPE endpoint and
+// report supported rpc methods. HTTPS is tried first and plaintext HTTP is used
+// as fallback. Endpoints requiring authentication are reported with AuthRequired.
+// @example
+// ```javascript
+// const cwmp = require('nuclei/cwmp');
+// const result = cwmp.Detect('acme.com', 7547, '/');
+// log(result.IsCWMP, result.Methods);
+// ```
+func Detect(ctx context.Context, host string, port int, path string) (DetectResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizeddetect(executionId, host, port, path)
+}
+
+// @memo
+func detect(executionId string, host string, port int, path string) (DetectResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return DetectResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	client, err := utils.NewHTTPClient(executionId, requestTimeout)
+	if err != nil {
+		return DetectResponse{}, err
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	resp, err := sendGetRPCMethods(executionId, client, "https://"+address+path)
+	if err == nil {
+		resp.TLS = true
+		return resp, nil
+	}
+	// fallback to plaintext http
+	return sendGetRPCMethods(executionId, client, "http://"+address+path)
+}
+
+// sendGetRPCMethods posts GetRPCMethods envelope to given url and parses the response
+func sendGetRPCMethods(executionId string, client *http.Client, url string) (DetectResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(getRPCMethodsEnvelope))
+	if err != nil {
+		return DetectResponse{}, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", "")
+
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return DetectResponse{}, err
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+
+	resp := DetectResponse{
+		StatusCode:   httpResp.StatusCode,
+		Server:       httpResp.Header.Get("Server"),
+		AuthRequired: httpResp.StatusCode == http.StatusUnauthorized,
+	}
+	body, err := utils.ReadAll(executionId, httpResp.Body)
+	if err != nil {
+		return resp, err
+	}
+	if resp.AuthRequired || !bytes.Contains(body, []byte(cwmpNamespacePrefix)) {
+		return resp, nil
+	}
+	resp.IsCWMP = true
+	resp.Methods, resp.Fault, err = parseEnvelope(body)
+	return resp, err
+}
+
+// parseEnvelope walks the soap envelope and extracts
+// MethodList strings and fault string
+func parseEnvelope(body []byte) ([]string, string, error) {
+	var methods []string
+	var fault string
+	var stack []string
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return methods, fault, fmt.Errorf("could not parse soap envelope: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) < 2 {
+				continue
+			}
+			value := strings.TrimSpace(string(t))
+			if value == "" {
+				continue
+			}
+			switch {
+			case stack[len(stack)-2] == "MethodList" && stack[len(stack)-1] == "string":
+				methods = append(methods, value)
+			case stack[len(stack)-1] == "FaultString" || stack[len(stack)-1] == "faultstring":
+				if fault == "" {
+					fault = value
+				}
+			}
+		}
+	}
+	return methods, fault, nil
+}
@@ -0,0 +1,34 @@
+// Warning - This is generated code
+package cwmp
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[DetectResponse]("cwmp.detect", 4)
+}
+
+func memoizeddetect(executionId string, host string, port int, path string) (DetectResponse, error) {
+	hash := "cwmp.detect" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "cwmp.detect" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (DetectResponse, error) {
+			return detect(executionId, host, port, path)
+		})
+	})
+	if err != nil {
+		return DetectResponse{}, err
+	}
+	if value, ok := v.(DetectResponse); ok {
+		return value, nil
+	}
+
+	return DetectResponse{}, errors.New("could not convert cached result")
+}
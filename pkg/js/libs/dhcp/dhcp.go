@@ -0,0 +1,238 @@
+package dhcp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	serverPort = 67
+	clientPort = 68
+
+	opBootRequest = 1
+	opBootReply   = 2
+
+	msgTypeDiscover = 1
+	msgTypeOffer    = 2
+
+	optSubnetMask       = 1
+	optRouter           = 3
+	optDNS              = 6
+	optLeaseTime        = 51
+	optMessageType      = 53
+	optServerIdentifier = 54
+	optParamRequestList = 55
+	optEnd              = 255
+)
+
+var (
+	magicCookie = []byte{0x63, 0x82, 0x53, 0x63}
+)
+
+type (
+	// DHCPOffer is a DHCPOFFER received in response to a DHCPDISCOVER.
+	// this is returned by Discover function.
+	// @example
+	// ```javascript
+	// const dhcp = require('nuclei/dhcp');
+	// const offers = dhcp.Discover('eth0');
+	// log(toJSON(offers));
+	// ```
+	DHCPOffer struct {
+		// OfferedIP is the ip address offered to the client (yiaddr)
+		OfferedIP string
+		// SubnetMask is the offered subnet mask
+		SubnetMask string
+		// Gateway is the first router advertised in the offer
+		Gateway string
+		// DNS is the list of advertised dns servers
+		DNS []string
+		// ServerIdentifier is the address of the dhcp server that sent the offer
+		ServerIdentifier string
+		// LeaseTime is the offered lease time in seconds
+		LeaseTime int
+	}
+)
+
+// Discover broadcasts a DHCPDISCOVER and collects DHCPOFFER responses
+// until the read timeout of the execution is reached. The argument can either be
+// a network interface name (ex: eth0) through which the request is broadcast using its
+// hardware address or a host/ip to which the DHCPDISCOVER is sent directly instead of broadcast.
+// This can be used to detect rogue dhcp servers on internal networks.
+//
+// Note: this function binds to udp port 68 and sends broadcast datagrams which
+// requires root privileges (or CAP_NET_BIND_SERVICE and CAP_NET_RAW on linux).
+// @example
+// ```javascript
+// const dhcp = require('nuclei/dhcp');
+// const offers = dhcp.Discover('eth0');
+// log(toJSON(offers));
+// ```
+func Discover(ctx context.Context, ifaceOrHost string) ([]DHCPOffer, error) {
+	executionId := ctx.Value("executionId").(string)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+
+	var hwAddr net.HardwareAddr
+	var ifaceName string
+	dst := &net.UDPAddr{IP: net.IPv4bcast, Port: serverPort}
+	if iface, err := net.InterfaceByName(ifaceOrHost); err == nil {
+		hwAddr = iface.HardwareAddr
+		ifaceName = iface.Name
+	} else if ifaceOrHost != "" {
+		if !protocolstate.IsHostAllowed(executionId, ifaceOrHost) {
+			// host is not valid according to network policy
+			return nil, protocolstate.ErrHostDenied.Msgf(ifaceOrHost)
+		}
+		ip := net.ParseIP(ifaceOrHost)
+		if ip == nil {
+			dnsData, err := dialer.Fastdialer.GetDNSData(ifaceOrHost)
+			if err != nil {
+				return nil, err
+			}
+			if len(dnsData.A) == 0 {
+				return nil, fmt.Errorf("could not resolve %s", ifaceOrHost)
+			}
+			ip = net.ParseIP(dnsData.A[0])
+		}
+		dst.IP = ip
+	}
+	if len(hwAddr) != 6 {
+		hwAddr = randomHardwareAddr(executionId)
+	}
+
+	var conn *protocolstate.UDPConn
+	if ifaceName != "" {
+		// broadcast through the given interface only
+		conn, err = protocolstate.ListenUDPInterface(executionId, ifaceName, clientPort, true)
+	} else {
+		conn, err = protocolstate.ListenUDP(executionId, fmt.Sprintf("0.0.0.0:%d", clientPort), true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	xid := make([]byte, 4)
+	protocolstate.RandomBytes(executionId, xid)
+	if _, err := conn.WriteToUDP(buildDiscover(xid, hwAddr), dst); err != nil {
+		return nil, err
+	}
+
+	// collect offers until timeout is reached
+	_ = conn.SetReadDeadline(protocolstate.GetDeadline(executionId, protocolstate.GetTimeouts(executionId).TcpReadTimeout))
+	var offers []DHCPOffer
+	buff := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFromUDP(buff)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return offers, err
+		}
+		offer, ok := parseOffer(buff[:n], xid)
+		if ok {
+			offers = append(offers, offer)
+		}
+	}
+	return offers, nil
+}
+
+// buildDiscover builds a DHCPDISCOVER packet with broadcast flag set
+func buildDiscover(xid []byte, hwAddr net.HardwareAddr) []byte {
+	packet := make([]byte, 240)
+	packet[0] = opBootRequest
+	packet[1] = 1 // htype: ethernet
+	packet[2] = 6 // hlen
+	copy(packet[4:8], xid)
+	binary.BigEndian.PutUint16(packet[10:12], 0x8000) // broadcast flag
+	copy(packet[28:34], hwAddr)
+	copy(packet[236:240], magicCookie)
+
+	packet = append(packet,
+		optMessageType, 1, msgTypeDiscover,
+		optParamRequestList, 4, optSubnetMask, optRouter, optDNS, optServerIdentifier,
+		optEnd,
+	)
+	// some servers drop packets smaller than minimum bootp size
+	if len(packet) < 300 {
+		packet = append(packet, make([]byte, 300-len(packet))...)
+	}
+	return packet
+}
+
+// parseOffer parses a DHCPOFFER matching given transaction id
+func parseOffer(data []byte, xid []byte) (DHCPOffer, bool) {
+	offer := DHCPOffer{}
+	if len(data) < 240 || data[0] != opBootReply || string(data[4:8]) != string(xid) || string(data[236:240]) != string(magicCookie) {
+		return offer, false
+	}
+	offer.OfferedIP = net.IP(data[16:20]).String()
+
+	isOffer := false
+	options := data[240:]
+	for i := 0; i < len(options); {
+		code := options[i]
+		if code == optEnd {
+			break
+		}
+		if code == 0 {
+			// pad
+			i++
+			continue
+		}
+		if i+1 >= len(options) {
+			break
+		}
+		length := int(options[i+1])
+		if i+2+length > len(options) {
+			break
+		}
+		value := options[i+2 : i+2+length]
+		switch code {
+		case optMessageType:
+			isOffer = length == 1 && value[0] == msgTypeOffer
+		case optSubnetMask:
+			if length == 4 {
+				offer.SubnetMask = net.IP(value).String()
+			}
+		case optRouter:
+			if length >= 4 {
+				offer.Gateway = net.IP(value[:4]).String()
+			}
+		case optDNS:
+			for j := 0; j+4 <= length; j += 4 {
+				offer.DNS = append(offer.DNS, net.IP(value[j:j+4]).String())
+			}
+		case optServerIdentifier:
+			if length == 4 {
+				offer.ServerIdentifier = net.IP(value).String()
+			}
+		case optLeaseTime:
+			if length == 4 {
+				offer.LeaseTime = int(binary.BigEndian.Uint32(value))
+			}
+		}
+		i += 2 + length
+	}
+	return offer, isOffer
+}
+
+// randomHardwareAddr returns a random locally administered unicast mac address
+func randomHardwareAddr(executionId string) net.HardwareAddr {
+	addr := make(net.HardwareAddr, 6)
+	protocolstate.RandomBytes(executionId, addr)
+	addr[0] = (addr[0] | 0x02) & 0xfe
+	return addr
+}
@@ -0,0 +1,193 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	miekgdns "github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/retryabledns"
+)
+
+var (
+	// recordTypes are the record types supported by Resolve
+	recordTypes = map[string]uint16{
+		"A":     miekgdns.TypeA,
+		"AAAA":  miekgdns.TypeAAAA,
+		"CNAME": miekgdns.TypeCNAME,
+		"MX":    miekgdns.TypeMX,
+		"TXT":   miekgdns.TypeTXT,
+		"NS":    miekgdns.TypeNS,
+	}
+)
+
+type (
+	// CachedAnswersResponse is the response from the CachedAnswers function.
+	// this is returned by CachedAnswers function.
+	// @example
+	// ```javascript
+	// const dns = require('nuclei/dns');
+	// const answers = dns.CachedAnswers('acme.com');
+	// log(toJSON(answers));
+	// ```
+	CachedAnswersResponse struct {
+		// Host is the hostname of the answers
+		Host string
+		// Cached is true if the host was resolved during the execution
+		Cached bool
+		// A are the ipv4 addresses of the host
+		A []string
+		// AAAA are the ipv6 addresses of the host
+		AAAA []string
+		// CNAME is the cname chain of the host in the order of the answers
+		CNAME []string
+	}
+)
+
+// Resolve resolves the records of given type (A, AAAA, CNAME, MX, TXT, NS)
+// of host using the resolvers configured for the execution (-r, -system-resolvers).
+// Results are memoized so repeated lookups of the same name are not sent again.
+// An empty list is returned for names without records of the given type.
+// @example
+// ```javascript
+// const dns = require('nuclei/dns');
+// const ips = dns.Resolve('acme.com', 'A');
+// const txt = dns.Resolve('acme.com', 'TXT');
+// log(toJSON(ips), toJSON(txt));
+// ```
+func Resolve(ctx context.Context, host string, recordType string) ([]string, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedresolve(executionId, strings.TrimSuffix(strings.ToLower(host), "."), strings.ToUpper(recordType))
+}
+
+// @memo(nodisk)
+func resolve(executionId string, host string, recordType string) ([]string, error) {
+	queryType, ok := recordTypes[recordType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+	data, err := query(executionId, host, queryType)
+	if err != nil {
+		return nil, err
+	}
+	return records(data, queryType), nil
+}
+
+// ReversePTR resolves the hostnames of given ipv4 or ipv6 address with a PTR
+// lookup (in-addr.arpa / ip6.arpa) using the resolvers configured for the execution.
+// An empty list is returned when the address has no PTR record while an error
+// is returned if the lookup itself fails (ex: SERVFAIL or timeout).
+// @example
+// ```javascript
+// const dns = require('nuclei/dns');
+// const names = dns.ReversePTR('93.184.216.34');
+// log(toJSON(names));
+// ```
+func ReversePTR(ctx context.Context, ip string) ([]string, error) {
+	executionId := ctx.Value("executionId").(string)
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid ip address %q", ip)
+	}
+	return memoizedreversePTR(executionId, parsed.String())
+}
+
+// @memo(nodisk)
+func reversePTR(executionId string, ip string) ([]string, error) {
+	name, err := miekgdns.ReverseAddr(ip)
+	if err != nil {
+		return nil, err
+	}
+	data, err := query(executionId, name, miekgdns.TypePTR)
+	if err != nil {
+		return nil, err
+	}
+	return records(data, miekgdns.TypePTR), nil
+}
+
+// CachedAnswers returns the A, AAAA and CNAME answers cached for host by the
+// dialer of the execution, that is the addresses network connections to host
+// (ex: rdp.IsRDP, net.Open) actually used. No query is sent: Cached is false
+// and the records are empty if host was not dialed during the execution.
+// @example
+// ```javascript
+// const dns = require('nuclei/dns');
+// const rdp = require('nuclei/rdp');
+// const isRDP = rdp.IsRDP('acme.com', 3389);
+// const answers = dns.CachedAnswers('acme.com');
+// log(toJSON(answers.A), toJSON(answers.CNAME));
+// ```
+func CachedAnswers(ctx context.Context, host string) (CachedAnswersResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	resp := CachedAnswersResponse{Host: host, A: []string{}, AAAA: []string{}, CNAME: []string{}}
+	data, ok, err := protocolstate.CachedDNSData(executionId, host)
+	if err != nil || !ok {
+		return resp, err
+	}
+	resp.Cached = true
+	resp.A = unique(data.A)
+	resp.AAAA = unique(data.AAAA)
+	// the chain is part of the answers of both the A and AAAA queries
+	resp.CNAME = unique(data.CNAME)
+	return resp, nil
+}
+
+// unique returns values without duplicates keeping their order
+func unique(values []string) []string {
+	result := []string{}
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
+// query sends a query of given type for name. Responses other than
+// NOERROR and NXDOMAIN are returned as lookup failures.
+func query(executionId string, name string, queryType uint16) (*retryabledns.DNSData, error) {
+	dialers, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	data, err := dialers.Resolver.Query(name, queryType)
+	if err != nil {
+		return nil, err
+	}
+	if data.StatusCodeRaw != miekgdns.RcodeSuccess && data.StatusCodeRaw != miekgdns.RcodeNameError {
+		return nil, fmt.Errorf("dns lookup of %s failed with %s", name, data.StatusCode)
+	}
+	return data, nil
+}
+
+// records returns the records of given type of a dns response
+func records(data *retryabledns.DNSData, queryType uint16) []string {
+	var values []string
+	switch queryType {
+	case miekgdns.TypeA:
+		values = data.A
+	case miekgdns.TypeAAAA:
+		values = data.AAAA
+	case miekgdns.TypeCNAME:
+		values = data.CNAME
+	case miekgdns.TypeMX:
+		values = data.MX
+	case miekgdns.TypeTXT:
+		values = data.TXT
+	case miekgdns.TypeNS:
+		values = data.NS
+	case miekgdns.TypePTR:
+		values = data.PTR
+	}
+	if values == nil {
+		// an empty array is returned to javascript instead of null
+		values = []string{}
+	}
+	return values
+}
@@ -0,0 +1,132 @@
+package dns
+
+import (
+	"net"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	miekgdns "github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// dnsServer starts a local dns server answering A and TXT queries of acme.com
+// and PTR queries of 192.0.2.10 and 2001:db8::10 (SERVFAIL for 192.0.2.99)
+// and returns its address along with the number of received queries
+func dnsServer(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queries := &atomic.Int32{}
+	handler := miekgdns.HandlerFunc(func(w miekgdns.ResponseWriter, r *miekgdns.Msg) {
+		queries.Add(1)
+		m := new(miekgdns.Msg)
+		m.SetReply(r)
+		question := r.Question[0]
+		header := miekgdns.RR_Header{Name: question.Name, Rrtype: question.Qtype, Class: miekgdns.ClassINET, Ttl: 60}
+		switch question.Name {
+		case "10.2.0.192.in-addr.arpa.", "0.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.":
+			if question.Qtype == miekgdns.TypePTR {
+				m.Answer = append(m.Answer, &miekgdns.PTR{Hdr: header, Ptr: "mail.acme.com."})
+			}
+		case "99.2.0.192.in-addr.arpa.":
+			m.Rcode = miekgdns.RcodeServerFailure
+		case "acme.com.":
+			switch question.Qtype {
+			case miekgdns.TypeA:
+				m.Answer = append(m.Answer,
+					&miekgdns.A{Hdr: header, A: net.ParseIP("192.0.2.10")},
+					&miekgdns.A{Hdr: header, A: net.ParseIP("192.0.2.11")})
+			case miekgdns.TypeTXT:
+				m.Answer = append(m.Answer, &miekgdns.TXT{Hdr: header, Txt: []string{"v=spf1 -all"}})
+			}
+		default:
+			m.Rcode = miekgdns.RcodeNameError
+		}
+		_ = w.WriteMsg(m)
+	})
+	server := &miekgdns.Server{PacketConn: conn, Handler: handler}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	return conn.LocalAddr().String(), queries
+}
+
+func TestResolve(t *testing.T) {
+	address, queries := dnsServer(t)
+	ctx := jstest.Context(t, func(options *types.Options) {
+		options.InternalResolversList = []string{address}
+	})
+
+	tests := []struct {
+		host       string
+		recordType string
+		want       []string
+	}{
+		{host: "acme.com", recordType: "A", want: []string{"192.0.2.10", "192.0.2.11"}},
+		{host: "acme.com", recordType: "txt", want: []string{"v=spf1 -all"}},
+		{host: "acme.com", recordType: "MX", want: []string{}},
+		{host: "missing.acme.com", recordType: "A", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.recordType, func(t *testing.T) {
+			got, err := Resolve(ctx, tt.host, tt.recordType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// repeated lookups are answered from the memoized result
+	sent := queries.Load()
+	if _, err := Resolve(ctx, "ACME.com.", "A"); err != nil {
+		t.Fatal(err)
+	}
+	if got := queries.Load(); got != sent {
+		t.Fatalf("expected memoized lookup, got %d new queries", got-sent)
+	}
+
+	if _, err := Resolve(ctx, "acme.com", "SRV"); err == nil {
+		t.Fatal("expected unsupported record type to be rejected")
+	}
+}
+
+func TestReversePTR(t *testing.T) {
+	address, _ := dnsServer(t)
+	ctx := jstest.Context(t, func(options *types.Options) {
+		options.InternalResolversList = []string{address}
+	})
+
+	tests := []struct {
+		name string
+		ip   string
+		want []string
+	}{
+		{name: "ipv4", ip: "192.0.2.10", want: []string{"mail.acme.com"}},
+		{name: "ipv6", ip: "2001:db8:0::10", want: []string{"mail.acme.com"}},
+		{name: "no ptr record", ip: "192.0.2.11", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReversePTR(ctx, tt.ip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := ReversePTR(ctx, "192.0.2.99"); err == nil {
+		t.Fatal("expected failed lookup to return an error")
+	}
+	if _, err := ReversePTR(ctx, "acme.com"); err == nil {
+		t.Fatal("expected invalid ip to be rejected")
+	}
+}
@@ -0,0 +1,49 @@
+// Warning - This is generated code
+package dns
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[[]string]("dns.resolve", 3)
+}
+
+func memoizedresolve(executionId string, host string, recordType string) ([]string, error) {
+	hash := "dns.resolve" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(recordType)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return resolve(executionId, host, recordType)
+	})
+	if err != nil {
+		return []string{}, err
+	}
+	if value, ok := v.([]string); ok {
+		return value, nil
+	}
+
+	return []string{}, errors.New("could not convert cached result")
+}
+
+func init() {
+	protocolstate.RegisterMemoized[[]string]("dns.reversePTR", 2)
+}
+
+func memoizedreversePTR(executionId string, ip string) ([]string, error) {
+	hash := "dns.reversePTR" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(ip)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return reversePTR(executionId, ip)
+	})
+	if err != nil {
+		return []string{}, err
+	}
+	if value, ok := v.([]string); ok {
+		return value, nil
+	}
+
+	return []string{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,258 @@
+package doh
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the whole query exchange
+	queryTimeout = 5 * time.Second
+	// default path of doh endpoints (rfc 8484)
+	defaultPath = "/dns-query"
+	// media type of dns messages (rfc 8484)
+	dnsMessageType = "application/dns-message"
+	// maximum size of a dns message
+	maxMessageSize = 65535
+)
+
+var (
+	errInvalidDNSMessage = errors.New("invalid dns message")
+	errHandshakeFailed   = errors.New("tls handshake failed")
+)
+
+type (
+	// IsDoHResponse is the response from the IsDoH function.
+	// this is returned by IsDoH function.
+	// @example
+	// ```javascript
+	// const doh = require('nuclei/doh');
+	// const resp = doh.IsDoH('dns.acme.com', 443, '/dns-query');
+	// log(toJSON(resp));
+	// ```
+	IsDoHResponse struct {
+		// IsDoH is true if the endpoint answered the query with a valid dns message
+		IsDoH bool
+		// StatusCode is the http status code of the response
+		StatusCode int
+		// ContentType is the content type of the response
+		ContentType string
+		// Rcode is the response code of the dns message (ex: NOERROR, REFUSED)
+		Rcode string
+	}
+
+	// IsDoTResponse is the response from the IsDoT function.
+	// this is returned by IsDoT function.
+	// @example
+	// ```javascript
+	// const doh = require('nuclei/doh');
+	// const resp = doh.IsDoT('dns.acme.com', 853);
+	// log(toJSON(resp));
+	// ```
+	IsDoTResponse struct {
+		// IsDoT is true if the server answered the query with a valid dns message
+		IsDoT bool
+		// Rcode is the response code of the dns message (ex: NOERROR, REFUSED)
+		Rcode string
+	}
+)
+
+// IsDoH checks if the given host and port expose a DNS-over-HTTPS endpoint
+// by sending a rfc 8484 POST query (root NS) to path (default: /dns-query)
+// and validating the dns message of the response body.
+// @example
+// ```javascript
+// const doh = require('nuclei/doh');
+// const resp = doh.IsDoH('dns.acme.com', 443, '/dns-query');
+// log(resp.IsDoH, resp.Rcode);
+// ```
+func IsDoH(ctx context.Context, host string, port int, path string) (IsDoHResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if path == "" {
+		path = defaultPath
+	}
+	return memoizedisDoH(executionId, host, port, path)
+}
+
+// @memo
+func isDoH(executionId string, host string, port int, path string) (IsDoHResponse, error) {
+	resp := IsDoHResponse{}
+	conn, err := dialTLS(executionId, host, port, "http/1.1")
+	if errors.Is(err, errHandshakeFailed) {
+		// not a tls service
+		return resp, nil
+	}
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	query, err := newQuery()
+	if err != nil {
+		return resp, err
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://"+net.JoinHostPort(host, strconv.Itoa(port))+path, bytes.NewReader(query))
+	if err != nil {
+		return resp, err
+	}
+	req.Header.Set("Content-Type", dnsMessageType)
+	req.Header.Set("Accept", dnsMessageType)
+	req.Header.Set("User-Agent", "nuclei")
+	if err := req.Write(conn); err != nil {
+		return resp, err
+	}
+	httpResp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		// not an http service
+		return resp, nil
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+	resp.StatusCode = httpResp.StatusCode
+	resp.ContentType = httpResp.Header.Get("Content-Type")
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, maxMessageSize))
+	if err != nil {
+		return resp, nil
+	}
+	msg, err := parseAnswer(query, body)
+	if err != nil {
+		return resp, nil
+	}
+	resp.IsDoH = true
+	resp.Rcode = dns.RcodeToString[msg.Rcode]
+	return resp, nil
+}
+
+// IsDoT checks if the given host and port expose a DNS-over-TLS server
+// (rfc 7858, usually on port 853) by sending a query (root NS) over tls
+// and validating the dns message of the response.
+// @example
+// ```javascript
+// const doh = require('nuclei/doh');
+// const resp = doh.IsDoT('dns.acme.com', 853);
+// log(resp.IsDoT, resp.Rcode);
+// ```
+func IsDoT(ctx context.Context, host string, port int) (IsDoTResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisDoT(executionId, host, port)
+}
+
+// @memo
+func isDoT(executionId string, host string, port int) (IsDoTResponse, error) {
+	resp := IsDoTResponse{}
+	// alpn is not offered since most servers predate the dot alpn id
+	conn, err := dialTLS(executionId, host, port, "")
+	if errors.Is(err, errHandshakeFailed) {
+		// not a tls service
+		return resp, nil
+	}
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	query, err := newQuery()
+	if err != nil {
+		return resp, err
+	}
+	// messages are prefixed with their length over tcp
+	if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(query))), query...)); err != nil {
+		return resp, err
+	}
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return resp, nil
+	}
+	answer := make([]byte, binary.BigEndian.Uint16(header))
+	if _, err := io.ReadFull(conn, answer); err != nil {
+		return resp, nil
+	}
+	msg, err := parseAnswer(query, answer)
+	if err != nil {
+		return resp, nil
+	}
+	resp.IsDoT = true
+	resp.Rcode = dns.RcodeToString[msg.Rcode]
+	return resp, nil
+}
+
+// dialTLS dials the given host and port and performs a tls handshake
+// offering alpn (if any) and using host as sni (unless it is an ip address)
+func dialTLS(executionId string, host string, port int, alpn string) (*tls.Conn, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), queryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}
+	if alpn != "" {
+		config.NextProtos = []string{alpn}
+	}
+	if net.ParseIP(host) == nil {
+		config.ServerName = host
+	}
+	tlsConn := tls.Client(utils.LimitConn(executionId, conn), config)
+	if err := tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: %w", errHandshakeFailed, err)
+	}
+	return tlsConn, nil
+}
+
+// newQuery returns a root NS query with id 0 (as recommended by rfc 8484)
+func newQuery() ([]byte, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(".", dns.TypeNS)
+	msg.Id = 0
+	return msg.Pack()
+}
+
+// parseAnswer parses data as the dns response to query
+func parseAnswer(query []byte, data []byte) (*dns.Msg, error) {
+	request := new(dns.Msg)
+	if err := request.Unpack(query); err != nil {
+		return nil, err
+	}
+	msg := new(dns.Msg)
+	if err := msg.Unpack(data); err != nil {
+		return nil, errInvalidDNSMessage
+	}
+	if !msg.Response || msg.Id != request.Id || msg.Opcode != dns.OpcodeQuery {
+		return nil, errInvalidDNSMessage
+	}
+	// servers may omit the question of error responses
+	if len(msg.Question) > 0 && (!strings.EqualFold(msg.Question[0].Name, request.Question[0].Name) || msg.Question[0].Qtype != request.Question[0].Qtype) {
+		return nil, errInvalidDNSMessage
+	}
+	return msg, nil
+}
@@ -0,0 +1,58 @@
+// Warning - This is generated code
+package doh
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsDoHResponse]("doh.isDoH", 4)
+}
+
+func memoizedisDoH(executionId string, host string, port int, path string) (IsDoHResponse, error) {
+	hash := "doh.isDoH" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "doh.isDoH" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsDoHResponse, error) {
+			return isDoH(executionId, host, port, path)
+		})
+	})
+	if err != nil {
+		return IsDoHResponse{}, err
+	}
+	if value, ok := v.(IsDoHResponse); ok {
+		return value, nil
+	}
+
+	return IsDoHResponse{}, errors.New("could not convert cached result")
+}
+
+func init() {
+	protocolstate.RegisterMemoized[IsDoTResponse]("doh.isDoT", 3)
+}
+
+func memoizedisDoT(executionId string, host string, port int) (IsDoTResponse, error) {
+	hash := "doh.isDoT" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "doh.isDoT" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsDoTResponse, error) {
+			return isDoT(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsDoTResponse{}, err
+	}
+	if value, ok := v.(IsDoTResponse); ok {
+		return value, nil
+	}
+
+	return IsDoTResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,287 @@
+package enip
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	defaultPort = 44818
+	// timeout of tcp list identity request
+	probeTimeout = 5 * time.Second
+
+	commandListIdentity = 0x0063
+	itemCIPIdentity     = 0x000c
+
+	headerSize = 24
+	// maximum size of an encapsulated reply that is read
+	maxReplySize = 65535
+)
+
+var (
+	errNotENIP = errors.New("not an ethernet/ip response")
+
+	// well known vendor ids
+	vendors = map[int]string{
+		1:   "Rockwell Automation/Allen-Bradley",
+		5:   "Rockwell Automation/Reliance Electric",
+		40:  "WAGO Corporation",
+		43:  "Phoenix Contact",
+		44:  "Parker Hannifin",
+		47:  "Omron Corporation",
+		48:  "Turck",
+		57:  "Siemens Energy & Automation",
+		90:  "HMS Industrial Networks",
+		161: "Eaton Electrical",
+		243: "Schneider Electric",
+		283: "Hilscher GmbH",
+		352: "Festo",
+		678: "Cognex Corporation",
+	}
+)
+
+type (
+	// Identity is the CIP identity returned in a List Identity response.
+	// this is returned by GetIdentity and Discover functions.
+	// @example
+	// ```javascript
+	// const enip = require('nuclei/enip');
+	// const identity = enip.GetIdentity('acme.com', 44818);
+	// log(toJSON(identity));
+	// ```
+	Identity struct {
+		// IsENIP is true if a valid list identity response was received
+		IsENIP bool
+		// Address is the address of the responding device
+		Address string
+		// VendorID is the cip vendor id
+		VendorID int
+		// Vendor is the name of the vendor (for well known vendor ids)
+		Vendor string
+		// DeviceType is the cip device type
+		DeviceType int
+		// ProductCode is the vendor specific product code
+		ProductCode int
+		// Revision is the major.minor revision of the device
+		Revision string
+		// Status is the device status word
+		Status int
+		// SerialNumber is the serial number of the device (hex)
+		SerialNumber string
+		// ProductName is the product name of the device
+		ProductName string
+		// State is the device state
+		State int
+	}
+)
+
+// GetIdentity sends an EtherNet/IP List Identity request over TCP to the given
+// host and port and returns the parsed CIP identity of the device.
+// @example
+// ```javascript
+// const enip = require('nuclei/enip');
+// const identity = enip.GetIdentity('acme.com', 44818);
+// log(identity.ProductName, identity.SerialNumber);
+// ```
+func GetIdentity(ctx context.Context, host string, port int) (Identity, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetIdentity(ctx, executionId, host, port)
+}
+
+// @memo
+func getIdentity(ctx context.Context, executionId string, host string, port int) (Identity, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return Identity{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return Identity{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Write(listIdentityRequest()); err != nil {
+		return Identity{}, err
+	}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+			// closed or silent responder is not enip
+			return Identity{}, nil
+		}
+		return Identity{}, err
+	}
+	length := int(binary.LittleEndian.Uint16(header[2:4]))
+	if binary.LittleEndian.Uint16(header[0:2]) != commandListIdentity || length > maxReplySize {
+		return Identity{}, nil
+	}
+	data := make([]byte, headerSize+length)
+	copy(data, header)
+	if _, err := io.ReadFull(conn, data[headerSize:]); err != nil {
+		return Identity{}, err
+	}
+	identity, err := parseListIdentity(data)
+	if errors.Is(err, errNotENIP) {
+		return Identity{}, nil
+	}
+	return identity, err
+}
+
+// Discover sends an EtherNet/IP List Identity request over UDP and collects
+// responses until the read timeout of the execution is reached. If host is empty
+// the request is broadcast (255.255.255.255) on the local network.
+//
+// Note: sending broadcast datagrams may require elevated privileges on some platforms.
+// @example
+// ```javascript
+// const enip = require('nuclei/enip');
+// const devices = enip.Discover('192.168.1.10');
+// log(toJSON(devices));
+// ```
+func Discover(ctx context.Context, host string) ([]Identity, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizeddiscover(executionId, host)
+}
+
+// @memo
+func discover(executionId string, host string) ([]Identity, error) {
+	dst := &net.UDPAddr{IP: net.IPv4bcast, Port: defaultPort}
+	if host != "" {
+		if !protocolstate.IsHostAllowed(executionId, host) {
+			// host is not valid according to network policy
+			return nil, protocolstate.ErrHostDenied.Msgf(host)
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			dialer, err := protocolstate.GetDialersOrError(executionId)
+			if err != nil {
+				return nil, err
+			}
+			dnsData, err := dialer.Fastdialer.GetDNSData(host)
+			if err != nil {
+				return nil, err
+			}
+			if len(dnsData.A) == 0 {
+				return nil, fmt.Errorf("could not resolve %s", host)
+			}
+			ip = net.ParseIP(dnsData.A[0])
+		}
+		dst.IP = ip
+	}
+
+	conn, err := protocolstate.ListenUDP(executionId, "0.0.0.0:0", host == "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if _, err := conn.WriteToUDP(listIdentityRequest(), dst); err != nil {
+		return nil, err
+	}
+
+	// collect responses until timeout is reached
+	_ = conn.SetReadDeadline(protocolstate.GetDeadline(executionId, protocolstate.GetTimeouts(executionId).TcpReadTimeout))
+	var identities []Identity
+	buff := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFromUDP(buff)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return identities, err
+		}
+		identity, err := parseListIdentity(buff[:n])
+		if err != nil {
+			continue
+		}
+		if identity.Address == "" || identity.Address == "0.0.0.0" {
+			identity.Address = addr.IP.String()
+		}
+		identities = append(identities, identity)
+		if host != "" {
+			// unicast target has answered
+			break
+		}
+	}
+	return identities, nil
+}
+
+// listIdentityRequest returns an encapsulated List Identity request
+func listIdentityRequest() []byte {
+	request := make([]byte, headerSize)
+	binary.LittleEndian.PutUint16(request[0:2], commandListIdentity)
+	return request
+}
+
+// parseListIdentity parses encapsulation header and CPF items of a List Identity reply
+func parseListIdentity(data []byte) (Identity, error) {
+	identity := Identity{}
+	if len(data) < headerSize+2 || binary.LittleEndian.Uint16(data[0:2]) != commandListIdentity {
+		return identity, errNotENIP
+	}
+	if status := binary.LittleEndian.Uint32(data[8:12]); status != 0 {
+		return identity, fmt.Errorf("enip encapsulation status 0x%x", status)
+	}
+	items := data[headerSize:]
+	count := int(binary.LittleEndian.Uint16(items[0:2]))
+	items = items[2:]
+	for i := 0; i < count; i++ {
+		if len(items) < 4 {
+			return identity, errNotENIP
+		}
+		itemType := binary.LittleEndian.Uint16(items[0:2])
+		itemLength := int(binary.LittleEndian.Uint16(items[2:4]))
+		if 4+itemLength > len(items) {
+			return identity, errNotENIP
+		}
+		item := items[4 : 4+itemLength]
+		items = items[4+itemLength:]
+		if itemType != itemCIPIdentity {
+			continue
+		}
+		return parseIdentityItem(item)
+	}
+	return identity, errNotENIP
+}
+
+// parseIdentityItem parses a CIP identity CPF item
+func parseIdentityItem(item []byte) (Identity, error) {
+	identity := Identity{}
+	// protocol version(2), socket address(16), vendor(2), device type(2), product code(2),
+	// revision(2), status(2), serial(4), product name length(1)
+	if len(item) < 33 {
+		return identity, errNotENIP
+	}
+	// socket address fields are big endian
+	identity.Address = net.IP(item[6:10]).String()
+	identity.VendorID = int(binary.LittleEndian.Uint16(item[18:20]))
+	identity.Vendor = vendors[identity.VendorID]
+	identity.DeviceType = int(binary.LittleEndian.Uint16(item[20:22]))
+	identity.ProductCode = int(binary.LittleEndian.Uint16(item[22:24]))
+	identity.Revision = fmt.Sprintf("%d.%d", item[24], item[25])
+	identity.Status = int(binary.LittleEndian.Uint16(item[26:28]))
+	identity.SerialNumber = fmt.Sprintf("0x%08x", binary.LittleEndian.Uint32(item[28:32]))
+	nameLength := int(item[32])
+	if 33+nameLength > len(item) {
+		return identity, errNotENIP
+	}
+	identity.ProductName = string(item[33 : 33+nameLength])
+	if 33+nameLength < len(item) {
+		identity.State = int(item[33+nameLength])
+	}
+	identity.IsENIP = true
+	return identity, nil
+}
@@ -0,0 +1,60 @@
+// Warning - This is generated code
+package enip
+
+import (
+	"context"
+
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[Identity]("enip.getIdentity", 3)
+}
+
+func memoizedgetIdentity(ctx context.Context, executionId string, host string, port int) (Identity, error) {
+	hash := "enip.getIdentity" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "enip.getIdentity" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (Identity, error) {
+			return getIdentity(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+	if value, ok := v.(Identity); ok {
+		return value, nil
+	}
+
+	return Identity{}, errors.New("could not convert cached result")
+}
+
+func init() {
+	protocolstate.RegisterMemoized[[]Identity]("enip.discover", 2)
+}
+
+func memoizeddiscover(executionId string, host string) ([]Identity, error) {
+	hash := "enip.discover" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "enip.discover" + ":" + fmt.Sprint(host)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() ([]Identity, error) {
+			return discover(executionId, host)
+		})
+	})
+	if err != nil {
+		return []Identity{}, err
+	}
+	if value, ok := v.([]Identity); ok {
+		return value, nil
+	}
+
+	return []Identity{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,172 @@
+package fox
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of fox hello exchange
+	probeTimeout = 5 * time.Second
+
+	helloRequest = "fox a 1 -1 fox hello\n" +
+		"{\n" +
+		"fox.version=s:1.0\n" +
+		"id=i:1\n" +
+		"};;\n"
+
+	// terminator of a fox message body
+	messageTerminator = "};;"
+)
+
+var (
+	errNotFox = errors.New("not a fox response")
+)
+
+type (
+	// GetInfoResponse is the response from the GetInfo function.
+	// this is returned by GetInfo function.
+	// @example
+	// ```javascript
+	// const fox = require('nuclei/fox');
+	// const info = fox.GetInfo('acme.com', 1911);
+	// log(toJSON(info));
+	// ```
+	GetInfoResponse struct {
+		// IsFox is true if the service responded with a fox hello
+		IsFox       bool
+		FoxVersion  string
+		HostName    string
+		HostAddress string
+		HostID      string
+		AppName     string
+		AppVersion  string
+		VMName      string
+		VMVersion   string
+		OSName      string
+		OSVersion   string
+		StationName string
+		BrandID     string
+		Language    string
+		TimeZone    string
+		// Properties contains all key/value pairs of the hello response
+		Properties map[string]string
+	}
+)
+
+// GetInfo sends a Niagara Fox hello to the given host and port and parses
+// the returned properties (host name, addresses, versions, station name).
+// Once the hello is confirmed no error is returned, properties are left
+// empty if the response is cut short (ex: read timeout).
+// @example
+// ```javascript
+// const fox = require('nuclei/fox');
+// const info = fox.GetInfo('acme.com', 1911);
+// log(info.StationName, info.HostAddress);
+// ```
+func GetInfo(ctx context.Context, host string, port int) (GetInfoResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetInfo(ctx, executionId, host, port)
+}
+
+// @memo
+func getInfo(ctx context.Context, executionId string, host string, port int) (GetInfoResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return GetInfoResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return GetInfoResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Write([]byte(helloRequest)); err != nil {
+		return GetInfoResponse{}, err
+	}
+	resp, err := readHello(bufio.NewReader(utils.LimitConn(executionId, conn)))
+	if errors.Is(err, errNotFox) {
+		return GetInfoResponse{}, nil
+	}
+	if err != nil && resp.IsFox && ctx.Err() == nil {
+		// the hello identified fox, a truncated body only leaves the remaining
+		// properties empty (cancelled probes keep their error so that they are not cached)
+		return resp, nil
+	}
+	return resp, err
+}
+
+// readHello reads and parses a fox hello response. the properties read
+// before an error are returned along with it
+func readHello(reader *bufio.Reader) (GetInfoResponse, error) {
+	resp := GetInfoResponse{}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+			return resp, errNotFox
+		}
+		return resp, err
+	}
+	if !strings.HasPrefix(line, "fox a ") || !strings.Contains(line, "fox hello") {
+		return resp, errNotFox
+	}
+	resp.IsFox = true
+	resp.Properties = map[string]string{}
+	var readErr error
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == messageTerminator {
+			break
+		}
+		if key, value, ok := parseProperty(line); ok {
+			resp.Properties[key] = value
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				readErr = err
+			}
+			break
+		}
+	}
+
+	resp.FoxVersion = resp.Properties["fox.version"]
+	resp.HostName = resp.Properties["hostName"]
+	resp.HostAddress = resp.Properties["hostAddress"]
+	resp.HostID = resp.Properties["hostId"]
+	resp.AppName = resp.Properties["app.name"]
+	resp.AppVersion = resp.Properties["app.version"]
+	resp.VMName = resp.Properties["vm.name"]
+	resp.VMVersion = resp.Properties["vm.version"]
+	resp.OSName = resp.Properties["os.name"]
+	resp.OSVersion = resp.Properties["os.version"]
+	resp.StationName = resp.Properties["station.name"]
+	resp.BrandID = resp.Properties["brandId"]
+	resp.Language = resp.Properties["lang"]
+	resp.TimeZone = resp.Properties["timeZone"]
+	return resp, readErr
+}
+
+// parseProperty parses a key=type:value line of fox message body
+func parseProperty(line string) (string, string, bool) {
+	key, typedValue, ok := strings.Cut(line, "=")
+	if !ok || key == "" {
+		return "", "", false
+	}
+	// values are prefixed with a single character type (s: string, i: integer, b: boolean, ...)
+	if _, value, ok := strings.Cut(typedValue, ":"); ok {
+		return key, value, true
+	}
+	return key, typedValue, true
+}
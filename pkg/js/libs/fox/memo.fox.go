@@ -0,0 +1,35 @@
+// Warning - This is generated code
+package fox
+
+import (
+	"context"
+	"fmt"
+
+	"errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[GetInfoResponse]("fox.getInfo", 3)
+}
+
+func memoizedgetInfo(ctx context.Context, executionId string, host string, port int) (GetInfoResponse, error) {
+	hash := "fox.getInfo" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "fox.getInfo" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (GetInfoResponse, error) {
+			return getInfo(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return GetInfoResponse{}, err
+	}
+	if value, ok := v.(GetInfoResponse); ok {
+		return value, nil
+	}
+
+	return GetInfoResponse{}, errors.New("could not convert cached result")
+}
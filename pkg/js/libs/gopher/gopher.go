@@ -0,0 +1,170 @@
+package gopher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the whole fetch
+	fetchTimeout = 10 * time.Second
+	// maximum size of a response that is read
+	maxResponseSize = 512 * 1024
+)
+
+var (
+	errInvalidSelector = errors.New("selector must not contain tab, cr or lf characters")
+)
+
+type (
+	// Item is an item of a gopher menu.
+	// @example
+	// ```javascript
+	// const gopher = require('nuclei/gopher');
+	// const resp = gopher.Fetch('acme.com', 70, '');
+	// log(resp.Items[0].Display);
+	// ```
+	Item struct {
+		// Type is the item type (ex: 0 for text, 1 for menu, i for info)
+		Type string
+		// Display is the user visible name of the item
+		Display string
+		// Selector is the selector of the item
+		Selector string
+		// Host is the host serving the item
+		Host string
+		// Port is the port serving the item
+		Port int
+	}
+
+	// FetchResponse is the response from the Fetch function.
+	// this is returned by Fetch function.
+	// @example
+	// ```javascript
+	// const gopher = require('nuclei/gopher');
+	// const resp = gopher.Fetch('acme.com', 70, '');
+	// log(toJSON(resp));
+	// ```
+	FetchResponse struct {
+		// IsMenu is true if the response was parsed as a gopher menu
+		IsMenu bool
+		// Items are the items of the menu (if IsMenu)
+		Items []Item
+		// Text is the response with control characters removed (if not IsMenu)
+		Text string
+		// Truncated is true if the response exceeded the read limit
+		Truncated bool
+	}
+)
+
+// Fetch sends the selector to the gopher server at the given host and port
+// and returns its response. Menus are parsed into items and other responses
+// are returned as text with control characters removed.
+// An empty selector fetches the root menu.
+// @example
+// ```javascript
+// const gopher = require('nuclei/gopher');
+// const resp = gopher.Fetch('acme.com', 70, '/');
+// for (const item of resp.Items) { log(item.Type, item.Selector); }
+// ```
+func Fetch(ctx context.Context, host string, port int, selector string) (FetchResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedfetch(executionId, host, port, selector)
+}
+
+// @memo(nodisk)
+func fetch(executionId string, host string, port int, selector string) (FetchResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return FetchResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if strings.ContainsAny(selector, "\t\r\n") {
+		return FetchResponse{}, errInvalidSelector
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), fetchTimeout)
+	if err != nil {
+		return FetchResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Write([]byte(selector + "\r\n")); err != nil {
+		return FetchResponse{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(utils.LimitConn(executionId, conn), maxResponseSize+1))
+	if err != nil && len(data) == 0 {
+		return FetchResponse{}, err
+	}
+	resp := FetchResponse{}
+	if len(data) > maxResponseSize {
+		data = data[:maxResponseSize]
+		resp.Truncated = true
+	}
+	if items, ok := parseMenu(string(data)); ok {
+		resp.IsMenu = true
+		resp.Items = items
+		return resp, nil
+	}
+	resp.Text = sanitize(string(data))
+	return resp, nil
+}
+
+// parseMenu parses a gopher menu (rfc 1436). false is returned if
+// any line is not a valid menu item
+func parseMenu(data string) ([]Item, bool) {
+	var items []Item
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "." {
+			// end of menu
+			break
+		}
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line[1:], "\t")
+		if len(fields) < 4 || !isItemType(line[0]) {
+			return nil, false
+		}
+		port, err := strconv.Atoi(strings.TrimSpace(fields[3]))
+		if err != nil || port < 0 || port > 65535 {
+			return nil, false
+		}
+		items = append(items, Item{
+			Type:     string(line[0]),
+			Display:  sanitize(fields[0]),
+			Selector: sanitize(fields[1]),
+			Host:     sanitize(fields[2]),
+			Port:     port,
+		})
+	}
+	return items, len(items) > 0
+}
+
+// isItemType checks if c is a printable item type character
+func isItemType(c byte) bool {
+	return c > ' ' && c < 0x7f
+}
+
+// sanitize removes control characters except newlines and tabs
+func sanitize(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, value)
+}
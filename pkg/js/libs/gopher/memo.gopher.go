@@ -0,0 +1,29 @@
+// Warning - This is generated code
+package gopher
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[FetchResponse]("gopher.fetch", 4)
+}
+
+func memoizedfetch(executionId string, host string, port int, selector string) (FetchResponse, error) {
+	hash := "gopher.fetch" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(selector)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return fetch(executionId, host, port, selector)
+	})
+	if err != nil {
+		return FetchResponse{}, err
+	}
+	if value, ok := v.(FetchResponse); ok {
+		return value, nil
+	}
+
+	return FetchResponse{}, errors.New("could not convert cached result")
+}
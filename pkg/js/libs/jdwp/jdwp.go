@@ -0,0 +1,198 @@
+package jdwp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of jdwp handshake and version command
+	probeTimeout = 5 * time.Second
+
+	flagReply = 0x80
+
+	commandSetVirtualMachine = 1
+	commandVersion           = 1
+
+	// maximum size of a reply packet that is read
+	maxPacketSize = 64 * 1024
+)
+
+var (
+	handshake = []byte("JDWP-Handshake")
+
+	errInvalidReply = errors.New("invalid jdwp reply")
+)
+
+type (
+	// IsJDWPResponse is the response from the IsJDWP function.
+	// this is returned by IsJDWP function.
+	// @example
+	// ```javascript
+	// const jdwp = require('nuclei/jdwp');
+	// const isJDWP = jdwp.IsJDWP('acme.com', 8000);
+	// log(toJSON(isJDWP));
+	// ```
+	IsJDWPResponse struct {
+		// IsJDWP is true if the endpoint completed the jdwp handshake
+		IsJDWP bool
+		// Description is the jvm description returned by VirtualMachine.Version
+		Description string
+		// JDWPMajor is the major version of jdwp protocol
+		JDWPMajor int
+		// JDWPMinor is the minor version of jdwp protocol
+		JDWPMinor int
+		// VMVersion is the version of the jvm (ex: 17.0.2)
+		VMVersion string
+		// VMName is the name of the jvm (ex: OpenJDK 64-Bit Server VM)
+		VMName string
+	}
+)
+
+// IsJDWP checks if the given host and port are running a java debug wire protocol
+// (jdwp) endpoint by performing the jdwp handshake and on success issues
+// VirtualMachine.Version command to return the jvm version and description.
+// Once the handshake succeeded no error is returned, the version is left
+// empty if the command fails (ex: read timeout).
+// @example
+// ```javascript
+// const jdwp = require('nuclei/jdwp');
+// const isJDWP = jdwp.IsJDWP('acme.com', 8000);
+// log(toJSON(isJDWP));
+// ```
+func IsJDWP(ctx context.Context, host string, port int) (IsJDWPResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisJDWP(ctx, executionId, host, port)
+}
+
+// @memo
+func isJDWP(ctx context.Context, executionId string, host string, port int) (IsJDWPResponse, error) {
+	resp := IsJDWPResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Write(handshake); err != nil {
+		return resp, err
+	}
+	reply := make([]byte, len(handshake))
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+			// closed or silent responder is not jdwp
+			return resp, nil
+		}
+		return resp, err
+	}
+	if !bytes.Equal(reply, handshake) {
+		return resp, nil
+	}
+	resp.IsJDWP = true
+
+	// the handshake identified jdwp, a failed version command only leaves the
+	// version empty (cancelled probes keep their error so that they are not cached)
+	if err := readVersion(conn, &resp); err != nil && ctx.Err() != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// readVersion issues VirtualMachine.Version command and parses its reply into resp
+func readVersion(conn net.Conn, resp *IsJDWPResponse) error {
+	const id = 1
+	packet := binary.BigEndian.AppendUint32(nil, 11)
+	packet = binary.BigEndian.AppendUint32(packet, id)
+	packet = append(packet, 0x00, commandSetVirtualMachine, commandVersion)
+	if _, err := conn.Write(packet); err != nil {
+		return err
+	}
+	data, err := readReply(conn, id)
+	if err != nil {
+		return err
+	}
+	return parseVersion(data, resp)
+}
+
+// readReply reads packets until the reply with given id is received
+// event packets sent by the jvm (ex: VMStart) are skipped
+func readReply(conn net.Conn, id uint32) ([]byte, error) {
+	header := make([]byte, 11)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return nil, err
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		if length < 11 || length > maxPacketSize {
+			return nil, errInvalidReply
+		}
+		data := make([]byte, length-11)
+		if _, err := io.ReadFull(conn, data); err != nil {
+			return nil, err
+		}
+		if header[8]&flagReply == 0 || binary.BigEndian.Uint32(header[4:8]) != id {
+			continue
+		}
+		if errorCode := binary.BigEndian.Uint16(header[9:11]); errorCode != 0 {
+			return nil, fmt.Errorf("jdwp error code %d", errorCode)
+		}
+		return data, nil
+	}
+}
+
+// parseVersion parses VirtualMachine.Version reply data
+func parseVersion(data []byte, resp *IsJDWPResponse) error {
+	readString := func() (string, error) {
+		if len(data) < 4 {
+			return "", errInvalidReply
+		}
+		length := int(binary.BigEndian.Uint32(data[0:4]))
+		if length < 0 || 4+length > len(data) {
+			return "", errInvalidReply
+		}
+		value := string(data[4 : 4+length])
+		data = data[4+length:]
+		return value, nil
+	}
+	readInt := func() (int, error) {
+		if len(data) < 4 {
+			return 0, errInvalidReply
+		}
+		value := int(int32(binary.BigEndian.Uint32(data[0:4])))
+		data = data[4:]
+		return value, nil
+	}
+
+	var err error
+	if resp.Description, err = readString(); err != nil {
+		return err
+	}
+	if resp.JDWPMajor, err = readInt(); err != nil {
+		return err
+	}
+	if resp.JDWPMinor, err = readInt(); err != nil {
+		return err
+	}
+	if resp.VMVersion, err = readString(); err != nil {
+		return err
+	}
+	if resp.VMName, err = readString(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Warning - This is generated code
+package jdwp
+
+import (
+	"context"
+
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsJDWPResponse]("jdwp.isJDWP", 3)
+}
+
+func memoizedisJDWP(ctx context.Context, executionId string, host string, port int) (IsJDWPResponse, error) {
+	hash := "jdwp.isJDWP" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "jdwp.isJDWP" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsJDWPResponse, error) {
+			return isJDWP(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsJDWPResponse{}, err
+	}
+	if value, ok := v.(IsJDWPResponse); ok {
+		return value, nil
+	}
+
+	return IsJDWPResponse{}, errors.New("could not convert cached result")
+}
@@ -69,9 +69,9 @@ func sendToKDCTcp(kclient *Client, msg string) ([]byte, error) {
 	kclient.nj.Require(len(kdcs) > 0, "no KDCs found")
 
 	executionId := kclient.nj.ExecutionId()
-	dialers := protocolstate.GetDialersWithId(executionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialers, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
 	}
 
 	var errs []string
@@ -86,11 +86,12 @@ func sendToKDCTcp(kclient *Client, msg string) ([]byte, error) {
 			errs = append(errs, fmt.Sprintf("error establishing connection to %s: %v", kdcs[i], err))
 			continue
 		}
+		tcpConn = protocolstate.RecordConn(executionId, tcpConn)
 		defer func() {
 			_ = tcpConn.Close()
 		}()
 		_ = tcpConn.SetDeadline(time.Now().Add(time.Duration(kclient.config.timeout) * time.Second)) //read and write deadline
-		rb, err := sendTCP(tcpConn.(*net.TCPConn), []byte(msg))
+		rb, err := sendTCP(tcpConn, []byte(msg))
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("error sending to %s: %v", kdcs[i], err))
 			continue
@@ -110,9 +111,9 @@ func sendToKDCUdp(kclient *Client, msg string) ([]byte, error) {
 	kclient.nj.Require(len(kdcs) > 0, "no KDCs found")
 
 	executionId := kclient.nj.ExecutionId()
-	dialers := protocolstate.GetDialersWithId(executionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialers, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
 	}
 	var errs []string
 	for i := 1; i <= len(kdcs); i++ {
@@ -126,11 +127,12 @@ func sendToKDCUdp(kclient *Client, msg string) ([]byte, error) {
 			errs = append(errs, fmt.Sprintf("error establishing connection to %s: %v", kdcs[i], err))
 			continue
 		}
+		udpConn = protocolstate.RecordConn(executionId, udpConn)
 		defer func() {
 			_ = udpConn.Close()
 		}()
 		_ = udpConn.SetDeadline(time.Now().Add(time.Duration(kclient.config.timeout) * time.Second)) //read and write deadline
-		rb, err := sendUDP(udpConn.(*net.UDPConn), []byte(msg))
+		rb, err := sendUDP(udpConn, []byte(msg))
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("error sending to %s: %v", kdcs[i], err))
 			continue
@@ -145,7 +147,7 @@ func sendToKDCUdp(kclient *Client, msg string) ([]byte, error) {
 }
 
 // sendUDP sends bytes to connection over UDP.
-func sendUDP(conn *net.UDPConn, b []byte) ([]byte, error) {
+func sendUDP(conn net.Conn, b []byte) ([]byte, error) {
 	var r []byte
 	defer func() {
 		_ = conn.Close()
@@ -155,7 +157,7 @@ func sendUDP(conn *net.UDPConn, b []byte) ([]byte, error) {
 		return r, fmt.Errorf("error sending to (%s): %v", conn.RemoteAddr().String(), err)
 	}
 	udpbuf := make([]byte, 4096)
-	n, _, err := conn.ReadFrom(udpbuf)
+	n, err := conn.Read(udpbuf)
 	r = udpbuf[:n]
 	if err != nil {
 		return r, fmt.Errorf("sending over UDP failed to %s: %v", conn.RemoteAddr().String(), err)
@@ -167,7 +169,7 @@ func sendUDP(conn *net.UDPConn, b []byte) ([]byte, error) {
 }
 
 // sendTCP sends bytes to connection over TCP.
-func sendTCP(conn *net.TCPConn, b []byte) ([]byte, error) {
+func sendTCP(conn net.Conn, b []byte) ([]byte, error) {
 	defer func() {
 		_ = conn.Close()
 	}()
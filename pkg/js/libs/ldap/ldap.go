@@ -130,12 +130,16 @@ func NewClient(call goja.ConstructorCall, runtime *goja.Runtime) *goja.Object {
 			if c.cfg.ServerName != "" {
 				serverName = c.cfg.ServerName
 			}
-			conn, err = dialers.Fastdialer.DialTLSWithConfig(context.TODO(), "tcp", net.JoinHostPort(host, port),
+			conn, err = protocolstate.DialTLSWithConfig(context.TODO(), executionId, "tcp", net.JoinHostPort(host, port),
 				&tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10, ServerName: serverName})
 		default:
 			err = fmt.Errorf("unsupported ldap url schema %v", u.Scheme)
 		}
 		c.nj.HandleError(err, "failed to connect to ldap server")
+		if u.Scheme != "ldaps" {
+			// ldaps connections are recorded below the tls layer when dialed
+			conn = protocolstate.RecordConn(executionId, conn)
+		}
 	}
 	c.conn = ldap.NewConn(conn, u.Scheme == "ldaps")
 	if u.Scheme != "ldaps" && c.cfg.Upgrade {
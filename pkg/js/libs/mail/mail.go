@@ -0,0 +1,249 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// TLSModeImplicit is returned when the service speaks tls from the start (ex: smtps, imaps, pop3s)
+	TLSModeImplicit = "implicit"
+	// TLSModeStartTLS is returned when the service is plaintext and advertises STARTTLS (or STLS for pop3)
+	TLSModeStartTLS = "starttls"
+	// TLSModePlaintext is returned when the service is plaintext only
+	TLSModePlaintext = "plaintext"
+	// TLSModeUnknown is returned when the service is not a smtp, imap or pop3 server
+	TLSModeUnknown = "unknown"
+)
+
+const (
+	// timeout of each connection attempt
+	probeTimeout = 5 * time.Second
+	// maximum number of lines read for a multiline response
+	maxResponseLines = 64
+)
+
+type (
+	// DetectTLSModeResponse is the response from the DetectTLSMode function.
+	// this is returned by DetectTLSMode function.
+	// @example
+	// ```javascript
+	// const mail = require('nuclei/mail');
+	// const resp = mail.DetectTLSMode('acme.com', 587);
+	// log(toJSON(resp));
+	// ```
+	DetectTLSModeResponse struct {
+		// Mode is one of TLSModeImplicit, TLSModeStartTLS, TLSModePlaintext or TLSModeUnknown
+		Mode string
+		// Protocol is the mail protocol identified from the greeting (smtp, imap or pop3)
+		Protocol string
+		// Banner is the greeting sent by the server
+		Banner string
+	}
+)
+
+// DetectTLSMode determines whether a smtp, imap or pop3 service speaks
+// implicit tls, supports STARTTLS or is plaintext only.
+// An implicit tls handshake is tried first, then the plaintext greeting
+// is read and the capabilities of the server are checked for STARTTLS.
+// @example
+// ```javascript
+// const mail = require('nuclei/mail');
+// const resp = mail.DetectTLSMode('acme.com', 2525);
+// if (resp.Mode == mail.TLSModeStartTLS) { log('starttls supported'); }
+// ```
+func DetectTLSMode(ctx context.Context, host string, port int) (DetectTLSModeResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizeddetectTLSMode(executionId, host, port)
+}
+
+// @memo
+func detectTLSMode(executionId string, host string, port int) (DetectTLSModeResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return DetectTLSModeResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	resp, err := detectImplicitTLS(executionId, host, address)
+	if err != nil || resp.Mode == TLSModeImplicit {
+		return resp, err
+	}
+	return detectPlaintext(executionId, address)
+}
+
+// detectImplicitTLS performs a tls handshake and reads the greeting over tls.
+// failed handshakes are not errors.
+func detectImplicitTLS(executionId string, host string, address string) (DetectTLSModeResponse, error) {
+	resp := DetectTLSModeResponse{Mode: TLSModeUnknown}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, probeTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	tlsConn := tls.Client(utils.LimitConn(executionId, conn), &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		ServerName:         host,
+	})
+	if err := tlsConn.Handshake(); err != nil {
+		// plaintext service
+		return resp, nil
+	}
+	banner, protocol := readGreeting(bufio.NewReader(tlsConn))
+	if protocol == "" {
+		return resp, nil
+	}
+	resp.Mode = TLSModeImplicit
+	resp.Protocol = protocol
+	resp.Banner = banner
+	return resp, nil
+}
+
+// detectPlaintext reads the plaintext greeting and checks the
+// capabilities of the server for STARTTLS support
+func detectPlaintext(executionId string, address string) (DetectTLSModeResponse, error) {
+	resp := DetectTLSModeResponse{Mode: TLSModeUnknown}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, probeTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	reader := bufio.NewReader(utils.LimitConn(executionId, conn))
+	banner, protocol := readGreeting(reader)
+	if protocol == "" {
+		return resp, nil
+	}
+	resp.Protocol = protocol
+	resp.Banner = banner
+	resp.Mode = TLSModePlaintext
+	if supportsStartTLS(conn, reader, protocol, banner) {
+		resp.Mode = TLSModeStartTLS
+	}
+	return resp, nil
+}
+
+// readGreeting reads the greeting of the server and identifies the protocol
+func readGreeting(reader *bufio.Reader) (string, string) {
+	line, err := readLine(reader)
+	if err != nil {
+		return "", ""
+	}
+	switch {
+	case strings.HasPrefix(line, "220"):
+		// skip continuation lines of multiline smtp greeting (220-...)
+		for next, i := line, 0; strings.HasPrefix(next, "220-") && i < maxResponseLines; i++ {
+			if next, err = readLine(reader); err != nil {
+				break
+			}
+		}
+		return line, "smtp"
+	case strings.HasPrefix(line, "* OK"), strings.HasPrefix(line, "* PREAUTH"):
+		return line, "imap"
+	case strings.HasPrefix(line, "+OK"):
+		return line, "pop3"
+	}
+	return "", ""
+}
+
+// supportsStartTLS checks if the capabilities of the server advertise STARTTLS
+func supportsStartTLS(conn net.Conn, reader *bufio.Reader, protocol string, banner string) bool {
+	return hasStartTLS(protocol, readCapabilities(conn, reader, protocol, banner))
+}
+
+// hasStartTLS checks if capabilities (see readCapabilities) contain STARTTLS (STLS for pop3)
+func hasStartTLS(protocol string, capabilities []string) bool {
+	keyword := "STARTTLS"
+	if protocol == "pop3" {
+		keyword = "STLS"
+	}
+	for _, capability := range capabilities {
+		if fields := strings.Fields(capability); len(fields) > 0 && strings.EqualFold(fields[0], keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCapabilities returns the capabilities advertised by the server before tls
+// which are the EHLO keywords for smtp (ex: AUTH PLAIN LOGIN), the CAPABILITY
+// atoms for imap (ex: AUTH=PLAIN) and the CAPA lines for pop3 (ex: SASL PLAIN)
+func readCapabilities(conn net.Conn, reader *bufio.Reader, protocol string, banner string) []string {
+	var capabilities []string
+	switch protocol {
+	case "smtp":
+		if _, err := conn.Write([]byte("EHLO nuclei\r\n")); err != nil {
+			return nil
+		}
+		for i := 0; i < maxResponseLines; i++ {
+			line, err := readLine(reader)
+			if err != nil || !strings.HasPrefix(line, "250") {
+				return capabilities
+			}
+			// the first line is the greeting of the server
+			if i > 0 && len(line) > 4 {
+				capabilities = append(capabilities, strings.TrimSpace(line[4:]))
+			}
+			if !strings.HasPrefix(line, "250-") {
+				return capabilities
+			}
+		}
+	case "imap":
+		// capabilities are usually included in the greeting
+		if start := strings.Index(banner, "[CAPABILITY "); start >= 0 {
+			list, _, _ := strings.Cut(banner[start+len("[CAPABILITY "):], "]")
+			return strings.Fields(list)
+		}
+		if _, err := conn.Write([]byte("a001 CAPABILITY\r\n")); err != nil {
+			return nil
+		}
+		for i := 0; i < maxResponseLines; i++ {
+			line, err := readLine(reader)
+			if err != nil || strings.HasPrefix(line, "a001 ") {
+				return capabilities
+			}
+			if list, ok := strings.CutPrefix(line, "* CAPABILITY "); ok {
+				capabilities = append(capabilities, strings.Fields(list)...)
+			}
+		}
+	case "pop3":
+		if _, err := conn.Write([]byte("CAPA\r\n")); err != nil {
+			return nil
+		}
+		line, err := readLine(reader)
+		if err != nil || !strings.HasPrefix(line, "+OK") {
+			return nil
+		}
+		for i := 0; i < maxResponseLines; i++ {
+			line, err := readLine(reader)
+			if err != nil || line == "." {
+				return capabilities
+			}
+			capabilities = append(capabilities, line)
+		}
+	}
+	return capabilities
+}
+
+// readLine reads a crlf terminated line
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
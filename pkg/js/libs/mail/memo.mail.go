@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package mail
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[DetectTLSModeResponse]("mail.detectTLSMode", 3)
+}
+
+func memoizeddetectTLSMode(executionId string, host string, port int) (DetectTLSModeResponse, error) {
+	hash := "mail.detectTLSMode" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "mail.detectTLSMode" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (DetectTLSModeResponse, error) {
+			return detectTLSMode(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return DetectTLSModeResponse{}, err
+	}
+	if value, ok := v.(DetectTLSModeResponse); ok {
+		return value, nil
+	}
+
+	return DetectTLSModeResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package mail
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[CheckStartTLSStrippingResponse]("mail.checkStartTLSStripping", 3)
+}
+
+func memoizedcheckStartTLSStripping(executionId string, host string, port int) (CheckStartTLSStrippingResponse, error) {
+	hash := "mail.checkStartTLSStripping" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "mail.checkStartTLSStripping" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (CheckStartTLSStrippingResponse, error) {
+			return checkStartTLSStripping(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return CheckStartTLSStrippingResponse{}, err
+	}
+	if value, ok := v.(CheckStartTLSStrippingResponse); ok {
+		return value, nil
+	}
+
+	return CheckStartTLSStrippingResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,168 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+type (
+	// CheckStartTLSStrippingResponse is the response from the CheckStartTLSStripping function.
+	// this is returned by CheckStartTLSStripping function.
+	// @example
+	// ```javascript
+	// const mail = require('nuclei/mail');
+	// const resp = mail.CheckStartTLSStripping('acme.com', 587);
+	// log(toJSON(resp));
+	// ```
+	CheckStartTLSStrippingResponse struct {
+		// Mode is the tls mode of the service (see DetectTLSMode)
+		Mode string
+		// Protocol is the mail protocol identified from the greeting (smtp, imap or pop3)
+		Protocol string
+		// Banner is the greeting sent by the server
+		Banner string
+		// Mechanisms are the authentication mechanisms advertised before tls
+		Mechanisms []string
+		// PlaintextAuth is true if a STARTTLS service accepts authentication
+		// before tls which allows a mitm to strip STARTTLS and capture credentials
+		PlaintextAuth bool
+	}
+)
+
+// CheckStartTLSStripping checks whether a smtp, imap or pop3 service advertising
+// STARTTLS (see DetectTLSMode) still permits authentication over plaintext, the
+// precondition of STARTTLS stripping attacks.
+// Authentication is started without credentials and cancelled right away: smtp
+// AUTH and pop3 USER/AUTH must be accepted, while imap LOGIN is permitted unless
+// LOGINDISABLED is advertised.
+// PlaintextAuth is only checked for starttls services (implicit tls cannot be
+// stripped and plaintext only services never upgrade).
+// @example
+// ```javascript
+// const mail = require('nuclei/mail');
+// const resp = mail.CheckStartTLSStripping('acme.com', 587);
+// if (resp.PlaintextAuth) { log('plaintext auth allowed before starttls'); }
+// ```
+func CheckStartTLSStripping(ctx context.Context, host string, port int) (CheckStartTLSStrippingResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedcheckStartTLSStripping(executionId, host, port)
+}
+
+// @memo
+func checkStartTLSStripping(executionId string, host string, port int) (CheckStartTLSStrippingResponse, error) {
+	mode, err := memoizeddetectTLSMode(executionId, host, port)
+	if err != nil {
+		return CheckStartTLSStrippingResponse{}, err
+	}
+	resp := CheckStartTLSStrippingResponse{Mode: mode.Mode, Protocol: mode.Protocol, Banner: mode.Banner, Mechanisms: []string{}}
+	if mode.Mode != TLSModeStartTLS {
+		return resp, nil
+	}
+
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	reader := bufio.NewReader(utils.LimitConn(executionId, conn))
+	banner, protocol := readGreeting(reader)
+	if protocol != mode.Protocol {
+		return resp, nil
+	}
+	capabilities := readCapabilities(conn, reader, protocol, banner)
+	resp.Mechanisms = authMechanisms(protocol, capabilities)
+	resp.PlaintextAuth = plaintextAuth(conn, reader, protocol, capabilities, resp.Mechanisms)
+	return resp, nil
+}
+
+// authMechanisms returns the sasl mechanisms of capabilities (see readCapabilities)
+func authMechanisms(protocol string, capabilities []string) []string {
+	mechanisms := []string{}
+	for _, capability := range capabilities {
+		var values []string
+		fields := strings.Fields(strings.ToUpper(capability))
+		switch {
+		case len(fields) == 0:
+			continue
+		case protocol == "smtp" && fields[0] == "AUTH":
+			values = fields[1:]
+		case protocol == "smtp" && strings.HasPrefix(fields[0], "AUTH="):
+			// legacy AUTH=LOGIN PLAIN keyword
+			values = append([]string{strings.TrimPrefix(fields[0], "AUTH=")}, fields[1:]...)
+		case protocol == "imap" && strings.HasPrefix(fields[0], "AUTH="):
+			values = []string{strings.TrimPrefix(fields[0], "AUTH=")}
+		case protocol == "pop3" && fields[0] == "SASL":
+			values = fields[1:]
+		}
+		for _, value := range values {
+			if value != "" && !slices.Contains(mechanisms, value) {
+				mechanisms = append(mechanisms, value)
+			}
+		}
+	}
+	return mechanisms
+}
+
+// plaintextAuth checks if the server accepts to start authentication before tls.
+// started sasl exchanges are cancelled and no credentials are sent.
+func plaintextAuth(conn net.Conn, reader *bufio.Reader, protocol string, capabilities []string, mechanisms []string) bool {
+	mechanism := "LOGIN"
+	if len(mechanisms) > 0 && !slices.Contains(mechanisms, mechanism) {
+		mechanism = mechanisms[0]
+	}
+	switch protocol {
+	case "smtp":
+		return startSASL(conn, reader, "AUTH "+mechanism, "", "334")
+	case "imap":
+		if !slices.ContainsFunc(capabilities, func(capability string) bool {
+			return strings.EqualFold(capability, "LOGINDISABLED")
+		}) {
+			// LOGIN cannot be checked without credentials
+			return true
+		}
+		return len(mechanisms) > 0 && startSASL(conn, reader, "a002 AUTHENTICATE "+mechanism, "a002 ", "+ ")
+	case "pop3":
+		if _, err := conn.Write([]byte("USER nuclei\r\n")); err != nil {
+			return false
+		}
+		if line, err := readLine(reader); err == nil && strings.HasPrefix(line, "+OK") {
+			return true
+		}
+		return len(mechanisms) > 0 && startSASL(conn, reader, "AUTH "+mechanism, "", "+ ")
+	}
+	return false
+}
+
+// startSASL sends command starting a sasl exchange and checks if the server
+// answers with a continuation (prefixed by continuation) before the tagged
+// response (prefixed by tag, if any). accepted exchanges are cancelled with *
+func startSASL(conn net.Conn, reader *bufio.Reader, command string, tag string, continuation string) bool {
+	if _, err := conn.Write([]byte(command + "\r\n")); err != nil {
+		return false
+	}
+	for i := 0; i < maxResponseLines; i++ {
+		line, err := readLine(reader)
+		if err != nil {
+			return false
+		}
+		if strings.HasPrefix(line, continuation) || line == strings.TrimSpace(continuation) {
+			_, _ = conn.Write([]byte("*\r\n"))
+			return true
+		}
+		// untagged imap responses may precede the tagged response
+		if tag == "" || strings.HasPrefix(line, tag) {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// mailServer sends greeting to every connection and answers each received
+// line with the response returned by handle (nothing if it is empty)
+func mailServer(t *testing.T, greeting string, handle func(line string) string) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				if _, err := conn.Write([]byte(greeting + "\r\n")); err != nil {
+					return
+				}
+				reader := bufio.NewReader(conn)
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if response := handle(strings.TrimRight(line, "\r\n")); response != "" {
+						if _, err := conn.Write([]byte(response)); err != nil {
+							return
+						}
+					}
+				}
+			}()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return portNum
+}
+
+// smtpHandler answers EHLO with the given keywords and AUTH with authResponse
+func smtpHandler(keywords []string, authResponse string) func(string) string {
+	return func(line string) string {
+		switch {
+		case strings.HasPrefix(line, "EHLO"):
+			response := "250-mail.acme.com\r\n"
+			for i, keyword := range keywords {
+				separator := "-"
+				if i == len(keywords)-1 {
+					separator = " "
+				}
+				response += "250" + separator + keyword + "\r\n"
+			}
+			return response
+		case strings.HasPrefix(line, "AUTH"):
+			return authResponse + "\r\n"
+		case line == "*":
+			return "501 5.0.0 authentication cancelled\r\n"
+		}
+		return "500 5.5.1 unknown command\r\n"
+	}
+}
+
+func TestCheckStartTLSStripping(t *testing.T) {
+	tests := []struct {
+		name           string
+		greeting       string
+		handle         func(string) string
+		wantMode       string
+		wantMechanisms []string
+		wantPlaintext  bool
+	}{
+		{
+			name:           "smtp auth before starttls",
+			greeting:       "220 mail.acme.com ESMTP",
+			handle:         smtpHandler([]string{"STARTTLS", "AUTH PLAIN LOGIN"}, "334 VXNlcm5hbWU6"),
+			wantMode:       TLSModeStartTLS,
+			wantMechanisms: []string{"PLAIN", "LOGIN"},
+			wantPlaintext:  true,
+		},
+		{
+			name:           "smtp enforcing starttls",
+			greeting:       "220 mail.acme.com ESMTP",
+			handle:         smtpHandler([]string{"STARTTLS", "8BITMIME"}, "530 5.7.0 Must issue a STARTTLS command first"),
+			wantMode:       TLSModeStartTLS,
+			wantMechanisms: []string{},
+		},
+		{
+			name:     "smtp without starttls",
+			greeting: "220 mail.acme.com ESMTP",
+			handle:   smtpHandler([]string{"AUTH PLAIN LOGIN"}, "334 VXNlcm5hbWU6"),
+			wantMode: TLSModePlaintext,
+			// plaintext only services are not checked
+			wantMechanisms: []string{},
+		},
+		{
+			name:           "imap login allowed",
+			greeting:       "* OK [CAPABILITY IMAP4rev1 STARTTLS AUTH=PLAIN] ready",
+			handle:         func(string) string { return "" },
+			wantMode:       TLSModeStartTLS,
+			wantMechanisms: []string{"PLAIN"},
+			wantPlaintext:  true,
+		},
+		{
+			name:     "imap login disabled",
+			greeting: "* OK ready",
+			handle: func(line string) string {
+				switch {
+				case strings.HasPrefix(line, "a001 CAPABILITY"):
+					return "* CAPABILITY IMAP4rev1 STARTTLS LOGINDISABLED\r\na001 OK done\r\n"
+				case strings.HasPrefix(line, "a002 "):
+					return "a002 NO [PRIVACYREQUIRED] tls required\r\n"
+				}
+				return ""
+			},
+			wantMode:       TLSModeStartTLS,
+			wantMechanisms: []string{},
+		},
+		{
+			name:     "pop3 user allowed",
+			greeting: "+OK ready",
+			handle: func(line string) string {
+				switch line {
+				case "CAPA":
+					return "+OK capabilities\r\nUSER\r\nSTLS\r\n.\r\n"
+				case "USER nuclei":
+					return "+OK send password\r\n"
+				}
+				return "-ERR unknown command\r\n"
+			},
+			wantMode:       TLSModeStartTLS,
+			wantMechanisms: []string{},
+			wantPlaintext:  true,
+		},
+		{
+			name:     "pop3 enforcing stls",
+			greeting: "+OK ready",
+			handle: func(line string) string {
+				switch {
+				case line == "CAPA":
+					return "+OK capabilities\r\nSTLS\r\nSASL PLAIN\r\n.\r\n"
+				case strings.HasPrefix(line, "USER"), strings.HasPrefix(line, "AUTH"):
+					return "-ERR tls required\r\n"
+				}
+				return "-ERR unknown command\r\n"
+			},
+			wantMode:       TLSModeStartTLS,
+			wantMechanisms: []string{"PLAIN"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := jstest.Context(t)
+			port := mailServer(t, test.greeting, test.handle)
+
+			resp, err := CheckStartTLSStripping(ctx, "127.0.0.1", port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Mode != test.wantMode || resp.Banner != test.greeting {
+				t.Fatalf("expected mode %s with banner %q, got %+v", test.wantMode, test.greeting, resp)
+			}
+			if !reflect.DeepEqual(resp.Mechanisms, test.wantMechanisms) {
+				t.Fatalf("expected mechanisms %v, got %v", test.wantMechanisms, resp.Mechanisms)
+			}
+			if resp.PlaintextAuth != test.wantPlaintext {
+				t.Fatalf("expected plaintext auth %v, got %+v", test.wantPlaintext, resp)
+			}
+		})
+	}
+}
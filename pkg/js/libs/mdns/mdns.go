@@ -0,0 +1,252 @@
+package mdns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	servicesQuery = "_services._dns-sd._udp.local."
+	mdnsPort      = 5353
+)
+
+var (
+	mdnsGroup = net.IPv4(224, 0, 0, 251)
+)
+
+type (
+	// QueryServicesResponse is the response from the QueryServices function.
+	// this is returned by QueryServices function.
+	// @example
+	// ```javascript
+	// const mdns = require('nuclei/mdns');
+	// const services = mdns.QueryServices('192.168.1.10');
+	// log(toJSON(services));
+	// ```
+	QueryServicesResponse struct {
+		// Services contains advertised service types (ex: _http._tcp.local)
+		Services []string
+		// Instances contains advertised service instances
+		Instances []ServiceInstance
+	}
+
+	// ServiceInstance is a DNS-SD service instance advertised over mDNS.
+	// @example
+	// ```javascript
+	// const mdns = require('nuclei/mdns');
+	// const services = mdns.QueryServices('192.168.1.10');
+	// for (const instance of services.Instances) {
+	//   log(instance.Name, instance.Target, instance.Port);
+	// }
+	// ```
+	ServiceInstance struct {
+		Name    string
+		Service string
+		Target  string
+		Port    int
+		TXT     []string
+	}
+)
+
+// QueryServices sends a DNS-SD service enumeration query (PTR _services._dns-sd._udp.local)
+// to the given host over mDNS and returns the advertised service types and their instances.
+// If host is empty the query is sent to the mDNS multicast group (224.0.0.251) and
+// responses from all responders are collected until the read timeout is reached.
+// @example
+// ```javascript
+// const mdns = require('nuclei/mdns');
+// const services = mdns.QueryServices('192.168.1.10');
+// log(toJSON(services));
+// ```
+func QueryServices(ctx context.Context, host string) (QueryServicesResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedqueryServices(executionId, host)
+}
+
+// @memo(nodisk)
+func queryServices(executionId string, host string) (QueryServicesResponse, error) {
+	resp := QueryServicesResponse{}
+	dst, err := protocolstate.ResolveUDPTarget(executionId, host, mdnsGroup, mdnsPort)
+	if err != nil {
+		return resp, err
+	}
+
+	msgs, err := query(executionId, dst, dns.Question{Name: servicesQuery, Qtype: dns.TypePTR, Qclass: dns.ClassINET})
+	if err != nil {
+		return resp, err
+	}
+	seen := map[string]struct{}{}
+	for _, msg := range msgs {
+		for _, rr := range append(msg.Answer, msg.Extra...) {
+			if ptr, ok := rr.(*dns.PTR); ok && strings.EqualFold(ptr.Hdr.Name, servicesQuery) {
+				if _, ok := seen[ptr.Ptr]; !ok {
+					seen[ptr.Ptr] = struct{}{}
+					resp.Services = append(resp.Services, strings.TrimSuffix(ptr.Ptr, "."))
+				}
+			}
+		}
+	}
+	if len(resp.Services) == 0 {
+		return resp, nil
+	}
+
+	// enumerate instances of advertised service types
+	questions := make([]dns.Question, 0, len(resp.Services))
+	for _, service := range resp.Services {
+		questions = append(questions, dns.Question{Name: dns.Fqdn(service), Qtype: dns.TypePTR, Qclass: dns.ClassINET})
+	}
+	msgs, err = query(executionId, dst, questions...)
+	if err != nil {
+		return resp, err
+	}
+	resp.Instances = parseInstances(msgs)
+	return resp, nil
+}
+
+// ResolveName resolves the given .local name using mDNS multicast
+// A and AAAA queries and returns the resolved addresses.
+// @example
+// ```javascript
+// const mdns = require('nuclei/mdns');
+// const addrs = mdns.ResolveName('printer.local');
+// log(addrs);
+// ```
+func ResolveName(ctx context.Context, name string) ([]string, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedresolveName(executionId, name)
+}
+
+// @memo(nodisk)
+func resolveName(executionId string, name string) ([]string, error) {
+	dst := &net.UDPAddr{IP: mdnsGroup, Port: mdnsPort}
+	fqdn := dns.Fqdn(name)
+	msgs, err := query(executionId, dst,
+		dns.Question{Name: fqdn, Qtype: dns.TypeA, Qclass: dns.ClassINET},
+		dns.Question{Name: fqdn, Qtype: dns.TypeAAAA, Qclass: dns.ClassINET},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []string
+	seen := map[string]struct{}{}
+	for _, msg := range msgs {
+		for _, rr := range append(msg.Answer, msg.Extra...) {
+			if !strings.EqualFold(rr.Header().Name, fqdn) {
+				continue
+			}
+			var addr string
+			switch v := rr.(type) {
+			case *dns.A:
+				addr = v.A.String()
+			case *dns.AAAA:
+				addr = v.AAAA.String()
+			default:
+				continue
+			}
+			if _, ok := seen[addr]; !ok {
+				seen[addr] = struct{}{}
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// query sends a one-shot (legacy unicast) mdns query from an ephemeral port and
+// collects all responses received until the read timeout of the execution.
+// responders reply directly to the source port of one-shot queries (RFC 6762 6.7)
+func query(executionId string, dst *net.UDPAddr, questions ...dns.Question) ([]*dns.Msg, error) {
+	conn, err := protocolstate.ListenUDP(executionId, "0.0.0.0:0", false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	msg := new(dns.Msg)
+	msg.Id = dns.Id()
+	msg.Question = questions
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(packed, dst); err != nil {
+		return nil, err
+	}
+
+	_ = conn.SetReadDeadline(protocolstate.GetDeadline(executionId, protocolstate.GetTimeouts(executionId).TcpReadTimeout))
+	var msgs []*dns.Msg
+	buff := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFromUDP(buff)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return msgs, err
+		}
+		reply := new(dns.Msg)
+		if err := reply.Unpack(buff[:n]); err != nil || !reply.Response {
+			continue
+		}
+		// responders echo the query id in unicast replies
+		if reply.Id != 0 && reply.Id != msg.Id {
+			continue
+		}
+		msgs = append(msgs, reply)
+		if !dst.IP.IsMulticast() {
+			// unicast target has answered
+			break
+		}
+	}
+	return msgs, nil
+}
+
+// parseInstances extracts service instances from PTR, SRV and TXT records
+func parseInstances(msgs []*dns.Msg) []ServiceInstance {
+	instances := map[string]*ServiceInstance{}
+	var order []string
+	get := func(name string) *ServiceInstance {
+		key := strings.ToLower(name)
+		if instance, ok := instances[key]; ok {
+			return instance
+		}
+		instance := &ServiceInstance{Name: strings.TrimSuffix(name, ".")}
+		instances[key] = instance
+		order = append(order, key)
+		return instance
+	}
+	for _, msg := range msgs {
+		records := append(msg.Answer, msg.Extra...)
+		for _, rr := range records {
+			if ptr, ok := rr.(*dns.PTR); ok {
+				get(ptr.Ptr).Service = strings.TrimSuffix(ptr.Hdr.Name, ".")
+			}
+		}
+		for _, rr := range records {
+			switch v := rr.(type) {
+			case *dns.SRV:
+				if instance, ok := instances[strings.ToLower(v.Hdr.Name)]; ok {
+					instance.Target = strings.TrimSuffix(v.Target, ".")
+					instance.Port = int(v.Port)
+				}
+			case *dns.TXT:
+				if instance, ok := instances[strings.ToLower(v.Hdr.Name)]; ok {
+					instance.TXT = v.Txt
+				}
+			}
+		}
+	}
+	result := make([]ServiceInstance, 0, len(order))
+	for _, key := range order {
+		result = append(result, *instances[key])
+	}
+	return result
+}
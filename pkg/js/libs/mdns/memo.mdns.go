@@ -0,0 +1,49 @@
+// Warning - This is generated code
+package mdns
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[QueryServicesResponse]("mdns.queryServices", 2)
+}
+
+func memoizedqueryServices(executionId string, host string) (QueryServicesResponse, error) {
+	hash := "mdns.queryServices" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return queryServices(executionId, host)
+	})
+	if err != nil {
+		return QueryServicesResponse{}, err
+	}
+	if value, ok := v.(QueryServicesResponse); ok {
+		return value, nil
+	}
+
+	return QueryServicesResponse{}, errors.New("could not convert cached result")
+}
+
+func init() {
+	protocolstate.RegisterMemoized[[]string]("mdns.resolveName", 2)
+}
+
+func memoizedresolveName(executionId string, name string) ([]string, error) {
+	hash := "mdns.resolveName" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(name)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return resolveName(executionId, name)
+	})
+	if err != nil {
+		return []string{}, err
+	}
+	if value, ok := v.([]string); ok {
+		return value, nil
+	}
+
+	return []string{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,35 @@
+// Warning - This is generated code
+package minecraft
+
+import (
+	"context"
+	"fmt"
+
+	"errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[ServerListPingResponse]("minecraft.serverListPing", 3)
+}
+
+func memoizedserverListPing(ctx context.Context, executionId string, host string, port int) (ServerListPingResponse, error) {
+	hash := "minecraft.serverListPing" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "minecraft.serverListPing" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (ServerListPingResponse, error) {
+			return serverListPing(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return ServerListPingResponse{}, err
+	}
+	if value, ok := v.(ServerListPingResponse); ok {
+		return value, nil
+	}
+
+	return ServerListPingResponse{}, errors.New("could not convert cached result")
+}
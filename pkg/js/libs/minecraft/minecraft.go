@@ -0,0 +1,248 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/json"
+)
+
+const (
+	// timeout of the server list ping exchange
+	probeTimeout = 5 * time.Second
+	// protocol version sent in handshake (-1 is used by clients to query the server version)
+	handshakeProtocolVersion = -1
+	// next state requested in handshake (1 = status)
+	statusState = 1
+	// maximum size of a minecraft packet (largest 3 byte varint)
+	maxPacketSize = 1<<21 - 1
+)
+
+var (
+	errNotMinecraft = errors.New("not a minecraft response")
+)
+
+type (
+	// ServerListPingResponse is the response from the ServerListPing function.
+	// this is returned by ServerListPing function.
+	// @example
+	// ```javascript
+	// const minecraft = require('nuclei/minecraft');
+	// const status = minecraft.ServerListPing('acme.com', 25565);
+	// log(toJSON(status));
+	// ```
+	ServerListPingResponse struct {
+		// IsMinecraft is true if the service responded with a status
+		IsMinecraft bool
+		// Version is the name of the server version (ex: 1.20.4)
+		Version string
+		// Protocol is the protocol version number of the server
+		Protocol int
+		// MOTD is the message of the day without formatting codes
+		MOTD          string
+		OnlinePlayers int
+		MaxPlayers    int
+		// Players contains the names of the player sample if any
+		Players []string
+		// Raw is the json status returned by server
+		Raw string
+	}
+
+	// status is the json status returned by server
+	status struct {
+		Version *struct {
+			Name     string `json:"name"`
+			Protocol int    `json:"protocol"`
+		} `json:"version"`
+		Players struct {
+			Max    int `json:"max"`
+			Online int `json:"online"`
+			Sample []struct {
+				Name string `json:"name"`
+			} `json:"sample"`
+		} `json:"players"`
+		Description json.Message `json:"description"`
+	}
+
+	// chatComponent is a text component used in the description
+	chatComponent struct {
+		Text  string         `json:"text"`
+		Extra []json.Message `json:"extra"`
+	}
+)
+
+// ServerListPing performs a server list ping (handshake followed by a status
+// request) on the given host and port and returns the server status
+// (version, protocol, MOTD and player counts).
+// @example
+// ```javascript
+// const minecraft = require('nuclei/minecraft');
+// const status = minecraft.ServerListPing('acme.com', 25565);
+// log(status.Version, status.MOTD, status.OnlinePlayers);
+// ```
+func ServerListPing(ctx context.Context, host string, port int) (ServerListPingResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedserverListPing(ctx, executionId, host, port)
+}
+
+// @memo
+func serverListPing(ctx context.Context, executionId string, host string, port int) (ServerListPingResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return ServerListPingResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return ServerListPingResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Write(statusRequest(host, port)); err != nil {
+		return ServerListPingResponse{}, err
+	}
+	resp, err := readStatus(bufio.NewReader(utils.LimitConn(executionId, conn)))
+	if errors.Is(err, errNotMinecraft) {
+		return ServerListPingResponse{}, nil
+	}
+	return resp, err
+}
+
+// statusRequest returns the handshake and status request packets
+func statusRequest(host string, port int) []byte {
+	var handshake []byte
+	handshake = appendVarInt(handshake, 0x00)
+	handshake = appendVarInt(handshake, handshakeProtocolVersion)
+	handshake = appendVarInt(handshake, int32(len(host)))
+	handshake = append(handshake, host...)
+	handshake = binary.BigEndian.AppendUint16(handshake, uint16(port))
+	handshake = appendVarInt(handshake, statusState)
+
+	var data []byte
+	data = appendVarInt(data, int32(len(handshake)))
+	data = append(data, handshake...)
+	// status request packet (length 1, id 0x00)
+	return append(data, 0x01, 0x00)
+}
+
+// readStatus reads and parses a status response packet
+func readStatus(reader *bufio.Reader) (ServerListPingResponse, error) {
+	resp := ServerListPingResponse{}
+	length, err := readVarInt(reader)
+	if err != nil {
+		return resp, err
+	}
+	if length <= 0 || length > maxPacketSize {
+		return resp, errNotMinecraft
+	}
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(reader, packet); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+			return resp, errNotMinecraft
+		}
+		return resp, err
+	}
+
+	packetReader := bytes.NewReader(packet)
+	if packetID, err := readVarInt(packetReader); err != nil || packetID != 0x00 {
+		return resp, errNotMinecraft
+	}
+	size, err := readVarInt(packetReader)
+	if err != nil || size <= 0 || int(size) > packetReader.Len() {
+		return resp, errNotMinecraft
+	}
+	raw := make([]byte, size)
+	_, _ = io.ReadFull(packetReader, raw)
+
+	var parsed status
+	if err := json.Unmarshal(raw, &parsed); err != nil || parsed.Version == nil {
+		return resp, errNotMinecraft
+	}
+	resp.IsMinecraft = true
+	resp.Version = parsed.Version.Name
+	resp.Protocol = parsed.Version.Protocol
+	resp.MOTD = stripFormatting(flattenChat(parsed.Description))
+	resp.OnlinePlayers = parsed.Players.Online
+	resp.MaxPlayers = parsed.Players.Max
+	for _, player := range parsed.Players.Sample {
+		resp.Players = append(resp.Players, player.Name)
+	}
+	resp.Raw = string(raw)
+	return resp, nil
+}
+
+// flattenChat returns the text of a chat component which is
+// either a plain string or an object with nested extra components
+func flattenChat(data json.Message) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return text
+	}
+	var component chatComponent
+	if err := json.Unmarshal(data, &component); err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(component.Text)
+	for _, extra := range component.Extra {
+		sb.WriteString(flattenChat(extra))
+	}
+	return sb.String()
+}
+
+// stripFormatting removes legacy formatting codes (ex: §a) from text
+func stripFormatting(text string) string {
+	var sb strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '§' {
+			i++
+			continue
+		}
+		sb.WriteRune(runes[i])
+	}
+	return sb.String()
+}
+
+// appendVarInt appends the minecraft varint encoding of value to data
+func appendVarInt(data []byte, value int32) []byte {
+	v := uint32(value)
+	for v >= 0x80 {
+		data = append(data, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(data, byte(v))
+}
+
+// readVarInt reads a minecraft varint (at most 5 bytes)
+func readVarInt(reader io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := reader.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+				return 0, errNotMinecraft
+			}
+			return 0, err
+		}
+		value |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, errNotMinecraft
+}
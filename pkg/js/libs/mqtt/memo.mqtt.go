@@ -0,0 +1,29 @@
+// Warning - This is generated code
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[SampleTopicsResponse]("mqtt.sampleTopics", 4)
+}
+
+func memoizedsampleTopics(executionId string, host string, port int, opts SampleTopicsOptions) (SampleTopicsResponse, error) {
+	hash := "mqtt.sampleTopics" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(opts)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return sampleTopics(executionId, host, port, opts)
+	})
+	if err != nil {
+		return SampleTopicsResponse{}, err
+	}
+	if value, ok := v.(SampleTopicsResponse); ok {
+		return value, nil
+	}
+
+	return SampleTopicsResponse{}, errors.New("could not convert cached result")
+}
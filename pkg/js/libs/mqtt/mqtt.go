@@ -0,0 +1,342 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the connect and subscribe exchange
+	connectTimeout = 5 * time.Second
+
+	// default and maximum sampling window
+	defaultDuration = 5 * time.Second
+	maxDuration     = 60 * time.Second
+	// default and maximum number of collected topics
+	defaultMaxTopics = 100
+	maxTopics        = 1000
+	// maximum size of a sampled payload
+	maxPayloadSample = 1024
+	// maximum number of bytes read while sampling
+	maxSampleBytes = 1024 * 1024
+
+	// control packet types (upper nibble of the fixed header)
+	packetConnect    = 0x10
+	packetConnAck    = 0x20
+	packetPublish    = 0x30
+	packetSubscribe  = 0x82
+	packetSubAck     = 0x90
+	packetDisconnect = 0xe0
+
+	// connect flags
+	flagCleanSession = 0x02
+	flagPassword     = 0x40
+	flagUsername     = 0x80
+
+	subscribePacketId = 1
+)
+
+var (
+	errInvalidPacket = errors.New("invalid mqtt packet")
+)
+
+type (
+	// SampleTopicsOptions contains options for SampleTopics function.
+	// @example
+	// ```javascript
+	// const mqtt = require('nuclei/mqtt');
+	// const resp = mqtt.SampleTopics('acme.com', 1883, { Topic: 'devices/#', Duration: 10, MaxPayload: 64 });
+	// ```
+	SampleTopicsOptions struct {
+		// Topic is the topic filter to subscribe to (default: #)
+		Topic string
+		// Duration is the sampling window in seconds (default: 5, max: 60)
+		Duration int
+		// MaxTopics is the maximum number of collected topics (default: 100, max: 1000)
+		MaxTopics int
+		// MaxPayload is the number of payload bytes sampled per topic (default: 0, max: 1024)
+		MaxPayload int
+		// Username is the username used to connect (optional)
+		Username string
+		// Password is the password used to connect (optional)
+		Password string
+		// Memoize returns the memoized result of a previous sampling of the
+		// same target and options instead of sampling again
+		Memoize bool
+	}
+
+	// SampleTopicsResponse is the response from the SampleTopics function.
+	// this is returned by SampleTopics function.
+	// @example
+	// ```javascript
+	// const mqtt = require('nuclei/mqtt');
+	// const resp = mqtt.SampleTopics('acme.com', 1883);
+	// log(toJSON(resp));
+	// ```
+	SampleTopicsResponse struct {
+		// Connected is true if the broker accepted the connection
+		Connected bool
+		// ReturnCode is the connack return code (ex: 5 for not authorized)
+		ReturnCode int
+		// Subscribed is true if the broker accepted the subscription
+		Subscribed bool
+		// Topics are the topic names observed during the window
+		Topics []string
+		// Samples contains the truncated first payload of each topic (if MaxPayload is set)
+		Samples map[string]string
+		// Messages is the number of received messages
+		Messages int
+		// Truncated is true if collection stopped due to the topic or byte limits
+		Truncated bool
+		// TimedOut is true if the scan deadline ended the sampling before the
+		// window, the collected topics are returned anyway
+		TimedOut bool
+	}
+)
+
+// SampleTopics connects to the mqtt broker (3.1.1) at the given host and port,
+// subscribes to the topic filter (default: #) and collects the topic names of
+// messages published during a bounded window. Open brokers leaking data can be
+// detected with it. The number of topics, bytes and the window are capped and
+// the result is not memoized unless Memoize is set. Sampling cut short by the
+// scan deadline returns the topics collected so far with TimedOut set.
+// @example
+// ```javascript
+// const mqtt = require('nuclei/mqtt');
+// const resp = mqtt.SampleTopics('acme.com', 1883, { Duration: 5 });
+// if (resp.Topics.length > 0) { log(resp.Topics); }
+// ```
+func SampleTopics(ctx context.Context, host string, port int, opts SampleTopicsOptions) (SampleTopicsResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if opts.Memoize {
+		return memoizedsampleTopics(executionId, host, port, opts)
+	}
+	return sampleTopics(executionId, host, port, opts)
+}
+
+// @memo(nodisk)
+func sampleTopics(executionId string, host string, port int, opts SampleTopicsOptions) (SampleTopicsResponse, error) {
+	resp := SampleTopicsResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if opts.Topic == "" {
+		opts.Topic = "#"
+	}
+	duration := time.Duration(opts.Duration) * time.Second
+	if duration <= 0 {
+		duration = defaultDuration
+	}
+	duration = min(duration, maxDuration)
+	topicLimit := opts.MaxTopics
+	if topicLimit <= 0 {
+		topicLimit = defaultMaxTopics
+	}
+	topicLimit = min(topicLimit, maxTopics)
+	payloadLimit := min(max(opts.MaxPayload, 0), maxPayloadSample)
+
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), connectTimeout+duration)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if err := conn.SetDeadline(protocolstate.GetDeadline(executionId, connectTimeout)); err != nil {
+		return resp, err
+	}
+	limited := &io.LimitedReader{R: utils.LimitConn(executionId, conn), N: maxSampleBytes}
+	reader := bufio.NewReader(limited)
+
+	connect, err := newConnect(opts.Username, opts.Password)
+	if err != nil {
+		return resp, err
+	}
+	if _, err := conn.Write(connect); err != nil {
+		return resp, err
+	}
+	packetType, data, err := readPacket(reader)
+	if err != nil || packetType != packetConnAck || len(data) != 2 {
+		// not a mqtt broker
+		return resp, nil
+	}
+	resp.ReturnCode = int(data[1])
+	if data[1] != 0 {
+		return resp, nil
+	}
+	resp.Connected = true
+
+	if _, err := conn.Write(newSubscribe(opts.Topic)); err != nil {
+		return resp, err
+	}
+	windowEnd := time.Now().Add(duration)
+	deadline := protocolstate.GetDeadline(executionId, duration)
+	if err := conn.SetDeadline(deadline); err != nil {
+		return resp, err
+	}
+	seen := map[string]struct{}{}
+	defer func() {
+		_, _ = conn.Write([]byte{packetDisconnect, 0x00})
+	}()
+	for {
+		packetType, data, err := readPacket(reader)
+		if err != nil {
+			// end of the window, byte limit or closed connection
+			resp.Truncated = limited.N <= 0
+			resp.TimedOut = deadline.Before(windowEnd) && isTimeout(err)
+			return resp, nil
+		}
+		switch packetType & 0xf0 {
+		case packetSubAck:
+			if len(data) >= 3 && binary.BigEndian.Uint16(data[:2]) == subscribePacketId && data[2] < 0x80 {
+				resp.Subscribed = true
+			}
+		case packetPublish:
+			topic, payload, err := parsePublish(packetType, data)
+			if err != nil {
+				return resp, nil
+			}
+			resp.Messages++
+			if _, ok := seen[topic]; ok {
+				continue
+			}
+			if len(seen) >= topicLimit {
+				resp.Truncated = true
+				return resp, nil
+			}
+			seen[topic] = struct{}{}
+			resp.Topics = append(resp.Topics, topic)
+			if payloadLimit > 0 {
+				if resp.Samples == nil {
+					resp.Samples = map[string]string{}
+				}
+				resp.Samples[topic] = string(payload[:min(len(payload), payloadLimit)])
+			}
+		}
+	}
+}
+
+// isTimeout checks if err is caused by the deadline of the connection
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// newConnect returns a connect packet with a random client id
+func newConnect(username string, password string) ([]byte, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	flags := byte(flagCleanSession)
+	payload := appendString(nil, "nuclei-"+hex.EncodeToString(id))
+	if username != "" {
+		flags |= flagUsername
+		payload = appendString(payload, username)
+		if password != "" {
+			flags |= flagPassword
+			payload = appendString(payload, password)
+		}
+	}
+	// protocol name, level 4 (3.1.1), flags and keep alive
+	body := appendString(nil, "MQTT")
+	body = append(body, 0x04, flags)
+	body = binary.BigEndian.AppendUint16(body, 60)
+	body = append(body, payload...)
+	return newPacket(packetConnect, body), nil
+}
+
+// newSubscribe returns a subscribe packet for topic with qos 0
+func newSubscribe(topic string) []byte {
+	body := binary.BigEndian.AppendUint16(nil, subscribePacketId)
+	body = appendString(body, topic)
+	body = append(body, 0x00)
+	return newPacket(packetSubscribe, body)
+}
+
+// newPacket frames body with the fixed header
+func newPacket(packetType byte, body []byte) []byte {
+	packet := []byte{packetType}
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// appendString appends a length prefixed utf-8 string
+func appendString(data []byte, value string) []byte {
+	data = binary.BigEndian.AppendUint16(data, uint16(len(value)))
+	return append(data, value...)
+}
+
+// readPacket reads a control packet and returns its first byte and body
+func readPacket(reader *bufio.Reader) (byte, []byte, error) {
+	packetType, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length := 0
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errInvalidPacket
+		}
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length |= int(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if length > maxSampleBytes {
+		return 0, nil, errInvalidPacket
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return 0, nil, err
+	}
+	return packetType, data, nil
+}
+
+// parsePublish returns the topic and payload of a publish packet
+func parsePublish(packetType byte, data []byte) (string, []byte, error) {
+	if len(data) < 2 {
+		return "", nil, errInvalidPacket
+	}
+	length := int(binary.BigEndian.Uint16(data[:2]))
+	if 2+length > len(data) {
+		return "", nil, errInvalidPacket
+	}
+	topic := string(data[2 : 2+length])
+	data = data[2+length:]
+	if qos := (packetType >> 1) & 0x03; qos > 0 {
+		// packet identifier
+		if len(data) < 2 {
+			return "", nil, errInvalidPacket
+		}
+		data = data[2:]
+	}
+	return topic, data, nil
+}
@@ -0,0 +1,93 @@
+package mqtt
+
+import (
+	"bufio"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// brokerListener accepts connections, subscribes them and publishes the
+// given topics before stalling until the client disconnects
+func brokerListener(t *testing.T, topics []string) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				reader := bufio.NewReader(conn)
+				if packetType, _, err := readPacket(reader); err != nil || packetType != packetConnect {
+					return
+				}
+				_, _ = conn.Write([]byte{packetConnAck, 0x02, 0x00, 0x00})
+				if packetType, _, err := readPacket(reader); err != nil || packetType != packetSubscribe {
+					return
+				}
+				_, _ = conn.Write([]byte{packetSubAck, 0x03, 0x00, subscribePacketId, 0x00})
+				for _, topic := range topics {
+					_, _ = conn.Write(newPacket(packetPublish, appendString(nil, topic)))
+				}
+				// stall until the disconnect of the client
+				_, _, _ = readPacket(reader)
+			}()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return portNum
+}
+
+func TestSampleTopics(t *testing.T) {
+	ctx := jstest.Context(t)
+	topics := []string{"devices/a/temp", "devices/b/temp"}
+	port := brokerListener(t, topics)
+
+	resp, err := SampleTopics(ctx, "127.0.0.1", port, SampleTopicsOptions{Duration: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Connected || !resp.Subscribed || resp.TimedOut || resp.Messages != 2 {
+		t.Fatalf("expected a complete sampling window, got %+v", resp)
+	}
+	if !reflect.DeepEqual(resp.Topics, topics) {
+		t.Fatalf("expected topics %v, got %v", topics, resp.Topics)
+	}
+}
+
+func TestSampleTopicsScanDeadline(t *testing.T) {
+	executionId := jstest.Init(t)
+	ctx := jstest.ExecutionContext(executionId)
+	topics := []string{"devices/a/temp", "devices/b/temp"}
+	port := brokerListener(t, topics)
+
+	// the scan deadline cuts the sampling window short
+	protocolstate.SetScanDeadline(executionId, time.Now().Add(500*time.Millisecond))
+	start := time.Now()
+	resp, err := SampleTopics(ctx, "127.0.0.1", port, SampleTopicsOptions{Duration: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected sampling to stop at the scan deadline, took %s", elapsed)
+	}
+	if !resp.TimedOut || resp.Truncated {
+		t.Fatalf("expected sampling to be timed out, got %+v", resp)
+	}
+	if !reflect.DeepEqual(resp.Topics, topics) {
+		t.Fatalf("expected topics collected before the deadline %v, got %v", topics, resp.Topics)
+	}
+}
@@ -2,7 +2,9 @@
 package mssql
 
 import (
+	"context"
 	"errors"
+
 	"fmt"
 
 	_ "github.com/microsoft/go-mssqldb"
@@ -10,11 +12,19 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
+func init() {
+	protocolstate.RegisterMemoized[bool]("mssql.connect", 6)
+}
+
 func memoizedconnect(executionId string, host string, port int, username string, password string, dbName string) (bool, error) {
-	hash := "connect" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName)
+	hash := "mssql.connect" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "mssql.connect" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return connect(executionId, host, port, username, password, dbName)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return connect(executionId, host, port, username, password, dbName)
+		})
 	})
 	if err != nil {
 		return false, err
@@ -26,11 +36,19 @@ func memoizedconnect(executionId string, host string, port int, username string,
 	return false, errors.New("could not convert cached result")
 }
 
-func memoizedisMssql(executionId string, host string, port int) (bool, error) {
-	hash := "isMssql" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[bool]("mssql.isMssql", 3)
+}
+
+func memoizedisMssql(ctx context.Context, executionId string, host string, port int) (bool, error) {
+	hash := "mssql.isMssql" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "mssql.isMssql" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isMssql(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return isMssql(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return false, err
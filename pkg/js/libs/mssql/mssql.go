@@ -111,22 +111,17 @@ func connect(executionId string, host string, port int, username string, passwor
 // ```
 func (c *MSSQLClient) IsMssql(ctx context.Context, host string, port int) (bool, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedisMssql(executionId, host, port)
+	return memoizedisMssql(ctx, executionId, host, port)
 }
 
 // @memo
-func isMssql(executionId string, host string, port int) (bool, error) {
+func isMssql(ctx context.Context, executionId string, host string, port int) (bool, error) {
 	if !protocolstate.IsHostAllowed(executionId, host) {
 		// host is not valid according to network policy
 		return false, protocolstate.ErrHostDenied.Msgf(host)
 	}
 
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return false, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)), 5*time.Second)
 	if err != nil {
 		return false, err
 	}
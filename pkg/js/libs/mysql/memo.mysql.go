@@ -2,17 +2,27 @@
 package mysql
 
 import (
+	"context"
 	"errors"
+
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisMySQL(executionId string, host string, port int) (bool, error) {
-	hash := "isMySQL" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[bool]("mysql.isMySQL", 3)
+}
+
+func memoizedisMySQL(ctx context.Context, executionId string, host string, port int) (bool, error) {
+	hash := "mysql.isMySQL" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "mysql.isMySQL" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isMySQL(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return isMySQL(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return false, err
@@ -24,11 +34,19 @@ func memoizedisMySQL(executionId string, host string, port int) (bool, error) {
 	return false, errors.New("could not convert cached result")
 }
 
+func init() {
+	protocolstate.RegisterMemoized[MySQLInfo]("mysql.fingerprintMySQL", 3)
+}
+
 func memoizedfingerprintMySQL(executionId string, host string, port int) (MySQLInfo, error) {
-	hash := "fingerprintMySQL" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	hash := "mysql.fingerprintMySQL" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "mysql.fingerprintMySQL" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return fingerprintMySQL(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (MySQLInfo, error) {
+			return fingerprintMySQL(executionId, host, port)
+		})
 	})
 	if err != nil {
 		return MySQLInfo{}, err
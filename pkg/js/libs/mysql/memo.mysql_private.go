@@ -8,9 +8,13 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedconnectWithDSN(dsn string) (bool, error) {
-	hash := "connectWithDSN" + ":" + fmt.Sprint(dsn)
+func init() {
+	protocolstate.RegisterMemoized[bool]("mysql.connectWithDSN", 1)
+}
 
+func memoizedconnectWithDSN(dsn string) (bool, error) {
+	hash := "mysql.connectWithDSN" + ":" + fmt.Sprint(dsn)
+	// results are only memoized in memory (nodisk)
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
 		return connectWithDSN(dsn)
 	})
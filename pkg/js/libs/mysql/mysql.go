@@ -38,21 +38,16 @@ type (
 func (c *MySQLClient) IsMySQL(ctx context.Context, host string, port int) (bool, error) {
 	executionId := ctx.Value("executionId").(string)
 	// todo: why this is exposed? Service fingerprint should be automatic
-	return memoizedisMySQL(executionId, host, port)
+	return memoizedisMySQL(ctx, executionId, host, port)
 }
 
 // @memo
-func isMySQL(executionId string, host string, port int) (bool, error) {
+func isMySQL(ctx context.Context, executionId string, host string, port int) (bool, error) {
 	if !protocolstate.IsHostAllowed(executionId, host) {
 		// host is not valid according to network policy
 		return false, protocolstate.ErrHostDenied.Msgf(host)
 	}
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return false, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)), 5*time.Second)
 	if err != nil {
 		return false, err
 	}
@@ -146,15 +141,16 @@ func fingerprintMySQL(executionId string, host string, port int) (MySQLInfo, err
 		// host is not valid according to network policy
 		return info, protocolstate.ErrHostDenied.Msgf(host)
 	}
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return MySQLInfo{}, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return MySQLInfo{}, err
 	}
 
 	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)))
 	if err != nil {
 		return info, err
 	}
+	conn = protocolstate.RecordConn(executionId, conn)
 	defer func() {
 		_ = conn.Close()
 	}()
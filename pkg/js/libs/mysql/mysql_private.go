@@ -71,7 +71,7 @@ func BuildDSN(opts MySQLOptions) (string, error) {
 	return dsn.String(), nil
 }
 
-// @memo
+// @memo(nodisk)
 func connectWithDSN(dsn string) (bool, error) {
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -0,0 +1,127 @@
+package net
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// maximum number of response bytes read for a probe
+	maxAmplificationResponse = 1024 * 1024
+	// maximum size of a udp datagram
+	maxDatagramSize = 64 * 1024
+)
+
+var (
+	// time to wait for the first response datagram
+	amplificationTimeout = 5 * time.Second
+	// time to wait for further response datagrams after the first one
+	amplificationIdleTimeout = time.Second
+)
+
+type (
+	// AmplificationResponse is the amplification measured for a udp probe.
+	// this is returned by MeasureAmplification function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const result = net.MeasureAmplification('acme.com', 11211, '000000000001000073746174730d0a');
+	// log(result.Factor, result.ResponseSize);
+	// ```
+	AmplificationResponse struct {
+		// Factor is the ratio of response bytes to probe bytes (0 without response)
+		Factor float64
+		// RequestSize is the size of the probe in bytes
+		RequestSize int
+		// ResponseSize is the total size of the response datagrams in bytes
+		ResponseSize int
+		// Datagrams is the number of response datagrams received
+		Datagrams int
+		// Truncated is true if the response exceeded the read limit (1MB)
+		Truncated bool
+	}
+)
+
+// MeasureAmplification sends a single udp probe (hex encoded) to the host and
+// port and measures the response to request byte ratio of the udp payloads
+// (ex: ntp monlist, memcached stats, dns any queries, ssdp m-search).
+// Datagrams are read until no more data is received for a second or the
+// response exceeds 1MB. Hosts not answering within 5 seconds or rejecting the
+// probe with an icmp port unreachable return a Factor of 0.
+// Only a single small probe is sent and responses are only received by the
+// scanner, but the probe still triggers the amplified response of the host
+// so it should only be used against hosts in scope and not in tight loops.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// // ntp mode 7 monlist request
+// const result = net.MeasureAmplification('acme.com', 123, '1700032a' + '00'.repeat(4));
+// if (result.Factor > 10) { log('amplification factor', result.Factor); }
+// ```
+func MeasureAmplification(ctx context.Context, host string, port int, probe string) (AmplificationResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedmeasureAmplification(executionId, host, port, probe)
+}
+
+// @memo
+func measureAmplification(executionId string, host string, port int, probe string) (AmplificationResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return AmplificationResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	request, err := hex.DecodeString(probe)
+	if err != nil {
+		return AmplificationResponse{}, err
+	}
+	if len(request) == 0 {
+		return AmplificationResponse{}, errors.New("probe is empty")
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "udp", net.JoinHostPort(host, strconv.Itoa(port)), amplificationTimeout)
+	if err != nil {
+		return AmplificationResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if _, err := conn.Write(request); err != nil {
+		return AmplificationResponse{}, err
+	}
+
+	resp := AmplificationResponse{RequestSize: len(request)}
+	buffer := make([]byte, maxDatagramSize)
+	timeout := amplificationTimeout
+	for {
+		if err := conn.SetReadDeadline(protocolstate.GetDeadline(executionId, timeout)); err != nil {
+			return resp, err
+		}
+		n, err := conn.Read(buffer)
+		if err != nil {
+			if isAmplificationEnd(err) {
+				break
+			}
+			return resp, err
+		}
+		resp.Datagrams++
+		resp.ResponseSize += n
+		if resp.ResponseSize >= maxAmplificationResponse {
+			resp.Truncated = true
+			break
+		}
+		timeout = amplificationIdleTimeout
+	}
+	resp.Factor = float64(resp.ResponseSize) / float64(resp.RequestSize)
+	return resp, nil
+}
+
+// isAmplificationEnd returns true if a read error ends the response
+// (read timeout or icmp port unreachable)
+func isAmplificationEnd(err error) bool {
+	var netErr net.Error
+	return (errors.As(err, &netErr) && netErr.Timeout()) || protocolstate.IsConnectionRefused(err)
+}
@@ -0,0 +1,107 @@
+package net
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// udpResponder answers every datagram with datagrams responses of given size.
+// responses are paced to not overflow the socket buffer of the client
+func udpResponder(t *testing.T, datagrams int, size int) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			_, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			for i := 0; i < datagrams; i++ {
+				_, _ = conn.WriteTo(make([]byte, size), addr)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestMeasureAmplification(t *testing.T) {
+	ctx := jstest.Context(t)
+	timeout, idleTimeout := amplificationTimeout, amplificationIdleTimeout
+	amplificationTimeout, amplificationIdleTimeout = 500*time.Millisecond, 200*time.Millisecond
+	t.Cleanup(func() { amplificationTimeout, amplificationIdleTimeout = timeout, idleTimeout })
+
+	probe := strings.Repeat("00", 8)
+	tests := []struct {
+		name      string
+		datagrams int
+		size      int
+		want      AmplificationResponse
+	}{
+		{
+			name:      "single datagram",
+			datagrams: 1,
+			size:      40,
+			want:      AmplificationResponse{Factor: 5, RequestSize: 8, ResponseSize: 40, Datagrams: 1},
+		},
+		{
+			name:      "multiple datagrams",
+			datagrams: 6,
+			size:      440,
+			want:      AmplificationResponse{Factor: 330, RequestSize: 8, ResponseSize: 2640, Datagrams: 6},
+		},
+		{
+			name:      "silent",
+			datagrams: 0,
+			want:      AmplificationResponse{RequestSize: 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MeasureAmplification(ctx, "127.0.0.1", udpResponder(t, tt.datagrams, tt.size), probe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	// responses are bounded
+	got, err := MeasureAmplification(ctx, "127.0.0.1", udpResponder(t, 40, 32*1024), probe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Truncated || got.ResponseSize < maxAmplificationResponse || got.ResponseSize > maxAmplificationResponse+maxDatagramSize {
+		t.Fatalf("expected truncated response of about %d bytes, got %+v", maxAmplificationResponse, got)
+	}
+
+	// icmp port unreachable
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := closed.LocalAddr().(*net.UDPAddr).Port
+	_ = closed.Close()
+	got, err = MeasureAmplification(ctx, "127.0.0.1", port, probe)
+	if err != nil || got.Factor != 0 || got.Datagrams != 0 {
+		t.Fatalf("expected no amplification for closed port, got %+v: %v", got, err)
+	}
+
+	if _, err := MeasureAmplification(ctx, "127.0.0.1", port, "zz"); err == nil {
+		t.Fatal("expected invalid hex probe to be rejected")
+	}
+	if _, err := MeasureAmplification(ctx, "127.0.0.1", port, ""); err == nil {
+		t.Fatal("expected empty probe to be rejected")
+	}
+}
@@ -0,0 +1,67 @@
+package net
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+type (
+	// ConnInfoResponse contains socket level information of a tcp connection.
+	// this is returned by ConnInfo function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const info = net.ConnInfo('acme.com', 443);
+	// log(info.MSS, info.RemoteAddress);
+	// ```
+	ConnInfoResponse struct {
+		// MSS is the maximum segment size negotiated with the target (ex: 1460)
+		MSS int
+		// LocalAddress is the local address (ip:port) of the connection
+		LocalAddress string
+		// RemoteAddress is the remote address (ip:port) of the connection
+		RemoteAddress string
+	}
+)
+
+// ConnInfo opens a tcp connection to the host and port and returns the
+// maximum segment size negotiated with the target along with the addresses
+// of the connection. The mss is read from the socket options and an error
+// is returned on platforms not exposing it (ex: windows).
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const info = net.ConnInfo('acme.com', 443);
+// if (info.MSS < 1460) { log('mss clamped to', info.MSS); }
+// ```
+func ConnInfo(ctx context.Context, host string, port int) (ConnInfoResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return ConnInfoResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), defaultTimeout)
+	if err != nil {
+		return ConnInfoResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	tcpConn, ok := protocolstate.UnwrapTCPConn(conn)
+	if !ok {
+		return ConnInfoResponse{}, protocolstate.ErrMSSUnsupported
+	}
+	mss, err := protocolstate.ReadMSS(tcpConn)
+	if err != nil {
+		return ConnInfoResponse{}, err
+	}
+	return ConnInfoResponse{
+		MSS:           mss,
+		LocalAddress:  tcpConn.LocalAddr().String(),
+		RemoteAddress: tcpConn.RemoteAddr().String(),
+	}, nil
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package net
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+func TestConnInfo(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	info, err := ConnInfo(ctx, "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// loopback mtu is usually 65536 but at least the ipv4 minimum mss is expected
+	if info.MSS < 536 || info.MSS > 65535 {
+		t.Fatalf("expected plausible mss, got %d", info.MSS)
+	}
+	if info.RemoteAddress != net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) {
+		t.Fatalf("unexpected remote address %s", info.RemoteAddress)
+	}
+	if host, _, err := net.SplitHostPort(info.LocalAddress); err != nil || host != "127.0.0.1" {
+		t.Fatalf("unexpected local address %s", info.LocalAddress)
+	}
+}
@@ -0,0 +1,221 @@
+package net
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+type (
+	// ExpectStep is a single step of an Expect interaction.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const step = { Send: 'EHLO nuclei\r\n', Expect: '^250[ -]', Timeout: 5 };
+	// ```
+	ExpectStep struct {
+		// Send is the data sent at the start of the step (optional)
+		Send string
+		// Expect is a regex matched against data received during the step (optional).
+		// data up to the end of the match is consumed, the rest is kept for next steps.
+		Expect string
+		// Timeout is the time in seconds to wait for Expect to match (default: ExpectOptions.Timeout)
+		Timeout int
+		// StartTLS upgrades the connection to tls before sending data of the step
+		StartTLS bool
+	}
+
+	// ExpectOptions contains options for Expect function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const options = new net.ExpectOptions();
+	// options.TLS = true;
+	// options.Timeout = 10;
+	// ```
+	ExpectOptions struct {
+		TLS     bool     // TLS wraps the connection in tls before the first step
+		Timeout int      // Timeout is the default timeout of steps in seconds (default: 5)
+		ALPN    []string // ALPN are the application protocols advertised in tls handshakes (ex: h2)
+		// NoGreetingWait skips the leading steps without Send (server greeting) instead of
+		// waiting for them. a greeting sent anyway is received by the next step
+		NoGreetingWait bool
+	}
+
+	// ExpectResponse is the result of an Expect interaction.
+	// this is returned by Expect function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const resp = net.Expect('acme.com', 25, [{ Expect: '^220 ' }], {});
+	// log(resp.Matched, resp.Outputs);
+	// ```
+	ExpectResponse struct {
+		// Matched is true if the expectations of all steps matched
+		Matched bool
+		// Completed is the number of steps whose expectation matched
+		Completed int
+		// Outputs contains the data received during each executed step
+		Outputs []string
+		// NegotiatedProtocol is the application protocol (alpn) selected by the server
+		// in the tls handshake (if any)
+		NegotiatedProtocol string
+	}
+)
+
+// Expect connects to the given host and port and executes given steps in
+// order over a single connection. Each step optionally sends data and then
+// waits until the received data matches its Expect regex or its timeout expires.
+// Execution stops at the first step whose expectation did not match.
+// NoGreetingWait sends the first data right away to services which only
+// respond to requests; skipped steps have an empty output and are not completed.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const resp = net.Expect('acme.com', 25, [{ Expect: '^220 ' }, { Send: 'EHLO nuclei\r\n', Expect: '(?m)^250 ' }, { Send: 'QUIT\r\n' }], { Timeout: 5 });
+// log(resp.Matched, toJSON(resp.Outputs));
+// ```
+func Expect(ctx context.Context, host string, port int, steps []ExpectStep, opts ExpectOptions) (ExpectResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return ExpectResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	defaultStepTimeout := defaultTimeout
+	if opts.Timeout > 0 {
+		defaultStepTimeout = time.Duration(opts.Timeout) * time.Second
+	}
+	patterns := make([]*regexp.Regexp, len(steps))
+	for i, step := range steps {
+		if step.Expect == "" {
+			continue
+		}
+		re, err := regexp.Compile(step.Expect)
+		if err != nil {
+			return ExpectResponse{}, fmt.Errorf("invalid expect regex of step %d: %w", i, err)
+		}
+		patterns[i] = re
+	}
+
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), defaultStepTimeout)
+	if err != nil {
+		return ExpectResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	session := &expectSession{conn: utils.LimitConn(executionId, conn), host: host, alpn: opts.ALPN}
+	if opts.TLS {
+		if err := session.startTLS(protocolstate.GetDeadline(executionId, defaultStepTimeout)); err != nil {
+			return ExpectResponse{}, err
+		}
+	}
+
+	resp := ExpectResponse{Outputs: []string{}, NegotiatedProtocol: negotiatedProtocol(session.conn)}
+	greeting := opts.NoGreetingWait
+	for i, step := range steps {
+		if greeting && step.Send == "" && !step.StartTLS {
+			resp.Outputs = append(resp.Outputs, "")
+			continue
+		}
+		greeting = false
+		timeout := defaultStepTimeout
+		if step.Timeout > 0 {
+			timeout = time.Duration(step.Timeout) * time.Second
+		}
+		deadline := protocolstate.GetDeadline(executionId, timeout)
+		if step.StartTLS {
+			if err := session.startTLS(deadline); err != nil {
+				return resp, err
+			}
+			resp.NegotiatedProtocol = negotiatedProtocol(session.conn)
+		}
+		output, matched, err := session.run(step.Send, patterns[i], deadline)
+		resp.Outputs = append(resp.Outputs, output)
+		if err != nil {
+			return resp, err
+		}
+		if !matched {
+			return resp, nil
+		}
+		resp.Completed++
+	}
+	resp.Matched = true
+	return resp, nil
+}
+
+// expectSession holds the connection and unconsumed data of an Expect interaction
+type expectSession struct {
+	conn    net.Conn
+	host    string
+	alpn    []string
+	pending []byte
+}
+
+// startTLS wraps the connection in tls and performs the handshake
+func (s *expectSession) startTLS(deadline time.Time) error {
+	tlsConn := tls.Client(s.conn, &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		ServerName:         s.host,
+		NextProtos:         s.alpn,
+	})
+	if err := tlsConn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	s.conn = tlsConn
+	// data received before the upgrade is not part of the tls session
+	s.pending = nil
+	return nil
+}
+
+// run sends data and reads until pattern matches or the deadline is reached.
+// In that case the data received so far is returned as output
+func (s *expectSession) run(send string, pattern *regexp.Regexp, deadline time.Time) (string, bool, error) {
+	if err := s.conn.SetDeadline(deadline); err != nil {
+		return "", false, err
+	}
+	if send != "" {
+		if _, err := s.conn.Write([]byte(send)); err != nil {
+			return "", false, err
+		}
+	}
+	if pattern == nil {
+		return "", true, nil
+	}
+
+	var readErr error
+	chunk := make([]byte, 4096)
+	for {
+		if loc := pattern.FindIndex(s.pending); loc != nil {
+			output := string(s.pending[:loc[1]])
+			s.pending = s.pending[loc[1]:]
+			return output, true, nil
+		}
+		if readErr != nil {
+			break
+		}
+		var n int
+		n, readErr = s.conn.Read(chunk)
+		s.pending = append(s.pending, chunk[:n]...)
+	}
+	output := string(s.pending)
+	s.pending = nil
+	if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) || errors.Is(readErr, context.DeadlineExceeded) {
+		// closed connection or timeout is a failed expectation
+		return output, false, nil
+	}
+	return output, false, readErr
+}
@@ -0,0 +1,199 @@
+package net
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// smtpListener serves a scripted smtp-like dialog supporting STARTTLS.
+// clients sending HANG get no response.
+func smtpListener(t *testing.T, implicitTLS bool) (string, int) {
+	t.Helper()
+	tlsConfig := jstest.TLSConfig(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		close(done)
+		_ = ln.Close()
+	})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer func() { _ = conn.Close() }()
+				if implicitTLS {
+					conn = tls.Server(conn, tlsConfig)
+				}
+				reader := bufio.NewReader(conn)
+				_, _ = conn.Write([]byte("220 mail.acme.com ESMTP\r\n"))
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					switch strings.TrimSpace(line) {
+					case "EHLO nuclei":
+						_, _ = conn.Write([]byte("250-mail.acme.com\r\n250-STARTTLS\r\n250 SIZE 1024\r\n"))
+					case "STARTTLS":
+						_, _ = conn.Write([]byte("220 ready\r\n"))
+						conn = tls.Server(conn, tlsConfig)
+						reader = bufio.NewReader(conn)
+					case "HANG":
+						<-done
+						return
+					case "QUIT":
+						_, _ = conn.Write([]byte("221 bye\r\n"))
+						return
+					default:
+						_, _ = conn.Write([]byte("500 unknown command\r\n"))
+					}
+				}
+			}(conn)
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return host, portNum
+}
+
+func TestExpect(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port := smtpListener(t, false)
+
+	resp, err := Expect(ctx, host, port, []ExpectStep{
+		{Expect: `^220 (\S+)`},
+		{Send: "EHLO nuclei\r\n", Expect: `(?m)^250 .*\r\n`},
+		{Send: "STARTTLS\r\n", Expect: `^220 `},
+		{StartTLS: true, Send: "EHLO nuclei\r\n", Expect: `STARTTLS`},
+		{Send: "QUIT\r\n"},
+	}, ExpectOptions{Timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Matched || resp.Completed != 5 {
+		t.Fatalf("expected all steps to match, got %+v", resp)
+	}
+	if resp.Outputs[0] != "220 mail.acme.com" {
+		t.Fatalf("unexpected banner output %q", resp.Outputs[0])
+	}
+	if !strings.HasPrefix(resp.Outputs[1], " ESMTP\r\n250-mail.acme.com") || !strings.HasSuffix(resp.Outputs[1], "250 SIZE 1024\r\n") {
+		t.Fatalf("unexpected ehlo output %q", resp.Outputs[1])
+	}
+
+	// failed expectation stops the interaction
+	resp, err = Expect(ctx, host, port, []ExpectStep{
+		{Expect: `^220 `},
+		{Send: "HELP\r\n", Expect: `^214 `},
+		{Send: "QUIT\r\n", Expect: `^221 `},
+	}, ExpectOptions{Timeout: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Matched || resp.Completed != 1 || len(resp.Outputs) != 2 {
+		t.Fatalf("expected interaction to stop at second step, got %+v", resp)
+	}
+	if !strings.Contains(resp.Outputs[1], "500 unknown command") {
+		t.Fatalf("expected received data in output, got %q", resp.Outputs[1])
+	}
+
+	if _, err := Expect(ctx, host, port, []ExpectStep{{Expect: `(`}}, ExpectOptions{}); err == nil {
+		t.Fatal("expected invalid regex error")
+	}
+}
+
+func TestExpectStepTimeout(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port := smtpListener(t, false)
+
+	start := time.Now()
+	resp, err := Expect(ctx, host, port, []ExpectStep{
+		{Expect: `^220 `},
+		{Send: "HANG\r\n", Expect: `^250 `, Timeout: 1},
+	}, ExpectOptions{Timeout: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Matched || resp.Completed != 1 {
+		t.Fatalf("expected step to time out, got %+v", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("step timeout was not honored, took=%v", elapsed)
+	}
+}
+
+func TestExpectNoGreetingWait(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	// the server never sends a greeting and only answers requests
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				if _, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+					_, _ = conn.Write([]byte("pong\r\n"))
+				}
+			}()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+	steps := []ExpectStep{{Expect: `^220 `}, {Send: "ping\r\n", Expect: `^pong`}}
+
+	start := time.Now()
+	resp, err := Expect(ctx, "127.0.0.1", port, steps, ExpectOptions{Timeout: 5, NoGreetingWait: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Matched || resp.Completed != 1 || !reflect.DeepEqual(resp.Outputs, []string{"", "pong"}) {
+		t.Fatalf("expected greeting step to be skipped, got %+v", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected probe to be sent immediately, took=%v", elapsed)
+	}
+
+	resp, err = Expect(ctx, "127.0.0.1", port, steps, ExpectOptions{Timeout: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Matched || resp.Completed != 0 {
+		t.Fatalf("expected greeting wait to time out, got %+v", resp)
+	}
+}
+
+func TestExpectTLS(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port := smtpListener(t, true)
+
+	resp, err := Expect(ctx, host, port, []ExpectStep{
+		{Expect: `^220 `},
+		{Send: "QUIT\r\n", Expect: `(?m)^221 `},
+	}, ExpectOptions{TLS: true, Timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Matched {
+		t.Fatalf("expected tls interaction to match, got %+v", resp)
+	}
+}
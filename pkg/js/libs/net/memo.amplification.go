@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package net
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[AmplificationResponse]("net.measureAmplification", 4)
+}
+
+func memoizedmeasureAmplification(executionId string, host string, port int, probe string) (AmplificationResponse, error) {
+	hash := "net.measureAmplification" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(probe)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "net.measureAmplification" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(probe)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (AmplificationResponse, error) {
+			return measureAmplification(executionId, host, port, probe)
+		})
+	})
+	if err != nil {
+		return AmplificationResponse{}, err
+	}
+	if value, ok := v.(AmplificationResponse); ok {
+		return value, nil
+	}
+
+	return AmplificationResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,31 @@
+// Warning - This is generated code
+package net
+
+import (
+	"errors"
+	"fmt"
+
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[PortResult]("net.scanPort", 4)
+}
+
+func memoizedscanPort(executionId string, host string, port int, timeout time.Duration) (PortResult, error) {
+	hash := "net.scanPort" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(timeout)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return scanPort(executionId, host, port, timeout)
+	})
+	if err != nil {
+		return PortResult{}, err
+	}
+	if value, ok := v.(PortResult); ok {
+		return value, nil
+	}
+
+	return PortResult{}, errors.New("could not convert cached result")
+}
@@ -1,52 +1,173 @@
 package net
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"time"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	errorutil "github.com/projectdiscovery/utils/errors"
 	"github.com/projectdiscovery/utils/reader"
+	ztls "github.com/zmap/zcrypto/tls"
 )
 
 var (
 	defaultTimeout = time.Duration(5) * time.Second
+
+	// ErrCertUntrusted is returned by OpenTLS when Verify is set and the
+	// certificate of the server could not be verified. the error contains the reason
+	ErrCertUntrusted = errors.New("tls certificate is not trusted")
+
+	// ErrDelimiterNotFound is returned by ReadUntil when the delimiter was not
+	// received before the timeout, the byte limit or the end of the connection
+	ErrDelimiterNotFound = errors.New("delimiter not found")
+
+	// defaultReadUntilLimit is the byte limit of ReadUntil if none is given
+	defaultReadUntilLimit = 64 * 1024
+)
+
+type (
+	// OpenOptions contains options for Open function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const conn = net.Open('tcp', 'acme.com:3389', { ProxyProtocol: 2 });
+	// const pinned = net.Open('tcp', 'acme.com:80', { Interface: 'eth1' });
+	// ```
+	OpenOptions struct {
+		// Interface binds the connection to the address of the given network
+		// interface (ex: eth1) on multi-homed scanners
+		Interface string
+		// ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+		// before any data (ex: backends behind load balancers)
+		ProxyProtocol int
+		// ProxySource is the client address (ip:port) announced in the PROXY header
+		// (default: local address of the connection)
+		ProxySource string
+		// ProxyDestination is the server address (ip:port) announced in the PROXY header
+		// (default: remote address of the connection)
+		ProxyDestination string
+	}
 )
 
 // Open opens a new connection to the address with a timeout.
-// supported protocols: tcp, udp
+// supported protocols: tcp, udp, unix
+// unix sockets can also be opened using unix:///path/to/app.sock addresses
+// and require local file access (-lfa).
+// Interface pins the connection to a network interface (ex: eth1).
 // @example
 // ```javascript
 // const net = require('nuclei/net');
 // const conn = net.Open('tcp', 'acme.com:80');
+// const local = net.Open('unix', 'unix:///var/run/app.sock');
 // ```
-func Open(ctx context.Context, protocol, address string) (*NetConn, error) {
+func Open(ctx context.Context, protocol, address string, opts OpenOptions) (*NetConn, error) {
 	executionId := ctx.Value("executionId").(string)
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", executionId)
+	if _, ok := protocolstate.ParseUnixAddress(address); ok || protocol == "unix" {
+		conn, err := protocolstate.DialWithDeadline(executionId, "unix", address, defaultTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return &NetConn{conn: conn, timeout: defaultTimeout}, nil
+	}
+	if err := protocolstate.CheckTransport(protocol, "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6"); err != nil {
+		return nil, err
+	}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if opts.Interface != "" {
+		conn, err = protocolstate.DialWithOptions(executionId, protocol, address, defaultTimeout, protocolstate.DialOptions{Interface: opts.Interface})
+	} else {
+		conn, err = dial(ctx, executionId, protocol, address)
 	}
-	conn, err := dialer.Fastdialer.Dial(ctx, protocol, address)
 	if err != nil {
 		return nil, err
 	}
+	if err := writeProxyHeader(conn, protocol, opts.ProxyProtocol, opts.ProxySource, opts.ProxyDestination); err != nil {
+		return nil, err
+	}
 	return &NetConn{conn: conn, timeout: defaultTimeout}, nil
 }
 
-// Open opens a new connection to the address with a timeout.
-// supported protocols: tcp, udp
+// dial dials address with the fastdialer of the execution
+// recording the connection to the probe pcap file (if enabled)
+func dial(ctx context.Context, executionId string, protocol, address string) (net.Conn, error) {
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialer.Fastdialer.Dial(ctx, protocol, address)
+	if err != nil {
+		return nil, err
+	}
+	return protocolstate.RecordConn(executionId, conn), nil
+}
+
+// writeProxyHeader sends the PROXY header (if any) closing conn on failure
+func writeProxyHeader(conn net.Conn, protocol string, version int, source, destination string) error {
+	proxy := protocolstate.ProxyProtocol{Version: version, Source: source, Destination: destination}
+	if err := protocolstate.WriteProxyHeader(conn, protocol, proxy); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	return nil
+}
+
+type (
+	// OpenTLSOptions contains options for OpenTLS function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const conn = net.OpenTLS('tcp', 'acme.com:443', { IP: '93.184.216.34' });
+	// ```
+	OpenTLSOptions struct {
+		// IP is dialed instead of resolving the host of the address which
+		// is still used as sni (ex: resolved by a previous dns step)
+		IP string
+		// ALPN are the application protocols advertised in the handshake (ex: h2, http/1.1).
+		// the protocol selected by the server is returned by NegotiatedProtocol
+		ALPN []string
+		// Verify verifies the certificate chain and hostname of the server against the
+		// system roots and fails with ErrCertUntrusted otherwise (disabled by default
+		// since scanned services often use self signed certificates)
+		Verify bool
+		// ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+		// before any data (ex: backends behind load balancers)
+		ProxyProtocol int
+		// ProxySource is the client address (ip:port) announced in the PROXY header
+		// (default: local address of the connection)
+		ProxySource string
+		// ProxyDestination is the server address (ip:port) announced in the PROXY header
+		// (default: remote address of the connection)
+		ProxyDestination string
+	}
+)
+
+// OpenTLS opens a new tls connection to the address with a timeout.
+// supported protocols: tcp (tls over udp is not supported)
+// The certificate of the server is only verified if Verify is set.
 // @example
 // ```javascript
 // const net = require('nuclei/net');
 // const conn = net.OpenTLS('tcp', 'acme.com:443');
+// const trusted = net.OpenTLS('tcp', 'acme.com:443', { Verify: true });
 // ```
-func OpenTLS(ctx context.Context, protocol, address string) (*NetConn, error) {
-	config := &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}
+func OpenTLS(ctx context.Context, protocol, address string, opts OpenTLSOptions) (*NetConn, error) {
+	if err := protocolstate.CheckTransport(protocol, "tcp", "tcp4", "tcp6"); err != nil {
+		return nil, err
+	}
+	config := &tls.Config{InsecureSkipVerify: !opts.Verify, MinVersion: tls.VersionTLS10, NextProtos: opts.ALPN}
 	host, _, _ := net.SplitHostPort(address)
 	if host != "" {
 		c := config.Clone()
@@ -54,16 +175,47 @@ func OpenTLS(ctx context.Context, protocol, address string) (*NetConn, error) {
 		config = c
 	}
 	executionId := ctx.Value("executionId").(string)
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", executionId)
+	address, err := protocolstate.DialOptions{IP: opts.IP}.DialAddress(address)
+	if err != nil {
+		return nil, err
 	}
 
-	conn, err := dialer.Fastdialer.DialTLSWithConfig(ctx, protocol, address, config)
+	if opts.ProxyProtocol == 0 && !opts.Verify {
+		conn, err := protocolstate.DialTLSWithConfig(ctx, executionId, protocol, address, config)
+		if err != nil {
+			return nil, err
+		}
+		return &NetConn{conn: conn, timeout: defaultTimeout}, nil
+	}
+	// the PROXY header precedes the tls handshake and verification errors
+	// must not be retried by the dialer with a different tls implementation
+	conn, err := dial(ctx, executionId, protocol, address)
 	if err != nil {
 		return nil, err
 	}
-	return &NetConn{conn: conn, timeout: defaultTimeout}, nil
+	if err := writeProxyHeader(conn, protocol, opts.ProxyProtocol, opts.ProxySource, opts.ProxyDestination); err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, certError(err)
+	}
+	return &NetConn{conn: tlsConn, timeout: defaultTimeout}, nil
+}
+
+// certError classifies certificate verification failures of a handshake as ErrCertUntrusted
+func certError(err error) error {
+	var (
+		verificationErr *tls.CertificateVerificationError
+		hostnameErr     x509.HostnameError
+		authorityErr    x509.UnknownAuthorityError
+		invalidErr      x509.CertificateInvalidError
+	)
+	if errors.As(err, &verificationErr) || errors.As(err, &hostnameErr) || errors.As(err, &authorityErr) || errors.As(err, &invalidErr) {
+		return fmt.Errorf("%w: %v", ErrCertUntrusted, err)
+	}
+	return err
 }
 
 type (
@@ -77,6 +229,9 @@ type (
 	NetConn struct {
 		conn    net.Conn
 		timeout time.Duration
+		// pending are the bytes received after the delimiter by ReadUntil
+		// which are returned by the next reads
+		pending []byte
 	}
 )
 
@@ -92,6 +247,31 @@ func (c *NetConn) Close() error {
 	return err
 }
 
+// NegotiatedProtocol returns the application protocol (alpn) selected by the
+// server during the tls handshake. An empty string is returned for plain
+// connections or if the server did not select any of the advertised protocols.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const conn = net.OpenTLS('tcp', 'acme.com:443', { ALPN: ['h2', 'http/1.1'] });
+// log(conn.NegotiatedProtocol());
+// ```
+func (c *NetConn) NegotiatedProtocol() string {
+	return negotiatedProtocol(c.conn)
+}
+
+// negotiatedProtocol returns the alpn protocol of a tls connection
+// (including ztls connections of the fastdialer fallback)
+func negotiatedProtocol(conn net.Conn) string {
+	switch c := conn.(type) {
+	case *tls.Conn:
+		return c.ConnectionState().NegotiatedProtocol
+	case *ztls.Conn:
+		return c.ConnectionState().NegotiatedProtocol
+	}
+	return ""
+}
+
 // SetTimeout sets read/write timeout for the connection (in seconds).
 // @example
 // ```javascript
@@ -199,11 +379,19 @@ func (c *NetConn) RecvFull(N int) ([]byte, error) {
 		// in utils we use -1 to indicate read all rather than 0
 		N = -1
 	}
-	bin, err := reader.ConnReadNWithTimeout(c.conn, int64(N), c.timeout)
+	buffered := c.takePending(N)
+	remaining := int64(N)
+	if N > 0 {
+		if len(buffered) == N {
+			return buffered, nil
+		}
+		remaining -= int64(len(buffered))
+	}
+	bin, err := reader.ConnReadNWithTimeout(c.conn, remaining, c.timeout)
 	if err != nil {
 		return []byte{}, errorutil.NewWithErr(err).Msgf("failed to read %d bytes", N)
 	}
-	return bin, nil
+	return append(buffered, bin...), nil
 }
 
 // Recv is similar to RecvFull but does not guarantee full read instead
@@ -223,6 +411,9 @@ func (c *NetConn) Recv(N int) ([]byte, error) {
 	if N == 0 {
 		N = 4096
 	}
+	if buffered := c.takePending(N); len(buffered) > 0 {
+		return buffered, nil
+	}
 	b := make([]byte, N)
 	n, err := c.conn.Read(b)
 	if err != nil {
@@ -266,6 +457,144 @@ func (c *NetConn) RecvString(N int) (string, error) {
 	return string(bin), nil
 }
 
+// ReadUntil receives data until delimiter (ex: \r\n or \r\n.\r\n for multiline
+// smtp and pop3 responses) and returns the received bytes including the delimiter.
+// ErrDelimiterNotFound is returned if the delimiter is not received within
+// maxBytes (default 64KB) and timeout seconds (default: timeout of the connection)
+// or before the server closes the connection. Bytes received after the delimiter
+// (or before a failure) are returned by the next reads.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const conn = net.Open('tcp', 'acme.com:110');
+// const banner = conn.ReadUntil('\r\n', 1024, 5);
+// conn.Send('CAPA\r\n');
+// const capabilities = conn.ReadUntil('\r\n.\r\n', 4096, 5);
+// ```
+func (c *NetConn) ReadUntil(delimiter string, maxBytes int, timeout int) ([]byte, error) {
+	if delimiter == "" {
+		return []byte{}, errors.New("empty delimiter")
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultReadUntilLimit
+	}
+	deadline := time.Now().Add(c.timeout)
+	if timeout > 0 {
+		deadline = time.Now().Add(time.Duration(timeout) * time.Second)
+	} else if c.timeout == 0 {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	_ = c.conn.SetDeadline(deadline)
+	defer c.unsetDeadLine()
+
+	data := c.takePending(-1)
+	chunk := make([]byte, 4096)
+	for {
+		if index := bytes.Index(data, []byte(delimiter)); index >= 0 && index+len(delimiter) <= maxBytes {
+			c.pending = data[index+len(delimiter):]
+			return data[:index+len(delimiter)], nil
+		}
+		if len(data) >= maxBytes {
+			c.pending = data
+			return []byte{}, fmt.Errorf("%w within %d bytes", ErrDelimiterNotFound, maxBytes)
+		}
+		n, err := c.conn.Read(chunk)
+		data = append(data, chunk[:n]...)
+		if err != nil {
+			if index := bytes.Index(data, []byte(delimiter)); index >= 0 && index+len(delimiter) <= maxBytes {
+				// the delimiter arrived with the last bytes
+				continue
+			}
+			c.pending = data
+			var netErr net.Error
+			switch {
+			case errors.As(err, &netErr) && netErr.Timeout():
+				return []byte{}, fmt.Errorf("%w before timeout (received %d bytes)", ErrDelimiterNotFound, len(data))
+			case errors.Is(err, io.EOF):
+				return []byte{}, fmt.Errorf("%w before connection close (received %d bytes)", ErrDelimiterNotFound, len(data))
+			}
+			return []byte{}, err
+		}
+	}
+}
+
+// takePending returns (and consumes) at most n bytes received past a
+// ReadUntil delimiter. a negative n returns every pending byte
+func (c *NetConn) takePending(n int) []byte {
+	if len(c.pending) == 0 {
+		return nil
+	}
+	if n < 0 || n > len(c.pending) {
+		n = len(c.pending)
+	}
+	data := append([]byte{}, c.pending[:n]...)
+	c.pending = c.pending[n:]
+	return data
+}
+
+type (
+	// DecodedData contains data received from the connection decoded to utf-8.
+	// this is returned by RecvDecoded and RecvFullDecoded functions.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const conn = net.Open('tcp', 'acme.com:21');
+	// const banner = conn.RecvDecoded(1024, 'shift_jis');
+	// log(banner.Data);
+	// ```
+	DecodedData struct {
+		// Data is the received data decoded to utf-8
+		Data string
+		// Raw contains the received bytes as is
+		Raw []byte
+	}
+)
+
+// RecvFullDecoded is similar to RecvFullString but transcodes the received data
+// from the given charset (ex: shift_jis, latin1, windows-1252) to utf-8.
+// An empty charset keeps the data as is. Raw bytes are always returned.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const conn = net.Open('tcp', 'acme.com:23');
+// const banner = conn.RecvFullDecoded(1024, 'latin1');
+// log(banner.Data, banner.Raw.length);
+// ```
+func (c *NetConn) RecvFullDecoded(N int, charset string) (*DecodedData, error) {
+	bin, err := c.RecvFull(N)
+	if err != nil {
+		return nil, err
+	}
+	return decode(bin, charset)
+}
+
+// RecvDecoded is similar to RecvString but transcodes the received data
+// from the given charset (ex: shift_jis, latin1, windows-1252) to utf-8.
+// An empty charset keeps the data as is. Raw bytes are always returned.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const conn = net.Open('tcp', 'acme.com:21');
+// const banner = conn.RecvDecoded(1024, 'shift_jis');
+// log(banner.Data);
+// ```
+func (c *NetConn) RecvDecoded(N int, charset string) (*DecodedData, error) {
+	bin, err := c.Recv(N)
+	if err != nil {
+		return nil, err
+	}
+	return decode(bin, charset)
+}
+
+// decode returns data decoded from charset along with raw bytes
+func decode(data []byte, charset string) (*DecodedData, error) {
+	decoded, err := utils.DecodeCharset(data, charset)
+	if err != nil {
+		return nil, err
+	}
+	return &DecodedData{Data: decoded, Raw: data}, nil
+}
+
 // RecvFullHex receives data from the connection with a timeout
 // in hex format.
 // If N is 0,it will read all data sent by the server with 8MB limit.
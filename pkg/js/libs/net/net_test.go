@@ -0,0 +1,510 @@
+package net
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+func TestOpenTLSWithIP(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	serverNames := make(chan string, 1)
+	config := jstest.TLSConfig(t)
+	config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		serverNames <- hello.ServerName
+		return nil, nil
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_ = conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+
+	// the hostname does not resolve and is only used as sni
+	conn, err := OpenTLS(ctx, "tcp", net.JoinHostPort("acme.invalid", port), OpenTLSOptions{IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	if got := conn.conn.RemoteAddr().String(); got != net.JoinHostPort("127.0.0.1", port) {
+		t.Fatalf("expected dial to supplied ip, got %s", got)
+	}
+	if got := <-serverNames; got != "acme.invalid" {
+		t.Fatalf("expected sni acme.invalid, got %q", got)
+	}
+
+	if _, err := OpenTLS(ctx, "tcp", net.JoinHostPort("acme.invalid", port), OpenTLSOptions{IP: "not-an-ip"}); err == nil {
+		t.Fatal("expected invalid ip to be rejected")
+	}
+}
+
+func TestRecvDecoded(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	// "220 ようこそ FTP" encoded in shift_jis
+	banner := []byte("220 \x82\xe6\x82\xa4\x82\xb1\x82\xbb FTP\r\n")
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write(banner)
+			_ = conn.Close()
+		}
+	}()
+
+	for name, recv := range map[string]func(*NetConn) (*DecodedData, error){
+		"RecvDecoded":     func(c *NetConn) (*DecodedData, error) { return c.RecvDecoded(1024, "shift_jis") },
+		"RecvFullDecoded": func(c *NetConn) (*DecodedData, error) { return c.RecvFullDecoded(0, "shift_jis") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			conn, err := Open(ctx, "tcp", ln.Addr().String(), OpenOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = conn.Close() }()
+			data, err := recv(conn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.Data != "220 ようこそ FTP\r\n" {
+				t.Fatalf("expected decoded banner, got %q", data.Data)
+			}
+			if !bytes.Equal(data.Raw, banner) {
+				t.Fatalf("expected raw banner %q, got %q", banner, data.Raw)
+			}
+		})
+	}
+}
+
+func TestOpenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "echo.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets not supported: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	newContext := func(lfa bool) context.Context {
+		return jstest.Context(t, func(options *types.Options) {
+			options.ExecutionId = t.Name() + strconv.FormatBool(lfa)
+			options.AllowLocalFileAccess = lfa
+		})
+	}
+
+	ctx := newContext(true)
+	for name, open := range map[string]func() (*NetConn, error){
+		"unix scheme":  func() (*NetConn, error) { return Open(ctx, "tcp", "unix://"+path, OpenOptions{}) },
+		"unix network": func() (*NetConn, error) { return Open(ctx, "unix", path, OpenOptions{}) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			conn, err := open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = conn.Close() }()
+			if err := conn.Send("ping"); err != nil {
+				t.Fatal(err)
+			}
+			data, err := conn.RecvFullString(4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data != "ping" {
+				t.Fatalf("expected echoed ping, got %q", data)
+			}
+		})
+	}
+
+	// unix sockets are local files and require -lfa
+	if _, err := Open(newContext(false), "unix", path, OpenOptions{}); !errors.Is(err, protocolstate.ErrUnixSocketDenied) {
+		t.Fatalf("expected unix socket to be denied without lfa, got %v", err)
+	}
+}
+
+// proxyListener echoes data after a valid PROXY header announcing source
+// and closes connections sending anything else
+func proxyListener(t *testing.T, source string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	sourceIP, sourcePort, _ := net.SplitHostPort(source)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				reader := bufio.NewReader(conn)
+				destination := conn.LocalAddr().(*net.TCPAddr)
+				signature, err := reader.Peek(1)
+				if err != nil {
+					return
+				}
+				switch signature[0] {
+				case 'P':
+					line, err := reader.ReadString('\n')
+					want := fmt.Sprintf("PROXY TCP4 %s %s %s %d\r\n", sourceIP, destination.IP, sourcePort, destination.Port)
+					if err != nil || line != want {
+						return
+					}
+				case 0x0d:
+					header := make([]byte, 28)
+					if _, err := io.ReadFull(reader, header); err != nil {
+						return
+					}
+					port, _ := strconv.Atoi(sourcePort)
+					if !bytes.Equal(header[:12], []byte("\r\n\r\n\x00\r\nQUIT\n")) || header[12] != 0x21 || header[13] != 0x11 ||
+						binary.BigEndian.Uint16(header[14:16]) != 12 || net.IP(header[16:20]).String() != sourceIP ||
+						!net.IP(header[20:24]).Equal(destination.IP) || int(binary.BigEndian.Uint16(header[24:26])) != port ||
+						int(binary.BigEndian.Uint16(header[26:28])) != destination.Port {
+						return
+					}
+				default:
+					return
+				}
+				_, _ = io.Copy(conn, reader)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestOpenProxyProtocol(t *testing.T) {
+	ctx := jstest.Context(t)
+	address := proxyListener(t, "10.0.0.1:4242")
+
+	echo := func(opts OpenOptions) (string, error) {
+		conn, err := Open(ctx, "tcp", address, opts)
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = conn.Close() }()
+		if err := conn.Send("ping"); err != nil {
+			return "", err
+		}
+		return conn.RecvFullString(4)
+	}
+	for _, version := range []int{1, 2} {
+		data, err := echo(OpenOptions{ProxyProtocol: version, ProxySource: "10.0.0.1:4242"})
+		if err != nil || data != "ping" {
+			t.Fatalf("expected echo with proxy protocol v%d, got %q err=%v", version, data, err)
+		}
+	}
+	// the backend rejects connections without the header
+	if data, _ := echo(OpenOptions{}); data == "ping" {
+		t.Fatalf("expected connection without proxy header to be rejected, got %q", data)
+	}
+	if _, err := Open(ctx, "tcp", address, OpenOptions{ProxyProtocol: 3}); err == nil {
+		t.Fatal("expected invalid proxy protocol version to be rejected")
+	}
+}
+
+func TestOpenUnsupportedTransport(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	if _, err := Open(ctx, "sctp", "127.0.0.1:3868", OpenOptions{}); !errors.Is(err, protocolstate.ErrUnsupportedTransport) {
+		t.Fatalf("expected unsupported transport error for sctp, got %v", err)
+	}
+	if _, err := OpenTLS(ctx, "udp", "127.0.0.1:443", OpenTLSOptions{}); !errors.Is(err, protocolstate.ErrUnsupportedTransport) {
+		t.Fatalf("expected unsupported transport error for tls over udp, got %v", err)
+	}
+	// the text header of proxy protocol v1 only describes tcp connections
+	if _, err := Open(ctx, "udp", "127.0.0.1:5060", OpenOptions{ProxyProtocol: 1}); !errors.Is(err, protocolstate.ErrUnsupportedTransport) {
+		t.Fatalf("expected unsupported transport error for proxy protocol v1 over udp, got %v", err)
+	}
+	conn, err := Open(ctx, "udp", "127.0.0.1:5060", OpenOptions{ProxyProtocol: 2})
+	if err != nil {
+		t.Fatalf("expected proxy protocol v2 over udp to be supported, got %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestOpenTLSALPN(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	config := jstest.TLSConfig(t)
+	config.NextProtos = []string{"h2", "http/1.1"}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				if conn.(*tls.Conn).Handshake() == nil {
+					_, _ = conn.Write([]byte("ok\n"))
+					_, _ = io.Copy(io.Discard, conn)
+				}
+			}()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+
+	tests := []struct {
+		alpn []string
+		want string
+	}{
+		{alpn: []string{"h2", "http/1.1"}, want: "h2"},
+		{alpn: []string{"http/1.1"}, want: "http/1.1"},
+		{alpn: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.alpn), func(t *testing.T) {
+			conn, err := OpenTLS(ctx, "tcp", address, OpenTLSOptions{ALPN: tt.alpn})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = conn.Close() }()
+			if got := conn.NegotiatedProtocol(); got != tt.want {
+				t.Fatalf("expected negotiated protocol %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	resp, err := Expect(ctx, "127.0.0.1", port, []ExpectStep{{Expect: `^ok`}}, ExpectOptions{TLS: true, ALPN: []string{"h2"}, Timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Matched || resp.NegotiatedProtocol != "h2" {
+		t.Fatalf("expected expect session to negotiate h2, got %+v", resp)
+	}
+
+	plain, err := Open(ctx, "tcp", address, OpenOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = plain.Close() }()
+	if got := plain.NegotiatedProtocol(); got != "" {
+		t.Fatalf("expected no negotiated protocol for plain connections, got %q", got)
+	}
+}
+
+func TestOpenTLSVerify(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", jstest.TLSConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_ = conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+	address := net.JoinHostPort("localhost", strconv.Itoa(ln.Addr().(*net.TCPAddr).Port))
+
+	// self signed certificates are accepted by default
+	conn, err := OpenTLS(ctx, "tcp", address, OpenTLSOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	_, err = OpenTLS(ctx, "tcp", address, OpenTLSOptions{Verify: true})
+	if !errors.Is(err, ErrCertUntrusted) {
+		t.Fatalf("expected untrusted certificate error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "x509: ") {
+		t.Fatalf("expected error to contain the verification reason, got %v", err)
+	}
+
+	// handshake failures unrelated to the certificate are not classified
+	plain, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = plain.Close() })
+	go func() {
+		for {
+			conn, err := plain.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+			_ = conn.Close()
+		}
+	}()
+	_, err = OpenTLS(ctx, "tcp", plain.Addr().String(), OpenTLSOptions{Verify: true})
+	if err == nil || errors.Is(err, ErrCertUntrusted) {
+		t.Fatalf("expected unclassified handshake error, got %v", err)
+	}
+}
+
+// chunkedConn returns a connection receiving chunks in separate writes
+// with a short delay between them. the connection is closed after the
+// last chunk if closeAfter is set
+func chunkedConn(t *testing.T, chunks []string, closeAfter bool) *NetConn {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+	go func() {
+		for _, chunk := range chunks {
+			if _, err := server.Write([]byte(chunk)); err != nil {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if closeAfter {
+			_ = server.Close()
+		}
+	}()
+	return &NetConn{conn: client, timeout: 5 * time.Second}
+}
+
+func TestReadUntil(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunks    []string
+		delimiter string
+		want      []string
+		rest      string
+	}{
+		{
+			name:      "multiple chunks",
+			chunks:    []string{"+OK capa", "bilities\r\nUSER\r\n", "STLS\r\n.\r\n+OK"},
+			delimiter: "\r\n.\r\n",
+			want:      []string{"+OK capabilities\r\nUSER\r\nSTLS\r\n.\r\n"},
+			rest:      "+OK",
+		},
+		{
+			name:      "delimiter split across reads",
+			chunks:    []string{"250-mail.acme.com\r", "\n250 STARTTLS\r", "\n"},
+			delimiter: "\r\n",
+			want:      []string{"250-mail.acme.com\r\n", "250 STARTTLS\r\n"},
+		},
+		{
+			name:      "several responses in one read",
+			chunks:    []string{"a001 OK\r\na002 OK\r\nextra"},
+			delimiter: "\r\n",
+			want:      []string{"a001 OK\r\n", "a002 OK\r\n"},
+			rest:      "extra",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := chunkedConn(t, test.chunks, true)
+			for _, want := range test.want {
+				data, err := conn.ReadUntil(test.delimiter, 1024, 2)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != want {
+					t.Fatalf("expected %q, got %q", want, data)
+				}
+			}
+			if test.rest == "" {
+				return
+			}
+			// bytes received after the delimiter are returned by the next read
+			data, err := conn.Recv(1024)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != test.rest {
+				t.Fatalf("expected remaining %q, got %q", test.rest, data)
+			}
+		})
+	}
+}
+
+func TestReadUntilNotFound(t *testing.T) {
+	// the byte limit is reached before the delimiter
+	conn := chunkedConn(t, []string{strings.Repeat("A", 64), "\r\n"}, false)
+	if _, err := conn.ReadUntil("\r\n", 32, 2); !errors.Is(err, ErrDelimiterNotFound) {
+		t.Fatalf("expected ErrDelimiterNotFound for overflow, got %v", err)
+	}
+	data, err := conn.RecvFull(64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != strings.Repeat("A", 64) {
+		t.Fatalf("expected received bytes to be kept, got %q", data)
+	}
+
+	// the server does not send the delimiter
+	conn = chunkedConn(t, []string{"220 partial"}, false)
+	start := time.Now()
+	if _, err := conn.ReadUntil("\r\n", 1024, 1); !errors.Is(err, ErrDelimiterNotFound) {
+		t.Fatalf("expected ErrDelimiterNotFound for timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("expected read to stop at the timeout, took %s", elapsed)
+	}
+
+	// the server closes the connection
+	conn = chunkedConn(t, []string{"220 partial"}, true)
+	if _, err := conn.ReadUntil("\r\n", 1024, 2); !errors.Is(err, ErrDelimiterNotFound) {
+		t.Fatalf("expected ErrDelimiterNotFound for closed connection, got %v", err)
+	}
+}
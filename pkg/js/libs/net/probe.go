@@ -0,0 +1,154 @@
+package net
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// ProbeOpenResponsive is returned when the service sent data after connect
+	ProbeOpenResponsive = "OPEN_RESPONSIVE"
+	// ProbeOpenSilent is returned when the connection stayed open without data
+	ProbeOpenSilent = "OPEN_SILENT"
+	// ProbeOpenImmediateRST is returned when the connection was reset right after connect
+	ProbeOpenImmediateRST = "OPEN_IMMEDIATE_RST"
+	// ProbeOpenImmediateClose is returned when the connection was closed (fin) right after connect
+	ProbeOpenImmediateClose = "OPEN_IMMEDIATE_CLOSE"
+	// ProbeRefused is returned when the connection was refused
+	ProbeRefused = "REFUSED"
+	// ProbeFiltered is returned when the connect timed out
+	ProbeFiltered = "FILTERED"
+)
+
+var (
+	defaultProbeReadTimeout = 2 * time.Second
+)
+
+type (
+	// ProbeOptions contains options for Probe function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const result = net.Probe('acme.com', 22, { Timeout: 3, ReadTimeout: 1 });
+	// ```
+	ProbeOptions struct {
+		Timeout     int // Timeout is the connect timeout in seconds (default: 5)
+		ReadTimeout int // ReadTimeout is the time in seconds to wait for data after connect (default: 2)
+	}
+)
+
+type (
+	// ProbeResponse is the classification of a tcp port.
+	// this is returned by Probe function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const result = net.Probe('acme.com', 22);
+	// log(result.State, result.Latency);
+	// ```
+	ProbeResponse struct {
+		// State is the state of the port (OPEN_RESPONSIVE, OPEN_SILENT, OPEN_IMMEDIATE_RST,
+		// OPEN_IMMEDIATE_CLOSE, REFUSED or FILTERED)
+		State string
+		// Latency is the tcp connect latency in milliseconds (open ports only)
+		Latency int64
+	}
+)
+
+// Probe connects to the host and port and classifies the port based on the
+// connect result and a short read after connect. Unlike ScanPorts it tells
+// apart services which accept connections to reset or close them right away
+// (ex: tcpwrappers, fail2ban) from silent and responsive services.
+// The host is resolved beforehand so that Latency only covers the tcp connect,
+// and results are not memoized so that every call measures it.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const result = net.Probe('acme.com', 22);
+// if (result.State === 'OPEN_IMMEDIATE_RST') { log('connection dropped by host filtering'); }
+// ```
+func Probe(ctx context.Context, host string, port int, opts ProbeOptions) (ProbeResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return ProbeResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	timeout := defaultTimeout
+	if opts.Timeout > 0 {
+		timeout = time.Duration(opts.Timeout) * time.Second
+	}
+	readTimeout := defaultProbeReadTimeout
+	if opts.ReadTimeout > 0 {
+		readTimeout = time.Duration(opts.ReadTimeout) * time.Second
+	}
+	return probe(executionId, host, port, timeout, readTimeout)
+}
+
+// probe classifies the port of host timing the connect to its first address
+func probe(executionId string, host string, port int, timeout time.Duration, readTimeout time.Duration) (ProbeResponse, error) {
+	ips, err := protocolstate.ResolveAll(executionId, host)
+	if err != nil {
+		return ProbeResponse{}, err
+	}
+	ip := ips[0]
+	if !protocolstate.IsHostAllowed(executionId, ip) {
+		// resolved address is not valid according to network policy
+		return ProbeResponse{}, protocolstate.ErrHostDenied.Msgf(ip)
+	}
+	start := time.Now()
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(ip, strconv.Itoa(port)), timeout)
+	if err != nil {
+		state, ok := dialState(err)
+		if !ok {
+			return ProbeResponse{}, err
+		}
+		return ProbeResponse{State: state}, nil
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	resp := ProbeResponse{Latency: time.Since(start).Milliseconds()}
+
+	if err := conn.SetReadDeadline(protocolstate.GetDeadline(executionId, readTimeout)); err != nil {
+		return resp, err
+	}
+	_, err = conn.Read(make([]byte, 1))
+	resp.State = readState(err)
+	return resp, nil
+}
+
+// dialState returns the state of a port for a dial error
+func dialState(err error) (string, bool) {
+	switch {
+	case protocolstate.IsConnectionRefused(err):
+		return ProbeRefused, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return ProbeFiltered, true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ProbeFiltered, true
+	}
+	return "", false
+}
+
+// readState returns the state of an open port for the error of the first read
+func readState(err error) string {
+	switch {
+	case err == nil:
+		return ProbeOpenResponsive
+	case errors.Is(err, io.EOF):
+		return ProbeOpenImmediateClose
+	case protocolstate.IsConnectionReset(err):
+		return ProbeOpenImmediateRST
+	default:
+		// the connection is still open (read timeout)
+		return ProbeOpenSilent
+	}
+}
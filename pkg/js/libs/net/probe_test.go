@@ -0,0 +1,128 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// probeListener accepts connections and handles them with handle
+func probeListener(t *testing.T, handle func(conn *net.TCPConn)) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn.(*net.TCPConn))
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestProbe(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refusedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	tests := []struct {
+		name string
+		port int
+		want string
+	}{
+		{
+			name: "responsive",
+			port: probeListener(t, func(conn *net.TCPConn) {
+				_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+				<-done
+				_ = conn.Close()
+			}),
+			want: ProbeOpenResponsive,
+		},
+		{
+			name: "silent",
+			port: probeListener(t, func(conn *net.TCPConn) {
+				<-done
+				_ = conn.Close()
+			}),
+			want: ProbeOpenSilent,
+		},
+		{
+			name: "immediate rst",
+			port: probeListener(t, func(conn *net.TCPConn) {
+				// closing with zero linger sends a rst
+				_ = conn.SetLinger(0)
+				_ = conn.Close()
+			}),
+			want: ProbeOpenImmediateRST,
+		},
+		{
+			name: "immediate close",
+			port: probeListener(t, func(conn *net.TCPConn) {
+				_ = conn.Close()
+			}),
+			want: ProbeOpenImmediateClose,
+		},
+		{
+			name: "refused",
+			port: refusedPort,
+			want: ProbeRefused,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Probe(ctx, "127.0.0.1", tt.port, ProbeOptions{ReadTimeout: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.State != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got.State)
+			}
+		})
+	}
+}
+
+func TestDialState(t *testing.T) {
+	// connect timeouts can not be reproduced reliably with local listeners
+	if state, ok := dialState(fmt.Errorf("dial: %w", context.DeadlineExceeded)); !ok || state != ProbeFiltered {
+		t.Fatalf("expected %s for a connect timeout, got %q", ProbeFiltered, state)
+	}
+	if _, ok := dialState(fmt.Errorf("no address found for host")); ok {
+		t.Fatal("expected unrelated dial errors not to be classified")
+	}
+}
+
+func TestProbeNotMemoized(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	if got, err := Probe(ctx, "localhost", port, ProbeOptions{ReadTimeout: 1}); err != nil || got.State != ProbeOpenSilent {
+		t.Fatalf("expected %s, got %+v err=%v", ProbeOpenSilent, got, err)
+	}
+	_ = ln.Close()
+
+	// every call measures the port again
+	if got, err := Probe(ctx, "localhost", port, ProbeOptions{ReadTimeout: 1}); err != nil || got.State != ProbeRefused {
+		t.Fatalf("expected %s after closing the listener, got %+v err=%v", ProbeRefused, got, err)
+	}
+}
@@ -0,0 +1,130 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	syncutil "github.com/projectdiscovery/utils/sync"
+)
+
+var (
+	defaultScanThreads = 25
+)
+
+type (
+	// ScanPortsOptions contains options for ScanPorts function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const options = new net.ScanPortsOptions();
+	// options.Timeout = 2;
+	// options.Threads = 50;
+	// ```
+	ScanPortsOptions struct {
+		Timeout int // Timeout is the connect timeout in seconds for each port (default: 5)
+		Threads int // Threads is the maximum number of ports probed concurrently, capped at the template concurrency (default: 25)
+	}
+)
+
+type (
+	// PortResult is the result of a single port probe.
+	// this is returned by ScanPorts function.
+	// @example
+	// ```javascript
+	// const net = require('nuclei/net');
+	// const results = net.ScanPorts('acme.com', [22, 80, 443], {});
+	// for (const result of results) {
+	//   log(result.Port, result.Open, result.Latency);
+	// }
+	// ```
+	PortResult struct {
+		Port    int
+		Open    bool
+		Latency int64 // Latency is the tcp connect latency in milliseconds
+	}
+)
+
+// ScanPorts tries to establish a tcp connection to each given port of the host
+// with bounded concurrency and returns whether the connection succeeded along with
+// the connect latency. Results are returned in the same order as given ports.
+// @example
+// ```javascript
+// const net = require('nuclei/net');
+// const results = net.ScanPorts('acme.com', [22, 80, 443, 3389], { Timeout: 2, Threads: 10 });
+// log(toJSON(results.filter(r => r.Open)));
+// ```
+func ScanPorts(ctx context.Context, host string, ports []int, opts ScanPortsOptions) ([]PortResult, error) {
+	executionId := ctx.Value("executionId").(string)
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if _, err := protocolstate.GetDialersOrError(executionId); err != nil {
+		return nil, err
+	}
+	timeout := defaultTimeout
+	if opts.Timeout > 0 {
+		timeout = time.Duration(opts.Timeout) * time.Second
+	}
+	threads := defaultScanThreads
+	if opts.Threads > 0 {
+		threads = opts.Threads
+	}
+	if maxThreads := protocolstate.GetThreads(executionId); maxThreads > 0 && threads > maxThreads {
+		threads = maxThreads
+	}
+
+	for _, port := range ports {
+		if port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %d", port)
+		}
+	}
+
+	wg, err := syncutil.New(syncutil.WithSize(threads))
+	if err != nil {
+		return nil, err
+	}
+	// each goroutine writes to its own index
+	results := make([]PortResult, len(ports))
+	for i, port := range ports {
+		wg.Add()
+		go func(i, port int) {
+			defer wg.Done()
+			result, err := memoizedscanPort(executionId, host, port, timeout)
+			if err != nil {
+				// ports not answering (ex: timeout) are reported closed
+				// without caching the result
+				result = PortResult{Port: port}
+			}
+			results[i] = result
+		}(i, port)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// @memo(nodisk)
+func scanPort(executionId string, host string, port int, timeout time.Duration) (PortResult, error) {
+	result := PortResult{Port: port}
+	// only dials take a token, cached results are returned right away
+	protocolstate.RateLimitTake(executionId)
+
+	start := time.Now()
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
+	if err != nil {
+		if protocolstate.IsConnectionRefused(err) {
+			// refused ports are closed and cached as such, other
+			// errors are transient and returned to be retried
+			return result, nil
+		}
+		return result, err
+	}
+	result.Latency = time.Since(start).Milliseconds()
+	result.Open = true
+	_ = conn.Close()
+	return result, nil
+}
@@ -0,0 +1,50 @@
+package net
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func TestScanPorts(t *testing.T) {
+	executionId := jstest.Init(t)
+	ctx := jstest.ExecutionContext(executionId)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refusedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+	openPort := probeListener(t, func(conn *net.TCPConn) { _ = conn.Close() })
+	ports := []int{openPort, refusedPort}
+
+	// ports not reached before the scan deadline are closed but not cached
+	protocolstate.SetScanDeadline(executionId, time.Now().Add(-time.Second))
+	results, err := ScanPorts(ctx, "127.0.0.1", ports, ScanPortsOptions{Timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Open {
+			t.Fatalf("expected ports to be closed after the scan deadline, got %+v", results)
+		}
+	}
+
+	protocolstate.SetScanDeadline(executionId, time.Time{})
+	results, err = ScanPorts(ctx, "127.0.0.1", ports, ScanPortsOptions{Timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Port != openPort || !results[0].Open || results[1].Port != refusedPort || results[1].Open {
+		t.Fatalf("expected open and refused port, got %+v", results)
+	}
+
+	// refused ports are closed without an error and cached as such
+	if result, err := scanPort(executionId, "127.0.0.1", refusedPort, 2*time.Second); err != nil || result.Open {
+		t.Fatalf("expected refused port to be closed without error, got %+v err=%v", result, err)
+	}
+}
@@ -0,0 +1,34 @@
+// Warning - This is generated code
+package ntlm
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[GetInfoResponse]("ntlm.getInfo", 4)
+}
+
+func memoizedgetInfo(executionId string, host string, port int, path string) (GetInfoResponse, error) {
+	hash := "ntlm.getInfo" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "ntlm.getInfo" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (GetInfoResponse, error) {
+			return getInfo(executionId, host, port, path)
+		})
+	})
+	if err != nil {
+		return GetInfoResponse{}, err
+	}
+	if value, ok := v.(GetInfoResponse); ok {
+		return value, nil
+	}
+
+	return GetInfoResponse{}, errors.New("could not convert cached result")
+}
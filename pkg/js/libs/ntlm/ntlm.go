@@ -0,0 +1,241 @@
+package ntlm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	negotiateVersion = 0x02000000
+	// negotiateTimeout bounds each connection including the negotiate exchange
+	negotiateTimeout = 5 * time.Second
+
+	avEOL             = 0
+	avNbComputerName  = 1
+	avNbDomainName    = 2
+	avDNSComputerName = 3
+	avDNSDomainName   = 4
+	avDNSTreeName     = 5
+	avTimestamp       = 7
+)
+
+var (
+	ntlmSignature = []byte("NTLMSSP\x00")
+
+	// NEGOTIATE_MESSAGE requesting target info and version
+	// flags: UNICODE, OEM, REQUEST_TARGET, NTLM, ALWAYS_SIGN,
+	// EXTENDED_SESSIONSECURITY, VERSION, 128, 56
+	negotiateMessage = []byte{
+		'N', 'T', 'L', 'M', 'S', 'S', 'P', 0x00,
+		0x01, 0x00, 0x00, 0x00,
+		0x07, 0x82, 0x08, 0xa2,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x0a, 0x00, 0x61, 0x4a, 0x00, 0x00, 0x00, 0x0f,
+	}
+
+	errNoChallenge = errors.New("no ntlm challenge received")
+)
+
+type (
+	// GetInfoResponse is the response from the GetInfo function.
+	// this is returned by GetInfo function.
+	// @example
+	// ```javascript
+	// const ntlm = require('nuclei/ntlm');
+	// const info = ntlm.GetInfo('acme.com', 443, '/EWS/');
+	// log(toJSON(info));
+	// ```
+	GetInfoResponse struct {
+		// TargetName is the target name from challenge message
+		TargetName string
+		// NetBIOSComputerName is the netbios name of server
+		NetBIOSComputerName string
+		// NetBIOSDomainName is the netbios name of domain
+		NetBIOSDomainName string
+		// DNSComputerName is the fqdn of server
+		DNSComputerName string
+		// DNSDomainName is the fqdn of domain
+		DNSDomainName string
+		// ForestName is the fqdn of forest
+		ForestName string
+		// OSVersion is the version of server os (ex: 10.0.17763)
+		OSVersion string
+		// Timestamp is the server time in unix seconds (if present)
+		Timestamp int64
+		// TLS is true if the endpoint was reached over TLS
+		TLS bool
+	}
+)
+
+// GetInfo sends a NTLM NEGOTIATE message in the Authorization header of a HTTP
+// request to given host, port and path and parses the NTLM CHALLENGE returned in
+// WWW-Authenticate header. This leaks internal names and os version of windows
+// endpoints like OWA, Exchange, RDWeb, WinRM etc. HTTPS is tried first and
+// plaintext HTTP is used as fallback.
+// @example
+// ```javascript
+// const ntlm = require('nuclei/ntlm');
+// const info = ntlm.GetInfo('acme.com', 443, '/EWS/');
+// log(info.DNSDomainName);
+// ```
+func GetInfo(ctx context.Context, host string, port int, path string) (GetInfoResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetInfo(executionId, host, port, path)
+}
+
+// @memo
+func getInfo(executionId string, host string, port int, path string) (GetInfoResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return GetInfoResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if path == "" {
+		path = "/"
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, negotiateTimeout)
+	if err != nil {
+		return GetInfoResponse{}, err
+	}
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10, ServerName: host})
+	if err := tlsConn.Handshake(); err == nil {
+		challenge, err := sendNegotiate(tlsConn, host, path)
+		_ = conn.Close()
+		if err == nil {
+			resp, err := parseChallenge(challenge)
+			resp.TLS = true
+			return resp, err
+		}
+		if errors.Is(err, errNoChallenge) {
+			// endpoint speaks https but does not offer ntlm
+			return GetInfoResponse{}, err
+		}
+	} else {
+		_ = conn.Close()
+	}
+
+	// fallback to plaintext http
+	conn, err = protocolstate.DialWithDeadline(executionId, "tcp", address, negotiateTimeout)
+	if err != nil {
+		return GetInfoResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	challenge, err := sendNegotiate(conn, host, path)
+	if err != nil {
+		return GetInfoResponse{}, err
+	}
+	return parseChallenge(challenge)
+}
+
+// sendNegotiate sends a http request with ntlm negotiate message
+// and returns the decoded challenge message from response
+func sendNegotiate(conn net.Conn, host string, path string) ([]byte, error) {
+	req := fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\nAuthorization: NTLM %s\r\nConnection: close\r\n\r\n",
+		path, host, base64.StdEncoding.EncodeToString(negotiateMessage))
+	if _, err := conn.Write([]byte(req)); err != nil {
+		return nil, err
+	}
+	httpResp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodGet})
+	if err != nil {
+		return nil, err
+	}
+	_ = httpResp.Body.Close()
+
+	for _, value := range httpResp.Header.Values("WWW-Authenticate") {
+		scheme, token, ok := strings.Cut(strings.TrimSpace(value), " ")
+		if !ok || (!strings.EqualFold(scheme, "NTLM") && !strings.EqualFold(scheme, "Negotiate")) {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(token))
+		if err != nil || !bytes.HasPrefix(decoded, ntlmSignature) {
+			continue
+		}
+		return decoded, nil
+	}
+	return nil, errNoChallenge
+}
+
+// parseChallenge parses a ntlm CHALLENGE_MESSAGE and its AV_PAIRs
+func parseChallenge(data []byte) (GetInfoResponse, error) {
+	resp := GetInfoResponse{}
+	if len(data) < 48 || !bytes.HasPrefix(data, ntlmSignature) || binary.LittleEndian.Uint32(data[8:12]) != 2 {
+		return resp, errors.New("invalid ntlm challenge message")
+	}
+	if target, ok := field(data, 12); ok {
+		resp.TargetName = decodeUTF16(target)
+	}
+	flags := binary.LittleEndian.Uint32(data[20:24])
+	if flags&negotiateVersion != 0 && len(data) >= 56 {
+		resp.OSVersion = fmt.Sprintf("%d.%d.%d", data[48], data[49], binary.LittleEndian.Uint16(data[50:52]))
+	}
+
+	info, ok := field(data, 40)
+	if !ok {
+		return resp, nil
+	}
+	for len(info) >= 4 {
+		id := binary.LittleEndian.Uint16(info[0:2])
+		length := int(binary.LittleEndian.Uint16(info[2:4]))
+		if id == avEOL || 4+length > len(info) {
+			break
+		}
+		value := info[4 : 4+length]
+		switch id {
+		case avNbComputerName:
+			resp.NetBIOSComputerName = decodeUTF16(value)
+		case avNbDomainName:
+			resp.NetBIOSDomainName = decodeUTF16(value)
+		case avDNSComputerName:
+			resp.DNSComputerName = decodeUTF16(value)
+		case avDNSDomainName:
+			resp.DNSDomainName = decodeUTF16(value)
+		case avDNSTreeName:
+			resp.ForestName = decodeUTF16(value)
+		case avTimestamp:
+			if length == 8 {
+				// FILETIME is 100ns intervals since 1601-01-01
+				filetime := int64(binary.LittleEndian.Uint64(value))
+				resp.Timestamp = filetime/10000000 - 11644473600
+			}
+		}
+		info = info[4+length:]
+	}
+	return resp, nil
+}
+
+// field returns the payload referenced by the len/maxlen/offset field at given offset
+func field(data []byte, at int) ([]byte, bool) {
+	length := int(binary.LittleEndian.Uint16(data[at : at+2]))
+	offset := int(binary.LittleEndian.Uint32(data[at+4 : at+8]))
+	if length == 0 || offset+length > len(data) {
+		return nil, false
+	}
+	return data[offset : offset+length], true
+}
+
+// decodeUTF16 decodes a little endian utf16 string
+func decodeUTF16(b []byte) string {
+	chars := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		chars = append(chars, binary.LittleEndian.Uint16(b[i:]))
+	}
+	return string(utf16.Decode(chars))
+}
@@ -0,0 +1,34 @@
+// Warning - This is generated code
+package opcua
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[GetEndpointsResponse]("opcua.getEndpoints", 3)
+}
+
+func memoizedgetEndpoints(executionId string, host string, port int) (GetEndpointsResponse, error) {
+	hash := "opcua.getEndpoints" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "opcua.getEndpoints" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (GetEndpointsResponse, error) {
+			return getEndpoints(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return GetEndpointsResponse{}, err
+	}
+	if value, ok := v.(GetEndpointsResponse); ok {
+		return value, nil
+	}
+
+	return GetEndpointsResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,512 @@
+package opcua
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the complete discovery exchange
+	probeTimeout = 10 * time.Second
+
+	securityPolicyNone = "http://opcfoundation.org/UA/SecurityPolicy#None"
+
+	// binary encoding ids of services
+	idOpenSecureChannelRequest  = 446
+	idOpenSecureChannelResponse = 449
+	idGetEndpointsRequest       = 428
+	idGetEndpointsResponse      = 431
+	idServiceFault              = 397
+
+	bufferSize = 65536
+	// maximum size of a reassembled message
+	maxMessageSize = 4 * 1024 * 1024
+)
+
+var (
+	securityModes = map[int32]string{1: "None", 2: "Sign", 3: "SignAndEncrypt"}
+	tokenTypes    = map[int32]string{0: "Anonymous", 1: "UserName", 2: "Certificate", 3: "IssuedToken"}
+
+	errInvalidMessage = errors.New("invalid opc ua message")
+)
+
+type (
+	// GetEndpointsResponse is the response from the GetEndpoints function.
+	// this is returned by GetEndpoints function.
+	// @example
+	// ```javascript
+	// const opcua = require('nuclei/opcua');
+	// const result = opcua.GetEndpoints('acme.com', 4840);
+	// log(toJSON(result));
+	// ```
+	GetEndpointsResponse struct {
+		// Endpoints contains endpoints advertised by the server
+		Endpoints []Endpoint
+		// AllowsNone is true if any endpoint uses None security mode or policy
+		AllowsNone bool
+		// AllowsAnonymous is true if any endpoint accepts anonymous user tokens
+		AllowsAnonymous bool
+	}
+
+	// Endpoint is an endpoint description returned by GetEndpoints service.
+	// @example
+	// ```javascript
+	// const opcua = require('nuclei/opcua');
+	// const result = opcua.GetEndpoints('acme.com', 4840);
+	// log(result.Endpoints[0].SecurityPolicyURI);
+	// ```
+	Endpoint struct {
+		// EndpointURL is the url of the endpoint
+		EndpointURL string
+		// SecurityMode is the message security mode (None, Sign, SignAndEncrypt)
+		SecurityMode string
+		// SecurityPolicyURI is the uri of the security policy
+		SecurityPolicyURI string
+		// SecurityLevel is the relative security level assigned by server
+		SecurityLevel int
+		// UserTokenTypes contains accepted user token types (Anonymous, UserName, Certificate, IssuedToken)
+		UserTokenTypes []string
+		// ApplicationURI is the application uri of the server
+		ApplicationURI string
+		// ProductURI is the product uri of the server
+		ProductURI string
+		// ApplicationName is the application name of the server
+		ApplicationName string
+	}
+)
+
+// GetEndpoints performs the OPC UA Hello/Acknowledge handshake, opens an
+// unsecured secure channel (SecurityPolicy#None) and calls the GetEndpoints
+// discovery service returning advertised endpoints, security policies and
+// message security modes.
+// @example
+// ```javascript
+// const opcua = require('nuclei/opcua');
+// const result = opcua.GetEndpoints('acme.com', 4840);
+// log(result.AllowsNone);
+// ```
+func GetEndpoints(ctx context.Context, host string, port int) (GetEndpointsResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetEndpoints(executionId, host, port)
+}
+
+// @memo
+func getEndpoints(executionId string, host string, port int) (GetEndpointsResponse, error) {
+	resp := GetEndpointsResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, probeTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	endpointURL := "opc.tcp://" + address
+
+	// hello / acknowledge
+	hello := &encoder{}
+	hello.uint32(0) // protocol version
+	hello.uint32(bufferSize)
+	hello.uint32(bufferSize)
+	hello.uint32(0) // max message size
+	hello.uint32(0) // max chunk count
+	hello.string(endpointURL)
+	if err := writeMessage(conn, "HEL", hello.buf); err != nil {
+		return resp, err
+	}
+	if _, err := readMessage(conn, "ACK"); err != nil {
+		return resp, err
+	}
+
+	// open secure channel with security policy none
+	open := &encoder{}
+	open.uint32(0) // secure channel id
+	open.string(securityPolicyNone)
+	open.byteString(nil) // sender certificate
+	open.byteString(nil) // receiver certificate thumbprint
+	open.uint32(1)       // sequence number
+	open.uint32(1)       // request id
+	open.nodeId(idOpenSecureChannelRequest)
+	open.requestHeader(1)
+	open.uint32(0)       // client protocol version
+	open.uint32(0)       // request type: issue
+	open.uint32(1)       // security mode: none
+	open.byteString(nil) // client nonce
+	open.uint32(3600000) // requested lifetime
+	if err := writeMessage(conn, "OPN", open.buf); err != nil {
+		return resp, err
+	}
+	data, err := readMessage(conn, "OPN")
+	if err != nil {
+		return resp, err
+	}
+	d := &decoder{buf: data}
+	_ = d.uint32() // secure channel id
+	d.string()     // security policy uri
+	d.byteString() // sender certificate
+	d.byteString() // receiver certificate thumbprint
+	_ = d.uint32() // sequence number
+	_ = d.uint32() // request id
+	if err := d.expectNodeId(idOpenSecureChannelResponse); err != nil {
+		return resp, err
+	}
+	d.responseHeader()
+	_ = d.uint32() // server protocol version
+	channelId := d.uint32()
+	tokenId := d.uint32()
+	if d.err != nil {
+		return resp, d.err
+	}
+
+	// get endpoints
+	request := &encoder{}
+	request.uint32(channelId)
+	request.uint32(tokenId)
+	request.uint32(2) // sequence number
+	request.uint32(2) // request id
+	request.nodeId(idGetEndpointsRequest)
+	request.requestHeader(2)
+	request.string(endpointURL)
+	request.uint32(0xffffffff) // locale ids: null array
+	request.uint32(0xffffffff) // profile uris: null array
+	if err := writeMessage(conn, "MSG", request.buf); err != nil {
+		return resp, err
+	}
+	data, err = readMessage(conn, "MSG")
+	if err != nil {
+		return resp, err
+	}
+	d = &decoder{buf: data}
+	if err := d.expectNodeId(idGetEndpointsResponse); err != nil {
+		return resp, err
+	}
+	d.responseHeader()
+	count := d.arrayLength()
+	for i := 0; i < count && d.err == nil; i++ {
+		endpoint := d.endpoint()
+		if endpoint.SecurityMode == "None" || endpoint.SecurityPolicyURI == securityPolicyNone {
+			resp.AllowsNone = true
+		}
+		for _, tokenType := range endpoint.UserTokenTypes {
+			if tokenType == "Anonymous" {
+				resp.AllowsAnonymous = true
+			}
+		}
+		resp.Endpoints = append(resp.Endpoints, endpoint)
+	}
+	if d.err != nil {
+		return resp, d.err
+	}
+	// the channel is discarded when the connection is closed
+	return resp, nil
+}
+
+// writeMessage writes a final chunk of given message type
+func writeMessage(conn net.Conn, messageType string, body []byte) error {
+	header := make([]byte, 8)
+	copy(header, messageType)
+	header[3] = 'F'
+	binary.LittleEndian.PutUint32(header[4:], uint32(8+len(body)))
+	_, err := conn.Write(append(header, body...))
+	return err
+}
+
+// readMessage reads chunks of expected message type until the final chunk
+// and returns the reassembled message. For MSG chunks the security and sequence
+// headers of intermediate chunks are stripped.
+func readMessage(conn net.Conn, messageType string) ([]byte, error) {
+	var message []byte
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return nil, err
+		}
+		size := int(binary.LittleEndian.Uint32(header[4:8]))
+		if size < 8 || len(message)+size > maxMessageSize {
+			return nil, errInvalidMessage
+		}
+		body := make([]byte, size-8)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return nil, err
+		}
+		switch string(header[:3]) {
+		case "ERR":
+			d := &decoder{buf: body}
+			code := d.uint32()
+			return nil, fmt.Errorf("opc ua error 0x%08x: %s", code, d.string())
+		case messageType:
+		default:
+			return nil, errInvalidMessage
+		}
+		if header[3] == 'A' {
+			return nil, errors.New("opc ua message aborted by server")
+		}
+		if messageType == "MSG" {
+			// secure channel id, token id, sequence number, request id
+			if len(body) < 16 {
+				return nil, errInvalidMessage
+			}
+			body = body[16:]
+		}
+		message = append(message, body...)
+		if header[3] == 'F' {
+			return message, nil
+		}
+		if messageType != "MSG" {
+			// only MSG can be chunked
+			return nil, errInvalidMessage
+		}
+	}
+}
+
+// encoder is a minimal opc ua binary encoder
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uint32(v uint32) {
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+func (e *encoder) string(v string) {
+	e.uint32(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) byteString(v []byte) {
+	if v == nil {
+		e.uint32(0xffffffff)
+		return
+	}
+	e.uint32(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// nodeId writes a four byte encoded numeric node id in namespace 0
+func (e *encoder) nodeId(id uint16) {
+	e.buf = append(e.buf, 0x01, 0x00)
+	e.buf = binary.LittleEndian.AppendUint16(e.buf, id)
+}
+
+func (e *encoder) requestHeader(handle uint32) {
+	e.buf = append(e.buf, 0x00, 0x00) // authentication token: null node id
+	// timestamp as windows filetime
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, uint64(time.Now().Unix()+11644473600)*10000000)
+	e.uint32(handle)
+	e.uint32(0)          // return diagnostics
+	e.uint32(0xffffffff) // audit entry id: null string
+	e.uint32(uint32(probeTimeout.Milliseconds()))
+	e.buf = append(e.buf, 0x00, 0x00, 0x00) // additional header: null extension object
+}
+
+// decoder is a minimal opc ua binary decoder which latches the first error
+type decoder struct {
+	buf []byte
+	err error
+	// serviceResult is the status code of last decoded response header
+	serviceResult uint32
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = errInvalidMessage
+		return nil
+	}
+	v := d.buf[:n]
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) byte() byte {
+	if v := d.next(1); v != nil {
+		return v[0]
+	}
+	return 0
+}
+
+func (d *decoder) uint16() uint16 {
+	if v := d.next(2); v != nil {
+		return binary.LittleEndian.Uint16(v)
+	}
+	return 0
+}
+
+func (d *decoder) uint32() uint32 {
+	if v := d.next(4); v != nil {
+		return binary.LittleEndian.Uint32(v)
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	return int32(d.uint32())
+}
+
+func (d *decoder) byteString() []byte {
+	length := d.int32()
+	if length <= 0 {
+		return nil
+	}
+	return d.next(int(length))
+}
+
+func (d *decoder) string() string {
+	return string(d.byteString())
+}
+
+// arrayLength returns length of an array (null arrays have length 0)
+func (d *decoder) arrayLength() int {
+	length := d.int32()
+	if length < 0 {
+		return 0
+	}
+	if int(length) > len(d.buf) {
+		d.err = errInvalidMessage
+		return 0
+	}
+	return int(length)
+}
+
+// nodeId decodes a node id and returns its numeric identifier (if numeric)
+func (d *decoder) nodeId() uint32 {
+	encoding := d.byte()
+	var id uint32
+	switch encoding & 0x3f {
+	case 0x00:
+		id = uint32(d.byte())
+	case 0x01:
+		d.byte()
+		id = uint32(d.uint16())
+	case 0x02:
+		d.uint16()
+		id = d.uint32()
+	case 0x03, 0x05:
+		d.uint16()
+		d.byteString()
+	case 0x04:
+		d.uint16()
+		d.next(16)
+	default:
+		d.err = errInvalidMessage
+	}
+	if encoding&0x80 != 0 {
+		d.string() // namespace uri
+	}
+	if encoding&0x40 != 0 {
+		d.uint32() // server index
+	}
+	return id
+}
+
+// expectNodeId decodes type id of a message body and checks it matches expected type
+func (d *decoder) expectNodeId(expected uint32) error {
+	id := d.nodeId()
+	if d.err != nil {
+		return d.err
+	}
+	if id == idServiceFault {
+		d.responseHeader()
+		return fmt.Errorf("opc ua service fault: 0x%08x", d.serviceResult)
+	}
+	if id != expected {
+		return fmt.Errorf("unexpected opc ua response type %d", id)
+	}
+	return nil
+}
+
+func (d *decoder) diagnosticInfo() {
+	mask := d.byte()
+	for _, bit := range []byte{0x01, 0x02, 0x04, 0x08} {
+		if mask&bit != 0 {
+			d.int32()
+		}
+	}
+	if mask&0x10 != 0 {
+		d.string() // additional info
+	}
+	if mask&0x20 != 0 {
+		d.uint32() // inner status code
+	}
+	if mask&0x40 != 0 && d.err == nil {
+		d.diagnosticInfo()
+	}
+}
+
+func (d *decoder) extensionObject() {
+	d.nodeId()
+	if encoding := d.byte(); encoding == 0x01 || encoding == 0x02 {
+		d.byteString()
+	}
+}
+
+func (d *decoder) localizedText() string {
+	mask := d.byte()
+	if mask&0x01 != 0 {
+		d.string() // locale
+	}
+	if mask&0x02 != 0 {
+		return d.string()
+	}
+	return ""
+}
+
+func (d *decoder) responseHeader() {
+	d.next(8) // timestamp
+	d.uint32()
+	d.serviceResult = d.uint32()
+	d.diagnosticInfo()
+	for i, count := 0, d.arrayLength(); i < count && d.err == nil; i++ {
+		d.string() // string table
+	}
+	d.extensionObject()
+}
+
+func (d *decoder) endpoint() Endpoint {
+	endpoint := Endpoint{}
+	endpoint.EndpointURL = d.string()
+	// application description
+	endpoint.ApplicationURI = d.string()
+	endpoint.ProductURI = d.string()
+	endpoint.ApplicationName = d.localizedText()
+	d.int32()  // application type
+	d.string() // gateway server uri
+	d.string() // discovery profile uri
+	for i, count := 0, d.arrayLength(); i < count && d.err == nil; i++ {
+		d.string() // discovery urls
+	}
+	d.byteString() // server certificate
+	mode := d.int32()
+	endpoint.SecurityMode = securityModes[mode]
+	if endpoint.SecurityMode == "" {
+		endpoint.SecurityMode = fmt.Sprintf("Unknown(%d)", mode)
+	}
+	endpoint.SecurityPolicyURI = d.string()
+	for i, count := 0, d.arrayLength(); i < count && d.err == nil; i++ {
+		d.string() // policy id
+		tokenType := d.int32()
+		d.string() // issued token type
+		d.string() // issuer endpoint url
+		d.string() // security policy uri
+		if name, ok := tokenTypes[tokenType]; ok {
+			endpoint.UserTokenTypes = append(endpoint.UserTokenTypes, name)
+		}
+	}
+	d.string() // transport profile uri
+	endpoint.SecurityLevel = int(d.byte())
+	return endpoint
+}
@@ -2,17 +2,26 @@
 package oracle
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisOracle(executionId string, host string, port int) (IsOracleResponse, error) {
-	hash := "isOracle" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[IsOracleResponse]("oracle.isOracle", 3)
+}
+
+func memoizedisOracle(ctx context.Context, executionId string, host string, port int) (IsOracleResponse, error) {
+	hash := "oracle.isOracle" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "oracle.isOracle" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isOracle(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsOracleResponse, error) {
+			return isOracle(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return IsOracleResponse{}, err
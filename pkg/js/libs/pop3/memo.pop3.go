@@ -2,17 +2,26 @@
 package pop3
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisPoP3(executionId string, host string, port int) (IsPOP3Response, error) {
-	hash := "isPoP3" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[IsPOP3Response]("pop3.isPoP3", 3)
+}
+
+func memoizedisPoP3(ctx context.Context, executionId string, host string, port int) (IsPOP3Response, error) {
+	hash := "pop3.isPoP3" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "pop3.isPoP3" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isPoP3(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsPOP3Response, error) {
+			return isPoP3(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return IsPOP3Response{}, err
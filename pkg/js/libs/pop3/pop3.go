@@ -2,7 +2,6 @@ package pop3
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -36,20 +35,15 @@ type (
 // ```
 func IsPOP3(ctx context.Context, host string, port int) (IsPOP3Response, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedisPoP3(executionId, host, port)
+	return memoizedisPoP3(ctx, executionId, host, port)
 }
 
 // @memo
-func isPoP3(executionId string, host string, port int) (IsPOP3Response, error) {
+func isPoP3(ctx context.Context, executionId string, host string, port int) (IsPOP3Response, error) {
 	resp := IsPOP3Response{}
 
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return IsPOP3Response{}, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-
 	timeout := 5 * time.Second
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
 	if err != nil {
 		return resp, err
 	}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"maps"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the startup handshake
+	startupTimeout = 10 * time.Second
+	// protocol version 3.0 of the startup message
+	protocolVersion = 196608
+	// maximum size of a backend message
+	maxMessageSize = 64 * 1024
+	// maximum number of backend messages read after the startup message
+	maxStartupMessages = 64
+
+	// wire compatible engines returned by DetectEngine
+	enginePostgreSQL  = "PostgreSQL"
+	engineCockroachDB = "CockroachDB"
+	engineYugabyteDB  = "YugabyteDB"
+	engineGreenplum   = "Greenplum"
+)
+
+var (
+	// version of crdb_version (ex: CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu, ...))
+	cockroachVersion = regexp.MustCompile(`v([0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.\-]*)`)
+	// version of yugabytedb server_version (ex: 11.2-YB-2.18.0.0-b0)
+	yugabyteVersion = regexp.MustCompile(`-YB-([0-9][0-9A-Za-z.\-]*)`)
+	// version of greenplum parameters (ex: Greenplum Database 6.25.3)
+	greenplumVersion = regexp.MustCompile(`([0-9]+\.[0-9]+(?:\.[0-9]+)?)`)
+
+	errInvalidMessage = errors.New("invalid postgres message")
+)
+
+type (
+	// DetectEngineResponse is the response from the DetectEngine function.
+	// this is returned by DetectEngine function.
+	// @example
+	// ```javascript
+	// const postgres = require('nuclei/postgres');
+	// const engine = postgres.DetectEngine('acme.com', 5432);
+	// log(toJSON(engine));
+	// ```
+	DetectEngineResponse struct {
+		// IsPostgres is true if the server speaks the postgres wire protocol
+		IsPostgres bool
+		// Engine is the identified engine (PostgreSQL, CockroachDB, YugabyteDB or Greenplum)
+		// and is empty if the server did not reveal it (ex: authentication required)
+		Engine string
+		// Version is the version of the engine (ex: 23.1.11 for CockroachDB)
+		Version string
+		// AuthRequired is true if the server requested authentication before
+		// sending its parameters
+		AuthRequired bool
+	}
+)
+
+// DetectEngine distinguishes real PostgreSQL from wire compatible engines
+// (CockroachDB, YugabyteDB, Greenplum) using the server_version and engine
+// specific parameter status fields sent after the startup handshake.
+// Parameters are only sent to unauthenticated clients by servers using trust
+// authentication, otherwise engine specific error messages are used if any.
+// The result is memoized on host and port.
+// @example
+// ```javascript
+// const postgres = require('nuclei/postgres');
+// const engine = postgres.DetectEngine('acme.com', 5432);
+// if (engine.Engine == 'CockroachDB') { log(engine.Version); }
+// ```
+func DetectEngine(ctx context.Context, host string, port int) (DetectEngineResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizeddetectEngine(executionId, host, port)
+}
+
+// @memo
+func detectEngine(executionId string, host string, port int) (DetectEngineResponse, error) {
+	resp := DetectEngineResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), startupTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Write(newStartupMessage("postgres", "postgres")); err != nil {
+		return resp, err
+	}
+	params, errorMessage, err := readStartup(bufio.NewReader(utils.LimitConn(executionId, conn)), &resp)
+	if err != nil && !resp.IsPostgres {
+		// not a postgres wire server
+		return resp, nil
+	}
+	resp.Engine, resp.Version = classifyEngine(params, errorMessage)
+	return resp, nil
+}
+
+// newStartupMessage returns a protocol 3.0 startup message
+func newStartupMessage(user string, database string) []byte {
+	var body []byte
+	body = binary.BigEndian.AppendUint32(body, protocolVersion)
+	for _, param := range []string{"user", user, "database", database, "application_name", "nuclei"} {
+		body = append(body, param...)
+		body = append(body, 0x00)
+	}
+	body = append(body, 0x00)
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(body)+4)), body...)
+}
+
+// readStartup reads the backend messages answering the startup message
+// until the server is ready, requests authentication or fails. It returns
+// the parameter status fields and the message of an error response
+func readStartup(reader *bufio.Reader, resp *DetectEngineResponse) (map[string]string, string, error) {
+	params := map[string]string{}
+	for i := 0; i < maxStartupMessages; i++ {
+		messageType, data, err := readMessage(reader)
+		if err != nil {
+			return params, "", err
+		}
+		switch messageType {
+		case 'R':
+			if len(data) < 4 {
+				return params, "", errInvalidMessage
+			}
+			resp.IsPostgres = true
+			if binary.BigEndian.Uint32(data[:4]) != 0 {
+				resp.AuthRequired = true
+				return params, "", nil
+			}
+		case 'S':
+			fields := bytes.Split(data, []byte{0x00})
+			if len(fields) < 2 {
+				return params, "", errInvalidMessage
+			}
+			params[string(fields[0])] = string(fields[1])
+		case 'E':
+			resp.IsPostgres = true
+			return params, errorField(data, 'M'), nil
+		case 'K', 'N':
+			// backend key data and notices
+		case 'Z':
+			return params, "", nil
+		default:
+			return params, "", errInvalidMessage
+		}
+	}
+	return params, "", nil
+}
+
+// readMessage reads a backend message and returns its type and body
+func readMessage(reader *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(header[1:5]))
+	if length < 4 || length > maxMessageSize {
+		return 0, nil, errInvalidMessage
+	}
+	data := make([]byte, length-4)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return 0, nil, err
+	}
+	return header[0], data, nil
+}
+
+// errorField returns the value of the given field of an error response
+func errorField(data []byte, field byte) string {
+	for len(data) > 1 && data[0] != 0x00 {
+		end := bytes.IndexByte(data[1:], 0x00)
+		if end < 0 {
+			return ""
+		}
+		if data[0] == field {
+			return string(data[1 : 1+end])
+		}
+		data = data[2+end:]
+	}
+	return ""
+}
+
+// classifyEngine returns the engine and version revealed by the parameter
+// status fields or the error message of the startup handshake
+func classifyEngine(params map[string]string, errorMessage string) (string, string) {
+	serverVersion := params["server_version"]
+	if crdbVersion, ok := params["crdb_version"]; ok || strings.Contains(strings.ToLower(serverVersion+errorMessage), "cockroach") {
+		if match := cockroachVersion.FindStringSubmatch(crdbVersion); match != nil {
+			return engineCockroachDB, match[1]
+		}
+		return engineCockroachDB, ""
+	}
+	if match := yugabyteVersion.FindStringSubmatch(serverVersion); match != nil {
+		return engineYugabyteDB, match[1]
+	}
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		value := params[name]
+		if strings.HasPrefix(name, "gp_") || strings.Contains(strings.ToLower(name+value), "greenplum") {
+			if match := greenplumVersion.FindStringSubmatch(value); match != nil && strings.Contains(strings.ToLower(value), "greenplum") {
+				return engineGreenplum, match[1]
+			}
+			return engineGreenplum, ""
+		}
+	}
+	if serverVersion != "" {
+		// ex: 16.2 (Debian 16.2-1.pgdg120+2)
+		return enginePostgreSQL, strings.Fields(serverVersion)[0]
+	}
+	return "", ""
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// backendMessage frames body as a backend message of the given type
+func backendMessage(messageType byte, body ...string) []byte {
+	var data []byte
+	for _, field := range body {
+		data = append(data, field...)
+	}
+	message := []byte{messageType}
+	message = binary.BigEndian.AppendUint32(message, uint32(len(data)+4))
+	return append(message, data...)
+}
+
+// startupListener answers the startup message with response
+func startupListener(t *testing.T, response []byte) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				header := make([]byte, 4)
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, make([]byte, binary.BigEndian.Uint32(header)-4)); err != nil {
+					return
+				}
+				_, _ = conn.Write(response)
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestDetectEngine(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	authOk := backendMessage('R', "\x00\x00\x00\x00")
+	ready := backendMessage('Z', "I")
+	parameter := func(name, value string) []byte {
+		return backendMessage('S', name, "\x00", value, "\x00")
+	}
+	join := func(messages ...[]byte) []byte {
+		var data []byte
+		for _, message := range messages {
+			data = append(data, message...)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     DetectEngineResponse
+	}{
+		{
+			name:     "postgresql",
+			response: join(authOk, parameter("server_version", "16.2 (Debian 16.2-1.pgdg120+2)"), ready),
+			want:     DetectEngineResponse{IsPostgres: true, Engine: "PostgreSQL", Version: "16.2"},
+		},
+		{
+			name: "cockroachdb",
+			response: join(authOk, parameter("server_version", "13.0.0"),
+				parameter("crdb_version", "CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu, built 2023/09/27 01:53:43, go1.19.10)"), ready),
+			want: DetectEngineResponse{IsPostgres: true, Engine: "CockroachDB", Version: "23.1.11"},
+		},
+		{
+			name:     "yugabytedb",
+			response: join(authOk, parameter("server_version", "11.2-YB-2.18.0.0-b0"), ready),
+			want:     DetectEngineResponse{IsPostgres: true, Engine: "YugabyteDB", Version: "2.18.0.0-b0"},
+		},
+		{
+			name:     "greenplum",
+			response: join(authOk, parameter("server_version", "9.4.26"), parameter("greenplum_version", "Greenplum Database 6.25.3"), ready),
+			want:     DetectEngineResponse{IsPostgres: true, Engine: "Greenplum", Version: "6.25.3"},
+		},
+		{
+			name:     "authentication required",
+			response: backendMessage('R', "\x00\x00\x00\x0a", "SCRAM-SHA-256\x00\x00"),
+			want:     DetectEngineResponse{IsPostgres: true, AuthRequired: true},
+		},
+		{
+			name:     "not postgres",
+			response: []byte("HTTP/1.1 400 Bad Request\r\n\r\n"),
+			want:     DetectEngineResponse{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectEngine(ctx, "127.0.0.1", startupListener(t, tt.response))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[DetectEngineResponse]("postgres.detectEngine", 3)
+}
+
+func memoizeddetectEngine(executionId string, host string, port int) (DetectEngineResponse, error) {
+	hash := "postgres.detectEngine" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "postgres.detectEngine" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (DetectEngineResponse, error) {
+			return detectEngine(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return DetectEngineResponse{}, err
+	}
+	if value, ok := v.(DetectEngineResponse); ok {
+		return value, nil
+	}
+
+	return DetectEngineResponse{}, errors.New("could not convert cached result")
+}
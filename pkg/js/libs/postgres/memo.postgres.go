@@ -2,21 +2,31 @@
 package postgres
 
 import (
+	"context"
 	"errors"
-	"fmt"
 
-	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/utils/pgwrap"
+	"fmt"
 
 	utils "github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
 
+	_ "github.com/projectdiscovery/nuclei/v3/pkg/js/utils/pgwrap"
+
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisPostgres(executionId string, host string, port int) (bool, error) {
-	hash := "isPostgres" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[bool]("postgres.isPostgres", 3)
+}
+
+func memoizedisPostgres(ctx context.Context, executionId string, host string, port int) (bool, error) {
+	hash := "postgres.isPostgres" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "postgres.isPostgres" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isPostgres(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return isPostgres(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return false, err
@@ -28,9 +38,13 @@ func memoizedisPostgres(executionId string, host string, port int) (bool, error)
 	return false, errors.New("could not convert cached result")
 }
 
-func memoizedexecuteQuery(executionId string, host string, port int, username string, password string, dbName string, query string) (*utils.SQLResult, error) {
-	hash := "executeQuery" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName) + ":" + fmt.Sprint(query)
+func init() {
+	protocolstate.RegisterMemoized[*utils.SQLResult]("postgres.executeQuery", 7)
+}
 
+func memoizedexecuteQuery(executionId string, host string, port int, username string, password string, dbName string, query string) (*utils.SQLResult, error) {
+	hash := "postgres.executeQuery" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName) + ":" + fmt.Sprint(query)
+	// results are only memoized in memory (nodisk)
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
 		return executeQuery(executionId, host, port, username, password, dbName, query)
 	})
@@ -44,11 +58,19 @@ func memoizedexecuteQuery(executionId string, host string, port int, username st
 	return nil, errors.New("could not convert cached result")
 }
 
+func init() {
+	protocolstate.RegisterMemoized[bool]("postgres.connect", 6)
+}
+
 func memoizedconnect(executionId string, host string, port int, username string, password string, dbName string) (bool, error) {
-	hash := "connect" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName)
+	hash := "postgres.connect" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "postgres.connect" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(dbName)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return connect(executionId, host, port, username, password, dbName)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return connect(executionId, host, port, username, password, dbName)
+		})
 	})
 	if err != nil {
 		return false, err
@@ -39,19 +39,14 @@ type (
 func (c *PGClient) IsPostgres(ctx context.Context, host string, port int) (bool, error) {
 	executionId := ctx.Value("executionId").(string)
 	// todo: why this is exposed? Service fingerprint should be automatic
-	return memoizedisPostgres(executionId, host, port)
+	return memoizedisPostgres(ctx, executionId, host, port)
 }
 
 // @memo
-func isPostgres(executionId string, host string, port int) (bool, error) {
+func isPostgres(ctx context.Context, executionId string, host string, port int) (bool, error) {
 	timeout := 10 * time.Second
 
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return false, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", fmt.Sprintf("%s:%d", host, port), timeout)
 	if err != nil {
 		return false, err
 	}
@@ -118,7 +113,7 @@ func (c *PGClient) ExecuteQuery(ctx context.Context, host string, port int, user
 	return memoizedexecuteQuery(executionId, host, port, username, password, dbName, query)
 }
 
-// @memo
+// @memo(nodisk)
 func executeQuery(executionId string, host string, port int, username string, password string, dbName string, query string) (*utils.SQLResult, error) {
 	if !protocolstate.IsHostAllowed(executionId, host) {
 		// host is not valid according to network policy
@@ -187,9 +182,9 @@ func connect(executionId string, host string, port int, username string, passwor
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return false, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return false, err
 	}
 
 	db := pg.Connect(&pg.Options{
@@ -198,7 +193,11 @@ func connect(executionId string, host string, port int, username string, passwor
 		Password: password,
 		Database: dbName,
 		Dialer: func(network, addr string) (net.Conn, error) {
-			return dialer.Fastdialer.Dial(context.Background(), network, addr)
+			conn, err := dialer.Fastdialer.Dial(context.Background(), network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return protocolstate.RecordConn(executionId, conn), nil
 		},
 		IdleCheckFrequency: -1,
 	}).WithContext(ctx).WithTimeout(10 * time.Second)
@@ -206,7 +205,7 @@ func connect(executionId string, host string, port int, username string, passwor
 		_ = db.Close()
 	}()
 
-	_, err := db.Exec("select 1")
+	_, err = db.Exec("select 1")
 	if err != nil {
 		switch true {
 		case strings.Contains(err.Error(), "connect: connection refused"):
@@ -0,0 +1,235 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	syncutil "github.com/projectdiscovery/utils/sync"
+)
+
+const (
+	// HoneypotMultipleProtocols is reported when unrelated protocol probes all succeed on the port
+	HoneypotMultipleProtocols = "multiple_protocols"
+	// HoneypotIdenticalBanners is reported when other ports of the host send the same banner
+	HoneypotIdenticalBanners = "identical_banners"
+	// HoneypotImplausibleLatency is reported when the port answers faster than a network round trip
+	HoneypotImplausibleLatency = "implausible_latency"
+	// HoneypotAnswersGarbage is reported when the port answers random binary data
+	HoneypotAnswersGarbage = "answers_garbage"
+)
+
+var (
+	// score added by each indicator
+	honeypotWeights = map[string]int{
+		HoneypotMultipleProtocols:  40,
+		HoneypotIdenticalBanners:   30,
+		HoneypotImplausibleLatency: 30,
+		HoneypotAnswersGarbage:     10,
+	}
+	// score added by each protocol matching beyond the second one
+	extraProtocolWeight = 15
+	// time to wait for a banner or the answer to random data
+	honeypotReadTimeout = 2 * time.Second
+	// connect latency below which answer times are not compared (ex: local networks)
+	minComparableLatency = 2 * time.Millisecond
+	// random data sent to the port
+	garbageProbe = []byte{0x8f, 0x1d, 0xe2, 0x00, 0x7a, 0xff, 0x3c, 0x91, 0x0d, 0x0a}
+)
+
+type (
+	// HoneypotScoreOptions contains options for HoneypotScore function.
+	// @example
+	// ```javascript
+	// const probe = require('nuclei/probe');
+	// const result = probe.HoneypotScore('acme.com', 22, { Probes: ['ssh', 'rdp', 'telnet'], Ports: [23, 2222] });
+	// ```
+	HoneypotScoreOptions struct {
+		// Probes are the protocol probes run against the port (default: all probes)
+		Probes []string
+		// Ports are other ports of the host whose banner is compared to the banner of the port
+		Ports []int
+	}
+
+	// HoneypotScoreResponse is the response from the HoneypotScore function.
+	// this is returned by HoneypotScore function.
+	// @example
+	// ```javascript
+	// const probe = require('nuclei/probe');
+	// const result = probe.HoneypotScore('acme.com', 22);
+	// log(result.Score, result.Indicators);
+	// ```
+	HoneypotScoreResponse struct {
+		// Score is the heuristic likelihood (0-100) of the target being a honeypot
+		Score int
+		// Indicators are the triggered indicators (multiple_protocols, identical_banners,
+		// implausible_latency, answers_garbage)
+		Indicators []string
+		// Protocols are the protocols identified on the port
+		Protocols []string
+	}
+)
+
+// HoneypotScore runs probes giving contradictory or implausible results on
+// real services against the host and port and returns a heuristic likelihood
+// of the target being a honeypot along with the triggered indicators:
+//   - multiple unrelated protocol probes succeed on the same port
+//   - other ports of the host (Ports option) send the same banner
+//   - the port answers faster than half of the tcp connect round trip
+//   - the port answers random binary data (weak, also done by ex: http servers)
+//
+// The score is meant to de-prioritize deception infrastructure and is not
+// a proof. Probes waiting for a banner take their full timeout against
+// honeypots which never send one.
+// @example
+// ```javascript
+// const probe = require('nuclei/probe');
+// const result = probe.HoneypotScore('acme.com', 22, { Ports: [23, 2222] });
+// if (result.Score >= 50) { log('likely honeypot', result.Indicators); }
+// ```
+func HoneypotScore(ctx context.Context, host string, port int, opts HoneypotScoreOptions) (HoneypotScoreResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return HoneypotScoreResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	probes := opts.Probes
+	if len(probes) == 0 {
+		probes = Probes()
+	}
+	for _, name := range probes {
+		if _, ok := detectors[name]; !ok {
+			return HoneypotScoreResponse{}, fmt.Errorf("unknown probe %s", name)
+		}
+	}
+
+	resp := HoneypotScoreResponse{Indicators: []string{}}
+	protocols, err := identifyAll(ctx, executionId, host, port, probes)
+	if err != nil {
+		return resp, err
+	}
+	resp.Protocols = protocols
+	if len(protocols) >= 2 {
+		resp.add(HoneypotMultipleProtocols)
+		resp.Score += extraProtocolWeight * (len(protocols) - 2)
+	}
+
+	sample, err := sampleService(executionId, host, port)
+	if err != nil {
+		return resp, err
+	}
+	if sample.banner != "" {
+		for _, other := range opts.Ports {
+			if other == port {
+				continue
+			}
+			// closed or silent ports do not share the banner
+			if otherSample, err := sampleService(executionId, host, other); err == nil && otherSample.banner == sample.banner {
+				resp.add(HoneypotIdenticalBanners)
+				break
+			}
+		}
+	}
+	if sample.answered {
+		resp.add(HoneypotAnswersGarbage)
+		if sample.connectLatency >= minComparableLatency && sample.answerLatency < sample.connectLatency/2 {
+			resp.add(HoneypotImplausibleLatency)
+		}
+	}
+	resp.Score = min(resp.Score, 100)
+	return resp, nil
+}
+
+// add records a triggered indicator and its score
+func (r *HoneypotScoreResponse) add(indicator string) {
+	r.Indicators = append(r.Indicators, indicator)
+	r.Score += honeypotWeights[indicator]
+}
+
+// identifyAll runs the given protocol probes concurrently (bounded like
+// Identify) and returns the names of all the matching probes
+func identifyAll(ctx context.Context, executionId string, host string, port int, probes []string) ([]string, error) {
+	wg, err := syncutil.New(syncutil.WithSize(probeThreads(executionId, len(probes))))
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	matches := []string{}
+	for _, name := range probes {
+		wg.Add()
+		go func(name string, detect detector) {
+			defer wg.Done()
+			protocolstate.RateLimitTake(executionId)
+			if ctx.Err() != nil {
+				return
+			}
+			if matched, _ := detect(ctx, host, port); matched {
+				mu.Lock()
+				matches = append(matches, name)
+				mu.Unlock()
+			}
+		}(name, detectors[name])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// serviceSample contains the behavior of a service after connect
+type serviceSample struct {
+	banner         string
+	answered       bool
+	connectLatency time.Duration
+	answerLatency  time.Duration
+}
+
+// sampleService reads the banner of the service (if any) and sends random
+// data measuring the time to its answer
+func sampleService(executionId string, host string, port int) (serviceSample, error) {
+	sample := serviceSample{}
+	// resolve first so the connect latency does not include dns resolution
+	ips, err := protocolstate.ResolveAll(executionId, host)
+	if err != nil {
+		return sample, err
+	}
+	start := time.Now()
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(ips[0], strconv.Itoa(port)), 2*honeypotReadTimeout)
+	if err != nil {
+		return sample, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	sample.connectLatency = time.Since(start)
+
+	buffer := make([]byte, 1024)
+	if err := conn.SetReadDeadline(protocolstate.GetDeadline(executionId, honeypotReadTimeout)); err != nil {
+		return sample, err
+	}
+	if n, _ := conn.Read(buffer); n > 0 {
+		sample.banner = strings.TrimSpace(string(buffer[:n]))
+	}
+
+	sent := time.Now()
+	if _, err := conn.Write(garbageProbe); err != nil {
+		// closed after the banner
+		return sample, nil
+	}
+	if err := conn.SetReadDeadline(protocolstate.GetDeadline(executionId, honeypotReadTimeout)); err != nil {
+		return sample, err
+	}
+	if n, _ := conn.Read(buffer); n > 0 {
+		sample.answered = true
+		sample.answerLatency = time.Since(sent)
+	}
+	return sample, nil
+}
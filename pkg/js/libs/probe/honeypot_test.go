@@ -0,0 +1,105 @@
+package probe
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// honeypotListener answers every protocol: jdwp handshakes and rdp connection
+// requests are acknowledged, any other data is answered and silent clients get banner
+func honeypotListener(t *testing.T, banner string) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				buffer := make([]byte, 1024)
+				for {
+					_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+					n, err := conn.Read(buffer)
+					var netErr net.Error
+					if errors.As(err, &netErr) && netErr.Timeout() {
+						_, _ = conn.Write([]byte(banner + "\r\n"))
+						_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+						if n, err = conn.Read(buffer); err != nil {
+							return
+						}
+					} else if err != nil {
+						return
+					}
+					_ = conn.SetReadDeadline(time.Time{})
+					switch data := buffer[:n]; {
+					case bytes.HasPrefix(data, []byte("JDWP-Handshake")):
+						_, _ = conn.Write([]byte("JDWP-Handshake"))
+						replyVersion(conn)
+						return
+					case bytes.HasPrefix(data, []byte{0x03, 0x00}):
+						_, _ = conn.Write([]byte{
+							0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+							0x02, 0x1f, 0x08, 0x00, 0x08, 0x00, 0x00, 0x00,
+						})
+					default:
+						_, _ = conn.Write([]byte("OK\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return host, portNum
+}
+
+func TestHoneypotScore(t *testing.T) {
+	ctx := jstest.Context(t)
+	readTimeout, comparableLatency := honeypotReadTimeout, minComparableLatency
+	// loopback latencies are not comparable
+	honeypotReadTimeout, minComparableLatency = time.Second, time.Hour
+	t.Cleanup(func() { honeypotReadTimeout, minComparableLatency = readTimeout, comparableLatency })
+
+	host, port := honeypotListener(t, "SSH-2.0-OpenSSH_7.4")
+	_, other := honeypotListener(t, "SSH-2.0-OpenSSH_7.4")
+	resp, err := HoneypotScore(ctx, host, port, HoneypotScoreOptions{Probes: []string{"jdwp", "rdp"}, Ports: []int{other}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := HoneypotScoreResponse{
+		Score:      80,
+		Indicators: []string{HoneypotMultipleProtocols, HoneypotIdenticalBanners, HoneypotAnswersGarbage},
+		Protocols:  []string{"jdwp", "rdp"},
+	}
+	if !reflect.DeepEqual(resp, want) {
+		t.Fatalf("expected %+v, got %+v", want, resp)
+	}
+
+	// a real service only matching its own protocol
+	host, port, _, _ = jdwpListener(t)
+	resp, err = HoneypotScore(ctx, host, port, HoneypotScoreOptions{Probes: []string{"jdwp", "rdp"}, Ports: []int{other}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = HoneypotScoreResponse{Indicators: []string{}, Protocols: []string{"jdwp"}}
+	if !reflect.DeepEqual(resp, want) {
+		t.Fatalf("expected %+v, got %+v", want, resp)
+	}
+
+	if _, err := HoneypotScore(ctx, host, port, HoneypotScoreOptions{Probes: []string{"gopher"}}); err == nil {
+		t.Fatal("expected error for unknown probe")
+	}
+}
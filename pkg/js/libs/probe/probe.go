@@ -0,0 +1,222 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/enip"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/fox"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/jdwp"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/minecraft"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mssql"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/mysql"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/oracle"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/pop3"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/postgres"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/rdp"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/rmi"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/rsync"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/smb"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/smi"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/socks"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/ssh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/telnet"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/libs/vnc"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	syncutil "github.com/projectdiscovery/utils/sync"
+)
+
+// defaultProbeThreads is the number of probes run concurrently
+// if the template concurrency of the execution is unknown
+const defaultProbeThreads = 25
+
+// detector returns true if the service at host:port speaks the protocol
+type detector func(ctx context.Context, host string, port int) (bool, error)
+
+var detectors = map[string]detector{
+	"enip": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := enip.GetIdentity(ctx, host, port)
+		return resp.IsENIP, err
+	},
+	"fox": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := fox.GetInfo(ctx, host, port)
+		return resp.IsFox, err
+	},
+	"jdwp": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := jdwp.IsJDWP(ctx, host, port)
+		return resp.IsJDWP, err
+	},
+	"minecraft": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := minecraft.ServerListPing(ctx, host, port)
+		return resp.IsMinecraft, err
+	},
+	"mssql": func(ctx context.Context, host string, port int) (bool, error) {
+		return (&mssql.MSSQLClient{}).IsMssql(ctx, host, port)
+	},
+	"mysql": func(ctx context.Context, host string, port int) (bool, error) {
+		return (&mysql.MySQLClient{}).IsMySQL(ctx, host, port)
+	},
+	"oracle": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := oracle.IsOracle(ctx, host, port)
+		return resp.IsOracle, err
+	},
+	"pop3": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := pop3.IsPOP3(ctx, host, port)
+		return resp.IsPOP3, err
+	},
+	"postgres": func(ctx context.Context, host string, port int) (bool, error) {
+		return (&postgres.PGClient{}).IsPostgres(ctx, host, port)
+	},
+	"rdp": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := rdp.IsRDP(ctx, host, port, rdp.IsRDPOptions{})
+		return resp.IsRDP, err
+	},
+	"rmi": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := rmi.IsRMI(ctx, host, port)
+		return resp.IsRMI, err
+	},
+	"rsync": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := rsync.IsRsync(ctx, host, port)
+		return resp.IsRsync, err
+	},
+	"smb": func(ctx context.Context, host string, port int) (bool, error) {
+		metadata, err := (&smb.SMBClient{}).ListSMBv2Metadata(ctx, host, port)
+		return metadata != nil, err
+	},
+	"smi": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := smi.IsSmartInstall(ctx, host, port)
+		return resp.Enabled, err
+	},
+	"socks": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := socks.IsOpenProxy(ctx, host, port)
+		return resp.IsSOCKS, err
+	},
+	"ssh": func(ctx context.Context, host string, port int) (bool, error) {
+		info, err := (&ssh.SSHClient{}).ConnectSSHInfoMode(ctx, host, port)
+		return info != nil, err
+	},
+	"telnet": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := telnet.IsTelnet(ctx, host, port)
+		return resp.IsTelnet, err
+	},
+	"vnc": func(ctx context.Context, host string, port int) (bool, error) {
+		resp, err := vnc.IsVNC(ctx, host, port)
+		return resp.IsVNC, err
+	},
+}
+
+type (
+	// IdentifyResponse is the response from the Identify function.
+	// this is returned by Identify function.
+	// @example
+	// ```javascript
+	// const probe = require('nuclei/probe');
+	// const result = probe.Identify('acme.com', 8443, ['rdp', 'ssh', 'smb']);
+	// log(toJSON(result));
+	// ```
+	IdentifyResponse struct {
+		// Matched is true if one of the probes identified the service
+		Matched bool
+		// Protocol is the name of the matching probe
+		Protocol string
+	}
+)
+
+// Probes returns the names of the available protocol probes.
+// @example
+// ```javascript
+// const probe = require('nuclei/probe');
+// log(probe.Probes());
+// ```
+func Probes() []string {
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Identify runs the given protocol probes concurrently against the same host
+// and port and returns the first positive identification. At most as many probes
+// as the template concurrency (-c) run at once. Once a probe matches, probes that
+// have not started yet are skipped and in-flight probes are cancelled (their
+// connections are closed) and their results are discarded. If no probes are
+// given all available probes are used.
+// @example
+// ```javascript
+// const probe = require('nuclei/probe');
+// const result = probe.Identify('acme.com', 8443, ['rdp', 'ssh', 'smb']);
+// log(result.Matched, result.Protocol);
+// ```
+func Identify(ctx context.Context, host string, port int, probes []string) (IdentifyResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return IdentifyResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if len(probes) == 0 {
+		probes = Probes()
+	}
+	for _, name := range probes {
+		if _, ok := detectors[name]; !ok {
+			return IdentifyResponse{}, fmt.Errorf("unknown probe %s", name)
+		}
+	}
+	wg, err := syncutil.New(syncutil.WithSize(probeThreads(executionId, len(probes))))
+	if err != nil {
+		return IdentifyResponse{}, err
+	}
+
+	// probes are cancelled on the first match
+	probeCtx, cancel := context.WithCancel(ctx)
+	// buffered so that losing probes never block after a match
+	matches := make(chan string, len(probes))
+	go func() {
+		for _, name := range probes {
+			wg.Add()
+			if probeCtx.Err() != nil {
+				// already matched or cancelled
+				wg.Done()
+				break
+			}
+			go func(name string, detect detector) {
+				defer wg.Done()
+				protocolstate.RateLimitTake(executionId)
+				if probeCtx.Err() != nil {
+					return
+				}
+				// errors after a positive identification (ex: failed
+				// follow-up requests) do not invalidate the match
+				if ok, _ := detect(probeCtx, host, port); ok {
+					matches <- name
+					cancel()
+				}
+			}(name, detectors[name])
+		}
+		wg.Wait()
+		cancel()
+		close(matches)
+	}()
+
+	select {
+	case name, ok := <-matches:
+		if !ok {
+			return IdentifyResponse{}, nil
+		}
+		return IdentifyResponse{Matched: true, Protocol: name}, nil
+	case <-ctx.Done():
+		cancel()
+		return IdentifyResponse{}, ctx.Err()
+	}
+}
+
+// probeThreads returns the number of probes run concurrently for an execution
+func probeThreads(executionId string, probes int) int {
+	threads := protocolstate.GetThreads(executionId)
+	if threads <= 0 {
+		threads = defaultProbeThreads
+	}
+	return max(min(threads, probes), 1)
+}
@@ -0,0 +1,183 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// jdwpListener only answers the jdwp handshake. clients sending anything
+// else are disconnected and clients waiting for a banner are held open.
+// The number of accepted connections and of clients that disconnected
+// before sending anything are returned along with the address.
+func jdwpListener(t *testing.T) (string, int, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	accepted, disconnected := &atomic.Int32{}, &atomic.Int32{}
+	t.Cleanup(func() {
+		close(done)
+		_ = ln.Close()
+	})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func() {
+				defer func() { _ = conn.Close() }()
+				handshake := []byte("JDWP-Handshake")
+				data := make(chan []byte, 1)
+				go func() {
+					buff := make([]byte, len(handshake))
+					if n, err := io.ReadFull(conn, buff); err != nil {
+						if n == 0 {
+							disconnected.Add(1)
+						}
+						close(data)
+						return
+					}
+					data <- buff
+				}()
+				select {
+				case buff, ok := <-data:
+					if ok && bytes.Equal(buff, handshake) {
+						_, _ = conn.Write(handshake)
+						replyVersion(conn)
+					}
+				case <-done:
+				}
+			}()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return host, portNum, accepted, disconnected
+}
+
+// replyVersion replies to the VirtualMachine.Version command
+func replyVersion(conn net.Conn) {
+	command := make([]byte, 11)
+	if _, err := io.ReadFull(conn, command); err != nil {
+		return
+	}
+	var data []byte
+	for _, value := range []any{"Java Debug Wire Protocol", 17, 0, "17.0.2", "OpenJDK 64-Bit Server VM"} {
+		switch v := value.(type) {
+		case string:
+			data = binary.BigEndian.AppendUint32(data, uint32(len(v)))
+			data = append(data, v...)
+		case int:
+			data = binary.BigEndian.AppendUint32(data, uint32(v))
+		}
+	}
+	reply := binary.BigEndian.AppendUint32(nil, uint32(11+len(data)))
+	reply = append(reply, command[4:8]...)
+	reply = append(reply, 0x80, 0x00, 0x00)
+	_, _ = conn.Write(append(reply, data...))
+}
+
+func TestIdentifyFirstMatch(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port, _, _ := jdwpListener(t)
+
+	start := time.Now()
+	// telnet waits for a banner that is never sent and would take
+	// its full timeout if losers were waited for
+	resp, err := Identify(ctx, host, port, []string{"telnet", "rdp", "jdwp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Matched || resp.Protocol != "jdwp" {
+		t.Fatalf("expected jdwp match, got %+v", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("identify waited for losing probes: %s", elapsed)
+	}
+}
+
+func TestIdentifyCancelsLosers(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port, _, disconnected := jdwpListener(t)
+
+	resp, err := Identify(ctx, host, port, []string{"telnet", "jdwp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Matched || resp.Protocol != "jdwp" {
+		t.Fatalf("expected jdwp match, got %+v", resp)
+	}
+	// the telnet probe waits for a banner and its connection is only
+	// closed before its own timeout if it was cancelled
+	deadline := time.Now().Add(2 * time.Second)
+	for disconnected.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the telnet probe to be cancelled after the jdwp match")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestIdentifyConcurrency(t *testing.T) {
+	ctx := jstest.Context(t, func(options *types.Options) { options.TemplateThreads = 1 })
+	host, port, accepted, _ := jdwpListener(t)
+
+	// probes run one at a time, so telnet is skipped after the jdwp match
+	resp, err := Identify(ctx, host, port, []string{"jdwp", "telnet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Matched || resp.Protocol != "jdwp" {
+		t.Fatalf("expected jdwp match, got %+v", resp)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := accepted.Load(); got != 1 {
+		t.Fatalf("expected only the jdwp probe to connect, got %d connections", got)
+	}
+}
+
+func TestIdentifyNoMatch(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port, _, _ := jdwpListener(t)
+
+	resp, err := Identify(ctx, host, port, []string{"rdp", "smi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Matched {
+		t.Fatalf("expected no match, got %+v", resp)
+	}
+}
+
+func TestIdentifyUnknownProbe(t *testing.T) {
+	ctx := jstest.Context(t)
+	if _, err := Identify(ctx, "127.0.0.1", 1, []string{"jdwp", "gopher"}); err == nil {
+		t.Fatal("expected error for unknown probe")
+	}
+}
+
+func TestIdentifyCancelled(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port, _, _ := jdwpListener(t)
+
+	ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	_, err := Identify(ctx, host, port, []string{"telnet"})
+	if err == nil || ctx.Err() == nil {
+		t.Fatalf("expected context error, got %v", err)
+	}
+}
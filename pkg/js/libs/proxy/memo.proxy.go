@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package proxy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsOpenHTTPProxyResponse]("proxy.isOpenHTTPProxy", 3)
+}
+
+func memoizedisOpenHTTPProxy(executionId string, host string, port int) (IsOpenHTTPProxyResponse, error) {
+	hash := "proxy.isOpenHTTPProxy" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "proxy.isOpenHTTPProxy" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsOpenHTTPProxyResponse, error) {
+			return isOpenHTTPProxy(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsOpenHTTPProxyResponse{}, err
+	}
+	if value, ok := v.(IsOpenHTTPProxyResponse); ok {
+		return value, nil
+	}
+
+	return IsOpenHTTPProxyResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// benign endpoint used as CONNECT target
+	connectTarget = "example.com:443"
+	// connectTimeout bounds each connection including the CONNECT exchange
+	connectTimeout = 5 * time.Second
+)
+
+type (
+	// IsOpenHTTPProxyResponse is the response from the IsOpenHTTPProxy function.
+	// this is returned by IsOpenHTTPProxy function.
+	// @example
+	// ```javascript
+	// const proxy = require('nuclei/proxy');
+	// const result = proxy.IsOpenHTTPProxy('acme.com', 3128);
+	// log(toJSON(result));
+	// ```
+	IsOpenHTTPProxyResponse struct {
+		// OpenProxy is true if the server established the CONNECT tunnel (200 response)
+		OpenProxy bool
+		// AuthRequired is true if the server responded with 407 Proxy Authentication Required
+		AuthRequired bool
+		// StatusCode is the status code of the CONNECT response
+		StatusCode int
+		// Server is the value of Server header (if any)
+		Server string
+		// TLS is true if the proxy is fronted by TLS
+		TLS bool
+	}
+)
+
+// IsOpenHTTPProxy checks if the given host and port are running an open HTTP forward proxy
+// by sending a CONNECT request to a benign endpoint. If the server does not speak
+// plaintext HTTP, the request is retried over TLS. No data is sent through the tunnel.
+// @example
+// ```javascript
+// const proxy = require('nuclei/proxy');
+// const result = proxy.IsOpenHTTPProxy('acme.com', 3128);
+// log(toJSON(result));
+// ```
+func IsOpenHTTPProxy(ctx context.Context, host string, port int) (IsOpenHTTPProxyResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisOpenHTTPProxy(executionId, host, port)
+}
+
+// @memo
+func isOpenHTTPProxy(executionId string, host string, port int) (IsOpenHTTPProxyResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return IsOpenHTTPProxyResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, connectTimeout)
+	if err != nil {
+		return IsOpenHTTPProxyResponse{}, err
+	}
+	resp, err := sendConnect(conn)
+	_ = conn.Close()
+	if err == nil {
+		return resp, nil
+	}
+
+	// retry with tls for tls fronted proxies
+	conn, err = protocolstate.DialWithDeadline(executionId, "tcp", address, connectTimeout)
+	if err != nil {
+		return IsOpenHTTPProxyResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10, ServerName: host})
+	if err := tlsConn.Handshake(); err != nil {
+		return IsOpenHTTPProxyResponse{}, err
+	}
+	resp, err = sendConnect(tlsConn)
+	if err != nil {
+		return IsOpenHTTPProxyResponse{}, err
+	}
+	resp.TLS = true
+	return resp, nil
+}
+
+// sendConnect sends a CONNECT request and parses the response status line and headers
+func sendConnect(conn net.Conn) (IsOpenHTTPProxyResponse, error) {
+	resp := IsOpenHTTPProxyResponse{}
+	req := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", connectTarget, connectTarget)
+	if _, err := conn.Write([]byte(req)); err != nil {
+		return resp, err
+	}
+	// CONNECT method makes ReadResponse skip the body of successful responses
+	httpResp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return resp, err
+	}
+	resp.StatusCode = httpResp.StatusCode
+	resp.Server = httpResp.Header.Get("Server")
+	resp.OpenProxy = httpResp.StatusCode == http.StatusOK
+	resp.AuthRequired = httpResp.StatusCode == http.StatusProxyAuthRequired
+	return resp, nil
+}
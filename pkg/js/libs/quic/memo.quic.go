@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package quic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsQUICResponse]("quic.isQUIC", 3)
+}
+
+func memoizedisQUIC(executionId string, host string, port int) (IsQUICResponse, error) {
+	hash := "quic.isQUIC" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "quic.isQUIC" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsQUICResponse, error) {
+			return isQUIC(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsQUICResponse{}, err
+	}
+	if value, ok := v.(IsQUICResponse); ok {
+		return value, nil
+	}
+
+	return IsQUICResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,149 @@
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	quicgo "github.com/quic-go/quic-go"
+)
+
+var (
+	// timeout of the quic and tls handshakes
+	handshakeTimeout = 5 * time.Second
+
+	// application protocols offered in the handshake, h3 first so that
+	// http/3 servers select it while other quic services can still complete
+	alpn = []string{"h3", "h3-29", "doq", "hq-interop"}
+)
+
+type (
+	// IsQUICResponse is the response from the IsQUIC function.
+	// this is returned by IsQUIC function.
+	// @example
+	// ```javascript
+	// const quic = require('nuclei/quic');
+	// const isQUIC = quic.IsQUIC('acme.com', 443);
+	// log(toJSON(isQUIC));
+	// ```
+	IsQUICResponse struct {
+		// IsQUIC is true if the endpoint answered the quic initial packet
+		// (completed handshake, connection close or version negotiation)
+		IsQUIC bool
+		// HandshakeComplete is true if the quic and tls handshakes completed
+		HandshakeComplete bool
+		// Version is the negotiated quic version (ex: v1, v2)
+		Version string
+		// ALPN is the application protocol selected by the server (ex: h3)
+		ALPN string
+		// HTTP3 is true if the server selected http/3 (h3)
+		HTTP3 bool
+		// SupportedVersions are the versions offered by the server in a version
+		// negotiation packet if none of the client versions is supported
+		SupportedVersions []string
+		// Error is the reason the handshake did not complete (if IsQUIC is true)
+		Error string
+	}
+)
+
+// IsQUIC sends a quic initial packet to the given host and port (udp, usually 443)
+// and performs the quic and tls handshakes offering the h3, h3-29, doq and
+// hq-interop application protocols. HTTP3 is set if the server selects h3.
+// Servers rejecting the handshake (ex: unsupported application protocol) are
+// still reported as quic with the reason in Error, while version negotiation
+// packets are returned in SupportedVersions. Hosts not answering within 5
+// seconds are not quic and are not reported as an error.
+// The certificate of the server is not verified.
+// @example
+// ```javascript
+// const quic = require('nuclei/quic');
+// const isQUIC = quic.IsQUIC('acme.com', 443);
+// if (isQUIC.HTTP3) { log('http/3 exposed', isQUIC.Version); }
+// ```
+func IsQUIC(ctx context.Context, host string, port int) (IsQUICResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisQUIC(executionId, host, port)
+}
+
+// @memo
+func isQUIC(executionId string, host string, port int) (IsQUICResponse, error) {
+	resp := IsQUICResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	ips, err := protocolstate.ResolveAll(executionId, host)
+	if err != nil {
+		return resp, err
+	}
+	ip := net.ParseIP(ips[0])
+	if !protocolstate.IsHostAllowed(executionId, ip.String()) {
+		// resolved address is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(ip.String())
+	}
+	laddr := "0.0.0.0:0"
+	if ip.To4() == nil {
+		laddr = "[::]:0"
+	}
+	pconn, err := protocolstate.ListenUDP(executionId, laddr, false)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = pconn.Close()
+	}()
+
+	ctx, cancel := context.WithDeadline(context.Background(), protocolstate.GetDeadline(executionId, handshakeTimeout))
+	defer cancel()
+	finish := protocolstate.StartEvent(executionId, net.JoinHostPort(host, strconv.Itoa(port)), "quic.handshake")
+	conn, err := quicgo.Dial(ctx, pconn.PacketConn(), &net.UDPAddr{IP: ip, Port: port}, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         host,
+		NextProtos:         alpn,
+		MinVersion:         tls.VersionTLS13,
+	}, &quicgo.Config{HandshakeIdleTimeout: handshakeTimeout})
+	finish(err)
+	if err != nil {
+		return handshakeError(resp, err), nil
+	}
+	defer func() {
+		_ = conn.CloseWithError(0, "")
+	}()
+
+	state := conn.ConnectionState()
+	resp.IsQUIC = true
+	resp.HandshakeComplete = true
+	resp.Version = state.Version.String()
+	resp.ALPN = state.TLS.NegotiatedProtocol
+	resp.HTTP3 = resp.ALPN == "h3"
+	return resp, nil
+}
+
+// handshakeError classifies the error of a failed quic handshake
+func handshakeError(resp IsQUICResponse, err error) IsQUICResponse {
+	var (
+		versionErr     *quicgo.VersionNegotiationError
+		transportErr   *quicgo.TransportError
+		applicationErr *quicgo.ApplicationError
+	)
+	switch {
+	case errors.As(err, &versionErr):
+		resp.IsQUIC = true
+		resp.SupportedVersions = make([]string, 0, len(versionErr.Theirs))
+		for _, version := range versionErr.Theirs {
+			resp.SupportedVersions = append(resp.SupportedVersions, version.String())
+		}
+	case errors.As(err, &transportErr) && transportErr.Remote, errors.As(err, &applicationErr) && applicationErr.Remote:
+		// the server closed the connection (ex: tls alert)
+		resp.IsQUIC = true
+	default:
+		// timeout, icmp port unreachable or local error
+		return resp
+	}
+	resp.Error = err.Error()
+	return resp
+}
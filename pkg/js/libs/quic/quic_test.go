@@ -0,0 +1,161 @@
+package quic
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	quicgo "github.com/quic-go/quic-go"
+)
+
+// quicListener serves quic connections selecting one of nextProtos
+func quicListener(t *testing.T, nextProtos ...string) int {
+	t.Helper()
+	ln, err := quicgo.ListenAddr("127.0.0.1:0", jstest.TLSConfig(t, nextProtos...), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept(context.Background())
+			if err != nil {
+				return
+			}
+			go func() {
+				<-conn.Context().Done()
+			}()
+		}
+	}()
+	return ln.Addr().(*net.UDPAddr).Port
+}
+
+// udpListener answers every datagram with the packet returned by respond
+// and returns the port of the listener
+func udpListener(t *testing.T, respond func(packet []byte) []byte) int {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buff := make([]byte, 2048)
+		for {
+			n, addr, err := conn.ReadFromUDP(buff)
+			if err != nil {
+				return
+			}
+			if response := respond(buff[:n]); response != nil {
+				_, _ = conn.WriteToUDP(response, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// versionNegotiation returns a version negotiation packet offering versions
+// in response to the long header packet of a client
+func versionNegotiation(packet []byte, versions ...uint32) []byte {
+	if len(packet) < 7 || packet[0]&0x80 == 0 {
+		return nil
+	}
+	dcidLen := int(packet[5])
+	if len(packet) < 7+dcidLen {
+		return nil
+	}
+	dcid := packet[6 : 6+dcidLen]
+	scidLen := int(packet[6+dcidLen])
+	if len(packet) < 7+dcidLen+scidLen {
+		return nil
+	}
+	scid := packet[7+dcidLen : 7+dcidLen+scidLen]
+
+	// the connection ids of the client are swapped
+	response := []byte{0xc0, 0, 0, 0, 0, byte(scidLen)}
+	response = append(response, scid...)
+	response = append(response, byte(dcidLen))
+	response = append(response, dcid...)
+	for _, version := range versions {
+		response = binary.BigEndian.AppendUint32(response, version)
+	}
+	return response
+}
+
+func TestIsQUIC(t *testing.T) {
+	ctx := jstest.Context(t)
+	port := quicListener(t, "h3")
+
+	resp, err := IsQUIC(ctx, "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsQUIC || !resp.HandshakeComplete || !resp.HTTP3 || resp.ALPN != "h3" || resp.Version != "v1" {
+		t.Fatalf("expected completed http/3 handshake, got %+v", resp)
+	}
+}
+
+func TestIsQUICOtherALPN(t *testing.T) {
+	ctx := jstest.Context(t)
+	port := quicListener(t, "doq")
+
+	resp, err := IsQUIC(ctx, "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsQUIC || !resp.HandshakeComplete || resp.HTTP3 || resp.ALPN != "doq" {
+		t.Fatalf("expected completed doq handshake, got %+v", resp)
+	}
+}
+
+func TestIsQUICRejectedALPN(t *testing.T) {
+	ctx := jstest.Context(t)
+	port := quicListener(t, "smb")
+
+	resp, err := IsQUIC(ctx, "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsQUIC || resp.HandshakeComplete || resp.Error == "" {
+		t.Fatalf("expected quic endpoint rejecting the handshake, got %+v", resp)
+	}
+}
+
+func TestIsQUICVersionNegotiation(t *testing.T) {
+	ctx := jstest.Context(t)
+	port := udpListener(t, func(packet []byte) []byte {
+		return versionNegotiation(packet, 0xff00001d, 0x1a2a3a4a)
+	})
+
+	resp, err := IsQUIC(ctx, "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsQUIC || resp.HandshakeComplete {
+		t.Fatalf("expected version negotiation, got %+v", resp)
+	}
+	if want := []string{"draft-29", "0x1a2a3a4a"}; !reflect.DeepEqual(resp.SupportedVersions, want) {
+		t.Fatalf("expected supported versions %v, got %v", want, resp.SupportedVersions)
+	}
+}
+
+func TestIsQUICNoResponse(t *testing.T) {
+	ctx := jstest.Context(t)
+	port := udpListener(t, func([]byte) []byte { return nil })
+
+	timeout := handshakeTimeout
+	handshakeTimeout = 500 * time.Millisecond
+	t.Cleanup(func() { handshakeTimeout = timeout })
+
+	resp, err := IsQUIC(ctx, "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.IsQUIC || resp.HandshakeComplete {
+		t.Fatalf("expected no quic endpoint, got %+v", resp)
+	}
+}
@@ -0,0 +1,34 @@
+// Warning - This is generated code
+package radius
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[CheckAuthResponse]("radius.checkAuth", 6)
+}
+
+func memoizedcheckAuth(executionId string, host string, port int, secret string, username string, password string) (CheckAuthResponse, error) {
+	hash := "radius.checkAuth" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(secret) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "radius.checkAuth" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(secret) + ":" + fmt.Sprint(username) + ":" + fmt.Sprint(password)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (CheckAuthResponse, error) {
+			return checkAuth(executionId, host, port, secret, username, password)
+		})
+	})
+	if err != nil {
+		return CheckAuthResponse{}, err
+	}
+	if value, ok := v.(CheckAuthResponse); ok {
+		return value, nil
+	}
+
+	return CheckAuthResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,216 @@
+package radius
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	codeAccessRequest   = 1
+	codeAccessAccept    = 2
+	codeAccessReject    = 3
+	codeAccessChallenge = 11
+
+	attrUserName             = 1
+	attrUserPassword         = 2
+	attrReplyMessage         = 18
+	attrMessageAuthenticator = 80
+
+	// timeout to wait for a response to the access request
+	requestTimeout = 5 * time.Second
+)
+
+var (
+	errNoResponse       = errors.New("no radius response received (wrong shared secret or not a radius server)")
+	errInvalidResponse  = errors.New("invalid radius response")
+	errAuthenticatorBad = errors.New("invalid response authenticator (wrong shared secret)")
+)
+
+type (
+	// CheckAuthResponse is the response from the CheckAuth function.
+	// this is returned by CheckAuth function.
+	// @example
+	// ```javascript
+	// const radius = require('nuclei/radius');
+	// const result = radius.CheckAuth('acme.com', 1812, 'testing123', 'admin', 'admin');
+	// log(toJSON(result));
+	// ```
+	CheckAuthResponse struct {
+		// Accepted is true if server responded with Access-Accept
+		Accepted bool
+		// Rejected is true if server responded with Access-Reject
+		Rejected bool
+		// Challenge is true if server responded with Access-Challenge
+		Challenge bool
+		// Code is the radius code of the response
+		Code int
+		// ReplyMessage is the Reply-Message attribute of the response (if any)
+		ReplyMessage string
+	}
+)
+
+// CheckAuth sends a RADIUS Access-Request with PAP authentication using given
+// shared secret, username and password and reports whether the server accepted
+// or rejected the credentials. Since servers silently drop requests with a wrong
+// shared secret, a missing or garbled response is returned as an error.
+// @example
+// ```javascript
+// const radius = require('nuclei/radius');
+// const result = radius.CheckAuth('acme.com', 1812, 'testing123', 'admin', 'admin');
+// log(result.Accepted);
+// ```
+func CheckAuth(ctx context.Context, host string, port int, secret string, username string, password string) (CheckAuthResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedcheckAuth(executionId, host, port, secret, username, password)
+}
+
+// @memo
+func checkAuth(executionId string, host string, port int, secret string, username string, password string) (CheckAuthResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return CheckAuthResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "udp", net.JoinHostPort(host, strconv.Itoa(port)), requestTimeout)
+	if err != nil {
+		return CheckAuthResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	request, authenticator, err := buildAccessRequest(executionId, secret, username, password)
+	if err != nil {
+		return CheckAuthResponse{}, err
+	}
+	if _, err := conn.Write(request); err != nil {
+		return CheckAuthResponse{}, err
+	}
+
+	buff := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buff)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return CheckAuthResponse{}, errNoResponse
+			}
+			return CheckAuthResponse{}, err
+		}
+		// ignore stray datagrams not matching our identifier
+		if n >= 20 && buff[1] != request[1] {
+			continue
+		}
+		return parseResponse(buff[:n], authenticator, secret)
+	}
+}
+
+// buildAccessRequest builds an Access-Request packet with PAP User-Password
+// and Message-Authenticator attributes and returns it with request authenticator.
+// identifier and authenticator are seeded by the execution probe seed (if any)
+func buildAccessRequest(executionId, secret, username, password string) ([]byte, []byte, error) {
+	header := make([]byte, 20)
+	protocolstate.RandomBytes(executionId, header[1:20])
+	header[0] = codeAccessRequest
+	authenticator := header[4:20]
+
+	packet := header
+	packet = appendAttribute(packet, attrUserName, []byte(username))
+	packet = appendAttribute(packet, attrUserPassword, encryptPassword(password, secret, authenticator))
+	// message authenticator is computed with the value zeroed
+	packet = appendAttribute(packet, attrMessageAuthenticator, make([]byte, md5.Size))
+	if len(packet) > 4096 {
+		return nil, nil, errors.New("radius request too large")
+	}
+	binary.BigEndian.PutUint16(packet[2:4], uint16(len(packet)))
+
+	mac := hmac.New(md5.New, []byte(secret))
+	mac.Write(packet)
+	copy(packet[len(packet)-md5.Size:], mac.Sum(nil))
+	return packet, authenticator, nil
+}
+
+// appendAttribute appends a radius attribute to packet
+func appendAttribute(packet []byte, attrType byte, value []byte) []byte {
+	if len(value) > 253 {
+		value = value[:253]
+	}
+	packet = append(packet, attrType, byte(len(value)+2))
+	return append(packet, value...)
+}
+
+// encryptPassword hides the password as described in RFC 2865 5.2
+func encryptPassword(password, secret string, authenticator []byte) []byte {
+	padded := []byte(password)
+	if len(padded) > 128 {
+		padded = padded[:128]
+	}
+	if len(padded) == 0 || len(padded)%16 != 0 {
+		padded = append(padded, make([]byte, 16-len(padded)%16)...)
+	}
+	result := make([]byte, len(padded))
+	prev := authenticator
+	for i := 0; i < len(padded); i += 16 {
+		hash := md5.Sum(append([]byte(secret), prev...))
+		for j := 0; j < 16; j++ {
+			result[i+j] = padded[i+j] ^ hash[j]
+		}
+		prev = result[i : i+16]
+	}
+	return result
+}
+
+// parseResponse validates response authenticator and parses the response
+func parseResponse(data []byte, requestAuthenticator []byte, secret string) (CheckAuthResponse, error) {
+	resp := CheckAuthResponse{}
+	if len(data) < 20 {
+		return resp, errInvalidResponse
+	}
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+	if length < 20 || length > len(data) {
+		return resp, errInvalidResponse
+	}
+	data = data[:length]
+
+	// ResponseAuth = MD5(Code+ID+Length+RequestAuth+Attributes+Secret)
+	hash := md5.New()
+	hash.Write(data[:4])
+	hash.Write(requestAuthenticator)
+	hash.Write(data[20:])
+	hash.Write([]byte(secret))
+	if !hmac.Equal(hash.Sum(nil), data[4:20]) {
+		return resp, errAuthenticatorBad
+	}
+
+	resp.Code = int(data[0])
+	switch data[0] {
+	case codeAccessAccept:
+		resp.Accepted = true
+	case codeAccessReject:
+		resp.Rejected = true
+	case codeAccessChallenge:
+		resp.Challenge = true
+	default:
+		return resp, fmt.Errorf("unexpected radius code %d", data[0])
+	}
+
+	attributes := data[20:]
+	for len(attributes) >= 2 {
+		attrLength := int(attributes[1])
+		if attrLength < 2 || attrLength > len(attributes) {
+			break
+		}
+		if attributes[0] == attrReplyMessage {
+			resp.ReplyMessage += string(attributes[2:attrLength])
+		}
+		attributes = attributes[attrLength:]
+	}
+	return resp, nil
+}
@@ -2,17 +2,27 @@
 package rdp
 
 import (
+	"context"
 	"errors"
+
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisRDP(executionId string, host string, port int) (IsRDPResponse, error) {
-	hash := "isRDP" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[IsRDPResponse]("rdp.isRDP", 6)
+}
+
+func memoizedisRDP(ctx context.Context, executionId string, host string, port int, dialOpts protocolstate.DialOptions, captureRaw bool, negotiateTLS bool) (IsRDPResponse, error) {
+	hash := "rdp.isRDP" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(dialOpts) + ":" + fmt.Sprint(captureRaw) + ":" + fmt.Sprint(negotiateTLS)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "rdp.isRDP" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(dialOpts) + ":" + fmt.Sprint(captureRaw) + ":" + fmt.Sprint(negotiateTLS)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isRDP(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsRDPResponse, error) {
+			return isRDP(ctx, executionId, host, port, dialOpts, captureRaw, negotiateTLS)
+		})
 	})
 	if err != nil {
 		return IsRDPResponse{}, err
@@ -24,11 +34,19 @@ func memoizedisRDP(executionId string, host string, port int) (IsRDPResponse, er
 	return IsRDPResponse{}, errors.New("could not convert cached result")
 }
 
-func memoizedcheckRDPAuth(executionId string, host string, port int) (CheckRDPAuthResponse, error) {
-	hash := "checkRDPAuth" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[CheckRDPAuthResponse]("rdp.checkRDPAuth", 5)
+}
+
+func memoizedcheckRDPAuth(executionId string, host string, port int, dialOpts protocolstate.DialOptions, negotiateTLS bool) (CheckRDPAuthResponse, error) {
+	hash := "rdp.checkRDPAuth" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(dialOpts) + ":" + fmt.Sprint(negotiateTLS)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "rdp.checkRDPAuth" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(dialOpts) + ":" + fmt.Sprint(negotiateTLS)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return checkRDPAuth(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (CheckRDPAuthResponse, error) {
+			return checkRDPAuth(executionId, host, port, dialOpts, negotiateTLS)
+		})
 	})
 	if err != nil {
 		return CheckRDPAuthResponse{}, err
@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package rdp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[RDWebVersionResponse]("rdp.getRDWebVersion", 4)
+}
+
+func memoizedgetRDWebVersion(executionId string, host string, port int, path string) (RDWebVersionResponse, error) {
+	hash := "rdp.getRDWebVersion" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "rdp.getRDWebVersion" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (RDWebVersionResponse, error) {
+			return getRDWebVersion(executionId, host, port, path)
+		})
+	})
+	if err != nil {
+		return RDWebVersionResponse{}, err
+	}
+	if value, ok := v.(RDWebVersionResponse); ok {
+		return value, nil
+	}
+
+	return RDWebVersionResponse{}, errors.New("could not convert cached result")
+}
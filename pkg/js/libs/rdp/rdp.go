@@ -2,11 +2,14 @@ package rdp
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
-	"github.com/praetorian-inc/fingerprintx/pkg/plugins/services/rdp"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
@@ -22,6 +25,79 @@ type (
 	IsRDPResponse struct {
 		IsRDP bool
 		OS    string
+		// ResolvedIP is the ip address the host resolved to when probed
+		ResolvedIP string
+		// PortOpen is true if the tcp connection succeeded (even if the service is not rdp)
+		PortOpen bool
+		// Confidence (0-100) reflects how strongly the handshake matched rdp:
+		//  - 100: rdp signature matched and the os was identified
+		//  - 80: rdp signature matched with unknown negotiation flags
+		//  - 50: connection confirm with rdp negotiation data but no rdp signature (IsRDP is false)
+		//  - 25: bare x.224 connection confirm, also used by other iso-tsap services (IsRDP is false)
+		//  - 0: not rdp or malformed response
+		Confidence int
+		// RawExchange contains the bytes exchanged during detection (if CaptureRaw is set)
+		RawExchange *RawExchange `json:",omitempty"`
+		// TLSVersion is the tls version negotiated after the connection confirm (if NegotiateTLS
+		// is set and the server selected tls security, ex: TLS 1.2)
+		TLSVersion string
+		// CipherSuite is the tls cipher suite chosen by the server (if NegotiateTLS is set and
+		// the server selected tls security, ex: TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384)
+		CipherSuite string
+	}
+
+	// RawExchange contains the raw bytes sent and received during a detection.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// const isRDP = rdp.IsRDP('acme.com', 3389, { CaptureRaw: true });
+	// log(isRDP.RawExchange.Received);
+	// ```
+	RawExchange struct {
+		// Sent is the hex encoded data written to the server
+		Sent string
+		// Received is the hex encoded data read from the server
+		Received string
+		// Truncated is true if the exchange exceeded the capture limit (64KB per direction)
+		Truncated bool
+	}
+
+	// IsRDPOptions contains options for IsRDP function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// const isRDP = rdp.IsRDP('acme.com', 3389, { NoCache: true });
+	// ```
+	IsRDPOptions struct {
+		// NoCache performs a fresh probe instead of returning the memoized
+		// result of the execution. the memoized result is left intact.
+		NoCache bool
+		// KeepAlive enables tcp keep-alive probes with the given interval in seconds.
+		// a negative value disables keep-alive
+		KeepAlive int
+		// NoDelay sets TCP_NODELAY on the connection (enabled by default)
+		NoDelay *bool
+		// IP is dialed instead of resolving host (ex: resolved by a previous dns step)
+		IP string
+		// Interface binds the connection to the address of the given network
+		// interface (ex: eth1) on multi-homed scanners
+		Interface string
+		// ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+		// before the rdp data (ex: backends behind load balancers)
+		ProxyProtocol int
+		// ProxySource is the client address (ip:port) announced in the PROXY header
+		// (default: local address of the connection)
+		ProxySource string
+		// ProxyDestination is the server address (ip:port) announced in the PROXY header
+		// (default: remote address of the connection)
+		ProxyDestination string
+		// CaptureRaw returns the raw bytes exchanged during detection in RawExchange
+		// for debugging (disabled by default)
+		CaptureRaw bool
+		// NegotiateTLS upgrades the connection to tls when selected by the server and
+		// returns the negotiated TLSVersion and CipherSuite (disabled by default).
+		// legacy rdp security leaves them empty.
+		NegotiateTLS bool
 	}
 )
 
@@ -29,45 +105,216 @@ type (
 // If connection is successful, it returns true.
 // If connection is unsuccessful, it returns false and error.
 // The Name of the OS is also returned if the connection is successful.
+// PortOpen is true whenever the tcp connection succeeded, even if the
+// service could not be identified as rdp.
+// Detection failures after the connection succeeded (ex: timeouts, truncated
+// or invalid negotiation responses) return IsRDP false without an error.
+// Confidence can be used to accept partial matches (ex: non windows servers).
+// CaptureRaw can be used to inspect the handshake of unexpected responders.
+// NegotiateTLS returns the tls version and cipher suite chosen by the server.
 // @example
 // ```javascript
 // const rdp = require('nuclei/rdp');
 // const isRDP = rdp.IsRDP('acme.com', 3389);
-// log(toJSON(isRDP));
+// if (isRDP.Confidence >= 50) { log(toJSON(isRDP)); }
 // ```
-func IsRDP(ctx context.Context, host string, port int) (IsRDPResponse, error) {
+func IsRDP(ctx context.Context, host string, port int, opts IsRDPOptions) (IsRDPResponse, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedisRDP(executionId, host, port)
+	return isRDPWithOptions(ctx, executionId, host, port, opts)
 }
 
-// @memo
-func isRDP(executionId string, host string, port int) (IsRDPResponse, error) {
-	resp := IsRDPResponse{}
-
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return IsRDPResponse{}, fmt.Errorf("dialers not initialized for %s", executionId)
+// isRDPWithOptions probes host honoring the memoization options
+func isRDPWithOptions(ctx context.Context, executionId string, host string, port int, opts IsRDPOptions) (IsRDPResponse, error) {
+	dialOpts := dialOptions(opts.KeepAlive, opts.NoDelay, opts.IP, opts.Interface, proxyProtocol(opts.ProxyProtocol, opts.ProxySource, opts.ProxyDestination))
+	if opts.NoCache {
+		// bypass memoization without touching cached result
+		return isRDP(ctx, executionId, host, port, dialOpts, opts.CaptureRaw, opts.NegotiateTLS)
 	}
+	return memoizedisRDP(ctx, executionId, host, port, dialOpts, opts.CaptureRaw, opts.NegotiateTLS)
+}
 
+// @memo
+func isRDP(ctx context.Context, executionId string, host string, port int, dialOpts protocolstate.DialOptions, captureRaw bool, negotiateTLS bool) (IsRDPResponse, error) {
+	resp := IsRDPResponse{}
 	timeout := 5 * time.Second
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
+	address := fmt.Sprintf("%s:%d", host, port)
+	conn, err := protocolstate.DialContextWithOptions(ctx, executionId, "tcp", address, timeout, dialOpts)
 	if err != nil {
 		return resp, err
 	}
 	defer func() {
 		_ = conn.Close()
 	}()
+	finish := protocolstate.StartEvent(executionId, address, "rdp.detect")
 
-	server, isRDP, err := rdp.DetectRDP(conn, timeout)
-	if err != nil {
-		return resp, err
+	var tee *utils.TeeConn
+	if captureRaw {
+		tee = utils.NewTeeConn(conn, 0)
+		conn = tee
+	}
+	// a hostile response must not crash the scan if it trips fingerprintx
+	resp, err = utils.WithRecover(ErrMalformedResponse, func() (IsRDPResponse, error) {
+		return detectRDP(utils.LimitConn(executionId, conn), time.Until(protocolstate.GetDeadline(executionId, timeout)), negotiateTLS)
+	})
+	finish(err)
+	if tee != nil {
+		resp.RawExchange = newRawExchange(tee)
 	}
-	if !isRDP {
+	resp.PortOpen = true
+	resp.ResolvedIP = protocolstate.ResolvedIP(conn)
+	if err != nil && ctx.Err() == nil {
+		// the port is open, failed detections are reported as not rdp
+		// (cancelled probes keep their error so that they are not cached)
 		return resp, nil
 	}
-	resp.IsRDP = true
-	resp.OS = server
-	return resp, nil
+	return resp, err
+}
+
+// newRawExchange returns the exchange recorded by tee
+func newRawExchange(tee *utils.TeeConn) *RawExchange {
+	return &RawExchange{
+		Sent:      hex.EncodeToString(tee.Sent()),
+		Received:  hex.EncodeToString(tee.Received()),
+		Truncated: tee.Truncated(),
+	}
+}
+
+type (
+	// IsRDPStreamOptions contains options for IsRDPStream function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// rdp.IsRDPStream(['acme.com', 'acme.org'], 3389, (result) => log(result.Host), { Concurrency: 5 });
+	// ```
+	IsRDPStreamOptions struct {
+		// Concurrency is the number of hosts probed concurrently (default: 10, max: 100)
+		Concurrency int
+		// Options are the options used to probe every host
+		Options IsRDPOptions
+	}
+
+	// IsRDPStreamResult is the result of a host probed by IsRDPStream.
+	// this is passed to the callback of IsRDPStream function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// rdp.IsRDPStream(['acme.com'], 3389, (result) => log(toJSON(result)));
+	// ```
+	IsRDPStreamResult struct {
+		// Host is the probed host
+		Host string
+		// Port is the probed port
+		Port int
+		// Response is the result of IsRDP for the host
+		Response IsRDPResponse
+		// Error is the error of the probe (if any)
+		Error string
+	}
+)
+
+// IsRDPStream checks if the given hosts are running rdp server and invokes
+// callback with the result of each host as soon as its probe completes
+// instead of buffering all the results. Hosts can be given as host:port to
+// override port. Concurrency bounds the number of running probes and the
+// callback is never invoked concurrently. An exception thrown by the
+// callback stops the probing of remaining hosts.
+// @example
+// ```javascript
+// const rdp = require('nuclei/rdp');
+// rdp.IsRDPStream(['acme.com', 'acme.org:3390'], 3389, (result) => {
+//   if (result.Response.IsRDP) { log(result.Host, result.Response.OS); }
+// }, { Concurrency: 20 });
+// ```
+func IsRDPStream(ctx context.Context, hosts []string, port int, callback func(IsRDPStreamResult) error, opts IsRDPStreamOptions) error {
+	executionId := ctx.Value("executionId").(string)
+	return utils.Stream(hosts, opts.Concurrency, func(host string) IsRDPStreamResult {
+		result := IsRDPStreamResult{Host: host, Port: port}
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			if n, err := strconv.Atoi(p); err == nil {
+				result.Host, result.Port = h, n
+			}
+		}
+		resp, err := isRDPWithOptions(ctx, executionId, result.Host, result.Port, opts.Options)
+		result.Response = resp
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	}, callback)
+}
+
+type (
+	// IsRDPAllAddrsOptions contains options for IsRDPAllAddrs function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// const results = rdp.IsRDPAllAddrs('acme.com', 3389, { Concurrency: 5 });
+	// ```
+	IsRDPAllAddrsOptions struct {
+		// Concurrency is the number of addresses probed concurrently (default: 10, max: 100)
+		Concurrency int
+		// Options are the options used to probe every address (IP is ignored)
+		Options IsRDPOptions
+	}
+
+	// IsRDPAddrResult is the result of an address probed by IsRDPAllAddrs.
+	// this is returned by IsRDPAllAddrs function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// const results = rdp.IsRDPAllAddrs('acme.com', 3389);
+	// log(toJSON(results));
+	// ```
+	IsRDPAddrResult struct {
+		// IP is the probed address of the host
+		IP string
+		// Response is the result of IsRDP for the address
+		Response IsRDPResponse
+		// Error is the error of the probe (if any)
+		Error string
+	}
+)
+
+// IsRDPAllAddrs resolves every A and AAAA record of host and checks if each
+// address is running rdp server, returning the result of every address in
+// the order of the records (ex: all backends of a round-robin or cdn
+// hostname). A failing address does not stop the probing of the others,
+// only resolution errors are returned.
+// @example
+// ```javascript
+// const rdp = require('nuclei/rdp');
+// const results = rdp.IsRDPAllAddrs('acme.com', 3389);
+// const confirmed = results.every((result) => result.Response.IsRDP);
+// ```
+func IsRDPAllAddrs(ctx context.Context, host string, port int, opts IsRDPAllAddrsOptions) ([]IsRDPAddrResult, error) {
+	executionId := ctx.Value("executionId").(string)
+	return utils.ProbeAllAddrs(executionId, host, opts.Concurrency, func(ip string) IsRDPAddrResult {
+		result := IsRDPAddrResult{IP: ip}
+		options := opts.Options
+		options.IP = ip
+		resp, err := isRDPWithOptions(ctx, executionId, host, port, options)
+		result.Response = resp
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	})
+}
+
+// dialOptions returns the tcp options of the rdp connection
+func dialOptions(keepAlive int, noDelay *bool, ip string, iface string, proxy protocolstate.ProxyProtocol) protocolstate.DialOptions {
+	return protocolstate.DialOptions{
+		KeepAlive:     time.Duration(keepAlive) * time.Second,
+		NoDelay:       noDelay,
+		IP:            ip,
+		Interface:     iface,
+		ProxyProtocol: proxy,
+	}
+}
+
+// proxyProtocol returns the PROXY header sent before the rdp data
+func proxyProtocol(version int, source string, destination string) protocolstate.ProxyProtocol {
+	return protocolstate.ProxyProtocol{Version: version, Source: source, Destination: destination}
 }
 
 type (
@@ -80,50 +327,129 @@ type (
 	// log(toJSON(checkRDPAuth));
 	// ```
 	CheckRDPAuthResponse struct {
-		PluginInfo *plugins.ServiceRDP
+		PluginInfo *ServerInfo
 		Auth       bool
+		// ResolvedIP is the ip address the host resolved to when probed
+		ResolvedIP string
+		// TLSVersion is the tls version negotiated before credssp (if NegotiateTLS is set)
+		TLSVersion string
+		// CipherSuite is the tls cipher suite chosen by the server (if NegotiateTLS is set)
+		CipherSuite string
+	}
+
+	// CheckRDPAuthOptions contains options for CheckRDPAuth function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// const checkRDPAuth = rdp.CheckRDPAuth('acme.com', 3389, { KeepAlive: 10 });
+	// ```
+	CheckRDPAuthOptions struct {
+		// KeepAlive enables tcp keep-alive probes with the given interval in seconds.
+		// a negative value disables keep-alive
+		KeepAlive int
+		// NoDelay sets TCP_NODELAY on the connection (enabled by default)
+		NoDelay *bool
+		// IP is dialed instead of resolving host (ex: resolved by a previous dns step)
+		IP string
+		// Interface binds the connection to the address of the given network
+		// interface (ex: eth1) on multi-homed scanners
+		Interface string
+		// ProxyProtocol sends a haproxy PROXY header of the given version (1 or 2)
+		// before the rdp data (ex: backends behind load balancers)
+		ProxyProtocol int
+		// ProxySource is the client address (ip:port) announced in the PROXY header
+		// (default: local address of the connection)
+		ProxySource string
+		// ProxyDestination is the server address (ip:port) announced in the PROXY header
+		// (default: remote address of the connection)
+		ProxyDestination string
+		// NegotiateTLS sends a x.224 connection request and upgrades the connection
+		// to tls before credssp, as done by rdp clients, and returns the negotiated
+		// TLSVersion and CipherSuite. servers selecting legacy rdp security return
+		// no auth info (disabled by default).
+		NegotiateTLS bool
+	}
+
+	// ServerInfo contains the metadata of a rdp server extracted from
+	// the ntlm challenge. field names are part of the template contract and
+	// do not depend on the underlying fingerprinting library.
+	// this is returned by CheckRDPAuth function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// const checkRDPAuth = rdp.CheckRDPAuth('acme.com', 3389);
+	// log(checkRDPAuth.PluginInfo.DNSDomainName);
+	// ```
+	ServerInfo struct {
+		// OSFingerprint is the os guessed from the os version (ex: Windows Server 2016 or 2019)
+		OSFingerprint string `json:"fingerprint,omitempty"`
+		// OSVersion is the os version with build number (ex: 10.0.17763)
+		OSVersion string `json:"osVersion,omitempty"`
+		// TargetName is the authentication realm (usually the domain name)
+		TargetName string `json:"targetName,omitempty"`
+		// NetBIOSComputerName is the netbios name of the server
+		NetBIOSComputerName string `json:"netBIOSComputerName,omitempty"`
+		// NetBIOSDomainName is the netbios name of the domain
+		NetBIOSDomainName string `json:"netBIOSDomainName,omitempty"`
+		// DNSComputerName is the fqdn of the server
+		DNSComputerName string `json:"dnsComputerName,omitempty"`
+		// DNSDomainName is the fqdn of the domain
+		DNSDomainName string `json:"dnsDomainName,omitempty"`
+		// ForestName is the fqdn of the forest
+		ForestName string `json:"forestName,omitempty"`
 	}
 )
 
+// newServerInfo converts the fingerprintx rdp metadata to ServerInfo
+func newServerInfo(info *plugins.ServiceRDP) *ServerInfo {
+	if info == nil {
+		return nil
+	}
+	return &ServerInfo{
+		OSFingerprint:       info.OSFingerprint,
+		OSVersion:           info.OSVersion,
+		TargetName:          info.TargetName,
+		NetBIOSComputerName: info.NetBIOSComputerName,
+		NetBIOSDomainName:   info.NetBIOSDomainName,
+		DNSComputerName:     info.DNSComputerName,
+		DNSDomainName:       info.DNSDomainName,
+		ForestName:          info.ForestName,
+	}
+}
+
 // CheckRDPAuth checks if the given host and port are running rdp server
 // with authentication and returns their metadata.
 // If connection is successful, it returns true.
+// Truncated or invalid ntlm challenges return ErrMalformedResponse.
 // @example
 // ```javascript
 // const rdp = require('nuclei/rdp');
 // const checkRDPAuth = rdp.CheckRDPAuth('acme.com', 3389);
 // log(toJSON(checkRDPAuth));
 // ```
-func CheckRDPAuth(ctx context.Context, host string, port int) (CheckRDPAuthResponse, error) {
+func CheckRDPAuth(ctx context.Context, host string, port int, opts CheckRDPAuthOptions) (CheckRDPAuthResponse, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedcheckRDPAuth(executionId, host, port)
+	return memoizedcheckRDPAuth(executionId, host, port, dialOptions(opts.KeepAlive, opts.NoDelay, opts.IP, opts.Interface, proxyProtocol(opts.ProxyProtocol, opts.ProxySource, opts.ProxyDestination)), opts.NegotiateTLS)
 }
 
 // @memo
-func checkRDPAuth(executionId string, host string, port int) (CheckRDPAuthResponse, error) {
+func checkRDPAuth(executionId string, host string, port int, dialOpts protocolstate.DialOptions, negotiateTLS bool) (CheckRDPAuthResponse, error) {
 	resp := CheckRDPAuthResponse{}
-
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return CheckRDPAuthResponse{}, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
 	timeout := 5 * time.Second
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
+	address := fmt.Sprintf("%s:%d", host, port)
+	conn, err := protocolstate.DialWithOptions(executionId, "tcp", address, timeout, dialOpts)
 	if err != nil {
 		return resp, err
 	}
 	defer func() {
 		_ = conn.Close()
 	}()
+	finish := protocolstate.StartEvent(executionId, address, "rdp.auth")
 
-	pluginInfo, auth, err := rdp.DetectRDPAuth(conn, timeout)
-	if err != nil {
-		return resp, err
-	}
-	if !auth {
-		return resp, nil
-	}
-	resp.Auth = true
-	resp.PluginInfo = pluginInfo
-	return resp, nil
+	resp, err = utils.WithRecover(ErrMalformedResponse, func() (CheckRDPAuthResponse, error) {
+		return detectRDPAuth(utils.LimitConn(executionId, conn), time.Until(protocolstate.GetDeadline(executionId, timeout)), negotiateTLS)
+	})
+	finish(err)
+	resp.ResolvedIP = protocolstate.ResolvedIP(conn)
+	return resp, err
 }
@@ -0,0 +1,377 @@
+package rdp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/praetorian-inc/fingerprintx/pkg/plugins/services/rdp"
+)
+
+var (
+	// ErrMalformedResponse is returned when the server responds with a truncated
+	// or invalid rdp pdu (ex: honeypots or ids sending crafted negotiation responses)
+	ErrMalformedResponse = errors.New("malformed rdp response")
+)
+
+const (
+	// maximum size of a pdu read during detection
+	maxPDUSize = 16 * 1024
+	// tpkt header (4) + minimal x.224 connection confirm (7)
+	minConnectionConfirmSize = 11
+	// offset and size of the rdp negotiation data following the connection confirm
+	negotiationOffset = minConnectionConfirmSize
+	negotiationSize   = 8
+	// rdp negotiation response and failure types
+	typeNegotiationResponse = 0x02
+	typeNegotiationFailure  = 0x03
+	// security protocols selected in the rdp negotiation response upgrading
+	// the connection to tls (0x00 is the legacy rdp security)
+	protocolSSL      = 0x01
+	protocolHybrid   = 0x02
+	protocolHybridEx = 0x08
+)
+
+// detection confidence of IsRDP (see IsRDPResponse.Confidence)
+const (
+	// rdp signature matched and the os was identified
+	confidenceFull = 100
+	// rdp signature matched with unknown negotiation flags
+	confidenceSignature = 80
+	// connection confirm carrying rdp negotiation data without matching the rdp signature
+	confidenceNegotiation = 50
+	// bare connection confirm (shared with other iso-tsap services)
+	confidenceConnectionConfirm = 25
+)
+
+var (
+	// x.224 connection request with rdp negotiation request (same as fingerprintx)
+	connectionRequest = []byte{
+		0x03, 0x00, 0x00, 0x13, 0x0e, 0xe0, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x08, 0x00, 0x0b,
+		0x00, 0x00, 0x00,
+	}
+	// credssp TSRequest containing a ntlm negotiate message (same as fingerprintx)
+	negotiateRequest = []byte{
+		0x30, 0x37, 0xA0, 0x03, 0x02, 0x01, 0x60, 0xA1, 0x30, 0x30, 0x2E, 0x30, 0x2C, 0xA0, 0x2A, 0x04, 0x28,
+		'N', 'T', 'L', 'M', 'S', 'S', 'P', 0x00,
+		0x01, 0x00, 0x00, 0x00,
+		0xF7, 0xBA, 0xDB, 0xE2,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	ntlmSignature = []byte("NTLMSSP\x00")
+
+	// fingerprintx detection functions (replaceable in tests)
+	fingerprintRDP     = rdp.DetectRDP
+	fingerprintRDPAuth = rdp.DetectRDPAuth
+)
+
+// malformed returns an ErrMalformedResponse with given reason
+func malformed(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrMalformedResponse, fmt.Sprintf(format, args...))
+}
+
+// isClosedOrSilent returns true if no data could be read due to
+// the connection being closed or the deadline being reached
+func isClosedOrSilent(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// detectRDP sends a x.224 connection request and validates the connection
+// confirm before fingerprinting it. If upgradeTLS is set and the server selected
+// tls based security, the negotiated tls version and cipher suite are returned.
+// The read deadline of conn is bounded by timeout.
+func detectRDP(conn net.Conn, timeout time.Duration, upgradeTLS bool) (IsRDPResponse, error) {
+	resp := IsRDPResponse{}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return resp, err
+	}
+	pdu, err := requestConnection(conn)
+	if err != nil || pdu == nil {
+		return resp, err
+	}
+
+	// fingerprintx only operates over a connection so the validated pdu is
+	// replayed to reuse its rdp and os signatures
+	server, _, err := fingerprintRDP(newReplayConn(conn, pdu), timeout)
+	if err != nil {
+		// valid connection confirm not matching rdp signature (ex: other iso-tsap services)
+		resp.Confidence = confirmConfidence(pdu)
+		return resp, nil
+	}
+	resp.IsRDP = true
+	resp.OS = server
+	resp.Confidence = confidenceSignature
+	if server != "" {
+		resp.Confidence = confidenceFull
+	}
+	if upgradeTLS && selectsTLS(pdu) {
+		// a failed upgrade does not invalidate the detection
+		if tlsConn, err := startTLS(conn); err == nil {
+			resp.TLSVersion, resp.CipherSuite = tlsInfo(tlsConn)
+		}
+	}
+	return resp, nil
+}
+
+// requestConnection sends a x.224 connection request and returns the
+// validated connection confirm. nil pdu is returned for non tpkt responders
+func requestConnection(conn net.Conn) ([]byte, error) {
+	if _, err := conn.Write(connectionRequest); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 4)
+	n, err := io.ReadFull(conn, header)
+	if err != nil {
+		if n == 0 && isClosedOrSilent(err) {
+			// closed or silent responder is not rdp
+			return nil, nil
+		}
+		if n > 0 && header[0] == 0x03 {
+			return nil, malformed("truncated tpkt header (%d bytes)", n)
+		}
+		if n > 0 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if header[0] != 0x03 {
+		// not a tpkt pdu
+		return nil, nil
+	}
+	length := int(binary.BigEndian.Uint16(header[2:4]))
+	if header[1] != 0x00 || length < minConnectionConfirmSize || length > maxPDUSize {
+		return nil, malformed("invalid tpkt length %d", length)
+	}
+	pdu := make([]byte, length)
+	copy(pdu, header)
+	if n, err := io.ReadFull(conn, pdu[4:]); err != nil {
+		return nil, malformed("truncated pdu (%d of %d bytes): %v", 4+n, length, err)
+	}
+	// x.224 length indicator and connection confirm code
+	if li := int(pdu[4]); li < 6 || 5+li > length {
+		return nil, malformed("invalid x.224 length indicator %d", li)
+	}
+	if pdu[5]&0xf0 != 0xd0 {
+		return nil, malformed("unexpected x.224 tpdu code 0x%02x", pdu[5])
+	}
+	return pdu, nil
+}
+
+// selectsTLS returns true if the negotiation response of the connection
+// confirm selected a tls based security protocol
+func selectsTLS(pdu []byte) bool {
+	if confirmConfidence(pdu) != confidenceNegotiation || pdu[negotiationOffset] != typeNegotiationResponse {
+		return false
+	}
+	selected := binary.LittleEndian.Uint32(pdu[negotiationOffset+4 : negotiationOffset+negotiationSize])
+	return selected&(protocolSSL|protocolHybrid|protocolHybridEx) != 0
+}
+
+// startTLS upgrades conn to tls after a connection confirm selecting tls
+func startTLS(conn net.Conn) (*tls.Conn, error) {
+	// rdp servers use self signed certificates and older ones only support tls 1.0
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10})
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// tlsInfo returns the names of the negotiated tls version and cipher suite
+func tlsInfo(conn *tls.Conn) (string, string) {
+	state := conn.ConnectionState()
+	return tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite)
+}
+
+// confirmConfidence returns the confidence of a valid connection confirm
+// that did not match the rdp signature
+func confirmConfidence(pdu []byte) int {
+	if len(pdu) < negotiationOffset+negotiationSize {
+		return confidenceConnectionConfirm
+	}
+	negotiation := pdu[negotiationOffset:]
+	if negotiation[0] != typeNegotiationResponse && negotiation[0] != typeNegotiationFailure {
+		return confidenceConnectionConfirm
+	}
+	if binary.LittleEndian.Uint16(negotiation[2:4]) != negotiationSize {
+		return confidenceConnectionConfirm
+	}
+	return confidenceNegotiation
+}
+
+// detectRDPAuth sends a credssp ntlm negotiate request and validates the
+// ntlm challenge before extracting server metadata from it. If upgradeTLS is
+// set, the connection is first negotiated and upgraded to tls and the negotiated
+// tls version and cipher suite are returned.
+// The read deadline of conn is bounded by timeout.
+func detectRDPAuth(conn net.Conn, timeout time.Duration, upgradeTLS bool) (CheckRDPAuthResponse, error) {
+	resp := CheckRDPAuthResponse{}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return resp, err
+	}
+	if upgradeTLS {
+		pdu, err := requestConnection(conn)
+		if err != nil || pdu == nil {
+			return resp, err
+		}
+		if !selectsTLS(pdu) {
+			// credssp is not available with legacy rdp security
+			return resp, nil
+		}
+		tlsConn, err := startTLS(conn)
+		if err != nil {
+			return resp, err
+		}
+		resp.TLSVersion, resp.CipherSuite = tlsInfo(tlsConn)
+		conn = tlsConn
+	}
+	if _, err := conn.Write(negotiateRequest); err != nil {
+		return resp, err
+	}
+
+	data, err := readTSRequest(conn)
+	if err != nil || data == nil {
+		return resp, err
+	}
+	offset := bytes.Index(data, ntlmSignature)
+	if offset == -1 {
+		return resp, nil
+	}
+	if err := validateChallenge(data[offset:]); err != nil {
+		return resp, err
+	}
+
+	pluginInfo, auth, err := fingerprintRDPAuth(newReplayConn(conn, data), timeout)
+	if err != nil {
+		return resp, malformed("%v", err)
+	}
+	if !auth {
+		return resp, nil
+	}
+	resp.Auth = true
+	resp.PluginInfo = newServerInfo(pluginInfo)
+	return resp, nil
+}
+
+// readTSRequest reads a complete der encoded TSRequest. nil data is
+// returned for non-credssp responders
+func readTSRequest(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 2)
+	n, err := io.ReadFull(conn, header)
+	if err != nil {
+		if n == 0 && isClosedOrSilent(err) {
+			return nil, nil
+		}
+		if n > 0 && header[0] == 0x30 {
+			return nil, malformed("truncated TSRequest header")
+		}
+		if n > 0 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if header[0] != 0x30 {
+		// not a der sequence
+		return nil, nil
+	}
+
+	data := header
+	length := int(header[1])
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 2 {
+			return nil, malformed("invalid TSRequest length encoding 0x%02x", header[1])
+		}
+		lengthBytes := make([]byte, size)
+		if _, err := io.ReadFull(conn, lengthBytes); err != nil {
+			return nil, malformed("truncated TSRequest length: %v", err)
+		}
+		data = append(data, lengthBytes...)
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxPDUSize {
+		return nil, malformed("invalid TSRequest length %d", length)
+	}
+	body := make([]byte, length)
+	if n, err := io.ReadFull(conn, body); err != nil {
+		return nil, malformed("truncated TSRequest (%d of %d bytes): %v", n, length, err)
+	}
+	return append(data, body...), nil
+}
+
+// validateChallenge checks that all offsets of a ntlm challenge message
+// are within its bounds
+func validateChallenge(challenge []byte) error {
+	// fixed part of the challenge message including version
+	if len(challenge) < 56 {
+		return malformed("truncated ntlm challenge (%d bytes)", len(challenge))
+	}
+	if binary.LittleEndian.Uint32(challenge[8:12]) != 2 {
+		// not a challenge message
+		return nil
+	}
+
+	nameLen := int(binary.LittleEndian.Uint16(challenge[12:14]))
+	nameOffset := int(binary.LittleEndian.Uint32(challenge[16:20]))
+	if nameLen > 0 && nameOffset+nameLen > len(challenge) {
+		return malformed("target name out of bounds")
+	}
+
+	infoLen := int(binary.LittleEndian.Uint16(challenge[40:42]))
+	infoOffset := int(binary.LittleEndian.Uint32(challenge[44:48]))
+	if infoLen == 0 {
+		return nil
+	}
+	end := infoOffset + infoLen
+	if end > len(challenge) {
+		return malformed("target info out of bounds")
+	}
+	for idx := infoOffset; ; {
+		if idx+4 > end {
+			return malformed("unterminated av_pair list")
+		}
+		avID := binary.LittleEndian.Uint16(challenge[idx : idx+2])
+		avLen := int(binary.LittleEndian.Uint16(challenge[idx+2 : idx+4]))
+		if avID == 0 {
+			return nil
+		}
+		if idx+4+avLen > end {
+			return malformed("av_pair 0x%x out of bounds", avID)
+		}
+		idx += 4 + avLen
+	}
+}
+
+// replayConn discards writes and replays a buffered response
+type replayConn struct {
+	net.Conn
+	reader *bytes.Reader
+}
+
+func newReplayConn(conn net.Conn, data []byte) *replayConn {
+	return &replayConn{Conn: conn, reader: bytes.NewReader(data)}
+}
+
+// Read reads from the buffered response
+func (c *replayConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// Write discards the data
+func (c *replayConn) Write(b []byte) (int, error) {
+	return len(b), nil
+}
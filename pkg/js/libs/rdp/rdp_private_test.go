@@ -0,0 +1,384 @@
+package rdp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// serve runs detect against a server reading a request of given size
+// and replying with response before closing the connection
+func serve(t *testing.T, requestSize int, response []byte, hold bool) net.Conn {
+	t.Helper()
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		close(done)
+		_ = client.Close()
+	})
+	go func() {
+		defer func() { _ = server.Close() }()
+		if _, err := io.ReadFull(server, make([]byte, requestSize)); err != nil {
+			return
+		}
+		if len(response) > 0 {
+			if _, err := server.Write(response); err != nil {
+				return
+			}
+		}
+		if hold {
+			<-done
+		}
+	}()
+	return client
+}
+
+func TestDetectRDP(t *testing.T) {
+	connectionConfirm := []byte{
+		0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+		0x02, 0x1f, 0x08, 0x00, 0x08, 0x00, 0x00, 0x00,
+	}
+	tests := []struct {
+		name      string
+		response  []byte
+		isRDP     bool
+		malformed bool
+	}{
+		{name: "connection confirm", response: connectionConfirm, isRDP: true},
+		{name: "truncated header", response: connectionConfirm[:2], malformed: true},
+		{name: "truncated pdu", response: connectionConfirm[:11], malformed: true},
+		{name: "short tpkt length", response: []byte{0x03, 0x00, 0x00, 0x05, 0x00}, malformed: true},
+		{name: "oversized tpkt length", response: []byte{0x03, 0x00, 0xff, 0xff}, malformed: true},
+		{name: "invalid length indicator", response: []byte{0x03, 0x00, 0x00, 0x0b, 0x20, 0xd0, 0x00, 0x00, 0x00, 0x00, 0x00}, malformed: true},
+		{name: "disconnect request", response: []byte{0x03, 0x00, 0x00, 0x0b, 0x06, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00}, malformed: true},
+		{name: "non rdp confirm", response: []byte{0x03, 0x00, 0x00, 0x0b, 0x06, 0xd0, 0x00, 0x00, 0x00, 0x01, 0x00}},
+		{name: "non tpkt response", response: []byte("HTTP/1.1 400 Bad Request\r\n\r\n")},
+		{name: "closed", response: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := serve(t, len(connectionRequest), test.response, false)
+			resp, err := detectRDP(conn, 2*time.Second, false)
+			if test.malformed {
+				if !errors.Is(err, ErrMalformedResponse) {
+					t.Fatalf("expected malformed response error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.IsRDP != test.isRDP {
+				t.Fatalf("expected IsRDP %v, got %+v", test.isRDP, resp)
+			}
+		})
+	}
+}
+
+func TestDetectRDPConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   []byte
+		isRDP      bool
+		confidence int
+	}{
+		{
+			name: "windows signature",
+			response: []byte{
+				0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+				0x02, 0x1f, 0x08, 0x00, 0x08, 0x00, 0x00, 0x00,
+			},
+			isRDP:      true,
+			confidence: 100,
+		},
+		{
+			name: "unknown negotiation flags",
+			response: []byte{
+				0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+				0x02, 0x00, 0x08, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+			isRDP:      true,
+			confidence: 80,
+		},
+		{
+			name: "negotiation response without signature",
+			response: []byte{
+				0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x08, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+			confidence: 50,
+		},
+		{
+			name: "negotiation failure without signature",
+			response: []byte{
+				0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x03, 0x00, 0x08, 0x00, 0x05, 0x00, 0x00, 0x00,
+			},
+			confidence: 50,
+		},
+		{
+			name: "invalid negotiation length",
+			response: []byte{
+				0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x04, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+			confidence: 25,
+		},
+		{name: "bare connection confirm", response: []byte{0x03, 0x00, 0x00, 0x0b, 0x06, 0xd0, 0x00, 0x00, 0x00, 0x01, 0x00}, confidence: 25},
+		{name: "non tpkt response", response: []byte("HTTP/1.1 400 Bad Request\r\n\r\n")},
+		{name: "closed", response: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := serve(t, len(connectionRequest), test.response, false)
+			resp, err := detectRDP(conn, 2*time.Second, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.IsRDP != test.isRDP || resp.Confidence != test.confidence {
+				t.Fatalf("expected IsRDP %v with confidence %d, got %+v", test.isRDP, test.confidence, resp)
+			}
+		})
+	}
+
+	conn := serve(t, len(connectionRequest), []byte{0x03, 0x00, 0x00, 0x13, 0x0e}, false)
+	resp, err := detectRDP(conn, 2*time.Second, false)
+	if !errors.Is(err, ErrMalformedResponse) || resp.Confidence != 0 {
+		t.Fatalf("expected malformed response without confidence, got %+v: %v", resp, err)
+	}
+}
+
+func TestDetectRDPNeverCompletes(t *testing.T) {
+	// only a part of the header is sent and the connection is held open
+	conn := serve(t, len(connectionRequest), []byte{0x03, 0x00}, true)
+	start := time.Now()
+	_, err := detectRDP(conn, 200*time.Millisecond, false)
+	if !errors.Is(err, ErrMalformedResponse) {
+		t.Fatalf("expected malformed response error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("detection did not honor read deadline: %s", elapsed)
+	}
+
+	conn = serve(t, len(connectionRequest), nil, true)
+	resp, err := detectRDP(conn, 200*time.Millisecond, false)
+	if err != nil || resp.IsRDP {
+		t.Fatalf("expected silent server not to be rdp, got %+v: %v", resp, err)
+	}
+}
+
+func encodeUTF16(value string) []byte {
+	var data []byte
+	for _, r := range utf16.Encode([]rune(value)) {
+		data = binary.LittleEndian.AppendUint16(data, r)
+	}
+	return data
+}
+
+// challenge builds a TSRequest containing a ntlm challenge message
+func challenge(targetName string, targetInfo []byte) []byte {
+	name := encodeUTF16(targetName)
+	message := []byte("NTLMSSP\x00")
+	message = binary.LittleEndian.AppendUint32(message, 2)
+	message = binary.LittleEndian.AppendUint16(message, uint16(len(name)))
+	message = binary.LittleEndian.AppendUint16(message, uint16(len(name)))
+	message = binary.LittleEndian.AppendUint32(message, 56)
+	message = binary.LittleEndian.AppendUint32(message, 0xe28a8215)
+	message = append(message, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88)
+	message = append(message, make([]byte, 8)...)
+	message = binary.LittleEndian.AppendUint16(message, uint16(len(targetInfo)))
+	message = binary.LittleEndian.AppendUint16(message, uint16(len(targetInfo)))
+	message = binary.LittleEndian.AppendUint32(message, uint32(56+len(name)))
+	message = append(message, 0x0a, 0x00, 0x63, 0x45, 0x00, 0x00, 0x00, 0x0f)
+	message = append(message, name...)
+	message = append(message, targetInfo...)
+
+	body := append([]byte{0xa0, 0x03, 0x02, 0x01, 0x06, 0xa1}, byte(len(message)))
+	body = append(body, message...)
+	return append([]byte{0x30, 0x81, byte(len(body))}, body...)
+}
+
+func avPairs(pairs ...string) []byte {
+	var data []byte
+	for i, value := range pairs {
+		encoded := encodeUTF16(value)
+		data = binary.LittleEndian.AppendUint16(data, uint16(i+1))
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(encoded)))
+		data = append(data, encoded...)
+	}
+	return append(data, 0, 0, 0, 0)
+}
+
+func TestDetectRDPAuth(t *testing.T) {
+	valid := challenge("ACME", avPairs("DC01", "ACME"))
+
+	conn := serve(t, len(negotiateRequest), valid, false)
+	resp, err := detectRDPAuth(conn, 2*time.Second, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Auth || resp.PluginInfo == nil {
+		t.Fatalf("expected auth info, got %+v", resp)
+	}
+	if resp.PluginInfo.TargetName != "ACME" || resp.PluginInfo.OSVersion != "10.0.17763" {
+		t.Fatalf("unexpected plugin info %+v", resp.PluginInfo)
+	}
+
+	// av_pair list without terminator
+	unterminated := challenge("ACME", avPairs("DC01"))
+	unterminated = unterminated[:len(unterminated)-4]
+	unterminated[2] -= 4
+	unterminated[9] -= 4
+	binary.LittleEndian.PutUint16(unterminated[10+40:10+42], binary.LittleEndian.Uint16(unterminated[10+40:10+42])-4)
+	// av_pair length pointing past the target info
+	overflow := avPairs("DC01")
+	binary.LittleEndian.PutUint16(overflow[2:4], 0x200)
+	// target name offset past the message
+	badName := challenge("ACME", nil)
+	binary.LittleEndian.PutUint32(badName[10+16:10+20], 0x1000)
+
+	tests := []struct {
+		name     string
+		response []byte
+	}{
+		{name: "truncated header", response: []byte{0x30}},
+		{name: "truncated TSRequest", response: valid[:40]},
+		{name: "truncated challenge", response: append([]byte{0x30, 0x10}, []byte("NTLMSSP\x00\x02\x00\x00\x00\x00\x00\x00\x00")...)},
+		{name: "unterminated av_pairs", response: unterminated},
+		{name: "av_pair overflow", response: challenge("ACME", overflow)},
+		{name: "target name overflow", response: badName},
+		{name: "invalid length encoding", response: []byte{0x30, 0x84, 0x00, 0x00, 0x00, 0x10}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := serve(t, len(negotiateRequest), test.response, false)
+			_, err := detectRDPAuth(conn, 2*time.Second, false)
+			if !errors.Is(err, ErrMalformedResponse) {
+				t.Fatalf("expected malformed response error, got %v", err)
+			}
+		})
+	}
+
+	// non credssp responders are not errors
+	conn = serve(t, len(negotiateRequest), []byte("SSH-2.0-OpenSSH_9.6\r\n"), false)
+	resp, err = detectRDPAuth(conn, 2*time.Second, false)
+	if err != nil || resp.Auth {
+		t.Fatalf("expected no auth info, got %+v: %v", resp, err)
+	}
+}
+
+// tlsConfig returns a server config with a self signed certificate
+// negotiating a fixed tls version and cipher suite
+func tlsConfig(t *testing.T) *tls.Config {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	}
+}
+
+// serveTLS answers the connection request with a connection confirm selecting
+// protocol and upgrades the connection to tls unless legacy security is selected.
+// response is written over tls after reading a request of given size
+func serveTLS(t *testing.T, protocol byte, requestSize int, response []byte) net.Conn {
+	t.Helper()
+	config := tlsConfig(t)
+	client, server := net.Pipe()
+	t.Cleanup(func() { _ = client.Close() })
+	go func() {
+		defer func() { _ = server.Close() }()
+		if _, err := io.ReadFull(server, make([]byte, len(connectionRequest))); err != nil {
+			return
+		}
+		if _, err := server.Write([]byte{
+			0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+			0x02, 0x1f, 0x08, 0x00, protocol, 0x00, 0x00, 0x00,
+		}); err != nil || protocol == 0x00 {
+			return
+		}
+		conn := tls.Server(server, config)
+		if err := conn.Handshake(); err != nil || requestSize == 0 {
+			return
+		}
+		if _, err := io.ReadFull(conn, make([]byte, requestSize)); err != nil {
+			return
+		}
+		_, _ = conn.Write(response)
+	}()
+	return client
+}
+
+func TestDetectRDPTLS(t *testing.T) {
+	const (
+		version = "TLS 1.2"
+		cipher  = "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"
+	)
+	tests := []struct {
+		name       string
+		protocol   byte
+		upgradeTLS bool
+		tls        bool
+	}{
+		{name: "ssl", protocol: protocolSSL, upgradeTLS: true, tls: true},
+		{name: "hybrid", protocol: protocolHybrid, upgradeTLS: true, tls: true},
+		{name: "legacy rdp security", protocol: 0x00, upgradeTLS: true},
+		{name: "upgrade disabled", protocol: protocolSSL},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := detectRDP(serveTLS(t, test.protocol, 0, nil), 2*time.Second, test.upgradeTLS)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !resp.IsRDP {
+				t.Fatalf("expected rdp, got %+v", resp)
+			}
+			if test.tls && (resp.TLSVersion != version || resp.CipherSuite != cipher) {
+				t.Fatalf("expected %s with %s, got %+v", version, cipher, resp)
+			}
+			if !test.tls && (resp.TLSVersion != "" || resp.CipherSuite != "") {
+				t.Fatalf("expected no tls info, got %+v", resp)
+			}
+		})
+	}
+
+	// credssp over tls
+	conn := serveTLS(t, protocolHybrid, len(negotiateRequest), challenge("ACME", avPairs("DC01", "ACME")))
+	auth, err := detectRDPAuth(conn, 2*time.Second, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !auth.Auth || auth.TLSVersion != version || auth.CipherSuite != cipher {
+		t.Fatalf("expected auth info over %s with %s, got %+v", version, cipher, auth)
+	}
+
+	conn = serveTLS(t, 0x00, 0, nil)
+	auth, err = detectRDPAuth(conn, 2*time.Second, true)
+	if err != nil || auth.Auth || auth.TLSVersion != "" || auth.CipherSuite != "" {
+		t.Fatalf("expected no auth and tls info for legacy rdp security, got %+v: %v", auth, err)
+	}
+}
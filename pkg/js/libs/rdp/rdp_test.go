@@ -0,0 +1,591 @@
+package rdp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
+	"github.com/praetorian-inc/fingerprintx/pkg/plugins/services/rdp"
+	dnslib "github.com/projectdiscovery/nuclei/v3/pkg/js/libs/dns"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// rdpListener answers connection requests with a connection confirm
+// and counts accepted connections
+func rdpListener(t *testing.T) (string, int, *atomic.Int32) {
+	t.Helper()
+	return rdpListenerOn(t, "127.0.0.1:0")
+}
+
+// rdpListenerOn is rdpListener listening on the given address
+func rdpListenerOn(t *testing.T, address string) (string, int, *atomic.Int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	dials := &atomic.Int32{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			dials.Add(1)
+			go func() {
+				defer func() { _ = conn.Close() }()
+				if _, err := io.ReadFull(conn, make([]byte, len(connectionRequest))); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{
+					0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+					0x02, 0x1f, 0x08, 0x00, 0x08, 0x00, 0x00, 0x00,
+				})
+			}()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return host, portNum, dials
+}
+
+func TestIsRDPNoCache(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	host, port, dials := rdpListener(t)
+	isRDP := func(opts IsRDPOptions) {
+		t.Helper()
+		resp, err := IsRDP(ctx, host, port, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.IsRDP {
+			t.Fatalf("expected rdp, got %+v", resp)
+		}
+	}
+
+	// second call is served from cache
+	isRDP(IsRDPOptions{})
+	isRDP(IsRDPOptions{})
+	if got := dials.Load(); got != 1 {
+		t.Fatalf("expected 1 dial for cached calls, got %d", got)
+	}
+
+	// noCache calls always dial
+	isRDP(IsRDPOptions{NoCache: true})
+	isRDP(IsRDPOptions{NoCache: true})
+	if got := dials.Load(); got != 3 {
+		t.Fatalf("expected noCache calls to dial, got %d dials", got)
+	}
+
+	// cached value is left intact
+	isRDP(IsRDPOptions{})
+	if got := dials.Load(); got != 3 {
+		t.Fatalf("expected cached call after noCache not to dial, got %d dials", got)
+	}
+}
+
+func TestIsRDPSeedMemo(t *testing.T) {
+	executionId := jstest.Init(t)
+	ctx := jstest.ExecutionContext(executionId)
+
+	host, port, dials := rdpListener(t)
+	seeded := IsRDPResponse{IsRDP: true, OS: "Windows 10/Windows Server 2016", PortOpen: true, Confidence: 100}
+	dialOpts := dialOptions(0, nil, "", "", proxyProtocol(0, "", ""))
+	if err := protocolstate.SeedMemo("rdp.isRDP", CheckRDPAuthResponse{}, executionId, host, port, dialOpts, false, false); err == nil {
+		t.Fatal("expected result type mismatch to be rejected")
+	}
+	if err := protocolstate.SeedMemo("rdp.isRDP", seeded, executionId, host, port); err == nil {
+		t.Fatal("expected missing arguments to be rejected")
+	}
+	if err := protocolstate.SeedMemo("rdp.unknown", seeded, executionId, host, port); err == nil {
+		t.Fatal("expected unknown function to be rejected")
+	}
+	if err := protocolstate.SeedMemo("rdp.isRDP", seeded, executionId, host, port, dialOpts, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := IsRDP(ctx, host, port, IsRDPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp, seeded) {
+		t.Fatalf("expected seeded result %+v, got %+v", seeded, resp)
+	}
+	if got := dials.Load(); got != 0 {
+		t.Fatalf("expected seeded result not to dial, got %d dials", got)
+	}
+}
+
+func TestIsRDPResolvedIP(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	// localhost resolves to the loopback address the listener is bound to
+	_, port, _ := rdpListener(t)
+	resp, err := IsRDP(ctx, "localhost", port, IsRDPOptions{NoCache: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsRDP || resp.ResolvedIP != "127.0.0.1" {
+		t.Fatalf("expected rdp with resolved ip 127.0.0.1, got %+v", resp)
+	}
+}
+
+// dnsServer starts a local dns server resolving rdp.test (and its alias www.rdp.test)
+// to the given addresses
+func dnsServer(t *testing.T, a []string, aaaa []string) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := miekgdns.HandlerFunc(func(w miekgdns.ResponseWriter, r *miekgdns.Msg) {
+		m := new(miekgdns.Msg)
+		m.SetReply(r)
+		question := r.Question[0]
+		name := question.Name
+		if name == "www.rdp.test." {
+			// www.rdp.test is an alias of rdp.test
+			cname := miekgdns.RR_Header{Name: name, Rrtype: miekgdns.TypeCNAME, Class: miekgdns.ClassINET, Ttl: 60}
+			m.Answer = append(m.Answer, &miekgdns.CNAME{Hdr: cname, Target: "rdp.test."})
+			name = "rdp.test."
+		}
+		header := miekgdns.RR_Header{Name: name, Rrtype: question.Qtype, Class: miekgdns.ClassINET, Ttl: 60}
+		if name != "rdp.test." {
+			m.Rcode = miekgdns.RcodeNameError
+		}
+		if name == "rdp.test." && question.Qtype == miekgdns.TypeA {
+			for _, ip := range a {
+				m.Answer = append(m.Answer, &miekgdns.A{Hdr: header, A: net.ParseIP(ip)})
+			}
+		}
+		if name == "rdp.test." && question.Qtype == miekgdns.TypeAAAA {
+			for _, ip := range aaaa {
+				m.Answer = append(m.Answer, &miekgdns.AAAA{Hdr: header, AAAA: net.ParseIP(ip)})
+			}
+		}
+		_ = w.WriteMsg(m)
+	})
+	server := &miekgdns.Server{PacketConn: conn, Handler: handler}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	return conn.LocalAddr().String()
+}
+
+func TestIsRDPAllAddrs(t *testing.T) {
+	ctx := jstest.Context(t, func(options *types.Options) {
+		options.InternalResolversList = []string{dnsServer(t, []string{"127.0.0.1", "127.0.0.2"}, []string{"::1"})}
+	})
+
+	// the listener accepts connections on every local address
+	_, port, dials := rdpListenerOn(t, ":0")
+	results, err := IsRDPAllAddrs(ctx, "rdp.test", port, IsRDPAllAddrsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ips []string
+	for _, result := range results {
+		if result.Error != "" || !result.Response.IsRDP || result.Response.ResolvedIP != result.IP {
+			t.Fatalf("expected rdp at %s, got %+v", result.IP, result)
+		}
+		ips = append(ips, result.IP)
+	}
+	if want := []string{"127.0.0.1", "127.0.0.2", "::1"}; !reflect.DeepEqual(ips, want) {
+		t.Fatalf("expected results for %v, got %v", want, ips)
+	}
+	if got := dials.Load(); got != 3 {
+		t.Fatalf("expected every address to be dialed, got %d dials", got)
+	}
+
+	// an address without rdp server is reported without failing the others
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, closedPort, _ := net.SplitHostPort(closed.Addr().String())
+	_ = closed.Close()
+	closedPortNum, _ := strconv.Atoi(closedPort)
+	results, err = IsRDPAllAddrs(ctx, "rdp.test", closedPortNum, IsRDPAllAddrsOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Error == "" || results[0].Response.IsRDP {
+		t.Fatalf("expected errors for a closed port, got %+v", results)
+	}
+
+	if _, err := IsRDPAllAddrs(ctx, "missing.test", port, IsRDPAllAddrsOptions{}); err == nil {
+		t.Fatal("expected resolution error for unknown host")
+	}
+}
+
+func TestIsRDPCachedAnswers(t *testing.T) {
+	ctx := jstest.Context(t, func(options *types.Options) {
+		options.InternalResolversList = []string{dnsServer(t, []string{"127.0.0.1"}, nil)}
+	})
+
+	answers, err := dnslib.CachedAnswers(ctx, "www.rdp.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answers.Cached || len(answers.A) != 0 {
+		t.Fatalf("expected no cached answers before dialing, got %+v", answers)
+	}
+
+	_, port, _ := rdpListener(t)
+	resp, err := IsRDP(ctx, "www.rdp.test", port, IsRDPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsRDP {
+		t.Fatalf("expected rdp, got %+v", resp)
+	}
+
+	answers, err = dnslib.CachedAnswers(ctx, "www.rdp.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dnslib.CachedAnswersResponse{
+		Host:   "www.rdp.test",
+		Cached: true,
+		A:      []string{resp.ResolvedIP},
+		AAAA:   []string{},
+		CNAME:  []string{"rdp.test"},
+	}
+	if !reflect.DeepEqual(answers, want) {
+		t.Fatalf("expected cached answers %+v, got %+v", want, answers)
+	}
+}
+
+func TestIsRDPWithIP(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	// the hostname does not resolve so the supplied ip must be dialed
+	ip, port, dials := rdpListener(t)
+	resp, err := IsRDP(ctx, "rdp.invalid", port, IsRDPOptions{IP: ip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsRDP || resp.ResolvedIP != ip || dials.Load() != 1 {
+		t.Fatalf("expected rdp dialed at %s, got %+v with %d dials", ip, resp, dials.Load())
+	}
+
+	authResp, err := CheckRDPAuth(ctx, "rdp.invalid", port, CheckRDPAuthOptions{IP: ip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authResp.ResolvedIP != ip || dials.Load() != 2 {
+		t.Fatalf("expected auth check dialed at %s, got %+v with %d dials", ip, authResp, dials.Load())
+	}
+}
+
+func TestIsRDPRecoversPanic(t *testing.T) {
+	ctx := jstest.Context(t)
+	host, port, _ := rdpListener(t)
+
+	// simulate fingerprintx indexing past a truncated signature
+	fingerprintRDP = func(conn net.Conn, timeout time.Duration) (string, bool, error) {
+		pdu := make([]byte, 4)
+		_, _ = conn.Read(pdu)
+		offset := len(pdu) + 1
+		return string(pdu[offset:]), true, nil
+	}
+	resp, err := IsRDP(ctx, host, port, IsRDPOptions{NoCache: true})
+	fingerprintRDP = rdp.DetectRDP
+	if err != nil || resp.IsRDP || !resp.PortOpen {
+		t.Fatalf("expected panic to be reported as open non-rdp port, got %+v err=%v", resp, err)
+	}
+
+	// scan continues with following probes
+	resp, err = IsRDP(ctx, host, port, IsRDPOptions{NoCache: true})
+	if err != nil || !resp.IsRDP {
+		t.Fatalf("expected rdp after recovered panic, got %+v err=%v", resp, err)
+	}
+}
+
+func TestIsRDPEvents(t *testing.T) {
+	executionId := jstest.Init(t)
+	ctx := jstest.ExecutionContext(executionId)
+
+	var mu sync.Mutex
+	var events []protocolstate.Event
+	protocolstate.SetEventSink(executionId, func(event protocolstate.Event) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	})
+	expectEvents := func(want ...string) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+		var got []string
+		for _, event := range events {
+			if event.ExecutionId != executionId || event.Target == "" {
+				t.Fatalf("unexpected event %+v", event)
+			}
+			got = append(got, event.Phase+":"+event.Outcome)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected events %v, got %v", want, got)
+		}
+		events = nil
+	}
+
+	host, port, _ := rdpListener(t)
+	if _, err := IsRDP(ctx, host, port, IsRDPOptions{NoCache: true}); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents("dial:success", "rdp.detect:success")
+
+	// responder sending a truncated connection confirm
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = io.ReadFull(conn, make([]byte, len(connectionRequest)))
+			_, _ = conn.Write([]byte{0x03, 0x00, 0x00, 0x04})
+			_ = conn.Close()
+		}
+	}()
+	if resp, err := IsRDP(ctx, host, ln.Addr().(*net.TCPAddr).Port, IsRDPOptions{NoCache: true}); err != nil || resp.IsRDP || !resp.PortOpen {
+		t.Fatalf("expected open non-rdp port, got %+v err=%v", resp, err)
+	}
+	expectEvents("dial:success", "rdp.detect:failure")
+
+	// closed port
+	closed := ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+	if _, err := IsRDP(ctx, host, closed, IsRDPOptions{NoCache: true}); err == nil {
+		t.Fatal("expected dial error")
+	}
+	expectEvents("dial:failure")
+}
+
+func TestIsRDPPortOpen(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	// open rdp
+	host, port, _ := rdpListener(t)
+	resp, err := IsRDP(ctx, host, port, IsRDPOptions{NoCache: true})
+	if err != nil || !resp.IsRDP || !resp.PortOpen {
+		t.Fatalf("expected open rdp port, got %+v err=%v", resp, err)
+	}
+
+	// open but not rdp (http server)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = io.ReadFull(conn, make([]byte, len(connectionRequest)))
+			_, _ = conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+			_ = conn.Close()
+		}
+	}()
+	resp, err = IsRDP(ctx, host, ln.Addr().(*net.TCPAddr).Port, IsRDPOptions{NoCache: true})
+	if err != nil || resp.IsRDP || !resp.PortOpen {
+		t.Fatalf("expected open non-rdp port, got %+v err=%v", resp, err)
+	}
+
+	// closed
+	closed := ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+	resp, err = IsRDP(ctx, host, closed, IsRDPOptions{NoCache: true})
+	if err == nil || resp.IsRDP || resp.PortOpen {
+		t.Fatalf("expected closed port, got %+v err=%v", resp, err)
+	}
+}
+
+func TestIsRDPCaptureRaw(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	// non rdp responder recording what it received
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	response := []byte("SSH-2.0-OpenSSH_9.6\r\n")
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		data := make([]byte, len(connectionRequest))
+		_, _ = io.ReadFull(conn, data)
+		received <- data
+		_, _ = conn.Write(response)
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	resp, err := IsRDP(ctx, host, portNum, IsRDPOptions{NoCache: true, CaptureRaw: true})
+	if err != nil || resp.IsRDP {
+		t.Fatalf("expected non rdp response, got %+v err=%v", resp, err)
+	}
+	if resp.RawExchange == nil {
+		t.Fatal("expected raw exchange to be captured")
+	}
+	if sent := hex.EncodeToString(<-received); resp.RawExchange.Sent != sent {
+		t.Fatalf("expected sent %s, got %s", sent, resp.RawExchange.Sent)
+	}
+	// only the tpkt header is read from a non tpkt response
+	if want := hex.EncodeToString(response); !strings.HasPrefix(want, resp.RawExchange.Received) || resp.RawExchange.Received == "" {
+		t.Fatalf("expected received prefix of %s, got %s", want, resp.RawExchange.Received)
+	}
+	if resp.RawExchange.Truncated {
+		t.Fatal("unexpected truncated capture")
+	}
+
+	// capture is disabled by default
+	host, portNum, _ = rdpListener(t)
+	resp, err = IsRDP(ctx, host, portNum, IsRDPOptions{NoCache: true})
+	if err != nil || !resp.IsRDP || resp.RawExchange != nil {
+		t.Fatalf("expected rdp without raw exchange, got %+v err=%v", resp, err)
+	}
+}
+
+func TestIsRDPProbePcap(t *testing.T) {
+	probePcap := filepath.Join(t.TempDir(), "probes.pcap")
+	executionId := jstest.Init(t, func(options *types.Options) { options.ProbePcap = probePcap })
+	ctx := jstest.ExecutionContext(executionId)
+
+	host, port, _ := rdpListener(t)
+	resp, err := IsRDP(ctx, host, port, IsRDPOptions{NoCache: true})
+	if err != nil || !resp.IsRDP {
+		t.Fatalf("expected rdp, got %+v err=%v", resp, err)
+	}
+	// closing the dialers closes the pcap file
+	protocolstate.Close(executionId)
+
+	data, err := os.ReadFile(probePcap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, connectionRequest) {
+		t.Fatalf("expected connection request in pcap, got %x", data)
+	}
+	// the tpkt header and the connection confirm are read (and recorded) separately
+	confirm := []byte{0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34}
+	if !bytes.Contains(data, confirm) {
+		t.Fatalf("expected connection confirm in pcap, got %x", data)
+	}
+}
+
+func TestIsRDPProxyProtocol(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	// rdp backend answering only after a PROXY v1 header
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				header := fmt.Sprintf("PROXY TCP4 10.0.0.1 %s 4242 %d\r\n", "127.0.0.1", ln.Addr().(*net.TCPAddr).Port)
+				data := make([]byte, len(header))
+				if _, err := io.ReadFull(conn, data[:6]); err != nil || string(data[:6]) != "PROXY " {
+					return
+				}
+				if _, err := io.ReadFull(conn, data[6:]); err != nil || string(data) != header {
+					return
+				}
+				if _, err := io.ReadFull(conn, make([]byte, len(connectionRequest))); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{
+					0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00,
+					0x02, 0x1f, 0x08, 0x00, 0x08, 0x00, 0x00, 0x00,
+				})
+			}()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	resp, err := IsRDP(ctx, host, portNum, IsRDPOptions{NoCache: true, ProxyProtocol: 1, ProxySource: "10.0.0.1:4242"})
+	if err != nil || !resp.IsRDP {
+		t.Fatalf("expected rdp behind proxy protocol, got %+v err=%v", resp, err)
+	}
+	// the backend drops connections without the header
+	if resp, _ = IsRDP(ctx, host, portNum, IsRDPOptions{NoCache: true}); resp.IsRDP {
+		t.Fatalf("expected no rdp without proxy header, got %+v", resp)
+	}
+}
+
+func TestServerInfoJSON(t *testing.T) {
+	info := newServerInfo(&plugins.ServiceRDP{
+		OSFingerprint:       "Windows Server 2016 or 2019",
+		OSVersion:           "10.0.17763",
+		TargetName:          "ACME",
+		NetBIOSComputerName: "DC01",
+		NetBIOSDomainName:   "ACME",
+		DNSComputerName:     "dc01.acme.local",
+		DNSDomainName:       "acme.local",
+		ForestName:          "acme.local",
+	})
+	data, err := json.Marshal(CheckRDPAuthResponse{PluginInfo: info, Auth: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		PluginInfo map[string]string
+		Auth       bool
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// field names are part of the template contract and must not change
+	expected := map[string]string{
+		"fingerprint":         "Windows Server 2016 or 2019",
+		"osVersion":           "10.0.17763",
+		"targetName":          "ACME",
+		"netBIOSComputerName": "DC01",
+		"netBIOSDomainName":   "ACME",
+		"dnsComputerName":     "dc01.acme.local",
+		"dnsDomainName":       "acme.local",
+		"forestName":          "acme.local",
+	}
+	if !decoded.Auth || !reflect.DeepEqual(decoded.PluginInfo, expected) {
+		t.Fatalf("unexpected json %s", data)
+	}
+}
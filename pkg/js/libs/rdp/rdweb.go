@@ -0,0 +1,179 @@
+package rdp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the rdweb request
+	rdwebTimeout = 10 * time.Second
+	// default path of the rdweb login page
+	defaultRDWebPath = "/RDWeb/Pages/en-US/login.aspx"
+)
+
+var (
+	// markers (lowercase) specific to rd web access pages (login form fields
+	// and its scripts). product names and the /rdweb/ path are not used since
+	// any page linking to a portal contains them
+	rdwebMarkers = [][]byte{
+		[]byte(`name="domainusername"`),
+		[]byte(`name="userpass"`),
+		[]byte(`name="workspaceid"`),
+		[]byte("tswaauthhttphandler"),
+		[]byte("renderform"),
+	}
+	// version of static assets (ex: renderscripts.js?v=10.0.17763.1)
+	rdwebAssetVersion = regexp.MustCompile(`(?i)\.(?:js|css|aspx|png|gif|ico)\?(?:v|ver|version)=([0-9]+(?:\.[0-9]+){2,3})`)
+	// rds product by major.minor.build prefix of the version
+	rdsProducts = []struct {
+		prefix  string
+		product string
+	}{
+		{"6.1.7601", "Windows Server 2008 R2"},
+		{"6.2.9200", "Windows Server 2012"},
+		{"6.3.9600", "Windows Server 2012 R2"},
+		{"10.0.14393", "Windows Server 2016"},
+		{"10.0.17763", "Windows Server 2019"},
+		{"10.0.20348", "Windows Server 2022"},
+		{"10.0.26100", "Windows Server 2025"},
+	}
+)
+
+type (
+	// RDWebVersionResponse is the response from the GetRDWebVersion function.
+	// this is returned by GetRDWebVersion function.
+	// @example
+	// ```javascript
+	// const rdp = require('nuclei/rdp');
+	// const rdweb = rdp.GetRDWebVersion('acme.com', 443, '/RDWeb/Pages/en-US/login.aspx');
+	// log(toJSON(rdweb));
+	// ```
+	RDWebVersionResponse struct {
+		// IsRDWeb is true if the page is a rd web access portal
+		IsRDWeb bool
+		// Version is the rds version found in the static assets (ex: 10.0.17763.1)
+		Version string
+		// Product is the windows server release of the version (if known)
+		Product string
+		// StatusCode is the http status code of the response
+		StatusCode int
+		// Server is the value of Server header (if any)
+		Server string
+		// TLS is true if the portal was reached over https
+		TLS bool
+	}
+)
+
+// GetRDWebVersion fetches the rd web access login page at path
+// (default: /RDWeb/Pages/en-US/login.aspx) and extracts the rds version
+// from the versioned static assets it references.
+// HTTPS is tried first and plaintext HTTP is used as fallback.
+// Pages that are not rd web access portals return IsRDWeb false.
+// @example
+// ```javascript
+// const rdp = require('nuclei/rdp');
+// const rdweb = rdp.GetRDWebVersion('acme.com', 443, '');
+// log(rdweb.IsRDWeb, rdweb.Version, rdweb.Product);
+// ```
+func GetRDWebVersion(ctx context.Context, host string, port int, path string) (RDWebVersionResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if path == "" {
+		path = defaultRDWebPath
+	}
+	return memoizedgetRDWebVersion(executionId, host, port, path)
+}
+
+// @memo
+func getRDWebVersion(executionId string, host string, port int, path string) (RDWebVersionResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return RDWebVersionResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	client, err := utils.NewHTTPClient(executionId, rdwebTimeout)
+	if err != nil {
+		return RDWebVersionResponse{}, err
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	resp, err := fetchRDWebPage(executionId, client, "https://"+address+path)
+	if err == nil {
+		resp.TLS = true
+		return resp, nil
+	}
+	// fallback to plaintext http
+	return fetchRDWebPage(executionId, client, "http://"+address+path)
+}
+
+// fetchRDWebPage fetches the page at url and parses the rdweb version
+func fetchRDWebPage(executionId string, client *http.Client, url string) (RDWebVersionResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return RDWebVersionResponse{}, err
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return RDWebVersionResponse{}, err
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+
+	resp := RDWebVersionResponse{
+		StatusCode: httpResp.StatusCode,
+		Server:     httpResp.Header.Get("Server"),
+	}
+	body, err := utils.ReadAll(executionId, httpResp.Body)
+	if err != nil {
+		return resp, err
+	}
+	if httpResp.StatusCode != http.StatusOK || !isRDWebPage(body) {
+		return resp, nil
+	}
+	resp.IsRDWeb = true
+	resp.Version = rdwebVersion(body)
+	resp.Product = rdsProduct(resp.Version)
+	return resp, nil
+}
+
+// isRDWebPage checks if body contains any of the rdweb markers
+func isRDWebPage(body []byte) bool {
+	body = bytes.ToLower(body)
+	for _, marker := range rdwebMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// rdwebVersion returns the first version referenced by a static asset
+func rdwebVersion(body []byte) string {
+	match := rdwebAssetVersion.FindSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
+// rdsProduct returns the windows server release of version
+func rdsProduct(version string) string {
+	for _, product := range rdsProducts {
+		if version == product.prefix || strings.HasPrefix(version, product.prefix+".") {
+			return product.product
+		}
+	}
+	return ""
+}
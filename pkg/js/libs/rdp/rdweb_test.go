@@ -0,0 +1,111 @@
+package rdp
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+const rdwebLoginPage = `<html><head><title>RD Web Access</title>
+<link href="../../Site.css?v=10.0.17763.1" rel="stylesheet" type="text/css" />
+<script type="text/javascript" src="../renderscripts.js?v=10.0.17763.1"></script>
+</head><body><form id="FrmLogin" name="FrmLogin" action="login.aspx" onsubmit="return onLoginFormSubmit()">
+<input id="DomainUserName" name="DomainUserName" type="text" />
+<input id="UserPass" name="UserPass" type="password" />
+</form></body></html>`
+
+// page linking to a portal without being one
+const rdwebLinkPage = `<html><head><title>Remote access</title></head>
+<body>Use <a href="https://acme.com/RDWeb/">RD Web Access</a> to connect.</body></html>`
+
+func TestGetRDWebVersion(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case defaultRDWebPath:
+			_, _ = w.Write([]byte(rdwebLoginPage))
+		case "/help.html":
+			_, _ = w.Write([]byte(rdwebLinkPage))
+		case "/index.html":
+			_, _ = w.Write([]byte("<html><title>Welcome</title><script src=\"app.js?v=1.2.3\"></script></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	tlsServer := httptest.NewTLSServer(handler)
+	t.Cleanup(tlsServer.Close)
+	plainServer := httptest.NewServer(handler)
+	t.Cleanup(plainServer.Close)
+
+	target := func(server *httptest.Server) (string, int) {
+		host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		portNum, _ := strconv.Atoi(port)
+		return host, portNum
+	}
+	tests := []struct {
+		name   string
+		server *httptest.Server
+		path   string
+		want   RDWebVersionResponse
+	}{
+		{
+			name:   "login page over https",
+			server: tlsServer,
+			want:   RDWebVersionResponse{IsRDWeb: true, Version: "10.0.17763.1", Product: "Windows Server 2019", StatusCode: 200, TLS: true},
+		},
+		{
+			name:   "login page over http",
+			server: plainServer,
+			path:   defaultRDWebPath,
+			want:   RDWebVersionResponse{IsRDWeb: true, Version: "10.0.17763.1", Product: "Windows Server 2019", StatusCode: 200},
+		},
+		{
+			name:   "other page",
+			server: tlsServer,
+			path:   "/index.html",
+			want:   RDWebVersionResponse{StatusCode: 200, TLS: true},
+		},
+		{
+			name:   "page linking to portal",
+			server: tlsServer,
+			path:   "/help.html",
+			want:   RDWebVersionResponse{StatusCode: 200, TLS: true},
+		},
+		{
+			name:   "not found",
+			server: tlsServer,
+			path:   "/RDWeb/Pages/fr-FR/login.aspx",
+			want:   RDWebVersionResponse{StatusCode: 404, TLS: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host, port := target(test.server)
+			got, err := GetRDWebVersion(ctx, host, port, test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRDSProduct(t *testing.T) {
+	for version, want := range map[string]string{
+		"6.3.9600.16384": "Windows Server 2012 R2",
+		"10.0.20348":     "Windows Server 2022",
+		"10.0.203481":    "",
+		"":               "",
+	} {
+		if got := rdsProduct(version); got != want {
+			t.Fatalf("expected product %q for %q, got %q", want, version, got)
+		}
+	}
+}
@@ -8,11 +8,19 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
+func init() {
+	protocolstate.RegisterMemoized[string]("redis.getServerInfo", 3)
+}
+
 func memoizedgetServerInfo(executionId string, host string, port int) (string, error) {
-	hash := "getServerInfo" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	hash := "redis.getServerInfo" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "redis.getServerInfo" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return getServerInfo(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (string, error) {
+			return getServerInfo(executionId, host, port)
+		})
 	})
 	if err != nil {
 		return "", err
@@ -24,11 +32,19 @@ func memoizedgetServerInfo(executionId string, host string, port int) (string, e
 	return "", errors.New("could not convert cached result")
 }
 
+func init() {
+	protocolstate.RegisterMemoized[bool]("redis.connect", 4)
+}
+
 func memoizedconnect(executionId string, host string, port int, password string) (bool, error) {
-	hash := "connect" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(password)
+	hash := "redis.connect" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(password)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "redis.connect" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(password)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return connect(executionId, host, port, password)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return connect(executionId, host, port, password)
+		})
 	})
 	if err != nil {
 		return false, err
@@ -40,9 +56,13 @@ func memoizedconnect(executionId string, host string, port int, password string)
 	return false, errors.New("could not convert cached result")
 }
 
-func memoizedgetServerInfoAuth(executionId string, host string, port int, password string) (string, error) {
-	hash := "getServerInfoAuth" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(password)
+func init() {
+	protocolstate.RegisterMemoized[string]("redis.getServerInfoAuth", 4)
+}
 
+func memoizedgetServerInfoAuth(executionId string, host string, port int, password string) (string, error) {
+	hash := "redis.getServerInfoAuth" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(password)
+	// results are only memoized in memory (nodisk)
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
 		return getServerInfoAuth(executionId, host, port, password)
 	})
@@ -56,11 +76,19 @@ func memoizedgetServerInfoAuth(executionId string, host string, port int, passwo
 	return "", errors.New("could not convert cached result")
 }
 
+func init() {
+	protocolstate.RegisterMemoized[bool]("redis.isAuthenticated", 3)
+}
+
 func memoizedisAuthenticated(executionId string, host string, port int) (bool, error) {
-	hash := "isAuthenticated" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	hash := "redis.isAuthenticated" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "redis.isAuthenticated" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isAuthenticated(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return isAuthenticated(executionId, host, port)
+		})
 	})
 	if err != nil {
 		return false, err
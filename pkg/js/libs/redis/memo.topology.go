@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[TopologyResponse]("redis.getTopology", 4)
+}
+
+func memoizedgetTopology(executionId string, host string, port int, password string) (TopologyResponse, error) {
+	hash := "redis.getTopology" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(password)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "redis.getTopology" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(password)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (TopologyResponse, error) {
+			return getTopology(executionId, host, port, password)
+		})
+	})
+	if err != nil {
+		return TopologyResponse{}, err
+	}
+	if value, ok := v.(TopologyResponse); ok {
+		return value, nil
+	}
+
+	return TopologyResponse{}, errors.New("could not convert cached result")
+}
@@ -105,7 +105,7 @@ func GetServerInfoAuth(ctx context.Context, host string, port int, password stri
 	return memoizedgetServerInfoAuth(executionId, host, port, password)
 }
 
-// @memo
+// @memo(nodisk)
 func getServerInfoAuth(executionId string, host string, port int, password string) (string, error) {
 	if !protocolstate.IsHostAllowed(executionId, host) {
 		// host is not valid according to network policy
@@ -151,15 +151,16 @@ func IsAuthenticated(ctx context.Context, host string, port int) (bool, error) {
 func isAuthenticated(executionId string, host string, port int) (bool, error) {
 	plugin := pluginsredis.REDISPlugin{}
 	timeout := 5 * time.Second
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return false, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return false, err
 	}
 
 	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
 	if err != nil {
 		return false, err
 	}
+	conn = protocolstate.RecordConn(executionId, conn)
 	defer func() {
 		_ = conn.Close()
 	}()
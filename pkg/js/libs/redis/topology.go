@@ -0,0 +1,173 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/redis/go-redis/v9"
+)
+
+type (
+	// TopologyResponse is the response from the GetTopology function.
+	// this is returned by GetTopology function.
+	// @example
+	// ```javascript
+	// const redis = require('nuclei/redis');
+	// const topology = redis.GetTopology('acme.com', 6379, 'password');
+	// log(toJSON(topology));
+	// ```
+	TopologyResponse struct {
+		// Mode is the redis mode (standalone, cluster or sentinel)
+		Mode string
+		// Role is the replication role of the node (master or slave)
+		Role string
+		// ReplicationID is the replication id shared by master and its replicas
+		// it can be used to identify nodes of the same logical datastore
+		ReplicationID string
+		// MasterHost is the host of the master (replicas only)
+		MasterHost string
+		// MasterPort is the port of the master (replicas only)
+		MasterPort int
+		// MasterLinkStatus is the status of link to the master (replicas only)
+		MasterLinkStatus string
+		// Replicas contains addresses (host:port) of connected replicas (masters only)
+		Replicas []string
+		// ClusterEnabled is true if cluster mode is enabled
+		ClusterEnabled bool
+		// ClusterState is the state of the cluster (ok or fail)
+		ClusterState string
+		// ClusterKnownNodes is the number of nodes known to the cluster
+		ClusterKnownNodes int
+		// ClusterSize is the number of master nodes serving slots
+		ClusterSize int
+		// SentinelMasters contains addresses (host:port) of masters monitored by a sentinel
+		SentinelMasters []string
+	}
+)
+
+// GetTopology returns the replication, cluster and sentinel topology of a redis node
+// using INFO and CLUSTER INFO commands. This helps to identify nodes belonging to the
+// same logical datastore. Empty password can be used for unauthenticated servers.
+// @example
+// ```javascript
+// const redis = require('nuclei/redis');
+// const topology = redis.GetTopology('acme.com', 6379, 'password');
+// log(topology.Role, topology.ClusterEnabled);
+// ```
+func GetTopology(ctx context.Context, host string, port int, password string) (TopologyResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetTopology(executionId, host, port, password)
+}
+
+// @memo
+func getTopology(executionId string, host string, port int, password string) (TopologyResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return TopologyResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return TopologyResponse{}, err
+	}
+	// create a new client
+	client := redis.NewClient(&redis.Options{
+		Addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		Password: password,
+		DB:       0, // use default DB
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.Fastdialer.Dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return protocolstate.RecordConn(executionId, conn), nil
+		},
+	})
+	defer func() {
+		_ = client.Close()
+	}()
+
+	infoCmd := client.Info(context.TODO())
+	if infoCmd.Err() != nil {
+		return TopologyResponse{}, infoCmd.Err()
+	}
+	topology := parseTopology(parseInfo(infoCmd.Val()))
+	if topology.ClusterEnabled {
+		clusterCmd := client.ClusterInfo(context.TODO())
+		if clusterCmd.Err() != nil {
+			return topology, clusterCmd.Err()
+		}
+		parseClusterInfo(parseInfo(clusterCmd.Val()), &topology)
+	}
+	return topology, nil
+}
+
+// parseInfo parses key:value lines of INFO and CLUSTER INFO replies
+func parseInfo(info string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// parseFields parses comma separated key=value fields (ex: slave0 and master0 entries)
+func parseFields(value string) map[string]string {
+	fields := map[string]string{}
+	for _, field := range strings.Split(value, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if ok {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// parseTopology extracts topology fields from parsed INFO reply
+func parseTopology(info map[string]string) TopologyResponse {
+	topology := TopologyResponse{
+		Mode:             info["redis_mode"],
+		Role:             info["role"],
+		ReplicationID:    info["master_replid"],
+		MasterHost:       info["master_host"],
+		MasterLinkStatus: info["master_link_status"],
+		ClusterEnabled:   info["cluster_enabled"] == "1",
+	}
+	if topology.Mode == "" {
+		topology.Mode = "standalone"
+	}
+	topology.MasterPort, _ = strconv.Atoi(info["master_port"])
+
+	connected, _ := strconv.Atoi(info["connected_slaves"])
+	for i := 0; i < connected; i++ {
+		replica := parseFields(info[fmt.Sprintf("slave%d", i)])
+		if replica["ip"] != "" {
+			topology.Replicas = append(topology.Replicas, net.JoinHostPort(replica["ip"], replica["port"]))
+		}
+	}
+	masters, _ := strconv.Atoi(info["sentinel_masters"])
+	for i := 0; i < masters; i++ {
+		master := parseFields(info[fmt.Sprintf("master%d", i)])
+		if master["address"] != "" {
+			topology.SentinelMasters = append(topology.SentinelMasters, master["address"])
+		}
+	}
+	return topology
+}
+
+// parseClusterInfo extracts cluster fields from parsed CLUSTER INFO reply
+func parseClusterInfo(info map[string]string, topology *TopologyResponse) {
+	topology.ClusterState = info["cluster_state"]
+	topology.ClusterKnownNodes, _ = strconv.Atoi(info["cluster_known_nodes"])
+	topology.ClusterSize, _ = strconv.Atoi(info["cluster_size"])
+}
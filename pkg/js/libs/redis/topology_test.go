@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+)
+
+const masterInfo = "# Server\r\n" +
+	"redis_version:7.2.4\r\n" +
+	"redis_mode:standalone\r\n" +
+	"run_id:8d3b4c1f2e6a7b9c0d1e2f3a4b5c6d7e8f9a0b1c\r\n" +
+	"\r\n" +
+	"# Replication\r\n" +
+	"role:master\r\n" +
+	"connected_slaves:2\r\n" +
+	"slave0:ip=10.0.0.12,port=6379,state=online,offset=5432,lag=0\r\n" +
+	"slave1:ip=10.0.0.13,port=6380,state=online,offset=5432,lag=1\r\n" +
+	"master_failover_state:no-failover\r\n" +
+	"master_replid:3f1c9de0a6b7e2d4c5b8a9f0e1d2c3b4a5f6e7d8\r\n" +
+	"master_replid2:0000000000000000000000000000000000000000\r\n" +
+	"\r\n" +
+	"# Cluster\r\n" +
+	"cluster_enabled:0\r\n"
+
+const replicaInfo = "# Server\r\n" +
+	"redis_version:7.2.4\r\n" +
+	"redis_mode:cluster\r\n" +
+	"\r\n" +
+	"# Replication\r\n" +
+	"role:slave\r\n" +
+	"master_host:10.0.0.11\r\n" +
+	"master_port:6379\r\n" +
+	"master_link_status:up\r\n" +
+	"connected_slaves:0\r\n" +
+	"master_replid:3f1c9de0a6b7e2d4c5b8a9f0e1d2c3b4a5f6e7d8\r\n" +
+	"\r\n" +
+	"# Cluster\r\n" +
+	"cluster_enabled:1\r\n"
+
+const clusterInfo = "cluster_state:ok\r\n" +
+	"cluster_slots_assigned:16384\r\n" +
+	"cluster_slots_ok:16384\r\n" +
+	"cluster_known_nodes:6\r\n" +
+	"cluster_size:3\r\n" +
+	"cluster_current_epoch:6\r\n"
+
+const sentinelInfo = "# Server\r\n" +
+	"redis_version:7.2.4\r\n" +
+	"redis_mode:sentinel\r\n" +
+	"\r\n" +
+	"# Sentinel\r\n" +
+	"sentinel_masters:1\r\n" +
+	"sentinel_tilt:0\r\n" +
+	"master0:name=mymaster,status=ok,address=10.0.0.11:6379,slaves=2,sentinels=3\r\n"
+
+func TestParseTopologyMaster(t *testing.T) {
+	got := parseTopology(parseInfo(masterInfo))
+	want := TopologyResponse{
+		Mode:          "standalone",
+		Role:          "master",
+		ReplicationID: "3f1c9de0a6b7e2d4c5b8a9f0e1d2c3b4a5f6e7d8",
+		Replicas:      []string{"10.0.0.12:6379", "10.0.0.13:6380"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected topology got=%+v want=%+v", got, want)
+	}
+}
+
+func TestParseTopologyClusterReplica(t *testing.T) {
+	got := parseTopology(parseInfo(replicaInfo))
+	parseClusterInfo(parseInfo(clusterInfo), &got)
+	want := TopologyResponse{
+		Mode:              "cluster",
+		Role:              "slave",
+		ReplicationID:     "3f1c9de0a6b7e2d4c5b8a9f0e1d2c3b4a5f6e7d8",
+		MasterHost:        "10.0.0.11",
+		MasterPort:        6379,
+		MasterLinkStatus:  "up",
+		ClusterEnabled:    true,
+		ClusterState:      "ok",
+		ClusterKnownNodes: 6,
+		ClusterSize:       3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected topology got=%+v want=%+v", got, want)
+	}
+}
+
+func TestParseTopologySentinel(t *testing.T) {
+	got := parseTopology(parseInfo(sentinelInfo))
+	want := TopologyResponse{
+		Mode:            "sentinel",
+		SentinelMasters: []string{"10.0.0.11:6379"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected topology got=%+v want=%+v", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+// Warning - This is generated code
+package rmi
+
+import (
+	"context"
+
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsRMIResponse]("rmi.isRMI", 3)
+}
+
+func memoizedisRMI(ctx context.Context, executionId string, host string, port int) (IsRMIResponse, error) {
+	hash := "rmi.isRMI" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "rmi.isRMI" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsRMIResponse, error) {
+			return isRMI(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsRMIResponse{}, err
+	}
+	if value, ok := v.(IsRMIResponse); ok {
+		return value, nil
+	}
+
+	return IsRMIResponse{}, errors.New("could not convert cached result")
+}
+
+func init() {
+	protocolstate.RegisterMemoized[[]string]("rmi.listBoundNames", 3)
+}
+
+func memoizedlistBoundNames(ctx context.Context, executionId string, host string, port int) ([]string, error) {
+	hash := "rmi.listBoundNames" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "rmi.listBoundNames" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() ([]string, error) {
+			return listBoundNames(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return []string{}, err
+	}
+	if value, ok := v.([]string); ok {
+		return value, nil
+	}
+
+	return []string{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,315 @@
+package rmi
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of jrmp handshake and registry call
+	probeTimeout = 5 * time.Second
+
+	msgCall       = 0x50
+	msgReturnData = 0x51
+	protocolAck   = 0x4e
+
+	returnNormal = 0x01
+
+	// java serialization constants
+	tcNull        = 0x70
+	tcReference   = 0x71
+	tcClassDesc   = 0x72
+	tcString      = 0x74
+	tcArray       = 0x75
+	tcBlockData   = 0x77
+	tcEndBlock    = 0x78
+	streamMagic   = 0xaced
+	streamVersion = 5
+
+	baseWireHandle = 0x7e0000
+
+	// sun.rmi.registry.RegistryImpl_Stub interface hash
+	registryHash = 0x44154dc9d4e63bdf
+	opList       = 1
+)
+
+var (
+	// JRMI magic, version 2, StreamProtocol
+	jrmpHeader = []byte{0x4a, 0x52, 0x4d, 0x49, 0x00, 0x02, 0x4b}
+
+	errNotRMI          = errors.New("not a java rmi endpoint")
+	errInvalidResponse = errors.New("invalid rmi registry response")
+)
+
+type (
+	// IsRMIResponse is the response from the IsRMI function.
+	// this is returned by IsRMI function.
+	// @example
+	// ```javascript
+	// const rmi = require('nuclei/rmi');
+	// const isRMI = rmi.IsRMI('acme.com', 1099);
+	// log(toJSON(isRMI));
+	// ```
+	IsRMIResponse struct {
+		// IsRMI is true if the endpoint acknowledged the jrmp handshake
+		IsRMI bool
+	}
+)
+
+// IsRMI checks if the given host and port are running a java rmi endpoint
+// by performing the JRMP stream protocol handshake.
+// @example
+// ```javascript
+// const rmi = require('nuclei/rmi');
+// const isRMI = rmi.IsRMI('acme.com', 1099);
+// log(toJSON(isRMI));
+// ```
+func IsRMI(ctx context.Context, host string, port int) (IsRMIResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisRMI(ctx, executionId, host, port)
+}
+
+// @memo
+func isRMI(ctx context.Context, executionId string, host string, port int) (IsRMIResponse, error) {
+	resp := IsRMIResponse{}
+	conn, err := dial(ctx, executionId, host, port)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if err := handshake(conn, bufio.NewReader(conn)); err != nil {
+		if errors.Is(err, errNotRMI) {
+			return resp, nil
+		}
+		return resp, err
+	}
+	resp.IsRMI = true
+	return resp, nil
+}
+
+// ListBoundNames queries the rmi registry running on given host and port
+// and returns the names of bound remote objects (Registry.list()).
+// @example
+// ```javascript
+// const rmi = require('nuclei/rmi');
+// const names = rmi.ListBoundNames('acme.com', 1099);
+// log(names);
+// ```
+func ListBoundNames(ctx context.Context, host string, port int) ([]string, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedlistBoundNames(ctx, executionId, host, port)
+}
+
+// @memo
+func listBoundNames(ctx context.Context, executionId string, host string, port int) ([]string, error) {
+	conn, err := dial(ctx, executionId, host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	if err := handshake(conn, reader); err != nil {
+		return nil, err
+	}
+
+	// call message with registry list operation
+	call := []byte{msgCall}
+	call = binary.BigEndian.AppendUint16(call, streamMagic)
+	call = binary.BigEndian.AppendUint16(call, streamVersion)
+	call = append(call, tcBlockData, 0x22)
+	call = append(call, make([]byte, 22)...) // registry ObjID (objNum 0, zero UID)
+	call = binary.BigEndian.AppendUint32(call, opList)
+	call = binary.BigEndian.AppendUint64(call, registryHash)
+	if _, err := conn.Write(call); err != nil {
+		return nil, err
+	}
+	return parseListReturn(reader)
+}
+
+// dial connects to given rmi endpoint
+func dial(ctx context.Context, executionId string, host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	return protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+}
+
+// handshake performs jrmp stream protocol handshake
+func handshake(conn net.Conn, reader *bufio.Reader) error {
+	if _, err := conn.Write(jrmpHeader); err != nil {
+		return err
+	}
+	ack, err := reader.ReadByte()
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+			return errNotRMI
+		}
+		return err
+	}
+	if ack != protocolAck {
+		return errNotRMI
+	}
+	// server echoes client endpoint as seen by it (utf host + int port)
+	if _, err := readUTF(reader); err != nil {
+		return errNotRMI
+	}
+	if _, err := io.CopyN(io.Discard, reader, 4); err != nil {
+		return errNotRMI
+	}
+	// send our endpoint (empty host, port 0)
+	_, err = conn.Write([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+	return err
+}
+
+// parseListReturn parses ReturnData message containing a String[]
+func parseListReturn(reader *bufio.Reader) ([]string, error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	if header[0] != msgReturnData || binary.BigEndian.Uint16(header[1:3]) != streamMagic || header[5] != tcBlockData {
+		return nil, errInvalidResponse
+	}
+	block := make([]byte, header[6])
+	if _, err := io.ReadFull(reader, block); err != nil {
+		return nil, err
+	}
+	if len(block) == 0 || block[0] != returnNormal {
+		return nil, errors.New("rmi registry returned an exception")
+	}
+
+	tc, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if tc == tcNull {
+		return nil, nil
+	}
+	if tc != tcArray {
+		return nil, errInvalidResponse
+	}
+	descHandles, err := skipClassDesc(reader)
+	if err != nil {
+		return nil, err
+	}
+	var size int32
+	if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size < 0 || size > 65535 {
+		return nil, errInvalidResponse
+	}
+
+	// handles of strings are needed to resolve back references
+	handles := map[uint32]string{}
+	nextHandle := uint32(baseWireHandle + descHandles + 1) // class desc and array
+	names := make([]string, 0, size)
+	for i := int32(0); i < size; i++ {
+		tc, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		switch tc {
+		case tcString:
+			name, err := readUTF(reader)
+			if err != nil {
+				return nil, err
+			}
+			handles[nextHandle] = name
+			nextHandle++
+			names = append(names, name)
+		case tcReference:
+			var handle uint32
+			if err := binary.Read(reader, binary.BigEndian, &handle); err != nil {
+				return nil, err
+			}
+			names = append(names, handles[handle])
+		case tcNull:
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected array element type 0x%x", tc)
+		}
+	}
+	return names, nil
+}
+
+// skipClassDesc skips the class descriptor of String[] array
+// and returns the number of handles assigned while reading it
+func skipClassDesc(reader *bufio.Reader) (int, error) {
+	tc, err := reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if tc == tcReference {
+		_, err := io.CopyN(io.Discard, reader, 4)
+		return 0, err
+	}
+	if tc != tcClassDesc {
+		return 0, errInvalidResponse
+	}
+	// class name, serialVersionUID, flags, field count
+	if _, err := readUTF(reader); err != nil {
+		return 0, err
+	}
+	if _, err := io.CopyN(io.Discard, reader, 8+1+2); err != nil {
+		return 0, err
+	}
+	handles := 1
+	// class annotations written by rmi marshal stream (codebase) until end block
+	for {
+		tc, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch tc {
+		case tcEndBlock:
+			// super class desc (null for arrays)
+			super, err := reader.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			if super != tcNull {
+				return 0, errInvalidResponse
+			}
+			return handles, nil
+		case tcNull:
+			continue
+		case tcString:
+			if _, err := readUTF(reader); err != nil {
+				return 0, err
+			}
+			handles++
+		default:
+			return 0, errInvalidResponse
+		}
+	}
+}
+
+// readUTF reads java modified utf-8 string with 2 byte length prefix
+func readUTF(reader *bufio.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	buff := make([]byte, length)
+	if _, err := io.ReadFull(reader, buff); err != nil {
+		return "", err
+	}
+	return string(buff), nil
+}
@@ -2,17 +2,26 @@
 package rsync
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisRsync(executionId string, host string, port int) (IsRsyncResponse, error) {
-	hash := "isRsync" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[IsRsyncResponse]("rsync.isRsync", 3)
+}
+
+func memoizedisRsync(ctx context.Context, executionId string, host string, port int) (IsRsyncResponse, error) {
+	hash := "rsync.isRsync" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "rsync.isRsync" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isRsync(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsRsyncResponse, error) {
+			return isRsync(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return IsRsyncResponse{}, err
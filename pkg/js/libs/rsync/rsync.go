@@ -2,7 +2,6 @@ package rsync
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -36,19 +35,15 @@ type (
 // ```
 func IsRsync(ctx context.Context, host string, port int) (IsRsyncResponse, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedisRsync(executionId, host, port)
+	return memoizedisRsync(ctx, executionId, host, port)
 }
 
 // @memo
-func isRsync(executionId string, host string, port int) (IsRsyncResponse, error) {
+func isRsync(ctx context.Context, executionId string, host string, port int) (IsRsyncResponse, error) {
 	resp := IsRsyncResponse{}
 
 	timeout := 5 * time.Second
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return IsRsyncResponse{}, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
 	if err != nil {
 		return resp, err
 	}
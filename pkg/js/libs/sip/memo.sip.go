@@ -0,0 +1,34 @@
+// Warning - This is generated code
+package sip
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[CheckRegisterResponse]("sip.checkRegister", 7)
+}
+
+func memoizedcheckRegister(executionId string, host string, port int, domain string, user string, password string, transport string) (CheckRegisterResponse, error) {
+	hash := "sip.checkRegister" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(domain) + ":" + fmt.Sprint(user) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(transport)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "sip.checkRegister" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(domain) + ":" + fmt.Sprint(user) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(transport)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (CheckRegisterResponse, error) {
+			return checkRegister(executionId, host, port, domain, user, password, transport)
+		})
+	})
+	if err != nil {
+		return CheckRegisterResponse{}, err
+	}
+	if value, ok := v.(CheckRegisterResponse); ok {
+		return value, nil
+	}
+
+	return CheckRegisterResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,407 @@
+package sip
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the whole registration exchange
+	registerTimeout = 5 * time.Second
+	// maximum size of a sip message
+	maxMessageSize = 64 * 1024
+	// maximum number of provisional (1xx) responses skipped
+	maxProvisionalResponses = 8
+)
+
+var (
+	errNoResponse = errors.New("no sip response received")
+)
+
+type (
+	// CheckRegisterResponse is the response from the CheckRegister function.
+	// this is returned by CheckRegister function.
+	// @example
+	// ```javascript
+	// const sip = require('nuclei/sip');
+	// const resp = sip.CheckRegister('acme.com', 5060, 'acme.com', '1000', 'secret');
+	// log(toJSON(resp));
+	// ```
+	CheckRegisterResponse struct {
+		// Valid is true if the registration was accepted with the credentials
+		Valid bool
+		// Status classifies the final response, one of:
+		// valid, no_auth_required, invalid_credentials, forbidden, not_found, unexpected
+		Status string
+		// StatusCode and Reason are the status line of the final response
+		StatusCode int
+		Reason     string
+		// Realm is the realm of the digest challenge
+		Realm string
+		// Server is the Server (or User-Agent) header of the response
+		Server string
+	}
+
+	// CheckRegisterOptions contains options for CheckRegister function.
+	// @example
+	// ```javascript
+	// const sip = require('nuclei/sip');
+	// const resp = sip.CheckRegister('acme.com', 5060, 'acme.com', '1000', 'secret', { Transport: 'tcp' });
+	// ```
+	CheckRegisterOptions struct {
+		// Transport is udp (default) or tcp
+		Transport string
+	}
+)
+
+// CheckRegister sends a REGISTER request for user@domain and answers the
+// digest challenge (401 or 407) of the registrar with given password.
+// The registration is requested with a zero expiry so no binding is kept.
+// 200 means valid credentials, a second challenge means invalid credentials
+// while 403 and 404 are returned for forbidden and unknown extensions.
+// @example
+// ```javascript
+// const sip = require('nuclei/sip');
+// const resp = sip.CheckRegister('acme.com', 5060, 'acme.com', '1000', 'secret');
+// log(resp.Valid, resp.Status, resp.Realm);
+// ```
+func CheckRegister(ctx context.Context, host string, port int, domain string, user string, password string, opts CheckRegisterOptions) (CheckRegisterResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	transport := strings.ToLower(opts.Transport)
+	if transport == "" {
+		transport = "udp"
+	}
+	if err := protocolstate.CheckTransport(transport, "udp", "tcp"); err != nil {
+		return CheckRegisterResponse{}, err
+	}
+	return memoizedcheckRegister(executionId, host, port, domain, user, password, transport)
+}
+
+// @memo
+func checkRegister(executionId string, host string, port int, domain string, user string, password string, transport string) (CheckRegisterResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return CheckRegisterResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if strings.ContainsAny(domain+user, "\r\n<>\" ") {
+		return CheckRegisterResponse{}, errors.New("domain and user cannot contain spaces, quotes, angle brackets or line breaks")
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, transport, net.JoinHostPort(host, strconv.Itoa(port)), registerTimeout)
+	if err != nil {
+		return CheckRegisterResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := &registerClient{
+		executionId: executionId,
+		conn:        conn,
+		reader:      bufio.NewReaderSize(utils.LimitConn(executionId, conn), maxMessageSize),
+		transport:   strings.ToUpper(transport),
+		uri:         "sip:" + domain,
+		aor:         fmt.Sprintf("<sip:%s@%s>", user, domain),
+		callID:      randomToken(executionId) + "@nuclei",
+		tag:         randomToken(executionId),
+	}
+	msg, err := client.register(1, "")
+	if err != nil {
+		return CheckRegisterResponse{}, err
+	}
+	resp := CheckRegisterResponse{}
+	if msg.code == 401 || msg.code == 407 {
+		challenge, ok := parseChallenge(msg.header, msg.code)
+		if !ok {
+			resp.fill(msg)
+			resp.Status = "unexpected"
+			return resp, nil
+		}
+		resp.Realm = challenge.params["realm"]
+		authorization, err := challenge.authorize(user, password, client.uri, randomToken(executionId))
+		if err != nil {
+			return CheckRegisterResponse{}, err
+		}
+		header := "Authorization"
+		if msg.code == 407 {
+			header = "Proxy-Authorization"
+		}
+		if msg, err = client.register(2, header+": "+authorization); err != nil {
+			return CheckRegisterResponse{}, err
+		}
+		resp.fill(msg)
+		resp.Status = classify(msg.code, true)
+	} else {
+		resp.fill(msg)
+		resp.Status = classify(msg.code, false)
+	}
+	resp.Valid = resp.Status == "valid"
+	return resp, nil
+}
+
+// fill sets the status line and server of resp from msg
+func (resp *CheckRegisterResponse) fill(msg *message) {
+	resp.StatusCode = msg.code
+	resp.Reason = msg.reason
+	resp.Server = msg.header.Get("Server")
+	if resp.Server == "" {
+		resp.Server = msg.header.Get("User-Agent")
+	}
+}
+
+// classify classifies the final response code of a registration
+func classify(code int, authenticated bool) string {
+	switch {
+	case code >= 200 && code < 300 && authenticated:
+		return "valid"
+	case code >= 200 && code < 300:
+		return "no_auth_required"
+	case code == 401 || code == 407:
+		return "invalid_credentials"
+	case code == 403:
+		return "forbidden"
+	case code == 404:
+		return "not_found"
+	default:
+		return "unexpected"
+	}
+}
+
+// registerClient sends REGISTER requests of a single dialog
+type registerClient struct {
+	executionId string
+	conn        net.Conn
+	reader      *bufio.Reader
+	transport   string
+	uri         string
+	aor         string
+	callID      string
+	tag         string
+}
+
+// message is a sip response
+type message struct {
+	code   int
+	reason string
+	header textproto.MIMEHeader
+}
+
+// register sends a REGISTER with given sequence number and extra header
+// and returns the final (non provisional) response to it
+func (c *registerClient) register(cseq int, extraHeader string) (*message, error) {
+	local := c.conn.LocalAddr().String()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "REGISTER %s SIP/2.0\r\n", c.uri)
+	fmt.Fprintf(&sb, "Via: SIP/2.0/%s %s;branch=z9hG4bK%s;rport\r\n", c.transport, local, randomToken(c.executionId))
+	sb.WriteString("Max-Forwards: 70\r\n")
+	fmt.Fprintf(&sb, "From: %s;tag=%s\r\n", c.aor, c.tag)
+	fmt.Fprintf(&sb, "To: %s\r\n", c.aor)
+	fmt.Fprintf(&sb, "Call-ID: %s\r\n", c.callID)
+	fmt.Fprintf(&sb, "CSeq: %d REGISTER\r\n", cseq)
+	fmt.Fprintf(&sb, "Contact: <sip:nuclei@%s;transport=%s>\r\n", local, strings.ToLower(c.transport))
+	// zero expiry does not keep a binding so calls are never diverted to us
+	sb.WriteString("Expires: 0\r\n")
+	if extraHeader != "" {
+		sb.WriteString(extraHeader + "\r\n")
+	}
+	sb.WriteString("User-Agent: nuclei\r\n")
+	sb.WriteString("Content-Length: 0\r\n\r\n")
+	if _, err := c.conn.Write([]byte(sb.String())); err != nil {
+		return nil, err
+	}
+
+	expectedCSeq := fmt.Sprintf("%d REGISTER", cseq)
+	for i := 0; i < maxProvisionalResponses; {
+		msg, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		// ignore responses to other requests (ex: retransmissions)
+		if !strings.EqualFold(strings.Join(strings.Fields(msg.header.Get("CSeq")), " "), expectedCSeq) {
+			continue
+		}
+		if msg.code >= 200 {
+			return msg, nil
+		}
+		i++
+	}
+	return nil, errNoResponse
+}
+
+// read reads a sip response from the connection
+func (c *registerClient) read() (*message, error) {
+	reader := textproto.NewReader(c.reader)
+	line, err := reader.ReadLine()
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, errNoResponse
+		}
+		return nil, err
+	}
+	version, status, _ := strings.Cut(line, " ")
+	if version != "SIP/2.0" {
+		return nil, fmt.Errorf("invalid sip status line %q", line)
+	}
+	codeValue, reason, _ := strings.Cut(status, " ")
+	code, err := strconv.Atoi(codeValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sip status line %q", line)
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	// skip the body (l is the compact form of Content-Length)
+	length := header.Get("Content-Length")
+	if length == "" {
+		length = header.Get("L")
+	}
+	if size, _ := strconv.Atoi(strings.TrimSpace(length)); size > 0 && size <= maxMessageSize {
+		if _, err := c.reader.Discard(size); err != nil {
+			return nil, err
+		}
+	}
+	return &message{code: code, reason: reason, header: header}, nil
+}
+
+// challenge is a digest authentication challenge
+type challenge struct {
+	params map[string]string
+}
+
+// parseChallenge returns the first digest challenge (of a supported
+// algorithm) of a 401 or 407 response
+func parseChallenge(header textproto.MIMEHeader, code int) (*challenge, bool) {
+	name := "Www-Authenticate"
+	if code == 407 {
+		name = "Proxy-Authenticate"
+	}
+	for _, value := range header.Values(name) {
+		scheme, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+		params := parseParams(rest)
+		if params["nonce"] == "" {
+			continue
+		}
+		if _, ok := digestHash(params["algorithm"]); !ok {
+			continue
+		}
+		return &challenge{params: params}, true
+	}
+	return nil, false
+}
+
+// parseParams parses comma separated key=value pairs with optionally quoted values
+func parseParams(value string) map[string]string {
+	params := map[string]string{}
+	for value != "" {
+		value = strings.TrimLeft(value, " ,\t")
+		key, rest, found := strings.Cut(value, "=")
+		if !found {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimLeft(rest, " \t")
+		var val string
+		if strings.HasPrefix(rest, "\"") {
+			end := 1
+			var sb strings.Builder
+			for ; end < len(rest) && rest[end] != '"'; end++ {
+				if rest[end] == '\\' && end+1 < len(rest) {
+					end++
+				}
+				sb.WriteByte(rest[end])
+			}
+			val = sb.String()
+			value = rest[min(end+1, len(rest)):]
+		} else {
+			val, value, _ = strings.Cut(rest, ",")
+			val = strings.TrimSpace(val)
+		}
+		params[key] = val
+	}
+	return params
+}
+
+// digestHash returns the hash function of a digest algorithm (default: MD5)
+func digestHash(algorithm string) (func() hash.Hash, bool) {
+	switch strings.ToUpper(strings.TrimSuffix(strings.ToLower(algorithm), "-sess")) {
+	case "", "MD5":
+		return md5.New, true
+	case "SHA-256":
+		return sha256.New, true
+	}
+	return nil, false
+}
+
+// authorize returns the value of the authorization header answering the
+// challenge for a REGISTER of uri (rfc 2617 and rfc 8760) using cnonce
+func (c *challenge) authorize(user string, password string, uri string, cnonce string) (string, error) {
+	algorithm := c.params["algorithm"]
+	newHash, _ := digestHash(algorithm)
+	digest := func(parts ...string) string {
+		h := newHash()
+		h.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+	realm, nonce := c.params["realm"], c.params["nonce"]
+
+	ha1 := digest(user, realm, password)
+	if strings.HasSuffix(strings.ToLower(algorithm), "-sess") {
+		ha1 = digest(ha1, nonce, cnonce)
+	}
+	ha2 := digest("REGISTER", uri)
+
+	var qop string
+	for _, value := range strings.Split(c.params["qop"], ",") {
+		if strings.EqualFold(strings.TrimSpace(value), "auth") {
+			qop = "auth"
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `Digest username="%s", realm="%s", nonce="%s", uri="%s"`, user, quote(realm), quote(nonce), uri)
+	if qop != "" {
+		const nc = "00000001"
+		fmt.Fprintf(&sb, `, response="%s", qop=%s, nc=%s, cnonce="%s"`, digest(ha1, nonce, nc, cnonce, qop, ha2), qop, nc, cnonce)
+	} else {
+		fmt.Fprintf(&sb, `, response="%s"`, digest(ha1, nonce, ha2))
+	}
+	if algorithm != "" {
+		fmt.Fprintf(&sb, ", algorithm=%s", algorithm)
+	}
+	if opaque, ok := c.params["opaque"]; ok {
+		fmt.Fprintf(&sb, `, opaque="%s"`, quote(opaque))
+	}
+	if strings.ContainsAny(sb.String(), "\r\n") {
+		return "", errors.New("invalid digest challenge")
+	}
+	return sb.String(), nil
+}
+
+// quote escapes quotes and backslashes of a quoted-string value
+func quote(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// randomToken returns a random hex token (seeded by the execution probe seed)
+func randomToken(executionId string) string {
+	b := make([]byte, 8)
+	protocolstate.RandomBytes(executionId, b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,136 @@
+package sip
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func md5Hex(value string) string {
+	sum := md5.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// registrarResponse returns the response of a test registrar accepting
+// extension 1000 with password secret using qop=auth digest
+func registrarResponse(request string) string {
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(request)))
+	_, _ = reader.ReadLine()
+	header, _ := reader.ReadMIMEHeader()
+	status := "401 Unauthorized"
+	if strings.Contains(header.Get("To"), "sip:9999@") {
+		status = "404 Not Found"
+	} else if authorization := header.Get("Authorization"); authorization != "" {
+		params := parseParams(strings.TrimPrefix(authorization, "Digest "))
+		ha1 := md5Hex(params["username"] + ":acme:secret")
+		ha2 := md5Hex("REGISTER:" + params["uri"])
+		expected := md5Hex(strings.Join([]string{ha1, "n0nce", params["nc"], params["cnonce"], params["qop"], ha2}, ":"))
+		if params["response"] == expected && params["opaque"] == "0paque" {
+			status = "200 OK"
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("SIP/2.0 100 Trying\r\n")
+	fmt.Fprintf(&sb, "CSeq: %s\r\nContent-Length: 0\r\n\r\n", header.Get("CSeq"))
+	fmt.Fprintf(&sb, "SIP/2.0 %s\r\n", status)
+	fmt.Fprintf(&sb, "CSeq: %s\r\nCall-ID: %s\r\nServer: test-pbx\r\n", header.Get("CSeq"), header.Get("Call-ID"))
+	if strings.HasPrefix(status, "401") {
+		sb.WriteString(`WWW-Authenticate: Digest realm="acme", nonce="n0nce", qop="auth,auth-int", opaque="0paque", algorithm=MD5` + "\r\n")
+	}
+	sb.WriteString("Content-Length: 0\r\n\r\n")
+	return sb.String()
+}
+
+func udpRegistrar(t *testing.T) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buff := make([]byte, maxMessageSize)
+		for {
+			n, addr, err := conn.ReadFrom(buff)
+			if err != nil {
+				return
+			}
+			_, _ = conn.WriteTo([]byte(registrarResponse(string(buff[:n]))), addr)
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func tcpRegistrar(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer func() { _ = conn.Close() }()
+				reader := bufio.NewReader(conn)
+				for {
+					var request strings.Builder
+					for {
+						line, err := reader.ReadString('\n')
+						if err != nil {
+							return
+						}
+						request.WriteString(line)
+						if line == "\r\n" {
+							break
+						}
+					}
+					_, _ = conn.Write([]byte(registrarResponse(request.String())))
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestCheckRegister(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	for transport, port := range map[string]int{"udp": udpRegistrar(t), "tcp": tcpRegistrar(t)} {
+		for _, tc := range []struct {
+			user, password, status string
+			code                   int
+		}{
+			{"1000", "secret", "valid", 200},
+			{"1000", "wrong", "invalid_credentials", 401},
+			{"9999", "secret", "not_found", 404},
+		} {
+			resp, err := CheckRegister(ctx, "127.0.0.1", port, "acme.com", tc.user, tc.password, CheckRegisterOptions{Transport: transport})
+			if err != nil {
+				t.Fatalf("%s %s:%s: %v", transport, tc.user, tc.password, err)
+			}
+			if resp.Status != tc.status || resp.StatusCode != tc.code || resp.Valid != (tc.status == "valid") {
+				t.Fatalf("%s %s:%s: unexpected response %+v", transport, tc.user, tc.password, resp)
+			}
+			if tc.code != 404 && (resp.Realm != "acme" || resp.Server != "test-pbx") {
+				t.Fatalf("%s: expected realm and server to be set, got %+v", transport, resp)
+			}
+		}
+	}
+	if _, err := CheckRegister(ctx, "127.0.0.1", 5060, "acme.com", "1000", "secret", CheckRegisterOptions{Transport: "sctp"}); !errors.Is(err, protocolstate.ErrUnsupportedTransport) {
+		t.Fatalf("expected unsupported transport error, got %v", err)
+	}
+}
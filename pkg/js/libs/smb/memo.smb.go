@@ -10,11 +10,19 @@ import (
 	"github.com/zmap/zgrab2/lib/smb/smb"
 )
 
+func init() {
+	protocolstate.RegisterMemoized[*smb.SMBLog]("smb.connectSMBInfoMode", 3)
+}
+
 func memoizedconnectSMBInfoMode(executionId string, host string, port int) (*smb.SMBLog, error) {
-	hash := "connectSMBInfoMode" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	hash := "smb.connectSMBInfoMode" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "smb.connectSMBInfoMode" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return connectSMBInfoMode(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (*smb.SMBLog, error) {
+			return connectSMBInfoMode(executionId, host, port)
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -26,9 +34,13 @@ func memoizedconnectSMBInfoMode(executionId string, host string, port int) (*smb
 	return nil, errors.New("could not convert cached result")
 }
 
-func memoizedlistShares(executionId string, host string, port int, user string, password string) ([]string, error) {
-	hash := "listShares" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(user) + ":" + fmt.Sprint(password)
+func init() {
+	protocolstate.RegisterMemoized[[]string]("smb.listShares", 5)
+}
 
+func memoizedlistShares(executionId string, host string, port int, user string, password string) ([]string, error) {
+	hash := "smb.listShares" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(user) + ":" + fmt.Sprint(password)
+	// results are only memoized in memory (nodisk)
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
 		return listShares(executionId, host, port, user, password)
 	})
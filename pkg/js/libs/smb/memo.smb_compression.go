@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package smb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[CompressionSupport]("smb.supportsCompression", 3)
+}
+
+func memoizedsupportsCompression(executionId string, host string, port int) (CompressionSupport, error) {
+	hash := "smb.supportsCompression" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "smb.supportsCompression" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (CompressionSupport, error) {
+			return supportsCompression(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return CompressionSupport{}, err
+	}
+	if value, ok := v.(CompressionSupport); ok {
+		return value, nil
+	}
+
+	return CompressionSupport{}, errors.New("could not convert cached result")
+}
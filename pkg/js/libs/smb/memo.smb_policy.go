@@ -0,0 +1,34 @@
+// Warning - This is generated code
+package smb
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[SecurityPolicy]("smb.getSecurityPolicy", 3)
+}
+
+func memoizedgetSecurityPolicy(executionId string, host string, port int) (SecurityPolicy, error) {
+	hash := "smb.getSecurityPolicy" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "smb.getSecurityPolicy" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (SecurityPolicy, error) {
+			return getSecurityPolicy(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return SecurityPolicy{}, err
+	}
+	if value, ok := v.(SecurityPolicy); ok {
+		return value, nil
+	}
+
+	return SecurityPolicy{}, errors.New("could not convert cached result")
+}
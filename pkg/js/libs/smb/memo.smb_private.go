@@ -2,7 +2,9 @@
 package smb
 
 import (
+	"context"
 	"errors"
+
 	"fmt"
 
 	"time"
@@ -12,11 +14,19 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedcollectSMBv2Metadata(executionId string, host string, port int, timeout time.Duration) (*plugins.ServiceSMB, error) {
-	hash := "collectSMBv2Metadata" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(timeout)
+func init() {
+	protocolstate.RegisterMemoized[*plugins.ServiceSMB]("smb.collectSMBv2Metadata", 4)
+}
+
+func memoizedcollectSMBv2Metadata(ctx context.Context, executionId string, host string, port int, timeout time.Duration) (*plugins.ServiceSMB, error) {
+	hash := "smb.collectSMBv2Metadata" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(timeout)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "smb.collectSMBv2Metadata" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(timeout)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return collectSMBv2Metadata(executionId, host, port, timeout)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (*plugins.ServiceSMB, error) {
+			return collectSMBv2Metadata(ctx, executionId, host, port, timeout)
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,30 @@
+// Warning - This is generated code
+package smb
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[ListSharesInfoResponse]("smb.listSharesInfo", 6)
+}
+
+func memoizedlistSharesInfo(executionId string, host string, port int, user string, password string, partialOnTimeout bool) (ListSharesInfoResponse, error) {
+	hash := "smb.listSharesInfo" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(user) + ":" + fmt.Sprint(password) + ":" + fmt.Sprint(partialOnTimeout)
+	// results are only memoized in memory (nodisk)
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return listSharesInfo(executionId, host, port, user, password, partialOnTimeout)
+	})
+	if err != nil {
+		return ListSharesInfoResponse{}, err
+	}
+	if value, ok := v.(ListSharesInfoResponse); ok {
+		return value, nil
+	}
+
+	return ListSharesInfoResponse{}, errors.New("could not convert cached result")
+}
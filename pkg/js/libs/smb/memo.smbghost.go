@@ -8,11 +8,19 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
+func init() {
+	protocolstate.RegisterMemoized[bool]("smb.detectSMBGhost", 3)
+}
+
 func memoizeddetectSMBGhost(executionId string, host string, port int) (bool, error) {
-	hash := "detectSMBGhost" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	hash := "smb.detectSMBGhost" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "smb.detectSMBGhost" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return detectSMBGhost(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (bool, error) {
+			return detectSMBGhost(executionId, host, port)
+		})
 	})
 	if err != nil {
 		return false, err
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
-	"github.com/projectdiscovery/go-smb2"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/zmap/zgrab2/lib/smb/smb"
 )
@@ -45,14 +44,15 @@ func connectSMBInfoMode(executionId string, host string, port int) (*smb.SMBLog,
 		// host is not valid according to network policy
 		return nil, protocolstate.ErrHostDenied.Msgf(host)
 	}
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
 	}
 	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
 	if err != nil {
 		return nil, err
 	}
+	conn = protocolstate.RecordConn(executionId, conn)
 	// try to get SMBv2/v3 info
 	result, err := getSMBInfo(conn, true, false)
 	_ = conn.Close() // close regardless of error
@@ -65,6 +65,7 @@ func connectSMBInfoMode(executionId string, host string, port int) (*smb.SMBLog,
 	if err != nil {
 		return nil, err
 	}
+	conn = protocolstate.RecordConn(executionId, conn)
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -92,7 +93,7 @@ func (c *SMBClient) ListSMBv2Metadata(ctx context.Context, host string, port int
 		// host is not valid according to network policy
 		return nil, protocolstate.ErrHostDenied.Msgf(host)
 	}
-	return memoizedcollectSMBv2Metadata(executionId, host, port, 5*time.Second)
+	return memoizedcollectSMBv2Metadata(ctx, executionId, host, port, 5*time.Second)
 }
 
 // ListShares tries to connect to provided host and port
@@ -115,37 +116,19 @@ func (c *SMBClient) ListShares(ctx context.Context, host string, port int, user,
 	return memoizedlistShares(executionId, host, port, user, password)
 }
 
-// @memo
+// @memo(nodisk)
 func listShares(executionId string, host string, port int, user string, password string) ([]string, error) {
 	if !protocolstate.IsHostAllowed(executionId, host) {
 		// host is not valid according to network policy
 		return nil, protocolstate.ErrHostDenied.Msgf(host)
 	}
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", fmt.Sprintf("%s:%d", host, port))
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = conn.Close()
-	}()
-
-	d := &smb2.Dialer{
-		Initiator: &smb2.NTLMInitiator{
-			User:     user,
-			Password: password,
-		},
-	}
-	s, err := d.Dial(conn)
+	conn, s, err := newSMBSession(executionId, host, port, user, password, sessionTimeout)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
 		_ = s.Logoff()
+		_ = conn.Close()
 	}()
 
 	names, err := s.ListSharenames()
@@ -0,0 +1,217 @@
+package smb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// smb2 negotiate context types
+	preauthIntegrityContext = 0x0001
+	compressionContext      = 0x0003
+	// sha-512 preauth integrity hash algorithm
+	preauthHashSHA512 = 0x0001
+)
+
+var (
+	// compression algorithms offered in the compression negotiate context
+	compressionAlgorithms = map[uint16]string{
+		0x0001: "LZNT1",
+		0x0002: "LZ77",
+		0x0003: "LZ77+Huffman",
+		0x0004: "Pattern_V1",
+		0x0005: "LZ4",
+	}
+)
+
+type (
+	// CompressionSupport is the smb 3.1.1 compression support of a smb server.
+	// this is returned by SupportsCompression function.
+	// @example
+	// ```javascript
+	// const smb = require('nuclei/smb');
+	// const client = new smb.SMBClient();
+	// const compression = client.SupportsCompression('acme.com', 445);
+	// log(toJSON(compression));
+	// ```
+	CompressionSupport struct {
+		// CompressionSupported is true if the server answered with a compression negotiate context
+		CompressionSupported bool
+		// Algorithms are the compression algorithms advertised by the server (ex: LZNT1)
+		Algorithms []string
+		// Dialect is the smb2 dialect selected by the server (ex: 3.1.1)
+		Dialect string
+	}
+)
+
+// SupportsCompression negotiates smb 3.1.1 with a preauth integrity and a
+// compression negotiate context offering all compression algorithms and
+// returns whether the server advertised compression along with the accepted
+// algorithms. Compression support is a precondition of SMBGhost (CVE-2020-0796)
+// and does not imply the server is vulnerable.
+// @example
+// ```javascript
+// const smb = require('nuclei/smb');
+// const client = new smb.SMBClient();
+// const compression = client.SupportsCompression('acme.com', 445);
+// if (compression.CompressionSupported) { log(compression.Algorithms); }
+// ```
+func (c *SMBClient) SupportsCompression(ctx context.Context, host string, port int) (CompressionSupport, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedsupportsCompression(executionId, host, port)
+}
+
+// @memo
+func supportsCompression(executionId string, host string, port int) (CompressionSupport, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return CompressionSupport{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), negotiateTimeout)
+	if err != nil {
+		return CompressionSupport{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	data, err := negotiateSMB2Request(conn, newCompressionNegotiate(executionId))
+	if err != nil {
+		return CompressionSupport{}, err
+	}
+	return parseCompressionNegotiate(data)
+}
+
+// newCompressionNegotiate returns a smb2 negotiate request (with netbios
+// session header) offering 3.1.1 with preauth integrity and compression contexts
+func newCompressionNegotiate(executionId string) []byte {
+	header := make([]byte, 64)
+	copy(header, "\xfeSMB")
+	binary.LittleEndian.PutUint16(header[4:6], 64)   // structure size
+	binary.LittleEndian.PutUint16(header[14:16], 31) // credits requested
+
+	dialects := []uint16{0x0202, 0x0210, 0x0300, 0x0302, 0x0311}
+	body := make([]byte, 36, 36+2*len(dialects))
+	binary.LittleEndian.PutUint16(body[0:2], 36)
+	binary.LittleEndian.PutUint16(body[2:4], uint16(len(dialects)))
+	binary.LittleEndian.PutUint16(body[4:6], smb2SigningEnabled)
+	protocolstate.RandomBytes(executionId, body[12:28]) // client guid
+	for _, dialect := range dialects {
+		body = binary.LittleEndian.AppendUint16(body, dialect)
+	}
+
+	salt := make([]byte, 32)
+	protocolstate.RandomBytes(executionId, salt)
+	preauth := binary.LittleEndian.AppendUint16(nil, 1)
+	preauth = binary.LittleEndian.AppendUint16(preauth, uint16(len(salt)))
+	preauth = binary.LittleEndian.AppendUint16(preauth, preauthHashSHA512)
+	preauth = append(preauth, salt...)
+
+	compression := binary.LittleEndian.AppendUint16(nil, uint16(len(compressionAlgorithms)))
+	compression = append(compression, make([]byte, 6)...) // padding and flags
+	for id := uint16(1); id <= uint16(len(compressionAlgorithms)); id++ {
+		compression = binary.LittleEndian.AppendUint16(compression, id)
+	}
+
+	// negotiate contexts are 8 byte aligned from the start of the smb2 header
+	message := append(header, body...)
+	message = append(message, make([]byte, padding(len(message)))...)
+	binary.LittleEndian.PutUint32(message[64+28:64+32], uint32(len(message))) // contexts offset
+	binary.LittleEndian.PutUint16(message[64+32:64+34], 2)                    // contexts count
+	message = appendNegotiateContext(message, preauthIntegrityContext, preauth)
+	message = append(message, make([]byte, padding(len(message)))...)
+	message = appendNegotiateContext(message, compressionContext, compression)
+
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(message))), message...)
+}
+
+// appendNegotiateContext appends a negotiate context of given type
+func appendNegotiateContext(message []byte, contextType uint16, data []byte) []byte {
+	message = binary.LittleEndian.AppendUint16(message, contextType)
+	message = binary.LittleEndian.AppendUint16(message, uint16(len(data)))
+	message = append(message, 0x00, 0x00, 0x00, 0x00)
+	return append(message, data...)
+}
+
+// padding returns the number of bytes aligning length to 8 bytes
+func padding(length int) int {
+	return (8 - length%8) % 8
+}
+
+// parseCompressionNegotiate parses the compression negotiate context of a
+// smb2 negotiate response
+func parseCompressionNegotiate(data []byte) (CompressionSupport, error) {
+	if len(data) < 128 || !bytes.Equal(data[:4], []byte("\xfeSMB")) {
+		return CompressionSupport{}, errNotSMB
+	}
+	if status := binary.LittleEndian.Uint32(data[8:12]); status != 0 {
+		return CompressionSupport{}, fmt.Errorf("smb2 negotiate failed with status 0x%08x", status)
+	}
+	dialect := binary.LittleEndian.Uint16(data[68:70])
+	resp := CompressionSupport{Dialect: dialectName(dialect)}
+	if dialect != 0x0311 {
+		// negotiate contexts are only sent for 3.1.1
+		return resp, nil
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[70:72]))
+	offset := int(binary.LittleEndian.Uint32(data[124:128]))
+	for i := 0; i < count; i++ {
+		offset += padding(offset)
+		if offset+8 > len(data) {
+			return resp, malformedNegotiate("negotiate context %d out of bounds", i)
+		}
+		contextType := binary.LittleEndian.Uint16(data[offset : offset+2])
+		length := int(binary.LittleEndian.Uint16(data[offset+2 : offset+4]))
+		offset += 8
+		if offset+length > len(data) {
+			return resp, malformedNegotiate("negotiate context 0x%04x length %d out of bounds", contextType, length)
+		}
+		if contextType == compressionContext {
+			algorithms, err := parseCompressionContext(data[offset : offset+length])
+			if err != nil {
+				return resp, err
+			}
+			resp.Algorithms = algorithms
+			resp.CompressionSupported = len(algorithms) > 0
+		}
+		offset += length
+	}
+	return resp, nil
+}
+
+// parseCompressionContext returns the algorithms of a compression negotiate context
+func parseCompressionContext(data []byte) ([]string, error) {
+	if len(data) < 8 {
+		return nil, malformedNegotiate("compression context too short (%d bytes)", len(data))
+	}
+	count := int(binary.LittleEndian.Uint16(data[0:2]))
+	if 8+2*count > len(data) {
+		return nil, malformedNegotiate("compression context with %d algorithms out of bounds", count)
+	}
+	var algorithms []string
+	for i := 0; i < count; i++ {
+		id := binary.LittleEndian.Uint16(data[8+2*i : 10+2*i])
+		if id == 0 {
+			// NONE is sent when no offered algorithm is supported
+			continue
+		}
+		name, ok := compressionAlgorithms[id]
+		if !ok {
+			name = fmt.Sprintf("0x%04x", id)
+		}
+		algorithms = append(algorithms, name)
+	}
+	return algorithms, nil
+}
+
+// malformedNegotiate returns an error for an invalid negotiate response
+func malformedNegotiate(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid smb2 negotiate response: "+format, args...)
+}
@@ -0,0 +1,110 @@
+package smb
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// smb2CompressionFixture returns a smb2 negotiate response with a preauth
+// integrity context and a compression context with given algorithms (if any)
+func smb2CompressionFixture(dialect uint16, algorithms ...uint16) []byte {
+	data := smb2NegotiateFixture(smb2SigningEnabled, dialect)
+	data = append(data, make([]byte, padding(len(data)))...)
+	binary.LittleEndian.PutUint32(data[124:128], uint32(len(data)))
+
+	preauth := []byte{0x01, 0x00, 0x20, 0x00, 0x01, 0x00}
+	preauth = append(preauth, make([]byte, 32)...)
+	data = appendNegotiateContext(data, preauthIntegrityContext, preauth)
+	count := uint16(1)
+	if len(algorithms) > 0 {
+		compression := binary.LittleEndian.AppendUint16(nil, uint16(len(algorithms)))
+		compression = append(compression, make([]byte, 6)...)
+		for _, algorithm := range algorithms {
+			compression = binary.LittleEndian.AppendUint16(compression, algorithm)
+		}
+		data = append(data, make([]byte, padding(len(data)))...)
+		data = appendNegotiateContext(data, compressionContext, compression)
+		count++
+	}
+	binary.LittleEndian.PutUint16(data[70:72], count)
+	return data
+}
+
+func TestSupportsCompression(t *testing.T) {
+	ctx := jstest.Context(t)
+	client := &SMBClient{}
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     CompressionSupport
+	}{
+		{
+			name:     "windows 10 1903",
+			response: smb2CompressionFixture(0x0311, 0x0001),
+			want:     CompressionSupport{CompressionSupported: true, Algorithms: []string{"LZNT1"}, Dialect: "3.1.1"},
+		},
+		{
+			name:     "multiple algorithms",
+			response: smb2CompressionFixture(0x0311, 0x0002, 0x0004, 0x0009),
+			want:     CompressionSupport{CompressionSupported: true, Algorithms: []string{"LZ77", "Pattern_V1", "0x0009"}, Dialect: "3.1.1"},
+		},
+		{
+			name:     "no compression context",
+			response: smb2CompressionFixture(0x0311),
+			want:     CompressionSupport{Dialect: "3.1.1"},
+		},
+		{
+			name:     "no supported algorithm",
+			response: smb2CompressionFixture(0x0311, 0x0000),
+			want:     CompressionSupport{Dialect: "3.1.1"},
+		},
+		{
+			name:     "smb 2.1",
+			response: smb2NegotiateFixture(smb2SigningEnabled, 0x0210),
+			want:     CompressionSupport{Dialect: "2.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port := smbListener(t, append(tt.response, make([]byte, 128-min(len(tt.response), 128))...), nil)
+			got, err := client.SupportsCompression(ctx, host, port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	// truncated compression context
+	data := smb2CompressionFixture(0x0311, 0x0001)
+	if _, err := parseCompressionNegotiate(data[:len(data)-4]); err == nil {
+		t.Fatal("expected truncated negotiate context to be rejected")
+	}
+}
+
+func TestNewCompressionNegotiate(t *testing.T) {
+	request := newCompressionNegotiate("")
+	if got := int(binary.BigEndian.Uint32(request[:4])); got != len(request)-4 {
+		t.Fatalf("expected netbios length %d, got %d", len(request)-4, got)
+	}
+	message := request[4:]
+	offset := int(binary.LittleEndian.Uint32(message[64+28 : 64+32]))
+	if count := binary.LittleEndian.Uint16(message[64+32 : 64+34]); count != 2 || offset%8 != 0 {
+		t.Fatalf("expected 2 aligned negotiate contexts, got %d at offset %d", count, offset)
+	}
+	var types []uint16
+	for offset < len(message) {
+		offset += padding(offset)
+		types = append(types, binary.LittleEndian.Uint16(message[offset:offset+2]))
+		offset += 8 + int(binary.LittleEndian.Uint16(message[offset+2:offset+4]))
+	}
+	if !reflect.DeepEqual(types, []uint16{preauthIntegrityContext, compressionContext}) {
+		t.Fatalf("unexpected negotiate contexts %v", types)
+	}
+}
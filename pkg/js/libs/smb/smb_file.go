@@ -0,0 +1,124 @@
+package smb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// defaultMaxFileSize is the default maximum size of a file read from a share
+	defaultMaxFileSize = 10 * 1024 * 1024
+	// fileReadTimeout is the time allowed to mount the share and read the
+	// file after the session is established
+	fileReadTimeout = 30 * time.Second
+)
+
+type (
+	// ReadFileOptions contains options for the ReadFile function.
+	// @example
+	// ```javascript
+	// const smb = require('nuclei/smb');
+	// const client = new smb.SMBClient();
+	// const file = client.ReadFile('acme.com', 445, 'Users', 'Public/config.ini', 'username', 'password', { MaxSize: 1048576 });
+	// ```
+	ReadFileOptions struct {
+		// MaxSize is the maximum size of file (in bytes) that will be read
+		// defaults to 10MB.
+		MaxSize int64
+	}
+
+	// ReadFileResponse is the response from the ReadFile function.
+	// this is returned by ReadFile function.
+	// @example
+	// ```javascript
+	// const smb = require('nuclei/smb');
+	// const client = new smb.SMBClient();
+	// const file = client.ReadFile('acme.com', 445, 'Users', 'Public/config.ini', 'username', 'password', {});
+	// log(file.Size);
+	// ```
+	ReadFileResponse struct {
+		// Data contains the content of the file
+		Data []byte
+		// Size is the size of the file in bytes
+		Size int64
+	}
+)
+
+// ReadFile tries to connect to provided host and port, mounts the given share
+// and reads the file at given path (relative to share root). Files larger than
+// MaxSize option (default 10MB) are not read and an error is returned.
+// null sessions can be tried by using empty username and password
+// and guest sessions by using 'guest' username with empty password.
+// File contents are not memoized, so they are never written to the
+// -js-cache-dir cache.
+// @example
+// ```javascript
+// const smb = require('nuclei/smb');
+// const client = new smb.SMBClient();
+// const file = client.ReadFile('acme.com', 445, 'Users', 'Public/config.ini', 'username', 'password', {});
+// log(ToString(file.Data));
+// ```
+func (c *SMBClient) ReadFile(ctx context.Context, host string, port int, share string, path string, user string, password string, opts ReadFileOptions) (ReadFileResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return readFile(executionId, host, port, share, path, user, password, opts.MaxSize)
+}
+
+// readFile reads the file at path from share
+func readFile(executionId string, host string, port int, share string, path string, user string, password string, maxSize int64) (ReadFileResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return ReadFileResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if maxSize <= 0 {
+		maxSize = defaultMaxFileSize
+	}
+	conn, s, err := newSMBSession(executionId, host, port, user, password, sessionTimeout)
+	if err != nil {
+		return ReadFileResponse{}, err
+	}
+	defer func() {
+		_ = s.Logoff()
+		_ = conn.Close()
+	}()
+	if err := conn.SetDeadline(protocolstate.GetDeadline(executionId, fileReadTimeout)); err != nil {
+		return ReadFileResponse{}, err
+	}
+
+	fs, err := s.Mount(fmt.Sprintf(`\\%s\%s`, host, strings.Trim(share, `\/`)))
+	if err != nil {
+		return ReadFileResponse{}, err
+	}
+	defer func() {
+		_ = fs.Umount()
+	}()
+
+	f, err := fs.Open(strings.TrimLeft(path, `\/`))
+	if err != nil {
+		return ReadFileResponse{}, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return ReadFileResponse{}, err
+	}
+	if stat.IsDir() {
+		return ReadFileResponse{}, fmt.Errorf("%s is a directory", path)
+	}
+	if stat.Size() > maxSize {
+		return ReadFileResponse{}, fmt.Errorf("file size %d exceeds max size %d", stat.Size(), maxSize)
+	}
+	// bound the read in case file grows after stat
+	data, err := io.ReadAll(io.LimitReader(f, maxSize))
+	if err != nil {
+		return ReadFileResponse{}, err
+	}
+	return ReadFileResponse{Data: data, Size: stat.Size()}, nil
+}
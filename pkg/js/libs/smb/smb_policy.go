@@ -0,0 +1,225 @@
+package smb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of each negotiate exchange
+	negotiateTimeout = 5 * time.Second
+
+	// smb2 negotiate response security mode flags
+	smb2SigningEnabled  = 0x01
+	smb2SigningRequired = 0x02
+	// smb1 negotiate response security mode flags
+	smb1SigningEnabled  = 0x04
+	smb1SigningRequired = 0x08
+)
+
+var (
+	// timeout of the whole policy probe (negotiates and session attempts)
+	policyTimeout = 20 * time.Second
+
+	// smb1 negotiate request only offering the NT LM 0.12 dialect
+	smb1NegotiateRequest = []byte{
+		0x00, 0x00, 0x00, 0x2f,
+		0xff, 'S', 'M', 'B', 0x72, 0x00, 0x00, 0x00, 0x00, 0x18, 0x01, 0xc8,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0xfe, 0xff, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x0c, 0x00,
+		0x02, 'N', 'T', ' ', 'L', 'M', ' ', '0', '.', '1', '2', 0x00,
+	}
+
+	errNotSMB = errors.New("not a smb server")
+)
+
+type (
+	// SecurityPolicy is the security policy of a smb server.
+	// this is returned by GetSecurityPolicy function.
+	// @example
+	// ```javascript
+	// const smb = require('nuclei/smb');
+	// const client = new smb.SMBClient();
+	// const policy = client.GetSecurityPolicy('acme.com', 445);
+	// log(toJSON(policy));
+	// ```
+	SecurityPolicy struct {
+		// SigningEnabled is true if the server supports message signing
+		SigningEnabled bool
+		// SigningRequired is true if the server requires message signing (not relayable)
+		SigningRequired bool
+		// GuestAuthAllowed is true if a session can be established as guest with a blank password
+		GuestAuthAllowed bool
+		// NullSessionAllowed is true if an anonymous session can be established
+		NullSessionAllowed bool
+		// SMBv1Enabled is true if the server accepts the NT LM 0.12 (smb1) dialect
+		SMBv1Enabled bool
+		// Dialect is the smb2 dialect selected by the server (ex: 3.1.1)
+		Dialect string
+	}
+)
+
+// GetSecurityPolicy returns the signing, guest, null session and smb1
+// policy of the smb server in a single set of handshakes.
+// Signing flags are taken from the smb2 negotiate response (or the smb1
+// one for smb1 only servers) and sessions are only attempted over smb2.
+// @example
+// ```javascript
+// const smb = require('nuclei/smb');
+// const client = new smb.SMBClient();
+// const policy = client.GetSecurityPolicy('acme.com', 445);
+// if (!policy.SigningRequired) { log('signing not required'); }
+// ```
+func (c *SMBClient) GetSecurityPolicy(ctx context.Context, host string, port int) (SecurityPolicy, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetSecurityPolicy(executionId, host, port)
+}
+
+// @memo
+func getSecurityPolicy(executionId string, host string, port int) (SecurityPolicy, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return SecurityPolicy{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	policy := SecurityPolicy{}
+	// a single deadline bounds all the handshakes of the probe
+	deadline := protocolstate.GetDeadline(executionId, policyTimeout)
+
+	smb2Mode, dialect, smb2Err := negotiateSMB2SecurityMode(executionId, address, untilDeadline(deadline, negotiateTimeout))
+	smb1Mode, smb1Err := negotiateSMB1SecurityMode(executionId, address, untilDeadline(deadline, negotiateTimeout))
+	switch {
+	case smb2Err == nil:
+		policy.SigningEnabled = smb2Mode&smb2SigningEnabled != 0
+		policy.SigningRequired = smb2Mode&smb2SigningRequired != 0
+		policy.Dialect = dialect
+	case smb1Err == nil:
+		policy.SigningEnabled = smb1Mode&smb1SigningEnabled != 0
+		policy.SigningRequired = smb1Mode&smb1SigningRequired != 0
+	default:
+		return SecurityPolicy{}, smb2Err
+	}
+	policy.SMBv1Enabled = smb1Err == nil
+	if smb2Err != nil {
+		return policy, nil
+	}
+
+	policy.NullSessionAllowed = canEstablishSession(executionId, host, port, "", "", untilDeadline(deadline, sessionTimeout))
+	policy.GuestAuthAllowed = canEstablishSession(executionId, host, port, "guest", "", untilDeadline(deadline, sessionTimeout))
+	return policy, nil
+}
+
+// untilDeadline returns timeout bounded by the time left before deadline
+func untilDeadline(deadline time.Time, timeout time.Duration) time.Duration {
+	return min(timeout, time.Until(deadline))
+}
+
+// negotiateSMB2SecurityMode returns the security mode and dialect of the smb2 negotiate response
+func negotiateSMB2SecurityMode(executionId string, address string, timeout time.Duration) (uint16, string, error) {
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, timeout)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	data, err := negotiateSMB2(conn)
+	if err != nil {
+		return 0, "", err
+	}
+	return parseSMB2Negotiate(data)
+}
+
+// parseSMB2Negotiate parses the security mode and dialect of a smb2 negotiate response
+func parseSMB2Negotiate(data []byte) (uint16, string, error) {
+	// 64 bytes header followed by structure size, security mode and dialect
+	if len(data) < 70 || !bytes.Equal(data[:4], []byte("\xfeSMB")) {
+		return 0, "", errNotSMB
+	}
+	if status := binary.LittleEndian.Uint32(data[8:12]); status != 0 {
+		return 0, "", fmt.Errorf("smb2 negotiate failed with status 0x%08x", status)
+	}
+	securityMode := binary.LittleEndian.Uint16(data[66:68])
+	return securityMode, dialectName(binary.LittleEndian.Uint16(data[68:70])), nil
+}
+
+// dialectName returns the name of a smb2 dialect revision
+func dialectName(revision uint16) string {
+	switch revision {
+	case 0x0202:
+		return "2.0.2"
+	case 0x0210:
+		return "2.1"
+	case 0x0300:
+		return "3.0"
+	case 0x0302:
+		return "3.0.2"
+	case 0x0311:
+		return "3.1.1"
+	}
+	return fmt.Sprintf("0x%04x", revision)
+}
+
+// negotiateSMB1SecurityMode returns the security mode of the smb1 negotiate response
+func negotiateSMB1SecurityMode(executionId string, address string, timeout time.Duration) (byte, error) {
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if _, err := conn.Write(smb1NegotiateRequest); err != nil {
+		return 0, err
+	}
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, err
+	}
+	length := int(binary.BigEndian.Uint32(header) & 0x00ffffff)
+	if length < 37 || length > 0xffff {
+		return 0, errNotSMB
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return 0, err
+	}
+	return parseSMB1Negotiate(data)
+}
+
+// parseSMB1Negotiate parses the security mode of a smb1 negotiate response
+func parseSMB1Negotiate(data []byte) (byte, error) {
+	// 32 bytes header, word count, dialect index and security mode
+	if len(data) < 36 || !bytes.Equal(data[:4], []byte("\xffSMB")) || data[4] != 0x72 {
+		return 0, errNotSMB
+	}
+	if status := binary.LittleEndian.Uint32(data[5:9]); status != 0 {
+		return 0, fmt.Errorf("smb1 negotiate failed with status 0x%08x", status)
+	}
+	if dialectIndex := binary.LittleEndian.Uint16(data[33:35]); dialectIndex == 0xffff || data[32] == 0 {
+		return 0, errors.New("smb1 dialect not accepted")
+	}
+	return data[35], nil
+}
+
+// canEstablishSession checks if a smb2 session can be established with given credentials
+// within timeout
+func canEstablishSession(executionId string, host string, port int, user string, password string, timeout time.Duration) bool {
+	conn, s, err := newSMBSession(executionId, host, port, user, password, timeout)
+	if err != nil {
+		return false
+	}
+	_ = s.Logoff()
+	_ = conn.Close()
+	return true
+}
@@ -0,0 +1,208 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// smb2NegotiateFixture returns a smb2 negotiate response with given security mode and dialect
+func smb2NegotiateFixture(securityMode uint16, dialect uint16) []byte {
+	header := make([]byte, 64)
+	copy(header, "\xfeSMB")
+	binary.LittleEndian.PutUint16(header[4:6], 64) // structure size
+	binary.LittleEndian.PutUint16(header[14:16], 1) // credits
+	binary.LittleEndian.PutUint32(header[16:20], 1) // flags: server to redirector
+	body := make([]byte, 65)
+	binary.LittleEndian.PutUint16(body[0:2], 65)
+	binary.LittleEndian.PutUint16(body[2:4], securityMode)
+	binary.LittleEndian.PutUint16(body[4:6], dialect)
+	return append(header, body...)
+}
+
+// smb1NegotiateFixture returns a smb1 NT LM 0.12 negotiate response with given security mode
+func smb1NegotiateFixture(securityMode byte) []byte {
+	header := make([]byte, 32)
+	copy(header, "\xffSMB\x72")
+	header[9] = 0x98 // flags: reply
+	body := make([]byte, 1+34+2)
+	body[0] = 17 // word count
+	binary.LittleEndian.PutUint16(body[1:3], 0)
+	body[3] = securityMode
+	return append(header, body...)
+}
+
+// smbListener serves the negotiate fixtures and closes connections on any other request
+func smbListener(t *testing.T, smb2Negotiate []byte, smb1Negotiate []byte) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer func() { _ = conn.Close() }()
+				header := make([]byte, 4)
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				request := make([]byte, binary.BigEndian.Uint32(header))
+				if _, err := io.ReadFull(conn, request); err != nil || len(request) < 16 {
+					return
+				}
+				var response []byte
+				switch {
+				case bytes.HasPrefix(request, []byte("\xfeSMB")) && binary.LittleEndian.Uint16(request[12:14]) == 0:
+					response = smb2Negotiate
+				case bytes.HasPrefix(request, []byte("\xffSMB\x72")):
+					response = smb1Negotiate
+				}
+				if response == nil {
+					return
+				}
+				_, _ = conn.Write(binary.BigEndian.AppendUint32(nil, uint32(len(response))))
+				_, _ = conn.Write(response)
+			}(conn)
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestGetSecurityPolicy(t *testing.T) {
+	ctx := jstest.Context(t)
+	client := &SMBClient{}
+
+	// domain controller like server: signing required, smb1 disabled
+	host, port := smbListener(t, smb2NegotiateFixture(smb2SigningEnabled|smb2SigningRequired, 0x0311), nil)
+	policy, err := client.GetSecurityPolicy(ctx, host, port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := SecurityPolicy{SigningEnabled: true, SigningRequired: true, Dialect: "3.1.1"}
+	if policy != expected {
+		t.Fatalf("expected %+v, got %+v", expected, policy)
+	}
+
+	// member server like server: signing optional, smb1 enabled
+	host, port = smbListener(t, smb2NegotiateFixture(smb2SigningEnabled, 0x0210), smb1NegotiateFixture(smb1SigningEnabled))
+	policy, err = client.GetSecurityPolicy(ctx, host, port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = SecurityPolicy{SigningEnabled: true, SMBv1Enabled: true, Dialect: "2.1"}
+	if policy != expected {
+		t.Fatalf("expected %+v, got %+v", expected, policy)
+	}
+
+	// smb1 only server
+	host, port = smbListener(t, nil, smb1NegotiateFixture(smb1SigningEnabled|smb1SigningRequired))
+	policy, err = client.GetSecurityPolicy(ctx, host, port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = SecurityPolicy{SigningEnabled: true, SigningRequired: true, SMBv1Enabled: true}
+	if policy != expected {
+		t.Fatalf("expected %+v, got %+v", expected, policy)
+	}
+}
+
+// silentListener accepts connections without ever answering
+func silentListener(t *testing.T) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestNewSMBSessionDeadline(t *testing.T) {
+	executionId := jstest.Init(t)
+	host, port := silentListener(t)
+
+	start := time.Now()
+	if _, _, err := newSMBSession(executionId, host, port, "guest", "", 200*time.Millisecond); err == nil {
+		t.Fatal("expected session setup with a silent server to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected session setup to be bounded by its timeout, took %s", elapsed)
+	}
+}
+
+func TestGetSecurityPolicyDeadline(t *testing.T) {
+	ctx := jstest.Context(t)
+	defer func(timeout time.Duration) { policyTimeout = timeout }(policyTimeout)
+	policyTimeout = 500 * time.Millisecond
+
+	// the smb2 negotiate is answered, following handshakes never are
+	smb2Host, smb2Port := smbListener(t, smb2NegotiateFixture(smb2SigningEnabled, 0x0311), nil)
+	silentHost, silentPort := silentListener(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for accepted := 0; ; accepted++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			address := net.JoinHostPort(silentHost, strconv.Itoa(silentPort))
+			if accepted == 0 {
+				address = net.JoinHostPort(smb2Host, strconv.Itoa(smb2Port))
+			}
+			go proxyConn(conn, address)
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+
+	start := time.Now()
+	policy, err := (&SMBClient{}).GetSecurityPolicy(ctx, addr.IP.String(), addr.Port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.Dialect != "3.1.1" || policy.SMBv1Enabled || policy.NullSessionAllowed || policy.GuestAuthAllowed {
+		t.Fatalf("unexpected policy %+v", policy)
+	}
+	// the smb1 negotiate and both session attempts share the probe deadline
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected policy probe to be bounded by a single deadline, took %s", elapsed)
+	}
+}
+
+// proxyConn forwards conn to address until either side closes
+func proxyConn(conn net.Conn, address string) {
+	defer func() { _ = conn.Close() }()
+	upstream, err := net.Dial("tcp", address)
+	if err != nil {
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+	go func() { _, _ = io.Copy(upstream, conn) }()
+	_, _ = io.Copy(conn, upstream)
+}
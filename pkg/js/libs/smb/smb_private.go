@@ -8,24 +8,23 @@ import (
 
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins/services/smb"
+	"github.com/projectdiscovery/go-smb2"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	zgrabsmb "github.com/zmap/zgrab2/lib/smb/smb"
 )
 
+// sessionTimeout is the time allowed to dial and establish a smb2 session
+const sessionTimeout = 10 * time.Second
+
 // ==== private helper functions/methods ====
 
 // collectSMBv2Metadata collects metadata for SMBv2 services.
 // @memo
-func collectSMBv2Metadata(executionId string, host string, port int, timeout time.Duration) (*plugins.ServiceSMB, error) {
+func collectSMBv2Metadata(ctx context.Context, executionId string, host string, port int, timeout time.Duration) (*plugins.ServiceSMB, error) {
 	if timeout == 0 {
 		timeout = 5 * time.Second
 	}
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)), timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +32,8 @@ func collectSMBv2Metadata(executionId string, host string, port int, timeout tim
 		_ = conn.Close()
 	}()
 
-	metadata, err := smb.DetectSMBv2(conn, timeout)
+	// detection resets the connection deadline, keep it within the scan deadline
+	metadata, err := smb.DetectSMBv2(conn, time.Until(protocolstate.GetDeadline(executionId, timeout)))
 	if err != nil {
 		return nil, err
 	}
@@ -53,3 +53,29 @@ func getSMBInfo(conn net.Conn, setupSession, v1 bool) (*zgrabsmb.SMBLog, error)
 	}
 	return result, nil
 }
+
+// newSMBSession dials given host and port and establishes an authenticated
+// smb2 session. null sessions can be established by using empty username
+// and password and guest sessions by using guest username with empty password.
+// The connection deadline (timeout or the scan deadline) bounds the session
+// setup and is left set for the requests of the caller.
+// caller is responsible for logging off the session and closing the connection
+func newSMBSession(executionId string, host string, port int, user string, password string, timeout time.Duration) (net.Conn, *smb2.Session, error) {
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, fmt.Sprintf("%d", port)), timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := &smb2.Dialer{
+		Initiator: &smb2.NTLMInitiator{
+			User:     user,
+			Password: password,
+		},
+	}
+	s, err := d.Dial(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	return conn, s, nil
+}
@@ -0,0 +1,406 @@
+package smb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+	"unicode/utf16"
+
+	"github.com/projectdiscovery/go-smb2"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+type (
+	// ShareInfo contains information about a share
+	// as returned by SRVSVC NetShareEnumAll (level 1).
+	// @example
+	// ```javascript
+	// const smb = require('nuclei/smb');
+	// const client = new smb.SMBClient();
+	// const shares = client.ListSharesInfo('acme.com', 445, 'username', 'password');
+	// log(toJSON(shares));
+	// ```
+	ShareInfo struct {
+		// Name is the name of the share
+		Name string
+		// Type is the type of the share (DISK, PRINTER, DEVICE, IPC)
+		Type string
+		// Special is true for administrative/special shares (ex: C$, IPC$)
+		Special bool
+		// Remark is the comment associated with the share
+		Remark string
+	}
+)
+
+var (
+	// time allowed for the share enumeration after the session is established
+	shareEnumTimeout = 10 * time.Second
+)
+
+type (
+	// ListSharesInfoOptions contains options for ListSharesInfoWithOptions function.
+	// @example
+	// ```javascript
+	// const smb = require('nuclei/smb');
+	// const client = new smb.SMBClient();
+	// const resp = client.ListSharesInfoWithOptions('acme.com', 445, 'username', 'password', { PartialOnTimeout: true });
+	// ```
+	ListSharesInfoOptions struct {
+		// PartialOnTimeout returns the shares received before the enumeration
+		// timed out with TimedOut set instead of an error
+		PartialOnTimeout bool
+	}
+
+	// ListSharesInfoResponse is the response from the ListSharesInfoWithOptions function.
+	// this is returned by ListSharesInfoWithOptions function.
+	// @example
+	// ```javascript
+	// const smb = require('nuclei/smb');
+	// const client = new smb.SMBClient();
+	// const resp = client.ListSharesInfoWithOptions('acme.com', 445, 'username', 'password', { PartialOnTimeout: true });
+	// log(resp.TimedOut, toJSON(resp.Shares));
+	// ```
+	ListSharesInfoResponse struct {
+		// Shares are the enumerated shares
+		Shares []ShareInfo
+		// TimedOut is true if the enumeration timed out and Shares only contains
+		// the shares received before (if PartialOnTimeout is set)
+		TimedOut bool
+	}
+)
+
+// ListSharesInfo tries to connect to provided host and port and enumerates
+// shares using the SRVSVC named pipe (NetShareEnumAll) returning share names,
+// types and remarks. null sessions can be tried by using empty username and password
+// and guest sessions by using 'guest' username with empty password.
+// @example
+// ```javascript
+// const smb = require('nuclei/smb');
+// const client = new smb.SMBClient();
+// const shares = client.ListSharesInfo('acme.com', 445, 'username', 'password');
+// log(toJSON(shares));
+// ```
+func (c *SMBClient) ListSharesInfo(ctx context.Context, host string, port int, user string, password string) ([]ShareInfo, error) {
+	executionId := ctx.Value("executionId").(string)
+	resp, err := memoizedlistSharesInfo(executionId, host, port, user, password, false)
+	return resp.Shares, err
+}
+
+// ListSharesInfoWithOptions is ListSharesInfo returning the shares in a response.
+// With PartialOnTimeout, an enumeration exceeding its timeout (10 seconds after
+// the session is established) returns the shares whose entries were received
+// before the deadline with TimedOut set instead of discarding them. Such
+// enumerations are not memoized since their result depends on timing.
+// @example
+// ```javascript
+// const smb = require('nuclei/smb');
+// const client = new smb.SMBClient();
+// const resp = client.ListSharesInfoWithOptions('acme.com', 445, 'username', 'password', { PartialOnTimeout: true });
+// if (resp.TimedOut) { log('partial share list'); }
+// log(toJSON(resp.Shares));
+// ```
+func (c *SMBClient) ListSharesInfoWithOptions(ctx context.Context, host string, port int, user string, password string, opts ListSharesInfoOptions) (ListSharesInfoResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	if opts.PartialOnTimeout {
+		return listSharesInfo(executionId, host, port, user, password, true)
+	}
+	return memoizedlistSharesInfo(executionId, host, port, user, password, false)
+}
+
+// @memo(nodisk)
+func listSharesInfo(executionId string, host string, port int, user string, password string, partialOnTimeout bool) (ListSharesInfoResponse, error) {
+	resp := ListSharesInfoResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, s, err := newSMBSession(executionId, host, port, user, password, sessionTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = s.Logoff()
+		_ = conn.Close()
+	}()
+	if err := conn.SetDeadline(protocolstate.GetDeadline(executionId, shareEnumTimeout)); err != nil {
+		return resp, err
+	}
+	shares, err := enumShares(s, host)
+	if err != nil {
+		if partialOnTimeout && isTimeout(err) {
+			// the session is unusable after a timeout, return what was received
+			return ListSharesInfoResponse{Shares: shares, TimedOut: true}, nil
+		}
+		return resp, err
+	}
+	resp.Shares = shares
+	return resp, nil
+}
+
+// enumShares enumerates the shares over the srvsvc pipe of the session.
+// on error the shares received before are returned along with it
+func enumShares(s *smb2.Session, host string) ([]ShareInfo, error) {
+	fs, err := s.Mount(fmt.Sprintf(`\\%s\IPC$`, host))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = fs.Umount()
+	}()
+
+	pipe, err := fs.OpenFile("srvsvc", os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = pipe.Close()
+	}()
+
+	return netShareEnumAll(pipe, host)
+}
+
+// isTimeout checks if err is caused by the deadline of the smb connection
+func isTimeout(err error) bool {
+	var transportErr *smb2.TransportError
+	if errors.As(err, &transportErr) {
+		// transport errors do not unwrap the connection error
+		err = transportErr.Err
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// ==== SRVSVC (MS-SRVS) over DCE/RPC ====
+
+const (
+	rpcPacketRequest  = 0
+	rpcPacketResponse = 2
+	rpcPacketFault    = 3
+	rpcPacketBind     = 11
+	rpcPacketBindAck  = 12
+
+	rpcFirstFrag = 0x01
+	rpcLastFrag  = 0x02
+
+	rpcMaxFrag = 4280
+
+	opNetrShareEnum = 15
+)
+
+var (
+	// 4b324fc8-1670-01d3-1278-5a47bf6ee188 v3.0
+	srvsvcSyntax = []byte{0xc8, 0x4f, 0x32, 0x4b, 0x70, 0x16, 0xd3, 0x01, 0x12, 0x78, 0x5a, 0x47, 0xbf, 0x6e, 0xe1, 0x88, 0x03, 0x00, 0x00, 0x00}
+	// 8a885d04-1ceb-11c9-9fe8-08002b104860 v2 (NDR)
+	ndrSyntax = []byte{0x04, 0x5d, 0x88, 0x8a, 0xeb, 0x1c, 0xc9, 0x11, 0x9f, 0xe8, 0x08, 0x00, 0x2b, 0x10, 0x48, 0x60, 0x02, 0x00, 0x00, 0x00}
+
+	errInvalidRPCResponse = errors.New("invalid dcerpc response")
+)
+
+// netShareEnumAll binds to srvsvc interface on given pipe and
+// enumerates shares using NetrShareEnum with level 1 info.
+// if reading the response fails, the shares of the fragments received
+// before are returned along with the error
+func netShareEnumAll(pipe io.ReadWriter, host string) ([]ShareInfo, error) {
+	// bind
+	bind := make([]byte, 0, 72)
+	bind = binary.LittleEndian.AppendUint16(bind, rpcMaxFrag) // max xmit frag
+	bind = binary.LittleEndian.AppendUint16(bind, rpcMaxFrag) // max recv frag
+	bind = binary.LittleEndian.AppendUint32(bind, 0)          // assoc group
+	bind = append(bind, 1, 0, 0, 0)                           // num ctx items + padding
+	bind = binary.LittleEndian.AppendUint16(bind, 0)          // context id
+	bind = append(bind, 1, 0)                                 // num transfer syntaxes + padding
+	bind = append(bind, srvsvcSyntax...)
+	bind = append(bind, ndrSyntax...)
+	if _, err := pipe.Write(rpcHeader(rpcPacketBind, 1, bind)); err != nil {
+		return nil, err
+	}
+	buff := make([]byte, 2*rpcMaxFrag)
+	n, err := pipe.Read(buff)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n < 16 || buff[2] != rpcPacketBindAck {
+		return nil, errInvalidRPCResponse
+	}
+
+	// NetrShareEnum request
+	stub := ndrString(nil, `\\`+host)
+	stub = binary.LittleEndian.AppendUint32(stub, 1)          // level
+	stub = binary.LittleEndian.AppendUint32(stub, 1)          // union switch
+	stub = binary.LittleEndian.AppendUint32(stub, 0x00020004) // container referent
+	stub = binary.LittleEndian.AppendUint32(stub, 0)          // entries read
+	stub = binary.LittleEndian.AppendUint32(stub, 0)          // null buffer
+	stub = binary.LittleEndian.AppendUint32(stub, 0xffffffff) // preferred maximum length
+	stub = binary.LittleEndian.AppendUint32(stub, 0x00020008) // resume handle referent
+	stub = binary.LittleEndian.AppendUint32(stub, 0)          // resume handle
+
+	request := make([]byte, 0, 8+len(stub))
+	request = binary.LittleEndian.AppendUint32(request, uint32(len(stub))) // alloc hint
+	request = binary.LittleEndian.AppendUint16(request, 0)                 // context id
+	request = binary.LittleEndian.AppendUint16(request, opNetrShareEnum)
+	request = append(request, stub...)
+	if _, err := pipe.Write(rpcHeader(rpcPacketRequest, 2, request)); err != nil {
+		return nil, err
+	}
+
+	// collect response fragments
+	var data []byte
+	for {
+		n, err := pipe.Read(buff)
+		if err != nil && !errors.Is(err, io.EOF) {
+			shares, _ := parseShareEnumResponse(data)
+			return shares, err
+		}
+		if n < 24 {
+			return nil, errInvalidRPCResponse
+		}
+		if buff[2] == rpcPacketFault {
+			return nil, fmt.Errorf("dcerpc fault: 0x%08x", binary.LittleEndian.Uint32(buff[24:28]))
+		}
+		if buff[2] != rpcPacketResponse {
+			return nil, errInvalidRPCResponse
+		}
+		fragLength := int(binary.LittleEndian.Uint16(buff[8:10]))
+		if fragLength > n || fragLength < 24 {
+			return nil, errInvalidRPCResponse
+		}
+		data = append(data, buff[24:fragLength]...)
+		if buff[3]&rpcLastFrag != 0 {
+			break
+		}
+	}
+	return parseShareEnumResponse(data)
+}
+
+// rpcHeader prefixes given body with dcerpc common header
+func rpcHeader(packetType byte, callId uint32, body []byte) []byte {
+	packet := []byte{5, 0, packetType, rpcFirstFrag | rpcLastFrag, 0x10, 0, 0, 0}
+	packet = binary.LittleEndian.AppendUint16(packet, uint16(16+len(body)))
+	packet = binary.LittleEndian.AppendUint16(packet, 0) // auth length
+	packet = binary.LittleEndian.AppendUint32(packet, callId)
+	return append(packet, body...)
+}
+
+// ndrString appends a unique pointer to a null terminated conformant varying utf16 string
+func ndrString(b []byte, value string) []byte {
+	encoded := utf16.Encode([]rune(value + "\x00"))
+	b = binary.LittleEndian.AppendUint32(b, 0x00020000) // referent id
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(encoded)))
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(encoded)))
+	for _, v := range encoded {
+		b = binary.LittleEndian.AppendUint16(b, v)
+	}
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// ndrReader is a minimal reader for ndr encoded response stubs
+type ndrReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *ndrReader) uint32() uint32 {
+	if r.err != nil || r.offset+4 > len(r.data) {
+		r.err = errInvalidRPCResponse
+		return 0
+	}
+	v := binary.LittleEndian.Uint32(r.data[r.offset:])
+	r.offset += 4
+	return v
+}
+
+func (r *ndrReader) string() string {
+	_ = r.uint32() // max count
+	_ = r.uint32() // offset
+	count := int(r.uint32())
+	if r.err != nil || count < 0 || r.offset+2*count > len(r.data) {
+		r.err = errInvalidRPCResponse
+		return ""
+	}
+	chars := make([]uint16, 0, count)
+	for i := 0; i < count; i++ {
+		chars = append(chars, binary.LittleEndian.Uint16(r.data[r.offset+2*i:]))
+	}
+	r.offset += 2 * count
+	r.offset += (4 - r.offset%4) % 4
+	for len(chars) > 0 && chars[len(chars)-1] == 0 {
+		chars = chars[:len(chars)-1]
+	}
+	return string(utf16.Decode(chars))
+}
+
+// parseShareEnumResponse parses NetrShareEnum response stub with SHARE_INFO_1 entries.
+// on error the entries parsed before (ex: of a truncated stub) are returned along with it
+func parseShareEnumResponse(data []byte) ([]ShareInfo, error) {
+	r := &ndrReader{data: data}
+	_ = r.uint32() // level
+	_ = r.uint32() // union switch
+	if r.uint32() == 0 {
+		// null container
+		return nil, r.err
+	}
+	entries := int(r.uint32())
+	if r.uint32() == 0 || entries == 0 {
+		// null buffer
+		return nil, r.err
+	}
+	if int(r.uint32()) != entries || r.err != nil {
+		return nil, errInvalidRPCResponse
+	}
+	if entries > len(data)/12 {
+		return nil, errInvalidRPCResponse
+	}
+
+	type entry struct {
+		namePtr, shareType, remarkPtr uint32
+	}
+	parsed := make([]entry, entries)
+	for i := range parsed {
+		parsed[i] = entry{namePtr: r.uint32(), shareType: r.uint32(), remarkPtr: r.uint32()}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	shares := make([]ShareInfo, 0, entries)
+	for _, e := range parsed {
+		share := ShareInfo{Type: shareTypeName(e.shareType), Special: e.shareType&0x80000000 != 0}
+		if e.namePtr != 0 {
+			share.Name = r.string()
+		}
+		if e.remarkPtr != 0 {
+			share.Remark = r.string()
+		}
+		if r.err != nil {
+			return shares, r.err
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+// shareTypeName returns name of base share type (STYPE_*)
+func shareTypeName(shareType uint32) string {
+	switch shareType & 0x0fffffff {
+	case 0:
+		return "DISK"
+	case 1:
+		return "PRINTER"
+	case 2:
+		return "DEVICE"
+	case 3:
+		return "IPC"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", shareType&0x0fffffff)
+	}
+}
@@ -0,0 +1,174 @@
+package smb
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/go-smb2"
+)
+
+var testShares = []ShareInfo{
+	{Name: "ADMIN$", Type: "DISK", Special: true, Remark: "Remote Admin"},
+	{Name: "IPC$", Type: "IPC", Special: true, Remark: "Remote IPC"},
+	{Name: "Public", Type: "DISK", Remark: "Public files"},
+}
+
+// shareEnumStub returns a NetrShareEnum response stub with SHARE_INFO_1 entries of shares
+func shareEnumStub(shares []ShareInfo) []byte {
+	stub := binary.LittleEndian.AppendUint32(nil, 1)          // level
+	stub = binary.LittleEndian.AppendUint32(stub, 1)          // union switch
+	stub = binary.LittleEndian.AppendUint32(stub, 0x00020000) // container referent
+	stub = binary.LittleEndian.AppendUint32(stub, uint32(len(shares)))
+	stub = binary.LittleEndian.AppendUint32(stub, 0x00020004) // buffer referent
+	stub = binary.LittleEndian.AppendUint32(stub, uint32(len(shares)))
+	for i, share := range shares {
+		shareType := uint32(0)
+		if share.Type == "IPC" {
+			shareType = 3
+		}
+		if share.Special {
+			shareType |= 0x80000000
+		}
+		stub = binary.LittleEndian.AppendUint32(stub, uint32(0x00020008+8*i))
+		stub = binary.LittleEndian.AppendUint32(stub, shareType)
+		stub = binary.LittleEndian.AppendUint32(stub, uint32(0x0002000c+8*i))
+	}
+	for _, share := range shares {
+		// deferred strings are not prefixed by a referent id
+		stub = append(stub, ndrString(nil, share.Name)[4:]...)
+		stub = append(stub, ndrString(nil, share.Remark)[4:]...)
+	}
+	return stub
+}
+
+// rpcResponse returns a dcerpc response fragment carrying stub
+func rpcResponse(stub []byte, flags byte) []byte {
+	packet := []byte{5, 0, rpcPacketResponse, flags, 0x10, 0, 0, 0}
+	packet = binary.LittleEndian.AppendUint16(packet, uint16(24+len(stub)))
+	packet = binary.LittleEndian.AppendUint16(packet, 0)
+	packet = binary.LittleEndian.AppendUint32(packet, 2)
+	packet = binary.LittleEndian.AppendUint32(packet, uint32(len(stub)))
+	packet = append(packet, 0, 0, 0, 0)
+	return append(packet, stub...)
+}
+
+// srvsvcPipe returns a pipe answering the bind and share enumeration with
+// the given response fragments. the pipe stalls after the fragments unless
+// closeAfter is set
+func srvsvcPipe(t *testing.T, fragments [][]byte, closeAfter bool) net.Conn {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+	go func() {
+		buff := make([]byte, 2*rpcMaxFrag)
+		if _, err := server.Read(buff); err != nil {
+			return
+		}
+		bindAck := make([]byte, 16)
+		copy(bindAck, []byte{5, 0, rpcPacketBindAck, rpcFirstFrag | rpcLastFrag})
+		if _, err := server.Write(bindAck); err != nil {
+			return
+		}
+		if _, err := server.Read(buff); err != nil {
+			return
+		}
+		for _, fragment := range fragments {
+			if _, err := server.Write(fragment); err != nil {
+				return
+			}
+		}
+		if closeAfter {
+			_ = server.Close()
+		}
+	}()
+	return client
+}
+
+func TestNetShareEnumAll(t *testing.T) {
+	stub := shareEnumStub(testShares)
+	pipe := srvsvcPipe(t, [][]byte{
+		rpcResponse(stub[:100], rpcFirstFrag),
+		rpcResponse(stub[100:], rpcLastFrag),
+	}, false)
+	_ = pipe.SetDeadline(time.Now().Add(5 * time.Second))
+
+	shares, err := netShareEnumAll(pipe, "acme.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(shares, testShares) {
+		t.Fatalf("expected %+v, got %+v", testShares, shares)
+	}
+}
+
+func TestNetShareEnumAllTimeout(t *testing.T) {
+	stub := shareEnumStub(testShares)
+	// the first fragment ends within the remark of the last share
+	cut := len(stub) - 8
+	pipe := srvsvcPipe(t, [][]byte{rpcResponse(stub[:cut], rpcFirstFrag)}, false)
+	_ = pipe.SetDeadline(time.Now().Add(200 * time.Millisecond))
+
+	shares, err := netShareEnumAll(pipe, "acme.com")
+	if err == nil || !isTimeout(err) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if !reflect.DeepEqual(shares, testShares[:2]) {
+		t.Fatalf("expected the shares received before the deadline %+v, got %+v", testShares[:2], shares)
+	}
+
+	// a closed pipe is not a timeout
+	pipe = srvsvcPipe(t, [][]byte{rpcResponse(stub[:cut], rpcFirstFrag)}, true)
+	_ = pipe.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := netShareEnumAll(pipe, "acme.com"); err == nil || isTimeout(err) {
+		t.Fatalf("expected non timeout error, got %v", err)
+	}
+}
+
+func TestParseShareEnumResponseTruncated(t *testing.T) {
+	stub := shareEnumStub(testShares)
+	tests := []struct {
+		name   string
+		length int
+		want   []ShareInfo
+	}{
+		{name: "fixed entries", length: 24 + 12*len(testShares), want: []ShareInfo{}},
+		{name: "first share", length: len(shareEnumStub(testShares[:1])) + 24, want: testShares[:1]},
+		{name: "header", length: 10, want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shares, err := parseShareEnumResponse(stub[:test.length])
+			if err == nil {
+				t.Fatal("expected error for truncated response")
+			}
+			if !reflect.DeepEqual(shares, test.want) {
+				t.Fatalf("expected %+v, got %+v", test.want, shares)
+			}
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: os.ErrDeadlineExceeded, want: true},
+		{err: &os.PathError{Op: "read", Path: "srvsvc", Err: &smb2.TransportError{Err: os.ErrDeadlineExceeded}}, want: true},
+		{err: &smb2.TransportError{Err: errors.New("connection reset")}, want: false},
+		{err: errInvalidRPCResponse, want: false},
+	}
+	for _, test := range tests {
+		if got := isTimeout(test.err); got != test.want {
+			t.Errorf("isTimeout(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
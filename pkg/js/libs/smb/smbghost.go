@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"errors"
-	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -38,44 +37,57 @@ func detectSMBGhost(executionId string, host string, port int) (bool, error) {
 		return false, protocolstate.ErrHostDenied.Msgf(host)
 	}
 	addr := net.JoinHostPort(host, strconv.Itoa(port))
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return false, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return false, err
 	}
 	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", addr)
 	if err != nil {
 		return false, err
 
 	}
+	conn = protocolstate.RecordConn(executionId, conn)
 	defer func() {
 		_ = conn.Close()
 	}()
 
-	_, err = conn.Write([]byte(pkt))
+	data, err := negotiateSMB2(conn)
 	if err != nil {
 		return false, err
 	}
+	if len(data) < 72 {
+		return false, errors.New("invalid response expected at least 72 bytes")
+	}
+
+	if !bytes.Equal(data[68:70], []byte("\x11\x03")) || !bytes.Equal(data[70:72], []byte("\x02\x00")) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// negotiateSMB2 sends a smb2 negotiate request (dialects 2.0.2 to 3.1.1)
+// and returns the negotiate response without the netbios session header
+func negotiateSMB2(conn net.Conn) ([]byte, error) {
+	return negotiateSMB2Request(conn, []byte(pkt))
+}
+
+// negotiateSMB2Request sends the given negotiate request (with netbios session
+// header) and returns the negotiate response without the netbios session header
+func negotiateSMB2Request(conn net.Conn, request []byte) ([]byte, error) {
+	_, err := conn.Write(request)
+	if err != nil {
+		return nil, err
+	}
 	buff, _ := reader.ConnReadNWithTimeout(conn, 4, time.Duration(5)*time.Second)
 	args, err := structs.Unpack(">I", buff)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	if len(args) != 1 {
-		return false, errors.New("invalid response")
+		return nil, errors.New("invalid response")
 	}
 
 	length := args[0].(int)
 	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
-	data, err := reader.ConnReadNWithTimeout(conn, int64(length), time.Duration(5)*time.Second)
-	if err != nil {
-		return false, err
-	}
-	if len(data) < 72 {
-		return false, errors.New("invalid response expected at least 72 bytes")
-	}
-
-	if !bytes.Equal(data[68:70], []byte("\x11\x03")) || !bytes.Equal(data[70:72], []byte("\x02\x00")) {
-		return false, nil
-	}
-	return true, nil
+	return reader.ConnReadNWithTimeout(conn, int64(length), time.Duration(5)*time.Second)
 }
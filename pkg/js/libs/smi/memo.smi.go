@@ -0,0 +1,35 @@
+// Warning - This is generated code
+package smi
+
+import (
+	"context"
+	"fmt"
+
+	"errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsSmartInstallResponse]("smi.isSmartInstall", 3)
+}
+
+func memoizedisSmartInstall(ctx context.Context, executionId string, host string, port int) (IsSmartInstallResponse, error) {
+	hash := "smi.isSmartInstall" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "smi.isSmartInstall" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsSmartInstallResponse, error) {
+			return isSmartInstall(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsSmartInstallResponse{}, err
+	}
+	if value, ok := v.(IsSmartInstallResponse); ok {
+		return value, nil
+	}
+
+	return IsSmartInstallResponse{}, errors.New("could not convert cached result")
+}
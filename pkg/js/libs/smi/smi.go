@@ -0,0 +1,93 @@
+package smi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the smart install handshake
+	probeTimeout = 5 * time.Second
+)
+
+var (
+	// smart install director hello message
+	helloMessage = []byte{
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+	}
+	// response sent by an enabled smart install client
+	clientResponse = []byte{
+		0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+	}
+)
+
+type (
+	// IsSmartInstallResponse is the response from the IsSmartInstall function.
+	// this is returned by IsSmartInstall function.
+	// @example
+	// ```javascript
+	// const smi = require('nuclei/smi');
+	// const smartInstall = smi.IsSmartInstall('acme.com', 4786);
+	// log(toJSON(smartInstall));
+	// ```
+	IsSmartInstallResponse struct {
+		// Enabled is true if the host runs an enabled smart install client
+		Enabled bool
+	}
+)
+
+// IsSmartInstall checks if the given host and port are running an enabled
+// Cisco Smart Install (SMI) client by sending the director hello message
+// and validating the SMI header of the response.
+// @example
+// ```javascript
+// const smi = require('nuclei/smi');
+// const smartInstall = smi.IsSmartInstall('acme.com', 4786);
+// log(toJSON(smartInstall));
+// ```
+func IsSmartInstall(ctx context.Context, host string, port int) (IsSmartInstallResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisSmartInstall(ctx, executionId, host, port)
+}
+
+// @memo
+func isSmartInstall(ctx context.Context, executionId string, host string, port int) (IsSmartInstallResponse, error) {
+	resp := IsSmartInstallResponse{}
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return resp, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), probeTimeout)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Write(helloMessage); err != nil {
+		return resp, err
+	}
+	// smi messages have a fixed 24 byte header, read is bounded to it
+	reply := make([]byte, len(clientResponse))
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+			// closed or silent responder is not smart install
+			return resp, nil
+		}
+		return resp, err
+	}
+	resp.Enabled = bytes.Equal(reply, clientResponse)
+	return resp, nil
+}
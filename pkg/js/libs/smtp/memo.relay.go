@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package smtp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[CheckOpenRelayResponse]("smtp.checkOpenRelay", 6)
+}
+
+func memoizedcheckOpenRelay(executionId string, host string, port int, from string, to string, noGreetingWait bool) (CheckOpenRelayResponse, error) {
+	hash := "smtp.checkOpenRelay" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(from) + ":" + fmt.Sprint(to) + ":" + fmt.Sprint(noGreetingWait)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "smtp.checkOpenRelay" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(from) + ":" + fmt.Sprint(to) + ":" + fmt.Sprint(noGreetingWait)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (CheckOpenRelayResponse, error) {
+			return checkOpenRelay(executionId, host, port, from, to, noGreetingWait)
+		})
+	})
+	if err != nil {
+		return CheckOpenRelayResponse{}, err
+	}
+	if value, ok := v.(CheckOpenRelayResponse); ok {
+		return value, nil
+	}
+
+	return CheckOpenRelayResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,193 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// timeout of the whole relay check
+	relayTimeout = 10 * time.Second
+)
+
+type (
+	// CheckOpenRelayResponse is the response from the CheckOpenRelay function.
+	// this is returned by CheckOpenRelay function.
+	// @example
+	// ```javascript
+	// const smtp = require('nuclei/smtp');
+	// const resp = smtp.CheckOpenRelay('acme.com', 25, 'probe@example.com', 'probe@example.org');
+	// log(toJSON(resp));
+	// ```
+	CheckOpenRelayResponse struct {
+		// IsOpenRelay is true if the server accepted the external recipient
+		IsOpenRelay bool
+		// Status classifies the server response, one of:
+		// accepted, relay_denied, sender_rejected, auth_required, temporary_failure
+		Status string
+		Banner string
+		// MailCode and MailMessage are the response to MAIL FROM
+		MailCode    int
+		MailMessage string
+		// RcptCode and RcptMessage are the response to RCPT TO (if sent)
+		RcptCode    int
+		RcptMessage string
+	}
+
+	// CheckOpenRelayOptions contains options for CheckOpenRelay function.
+	// @example
+	// ```javascript
+	// const smtp = require('nuclei/smtp');
+	// const resp = smtp.CheckOpenRelay('acme.com', 25, 'probe@example.com', 'probe@example.org', { NoGreetingWait: true });
+	// ```
+	CheckOpenRelayOptions struct {
+		// NoGreetingWait sends EHLO right after connecting instead of waiting for the
+		// greeting of the server (ex: servers only answering commands). a greeting
+		// received afterwards is still returned as Banner
+		NoGreetingWait bool
+	}
+)
+
+// CheckOpenRelay checks if the smtp server accepts relaying a message from
+// an external sender to an external recipient. Only MAIL FROM and RCPT TO are
+// sent, the DATA phase is never started and the session is reset before QUIT.
+// NoGreetingWait avoids waiting for the greeting on servers that never send one.
+// @example
+// ```javascript
+// const smtp = require('nuclei/smtp');
+// const resp = smtp.CheckOpenRelay('acme.com', 25, 'probe@example.com', 'probe@example.org');
+// log(resp.IsOpenRelay, resp.Status);
+// ```
+func CheckOpenRelay(ctx context.Context, host string, port int, from string, to string, opts CheckOpenRelayOptions) (CheckOpenRelayResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedcheckOpenRelay(executionId, host, port, from, to, opts.NoGreetingWait)
+}
+
+// @memo
+func checkOpenRelay(executionId string, host string, port int, from string, to string, noGreetingWait bool) (CheckOpenRelayResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return CheckOpenRelayResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if strings.ContainsAny(from+to, "\r\n") {
+		return CheckOpenRelayResponse{}, errors.New("from and to addresses cannot contain line breaks")
+	}
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), relayTimeout)
+	if err != nil {
+		return CheckOpenRelayResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	text := textproto.NewConn(utils.LimitConn(executionId, conn))
+	resp := CheckOpenRelayResponse{}
+	if !noGreetingWait {
+		_, banner, err := text.ReadResponse(220)
+		if err != nil {
+			return resp, err
+		}
+		resp.Banner = banner
+	}
+	defer func() {
+		// never complete a transaction: reset it and quit cleanly
+		_, _, _ = command(text, 250, "RSET")
+		_, _, _ = command(text, 221, "QUIT")
+	}()
+
+	banner, err := hello(text)
+	if banner != "" {
+		resp.Banner = banner
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	code, msg, err := command(text, 250, "MAIL FROM:<%s>", from)
+	resp.MailCode, resp.MailMessage = code, msg
+	if err != nil {
+		if !isProtocolError(err) {
+			return resp, err
+		}
+		resp.Status = classifyRejection(code, "sender_rejected")
+		return resp, nil
+	}
+
+	// 250 and 251 (user not local, will forward) both accept the recipient
+	code, msg, err = command(text, 25, "RCPT TO:<%s>", to)
+	resp.RcptCode, resp.RcptMessage = code, msg
+	if err != nil {
+		if !isProtocolError(err) {
+			return resp, err
+		}
+		resp.Status = classifyRejection(code, "relay_denied")
+		return resp, nil
+	}
+	resp.IsOpenRelay = true
+	resp.Status = "accepted"
+	return resp, nil
+}
+
+// command sends a smtp command and reads its response.
+// expectCode follows the semantics of textproto.Reader.ReadResponse
+func command(text *textproto.Conn, expectCode int, format string, args ...interface{}) (int, string, error) {
+	id, err := text.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	text.StartResponse(id)
+	defer text.EndResponse(id)
+	return text.ReadResponse(expectCode)
+}
+
+// hello sends EHLO falling back to HELO. A greeting received before the
+// EHLO response (greeting not waited for) is returned as banner
+func hello(text *textproto.Conn) (string, error) {
+	id, err := text.Cmd("EHLO nuclei")
+	if err != nil {
+		return "", err
+	}
+	text.StartResponse(id)
+	var banner string
+	code, msg, err := text.ReadResponse(0)
+	if err == nil && code == 220 {
+		banner = msg
+		code, _, err = text.ReadResponse(0)
+	}
+	text.EndResponse(id)
+	if err != nil && !isProtocolError(err) {
+		return banner, err
+	}
+	if code != 250 {
+		if _, _, err := command(text, 250, "HELO nuclei"); err != nil {
+			return banner, err
+		}
+	}
+	return banner, nil
+}
+
+// isProtocolError checks if err is a smtp error response (as opposed to a network error)
+func isProtocolError(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr)
+}
+
+// classifyRejection classifies a rejection code of MAIL FROM or RCPT TO
+func classifyRejection(code int, permanent string) string {
+	switch {
+	case code == 530 || code == 535:
+		return "auth_required"
+	case code >= 400 && code < 500:
+		return "temporary_failure"
+	default:
+		return permanent
+	}
+}
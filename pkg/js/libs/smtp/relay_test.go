@@ -0,0 +1,86 @@
+package smtp
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// relayListener serves a smtp dialog rejecting relaying. The greeting is
+// sent on connect, after the first command (late) or never
+func relayListener(t *testing.T, greeting string) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				if greeting == "connect" {
+					_, _ = conn.Write([]byte("220 mail.acme.com ESMTP\r\n"))
+				}
+				reader := bufio.NewReader(conn)
+				for first := true; ; first = false {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if first && greeting == "late" {
+						_, _ = conn.Write([]byte("220 mail.acme.com ESMTP\r\n"))
+					}
+					reply := "250 ok\r\n"
+					switch {
+					case strings.HasPrefix(line, "RCPT"):
+						reply = "550 relay not permitted\r\n"
+					case strings.HasPrefix(line, "QUIT"):
+						_, _ = conn.Write([]byte("221 bye\r\n"))
+						return
+					}
+					_, _ = conn.Write([]byte(reply))
+				}
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestCheckOpenRelayNoGreetingWait(t *testing.T) {
+	ctx := jstest.Context(t)
+
+	tests := []struct {
+		greeting string
+		opts     CheckOpenRelayOptions
+		banner   string
+	}{
+		{greeting: "connect", banner: "mail.acme.com ESMTP"},
+		{greeting: "connect", opts: CheckOpenRelayOptions{NoGreetingWait: true}, banner: "mail.acme.com ESMTP"},
+		{greeting: "late", opts: CheckOpenRelayOptions{NoGreetingWait: true}, banner: "mail.acme.com ESMTP"},
+		{greeting: "none", opts: CheckOpenRelayOptions{NoGreetingWait: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.greeting, func(t *testing.T) {
+			start := time.Now()
+			resp, err := CheckOpenRelay(ctx, "127.0.0.1", relayListener(t, tt.greeting), "probe@example.com", "probe@example.org", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.IsOpenRelay || resp.Status != "relay_denied" || resp.Banner != tt.banner {
+				t.Fatalf("expected relay to be denied with banner %q, got %+v", tt.banner, resp)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("expected relay check not to wait for the greeting, took=%v", elapsed)
+			}
+		})
+	}
+}
@@ -90,15 +90,16 @@ func (c *Client) IsSMTP() (SMTPResponse, error) {
 	timeout := 5 * time.Second
 
 	executionId := c.nj.ExecutionId()
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return SMTPResponse{}, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return SMTPResponse{}, err
 	}
 
 	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(c.host, c.port))
 	if err != nil {
 		return resp, err
 	}
+	conn = protocolstate.RecordConn(executionId, conn)
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -133,9 +134,9 @@ func (c *Client) IsOpenRelay(msg *SMTPMessage) (bool, error) {
 	c.nj.Require(c.port != "", "port cannot be empty")
 
 	executionId := c.nj.ExecutionId()
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return false, fmt.Errorf("dialers not initialized for %s", executionId)
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return false, err
 	}
 
 	addr := net.JoinHostPort(c.host, c.port)
@@ -143,6 +144,7 @@ func (c *Client) IsOpenRelay(msg *SMTPMessage) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	conn = protocolstate.RecordConn(executionId, conn)
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -0,0 +1,135 @@
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ber universal tags used by snmp messages
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagOID         = 0x06
+	tagSequence    = 0x30
+)
+
+var errInvalidBER = errors.New("invalid ber encoding")
+
+// berTLV encodes a ber element of given tag with the concatenated contents
+func berTLV(tag byte, contents ...[]byte) []byte {
+	var content []byte
+	for _, c := range contents {
+		content = append(content, c...)
+	}
+	data := []byte{tag}
+	switch length := len(content); {
+	case length < 0x80:
+		data = append(data, byte(length))
+	case length <= 0xff:
+		data = append(data, 0x81, byte(length))
+	default:
+		data = append(data, 0x82, byte(length>>8), byte(length))
+	}
+	return append(data, content...)
+}
+
+// berInteger encodes a ber integer
+func berInteger(value int64) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(value)}, content...)
+		value >>= 8
+		// stop once the sign bit of the first byte matches the remaining value
+		if (value == 0 && content[0]&0x80 == 0) || (value == -1 && content[0]&0x80 != 0) {
+			break
+		}
+	}
+	return berTLV(tagInteger, content)
+}
+
+// berOctetString encodes a ber octet string
+func berOctetString(value []byte) []byte {
+	return berTLV(tagOctetString, value)
+}
+
+// readTLV reads a ber element from data and returns its tag, content and
+// the remaining data
+func readTLV(data []byte) (byte, []byte, []byte, error) {
+	if len(data) < 2 {
+		return 0, nil, nil, errInvalidBER
+	}
+	tag := data[0]
+	length := int(data[1])
+	offset := 2
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 3 || len(data) < 2+size {
+			return 0, nil, nil, errInvalidBER
+		}
+		length = 0
+		for _, b := range data[2 : 2+size] {
+			length = length<<8 | int(b)
+		}
+		offset += size
+	}
+	if offset+length > len(data) {
+		return 0, nil, nil, errInvalidBER
+	}
+	return tag, data[offset : offset+length], data[offset+length:], nil
+}
+
+// expectTLV reads a ber element of the given tag from data
+func expectTLV(data []byte, tag byte) ([]byte, []byte, error) {
+	got, content, rest, err := readTLV(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if got != tag {
+		return nil, nil, fmt.Errorf("%w: expected tag 0x%02x, got 0x%02x", errInvalidBER, tag, got)
+	}
+	return content, rest, nil
+}
+
+// readInteger reads a ber integer from data
+func readInteger(data []byte) (int64, []byte, error) {
+	content, rest, err := expectTLV(data, tagInteger)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(content) == 0 || len(content) > 8 {
+		return 0, nil, errInvalidBER
+	}
+	value := int64(int8(content[0]))
+	for _, b := range content[1:] {
+		value = value<<8 | int64(b)
+	}
+	return value, rest, nil
+}
+
+// decodeOID returns the dotted representation of a ber object identifier
+func decodeOID(content []byte) (string, error) {
+	if len(content) == 0 {
+		return "", errInvalidBER
+	}
+	var parts []string
+	var value uint64
+	for i, b := range content {
+		value = value<<7 | uint64(b&0x7f)
+		if b&0x80 != 0 {
+			if i == len(content)-1 {
+				return "", errInvalidBER
+			}
+			continue
+		}
+		if len(parts) == 0 {
+			// the first subidentifier encodes the first two arcs
+			first := min(value/40, 2)
+			parts = append(parts, fmt.Sprint(first), fmt.Sprint(value-first*40))
+		} else {
+			parts = append(parts, fmt.Sprint(value))
+		}
+		value = 0
+	}
+	return strings.Join(parts, "."), nil
+}
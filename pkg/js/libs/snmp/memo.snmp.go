@@ -0,0 +1,58 @@
+// Warning - This is generated code
+package snmp
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[EngineIDResponse]("snmp.getEngineID", 3)
+}
+
+func memoizedgetEngineID(executionId string, host string, port int) (EngineIDResponse, error) {
+	hash := "snmp.getEngineID" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "snmp.getEngineID" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (EngineIDResponse, error) {
+			return getEngineID(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return EngineIDResponse{}, err
+	}
+	if value, ok := v.(EngineIDResponse); ok {
+		return value, nil
+	}
+
+	return EngineIDResponse{}, errors.New("could not convert cached result")
+}
+
+func init() {
+	protocolstate.RegisterMemoized[CheckV3UserResponse]("snmp.checkV3User", 4)
+}
+
+func memoizedcheckV3User(executionId string, host string, port int, user string) (CheckV3UserResponse, error) {
+	hash := "snmp.checkV3User" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(user)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "snmp.checkV3User" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(user)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (CheckV3UserResponse, error) {
+			return checkV3User(executionId, host, port, user)
+		})
+	})
+	if err != nil {
+		return CheckV3UserResponse{}, err
+	}
+	if value, ok := v.(CheckV3UserResponse); ok {
+		return value, nil
+	}
+
+	return CheckV3UserResponse{}, errors.New("could not convert cached result")
+}
@@ -0,0 +1,375 @@
+package snmp
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	snmpVersion3     = 3
+	usmSecurityModel = 3
+	// maximum message size accepted in responses
+	maxMessageSize = 65507
+
+	// msgFlags of the snmpv3 header
+	flagAuth       = 0x01
+	flagReportable = 0x04
+
+	pduGetRequest  = 0xa0
+	pduGetResponse = 0xa2
+	pduReport      = 0xa8
+
+	// timeout of the whole discovery exchange
+	requestTimeout = 5 * time.Second
+)
+
+var (
+	errNoResponse      = errors.New("no snmpv3 response received (not a snmpv3 agent or filtered)")
+	errInvalidResponse = errors.New("invalid snmpv3 response")
+
+	// usm report oids (rfc 3414)
+	usmReports = map[string]string{
+		"1.3.6.1.6.3.15.1.1.1.0": "usmStatsUnsupportedSecLevels",
+		"1.3.6.1.6.3.15.1.1.2.0": "usmStatsNotInTimeWindows",
+		"1.3.6.1.6.3.15.1.1.3.0": "usmStatsUnknownUserNames",
+		"1.3.6.1.6.3.15.1.1.4.0": "usmStatsUnknownEngineIDs",
+		"1.3.6.1.6.3.15.1.1.5.0": "usmStatsWrongDigests",
+		"1.3.6.1.6.3.15.1.1.6.0": "usmStatsDecryptionErrors",
+	}
+)
+
+type (
+	// EngineIDResponse is the response from the GetEngineID function.
+	// this is returned by GetEngineID function.
+	// @example
+	// ```javascript
+	// const snmp = require('nuclei/snmp');
+	// const engine = snmp.GetEngineID('acme.com', 161);
+	// log(toJSON(engine));
+	// ```
+	EngineIDResponse struct {
+		// EngineID is the hex encoded authoritative snmp engine id
+		EngineID string
+		// EngineBoots is the number of times the snmp engine was (re)initialized
+		EngineBoots int
+		// EngineTime is the number of seconds since the engine boots counter was last changed
+		EngineTime int
+	}
+
+	// CheckV3UserResponse is the response from the CheckV3User function.
+	// this is returned by CheckV3User function.
+	// @example
+	// ```javascript
+	// const snmp = require('nuclei/snmp');
+	// const user = snmp.CheckV3User('acme.com', 161, 'admin');
+	// log(toJSON(user));
+	// ```
+	CheckV3UserResponse struct {
+		// UserExists is true if the agent did not report the user as unknown
+		UserExists bool
+		// AuthRequired is true if the agent rejected the authentication digest of the user
+		AuthRequired bool
+		// Report is the usm report returned by the agent (ex: usmStatsUnknownUserNames)
+		Report string
+	}
+)
+
+// message is a parsed snmpv3 message
+type message struct {
+	msgID       int64
+	engineID    []byte
+	engineBoots int64
+	engineTime  int64
+	pduType     byte
+	oids        []string
+}
+
+// GetEngineID performs the snmpv3 engine discovery (rfc 3414 section 4)
+// and returns the authoritative engine id along with engine boots and time
+// from the report of the agent.
+// @example
+// ```javascript
+// const snmp = require('nuclei/snmp');
+// const engine = snmp.GetEngineID('acme.com', 161);
+// log(engine.EngineID, engine.EngineBoots, engine.EngineTime);
+// ```
+func GetEngineID(ctx context.Context, host string, port int) (EngineIDResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetEngineID(executionId, host, port)
+}
+
+// @memo
+func getEngineID(executionId string, host string, port int) (EngineIDResponse, error) {
+	conn, err := dialAgent(executionId, host, port)
+	if err != nil {
+		return EngineIDResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	discovery, err := discover(executionId, conn)
+	if err != nil {
+		return EngineIDResponse{}, err
+	}
+	return EngineIDResponse{
+		EngineID:    hex.EncodeToString(discovery.engineID),
+		EngineBoots: int(discovery.engineBoots),
+		EngineTime:  int(discovery.engineTime),
+	}, nil
+}
+
+// CheckV3User checks whether the given usm user exists on the snmpv3 agent.
+// After the engine discovery an authenticated request with an invalid digest
+// is sent for the user and the usm report of the agent is returned:
+// usmStatsUnknownUserNames for unknown users, usmStatsWrongDigests for users
+// with authentication and usmStatsUnsupportedSecLevels for users without it.
+// @example
+// ```javascript
+// const snmp = require('nuclei/snmp');
+// const user = snmp.CheckV3User('acme.com', 161, 'admin');
+// if (user.UserExists) { log('found snmpv3 user', user.Report); }
+// ```
+func CheckV3User(ctx context.Context, host string, port int, user string) (CheckV3UserResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedcheckV3User(executionId, host, port, user)
+}
+
+// @memo
+func checkV3User(executionId string, host string, port int, user string) (CheckV3UserResponse, error) {
+	conn, err := dialAgent(executionId, host, port)
+	if err != nil {
+		return CheckV3UserResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	discovery, err := discover(executionId, conn)
+	if err != nil {
+		return CheckV3UserResponse{}, err
+	}
+	// hmac-md5-96 and hmac-sha-96 digests are 12 bytes long
+	request, msgID := newMessage(executionId, flagAuth|flagReportable, discovery, user, make([]byte, 12))
+	response, err := exchange(conn, request, msgID)
+	if err != nil {
+		return CheckV3UserResponse{}, err
+	}
+	if response.pduType != pduReport {
+		// the request was processed so the user exists
+		return CheckV3UserResponse{UserExists: true}, nil
+	}
+	resp := CheckV3UserResponse{}
+	if len(response.oids) > 0 {
+		resp.Report = reportName(response.oids[0])
+	}
+	switch resp.Report {
+	case "usmStatsUnknownUserNames":
+	case "usmStatsWrongDigests":
+		resp.UserExists = true
+		resp.AuthRequired = true
+	case "usmStatsUnknownEngineIDs":
+		return resp, fmt.Errorf("%w: engine id %x not accepted", errInvalidResponse, discovery.engineID)
+	default:
+		resp.UserExists = true
+	}
+	return resp, nil
+}
+
+// dialAgent opens an udp connection to the snmp agent
+func dialAgent(executionId string, host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	return protocolstate.DialWithDeadline(executionId, "udp", net.JoinHostPort(host, strconv.Itoa(port)), requestTimeout)
+}
+
+// discover sends an unauthenticated request without engine id and returns
+// the report of the agent containing its authoritative engine id
+func discover(executionId string, conn net.Conn) (*message, error) {
+	request, msgID := newMessage(executionId, flagReportable, &message{}, "", nil)
+	response, err := exchange(conn, request, msgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.engineID) == 0 {
+		return nil, fmt.Errorf("%w: empty engine id", errInvalidResponse)
+	}
+	return response, nil
+}
+
+// exchange sends request and returns the response matching msgID
+func exchange(conn net.Conn, request []byte, msgID int64) (*message, error) {
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+	buff := make([]byte, maxMessageSize)
+	for {
+		n, err := conn.Read(buff)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, errNoResponse
+			}
+			return nil, err
+		}
+		response, err := parseMessage(buff[:n])
+		if err != nil {
+			return nil, err
+		}
+		// ignore stray datagrams not matching our message id
+		if response.msgID != msgID {
+			continue
+		}
+		return response, nil
+	}
+}
+
+// newMessage builds a snmpv3 get request with usm security parameters of
+// the given engine and user and returns it with its message id. message
+// and request ids are seeded by the execution probe seed (if any)
+func newMessage(executionId string, flags byte, engine *message, user string, authParams []byte) ([]byte, int64) {
+	ids := make([]byte, 8)
+	protocolstate.RandomBytes(executionId, ids)
+	msgID := int64(binary.BigEndian.Uint32(ids[:4]) & 0x7fffffff)
+	requestID := int64(binary.BigEndian.Uint32(ids[4:]) & 0x7fffffff)
+
+	globalData := berTLV(tagSequence,
+		berInteger(msgID),
+		berInteger(maxMessageSize),
+		berOctetString([]byte{flags}),
+		berInteger(usmSecurityModel),
+	)
+	securityParameters := berTLV(tagSequence,
+		berOctetString(engine.engineID),
+		berInteger(engine.engineBoots),
+		berInteger(engine.engineTime),
+		berOctetString([]byte(user)),
+		berOctetString(authParams),
+		berOctetString(nil),
+	)
+	pdu := berTLV(pduGetRequest,
+		berInteger(requestID),
+		berInteger(0), // error status
+		berInteger(0), // error index
+		berTLV(tagSequence),
+	)
+	scopedPDU := berTLV(tagSequence,
+		berOctetString(engine.engineID),
+		berOctetString(nil),
+		pdu,
+	)
+	return berTLV(tagSequence,
+		berInteger(snmpVersion3),
+		globalData,
+		berOctetString(securityParameters),
+		scopedPDU,
+	), msgID
+}
+
+// parseMessage parses a plaintext snmpv3 message with usm security parameters
+func parseMessage(data []byte) (*message, error) {
+	msg := &message{}
+	content, _, err := expectTLV(data, tagSequence)
+	if err != nil {
+		return nil, errInvalidResponse
+	}
+	version, content, err := readInteger(content)
+	if err != nil || version != snmpVersion3 {
+		return nil, errInvalidResponse
+	}
+	globalData, content, err := expectTLV(content, tagSequence)
+	if err != nil {
+		return nil, errInvalidResponse
+	}
+	if msg.msgID, _, err = readInteger(globalData); err != nil {
+		return nil, errInvalidResponse
+	}
+	securityParameters, content, err := expectTLV(content, tagOctetString)
+	if err != nil {
+		return nil, errInvalidResponse
+	}
+	if err := parseSecurityParameters(securityParameters, msg); err != nil {
+		return nil, err
+	}
+
+	scopedPDU, _, err := expectTLV(content, tagSequence)
+	if err != nil {
+		// encrypted scoped pdus are not expected in reports
+		return nil, errInvalidResponse
+	}
+	for i := 0; i < 2; i++ {
+		// context engine id and context name
+		if _, scopedPDU, err = expectTLV(scopedPDU, tagOctetString); err != nil {
+			return nil, errInvalidResponse
+		}
+	}
+	pduType, pdu, _, err := readTLV(scopedPDU)
+	if err != nil {
+		return nil, errInvalidResponse
+	}
+	msg.pduType = pduType
+	if pduType != pduReport && pduType != pduGetResponse {
+		return nil, fmt.Errorf("%w: unexpected pdu type 0x%02x", errInvalidResponse, pduType)
+	}
+	for i := 0; i < 3; i++ {
+		// request id, error status and error index
+		if _, pdu, err = readInteger(pdu); err != nil {
+			return nil, errInvalidResponse
+		}
+	}
+	varbinds, _, err := expectTLV(pdu, tagSequence)
+	if err != nil {
+		return nil, errInvalidResponse
+	}
+	for len(varbinds) > 0 {
+		var varbind []byte
+		if varbind, varbinds, err = expectTLV(varbinds, tagSequence); err != nil {
+			return nil, errInvalidResponse
+		}
+		content, _, err := expectTLV(varbind, tagOID)
+		if err != nil {
+			return nil, errInvalidResponse
+		}
+		oid, err := decodeOID(content)
+		if err != nil {
+			return nil, errInvalidResponse
+		}
+		msg.oids = append(msg.oids, oid)
+	}
+	return msg, nil
+}
+
+// parseSecurityParameters parses the usm security parameters into msg
+func parseSecurityParameters(data []byte, msg *message) error {
+	content, _, err := expectTLV(data, tagSequence)
+	if err != nil {
+		return errInvalidResponse
+	}
+	if msg.engineID, content, err = expectTLV(content, tagOctetString); err != nil {
+		return errInvalidResponse
+	}
+	if msg.engineBoots, content, err = readInteger(content); err != nil {
+		return errInvalidResponse
+	}
+	if msg.engineTime, _, err = readInteger(content); err != nil {
+		return errInvalidResponse
+	}
+	return nil
+}
+
+// reportName returns the name of a usm report oid
+func reportName(oid string) string {
+	if name, ok := usmReports[oid]; ok {
+		return name
+	}
+	return oid
+}
@@ -0,0 +1,151 @@
+package snmp
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+)
+
+// testEngineID is the engine id of the test agent
+var testEngineID = []byte{0x80, 0x00, 0x1f, 0x88, 0x80, 0x5e, 0x12, 0x34, 0x56}
+
+// encodeOID encodes a dotted object identifier
+func encodeOID(oid string) []byte {
+	var arcs []uint64
+	for _, part := range strings.Split(oid, ".") {
+		arc, _ := strconv.ParseUint(part, 10, 64)
+		arcs = append(arcs, arc)
+	}
+	content := []byte{byte(arcs[0]*40 + arcs[1])}
+	for _, arc := range arcs[2:] {
+		encoded := []byte{byte(arc & 0x7f)}
+		for arc >>= 7; arc > 0; arc >>= 7 {
+			encoded = append([]byte{byte(arc&0x7f) | 0x80}, encoded...)
+		}
+		content = append(content, encoded...)
+	}
+	return berTLV(tagOID, content)
+}
+
+// parseRequest returns the message id, engine id and user of a snmpv3 request
+func parseRequest(t *testing.T, data []byte) (int64, []byte, string) {
+	content, _, err := expectTLV(data, tagSequence)
+	if err != nil {
+		t.Error(err)
+		return 0, nil, ""
+	}
+	_, content, _ = readInteger(content)
+	globalData, content, _ := expectTLV(content, tagSequence)
+	msgID, _, _ := readInteger(globalData)
+	securityParameters, _, _ := expectTLV(content, tagOctetString)
+	usm, _, _ := expectTLV(securityParameters, tagSequence)
+	engineID, usm, _ := expectTLV(usm, tagOctetString)
+	_, usm, _ = readInteger(usm)
+	_, usm, _ = readInteger(usm)
+	user, _, _ := expectTLV(usm, tagOctetString)
+	return msgID, engineID, string(user)
+}
+
+// newReport builds a usm report of given oid for msgID
+func newReport(msgID int64, user string, oid string) []byte {
+	securityParameters := berTLV(tagSequence,
+		berOctetString(testEngineID), berInteger(3), berInteger(86400),
+		berOctetString([]byte(user)), berOctetString(nil), berOctetString(nil))
+	varbind := berTLV(tagSequence, encodeOID(oid), berTLV(0x41, []byte{0x01}))
+	pdu := berTLV(pduReport, berInteger(1), berInteger(0), berInteger(0), berTLV(tagSequence, varbind))
+	return berTLV(tagSequence,
+		berInteger(snmpVersion3),
+		berTLV(tagSequence, berInteger(msgID), berInteger(maxMessageSize), berOctetString([]byte{0x00}), berInteger(usmSecurityModel)),
+		berOctetString(securityParameters),
+		berTLV(tagSequence, berOctetString(testEngineID), berOctetString(nil), pdu))
+}
+
+// snmpAgent answers snmpv3 discovery and usm requests. admin is an
+// authenticated user, public a user without authentication
+func snmpAgent(t *testing.T) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buff := make([]byte, 4096)
+		for {
+			n, addr, err := conn.ReadFrom(buff)
+			if err != nil {
+				return
+			}
+			msgID, engineID, user := parseRequest(t, buff[:n])
+			var oid string
+			switch {
+			case len(engineID) == 0:
+				oid = "1.3.6.1.6.3.15.1.1.4.0"
+				// a stray datagram precedes the report
+				_, _ = conn.WriteTo(newReport(msgID+1, user, oid), addr)
+			case user == "admin":
+				oid = "1.3.6.1.6.3.15.1.1.5.0"
+			case user == "public":
+				oid = "1.3.6.1.6.3.15.1.1.1.0"
+			default:
+				oid = "1.3.6.1.6.3.15.1.1.3.0"
+			}
+			_, _ = conn.WriteTo(newReport(msgID, user, oid), addr)
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestGetEngineID(t *testing.T) {
+	ctx := jstest.Context(t)
+	port := snmpAgent(t)
+
+	got, err := GetEngineID(ctx, "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := EngineIDResponse{EngineID: "80001f88805e123456", EngineBoots: 3, EngineTime: 86400}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCheckV3User(t *testing.T) {
+	ctx := jstest.Context(t)
+	port := snmpAgent(t)
+
+	tests := []struct {
+		user string
+		want CheckV3UserResponse
+	}{
+		{user: "admin", want: CheckV3UserResponse{UserExists: true, AuthRequired: true, Report: "usmStatsWrongDigests"}},
+		{user: "public", want: CheckV3UserResponse{UserExists: true, Report: "usmStatsUnsupportedSecLevels"}},
+		{user: "guest", want: CheckV3UserResponse{Report: "usmStatsUnknownUserNames"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			got, err := CheckV3User(ctx, "127.0.0.1", port, tt.user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBERInteger(t *testing.T) {
+	for _, value := range []int64{0, 1, 127, 128, 255, 256, 65507, -1, -128, -129, 2147483647} {
+		got, rest, err := readInteger(berInteger(value))
+		if err != nil || len(rest) != 0 || got != value {
+			t.Fatalf("expected %d, got %d (%v)", value, got, err)
+		}
+	}
+	if got, err := decodeOID(encodeOID("1.3.6.1.6.3.15.1.1.4.0")[2:]); err != nil || got != "1.3.6.1.6.3.15.1.1.4.0" {
+		t.Fatalf("unexpected oid %s (%v)", got, err)
+	}
+}
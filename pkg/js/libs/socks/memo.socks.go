@@ -0,0 +1,34 @@
+// Warning - This is generated code
+package socks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[IsOpenProxyResponse]("socks.isOpenProxy", 3)
+}
+
+func memoizedisOpenProxy(ctx context.Context, executionId string, host string, port int) (IsOpenProxyResponse, error) {
+	hash := "socks.isOpenProxy" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "socks.isOpenProxy" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsOpenProxyResponse, error) {
+			return isOpenProxy(ctx, executionId, host, port)
+		})
+	})
+	if err != nil {
+		return IsOpenProxyResponse{}, err
+	}
+	if value, ok := v.(IsOpenProxyResponse); ok {
+		return value, nil
+	}
+
+	return IsOpenProxyResponse{}, errors.New("could not convert cached result")
+}
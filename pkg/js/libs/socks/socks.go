@@ -0,0 +1,156 @@
+package socks
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// benign endpoint used to verify that the proxy forwards connections
+	probeHost = "example.com"
+	probePort = 80
+	// timeout of each handshake attempt
+	probeTimeout = 5 * time.Second
+)
+
+type (
+	// IsOpenProxyResponse is the response from the IsOpenProxy function.
+	// this is returned by IsOpenProxy function.
+	// @example
+	// ```javascript
+	// const socks = require('nuclei/socks');
+	// const proxy = socks.IsOpenProxy('acme.com', 1080);
+	// log(toJSON(proxy));
+	// ```
+	IsOpenProxyResponse struct {
+		// IsSOCKS is true if the server speaks SOCKS4 or SOCKS5
+		IsSOCKS bool
+		// Version is the SOCKS version (4 or 5) spoken by the server
+		Version int
+		// AuthRequired is true if the server requires authentication (or ident for SOCKS4)
+		AuthRequired bool
+		// OpenProxy is true if the server forwarded a connection without authentication
+		OpenProxy bool
+	}
+)
+
+// IsOpenProxy checks if the given host and port are running an open SOCKS proxy.
+// It performs a SOCKS5 handshake (falling back to SOCKS4a) without authentication
+// and tries to connect through the proxy to a benign external endpoint.
+// No data is sent through the established tunnel.
+// @example
+// ```javascript
+// const socks = require('nuclei/socks');
+// const proxy = socks.IsOpenProxy('acme.com', 1080);
+// log(toJSON(proxy));
+// ```
+func IsOpenProxy(ctx context.Context, host string, port int) (IsOpenProxyResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedisOpenProxy(ctx, executionId, host, port)
+}
+
+// @memo
+func isOpenProxy(ctx context.Context, executionId string, host string, port int) (IsOpenProxyResponse, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return IsOpenProxyResponse{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", address, probeTimeout)
+	if err != nil {
+		return IsOpenProxyResponse{}, err
+	}
+	resp, err := probeSOCKS5(conn)
+	_ = conn.Close()
+	if err == nil && resp.IsSOCKS {
+		return resp, nil
+	}
+
+	// not socks5 try socks4a on a fresh connection
+	conn, err = protocolstate.DialContext(ctx, executionId, "tcp", address, probeTimeout)
+	if err != nil {
+		return IsOpenProxyResponse{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	return probeSOCKS4(conn)
+}
+
+// probeSOCKS5 performs a SOCKS5 handshake offering no-auth and
+// username/password methods and requests a connect to the probe endpoint
+func probeSOCKS5(conn net.Conn) (IsOpenProxyResponse, error) {
+	resp := IsOpenProxyResponse{}
+	// version 5, 2 methods: no-auth (0x00), username/password (0x02)
+	if _, err := conn.Write([]byte{0x05, 0x02, 0x00, 0x02}); err != nil {
+		return resp, err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return resp, err
+	}
+	if reply[0] != 0x05 {
+		return resp, nil
+	}
+	resp.IsSOCKS = true
+	resp.Version = 5
+	if reply[1] != 0x00 {
+		// server selected username/password (or another method) or rejected no-auth
+		resp.AuthRequired = true
+		return resp, nil
+	}
+
+	// connect request with domain name address type
+	req := []byte{0x05, 0x01, 0x00, 0x03, byte(len(probeHost))}
+	req = append(req, probeHost...)
+	req = binary.BigEndian.AppendUint16(req, probePort)
+	if _, err := conn.Write(req); err != nil {
+		return resp, err
+	}
+	// only the reply code is required
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return resp, err
+	}
+	resp.OpenProxy = header[0] == 0x05 && header[1] == 0x00
+	return resp, nil
+}
+
+// probeSOCKS4 performs a SOCKS4a connect request to the probe endpoint
+func probeSOCKS4(conn net.Conn) (IsOpenProxyResponse, error) {
+	resp := IsOpenProxyResponse{}
+	// version 4, connect, port, ip 0.0.0.1 (socks4a), empty userid, hostname
+	req := []byte{0x04, 0x01}
+	req = binary.BigEndian.AppendUint16(req, probePort)
+	req = append(req, 0x00, 0x00, 0x00, 0x01, 0x00)
+	req = append(req, probeHost...)
+	req = append(req, 0x00)
+	if _, err := conn.Write(req); err != nil {
+		return resp, err
+	}
+	reply := make([]byte, 8)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		// neither socks5 nor socks4 handshake succeeded
+		return resp, nil
+	}
+	if reply[0] != 0x00 || reply[1] < 0x5a || reply[1] > 0x5d {
+		return resp, nil
+	}
+	resp.IsSOCKS = true
+	resp.Version = 4
+	switch reply[1] {
+	case 0x5a:
+		resp.OpenProxy = true
+	case 0x5c, 0x5d:
+		// identd required / identd user mismatch
+		resp.AuthRequired = true
+	}
+	return resp, nil
+}
@@ -0,0 +1,58 @@
+// Warning - This is generated code
+package ssdp
+
+import (
+	"errors"
+
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[[]SSDPResponse]("ssdp.discover", 2)
+}
+
+func memoizeddiscover(executionId string, host string) ([]SSDPResponse, error) {
+	hash := "ssdp.discover" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "ssdp.discover" + ":" + fmt.Sprint(host)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() ([]SSDPResponse, error) {
+			return discover(executionId, host)
+		})
+	})
+	if err != nil {
+		return []SSDPResponse{}, err
+	}
+	if value, ok := v.([]SSDPResponse); ok {
+		return value, nil
+	}
+
+	return []SSDPResponse{}, errors.New("could not convert cached result")
+}
+
+func init() {
+	protocolstate.RegisterMemoized[DeviceDescription]("ssdp.getDeviceDescription", 2)
+}
+
+func memoizedgetDeviceDescription(executionId string, url string) (DeviceDescription, error) {
+	hash := "ssdp.getDeviceDescription" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(url)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "ssdp.getDeviceDescription" + ":" + fmt.Sprint(url)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (DeviceDescription, error) {
+			return getDeviceDescription(executionId, url)
+		})
+	})
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+	if value, ok := v.(DeviceDescription); ok {
+		return value, nil
+	}
+
+	return DeviceDescription{}, errors.New("could not convert cached result")
+}
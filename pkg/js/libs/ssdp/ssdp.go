@@ -0,0 +1,262 @@
+package ssdp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	ssdpPort = 1900
+	// timeout for fetching device descriptions
+	fetchTimeout = 10 * time.Second
+)
+
+var (
+	ssdpGroup = net.IPv4(239, 255, 255, 250)
+)
+
+type (
+	// SSDPResponse is a response to a SSDP M-SEARCH request.
+	// this is returned by Discover function.
+	// @example
+	// ```javascript
+	// const ssdp = require('nuclei/ssdp');
+	// const devices = ssdp.Discover('192.168.1.1');
+	// log(toJSON(devices));
+	// ```
+	SSDPResponse struct {
+		// Address is the address of the responding device
+		Address string
+		// ST is the search target of the response
+		ST string
+		// USN is the unique service name of the device
+		USN string
+		// Server is the value of SERVER header (os, upnp version and product)
+		Server string
+		// Location is the url of the device description
+		Location string
+	}
+
+	// DeviceDescription is the parsed UPnP device description.
+	// this is returned by GetDeviceDescription function.
+	// @example
+	// ```javascript
+	// const ssdp = require('nuclei/ssdp');
+	// const device = ssdp.GetDeviceDescription('http://192.168.1.1:49152/rootDesc.xml');
+	// log(device.Manufacturer, device.ModelName);
+	// ```
+	DeviceDescription struct {
+		DeviceType       string
+		FriendlyName     string
+		Manufacturer     string
+		ModelName        string
+		ModelNumber      string
+		ModelDescription string
+		SerialNumber     string
+		UDN              string
+		// Services contains service types of the device and its embedded devices
+		Services []string
+		// IsIGD is true for internet gateway devices exposing port mapping services
+		IsIGD bool
+	}
+)
+
+// Discover sends a SSDP M-SEARCH request (ssdp:all) to the given host and collects
+// the responses until the read timeout of the execution is reached. If host is empty
+// the request is sent to the SSDP multicast group (239.255.255.250).
+// @example
+// ```javascript
+// const ssdp = require('nuclei/ssdp');
+// const devices = ssdp.Discover('192.168.1.1');
+// log(toJSON(devices));
+// ```
+func Discover(ctx context.Context, host string) ([]SSDPResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizeddiscover(executionId, host)
+}
+
+// @memo
+func discover(executionId string, host string) ([]SSDPResponse, error) {
+	dst, err := protocolstate.ResolveUDPTarget(executionId, host, ssdpGroup, ssdpPort)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := protocolstate.ListenUDP(executionId, "0.0.0.0:0", false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	request := "M-SEARCH * HTTP/1.1\r\n" +
+		fmt.Sprintf("HOST: %s\r\n", net.JoinHostPort(ssdpGroup.String(), fmt.Sprint(ssdpPort))) +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: ssdp:all\r\n\r\n"
+	if _, err := conn.WriteToUDP([]byte(request), dst); err != nil {
+		return nil, err
+	}
+
+	// collect responses until discovery window is over
+	_ = conn.SetReadDeadline(protocolstate.GetDeadline(executionId, protocolstate.GetTimeouts(executionId).TcpReadTimeout))
+	var responses []SSDPResponse
+	seen := map[string]struct{}{}
+	buff := make([]byte, 8192)
+	for {
+		n, addr, err := conn.ReadFromUDP(buff)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return responses, err
+		}
+		resp, ok := parseResponse(buff[:n])
+		if !ok {
+			continue
+		}
+		resp.Address = addr.IP.String()
+		key := resp.Address + "|" + resp.USN + "|" + resp.ST
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// GetDeviceDescription fetches the UPnP device description from given url
+// (usually the Location of a SSDP response) and parses the device details.
+// @example
+// ```javascript
+// const ssdp = require('nuclei/ssdp');
+// const device = ssdp.GetDeviceDescription('http://192.168.1.1:49152/rootDesc.xml');
+// log(toJSON(device));
+// ```
+func GetDeviceDescription(ctx context.Context, url string) (DeviceDescription, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedgetDeviceDescription(executionId, url)
+}
+
+// @memo
+func getDeviceDescription(executionId string, url string) (DeviceDescription, error) {
+	client, err := utils.NewHTTPClient(executionId, fetchTimeout)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return DeviceDescription{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	body, err := utils.ReadAll(executionId, resp.Body)
+	if err != nil {
+		return DeviceDescription{}, err
+	}
+	return parseDescription(body)
+}
+
+// parseResponse parses a http like M-SEARCH response or NOTIFY message
+func parseResponse(data []byte) (SSDPResponse, bool) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	var header http.Header
+	if bytes.HasPrefix(data, []byte("HTTP/")) {
+		resp, err := http.ReadResponse(reader, nil)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			return SSDPResponse{}, false
+		}
+		header = resp.Header
+	} else if bytes.HasPrefix(data, []byte("NOTIFY ")) {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return SSDPResponse{}, false
+		}
+		header = req.Header
+	} else {
+		return SSDPResponse{}, false
+	}
+	st := header.Get("ST")
+	if st == "" {
+		st = header.Get("NT")
+	}
+	return SSDPResponse{
+		ST:       st,
+		USN:      header.Get("USN"),
+		Server:   header.Get("Server"),
+		Location: header.Get("Location"),
+	}, true
+}
+
+type xmlDevice struct {
+	DeviceType       string       `xml:"deviceType"`
+	FriendlyName     string       `xml:"friendlyName"`
+	Manufacturer     string       `xml:"manufacturer"`
+	ModelName        string       `xml:"modelName"`
+	ModelNumber      string       `xml:"modelNumber"`
+	ModelDescription string       `xml:"modelDescription"`
+	SerialNumber     string       `xml:"serialNumber"`
+	UDN              string       `xml:"UDN"`
+	Services         []xmlService `xml:"serviceList>service"`
+	Devices          []xmlDevice  `xml:"deviceList>device"`
+}
+
+type xmlService struct {
+	ServiceType string `xml:"serviceType"`
+}
+
+// parseDescription parses UPnP device description xml
+func parseDescription(data []byte) (DeviceDescription, error) {
+	var root struct {
+		Device xmlDevice `xml:"device"`
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return DeviceDescription{}, err
+	}
+	device := root.Device
+	desc := DeviceDescription{
+		DeviceType:       strings.TrimSpace(device.DeviceType),
+		FriendlyName:     strings.TrimSpace(device.FriendlyName),
+		Manufacturer:     strings.TrimSpace(device.Manufacturer),
+		ModelName:        strings.TrimSpace(device.ModelName),
+		ModelNumber:      strings.TrimSpace(device.ModelNumber),
+		ModelDescription: strings.TrimSpace(device.ModelDescription),
+		SerialNumber:     strings.TrimSpace(device.SerialNumber),
+		UDN:              strings.TrimSpace(device.UDN),
+	}
+	desc.IsIGD = strings.Contains(desc.DeviceType, "InternetGatewayDevice")
+
+	// walk embedded devices for services
+	var walk func(d xmlDevice)
+	walk = func(d xmlDevice) {
+		for _, service := range d.Services {
+			serviceType := strings.TrimSpace(service.ServiceType)
+			desc.Services = append(desc.Services, serviceType)
+			if strings.Contains(serviceType, "WANIPConnection") || strings.Contains(serviceType, "WANPPPConnection") {
+				desc.IsIGD = true
+			}
+		}
+		for _, embedded := range d.Devices {
+			walk(embedded)
+		}
+	}
+	walk(device)
+	return desc, nil
+}
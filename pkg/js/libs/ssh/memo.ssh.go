@@ -2,7 +2,9 @@
 package ssh
 
 import (
+	"context"
 	"errors"
+
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
@@ -10,11 +12,15 @@ import (
 	"github.com/zmap/zgrab2/lib/ssh"
 )
 
-func memoizedconnectSSHInfoMode(opts *connectOptions) (*ssh.HandshakeLog, error) {
-	hash := "connectSSHInfoMode" + ":" + fmt.Sprint(opts)
+func init() {
+	protocolstate.RegisterMemoized[*ssh.HandshakeLog]("ssh.connectSSHInfoMode", 1)
+}
 
+func memoizedconnectSSHInfoMode(ctx context.Context, opts *connectOptions) (*ssh.HandshakeLog, error) {
+	hash := "ssh.connectSSHInfoMode" + ":" + fmt.Sprint(opts)
+	// results are only memoized in memory (nodisk)
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return connectSSHInfoMode(opts)
+		return connectSSHInfoMode(ctx, opts)
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package ssh
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[hostKey]("ssh.getHostKey", 3)
+}
+
+func memoizedgetHostKey(executionId string, host string, port int) (hostKey, error) {
+	hash := "ssh.getHostKey" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "ssh.getHostKey" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (hostKey, error) {
+			return getHostKey(executionId, host, port)
+		})
+	})
+	if err != nil {
+		return hostKey{}, err
+	}
+	if value, ok := v.(hostKey); ok {
+		return value, nil
+	}
+
+	return hostKey{}, errors.New("could not convert cached result")
+}
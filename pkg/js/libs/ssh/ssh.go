@@ -107,7 +107,7 @@ func (c *SSHClient) ConnectWithKey(ctx context.Context, host string, port int, u
 // ```
 func (c *SSHClient) ConnectSSHInfoMode(ctx context.Context, host string, port int) (*ssh.HandshakeLog, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedconnectSSHInfoMode(&connectOptions{
+	return memoizedconnectSSHInfoMode(ctx, &connectOptions{
 		Host:        host,
 		Port:        port,
 		ExecutionId: executionId,
@@ -192,8 +192,8 @@ func (c *connectOptions) validate() error {
 	return nil
 }
 
-// @memo
-func connectSSHInfoMode(opts *connectOptions) (*ssh.HandshakeLog, error) {
+// @memo(nodisk)
+func connectSSHInfoMode(ctx context.Context, opts *connectOptions) (*ssh.HandshakeLog, error) {
 	if err := opts.validate(); err != nil {
 		return nil, err
 	}
@@ -209,10 +209,16 @@ func connectSSHInfoMode(opts *connectOptions) (*ssh.HandshakeLog, error) {
 		return nil
 	}
 	rhost := fmt.Sprintf("%s:%d", opts.Host, opts.Port)
-	client, err := ssh.Dial("tcp", rhost, sshConfig)
+	conn, err := protocolstate.DialContext(ctx, opts.ExecutionId, "tcp", rhost, sshConfig.Timeout)
 	if err != nil {
 		return nil, err
 	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, rhost, sshConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(c, chans, reqs)
 	defer func() {
 		_ = client.Close()
 	}()
@@ -0,0 +1,127 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/zmap/zgrab2/lib/ssh"
+)
+
+const (
+	// timeout of the key exchange used to collect host key
+	hostKeyTimeout = 10 * time.Second
+)
+
+var (
+	// errHostKeyCollected aborts the handshake once the host key is known
+	errHostKeyCollected = errors.New("host key collected")
+)
+
+type (
+	// VerifyHostKeyResponse is the response from the VerifyHostKey function.
+	// this is returned by VerifyHostKey function.
+	// @example
+	// ```javascript
+	// const ssh = require('nuclei/ssh');
+	// const result = ssh.VerifyHostKey('acme.com', 22, 'SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8');
+	// log(toJSON(result));
+	// ```
+	VerifyHostKeyResponse struct {
+		// Matched is true if the host key matches the expected fingerprint
+		Matched bool
+		// Fingerprint is the sha256 fingerprint of the host key (SHA256:base64)
+		Fingerprint string
+		// KeyType is the type of the host key (ex: ssh-ed25519)
+		KeyType string
+	}
+
+	// hostKey is the host key presented by a ssh server
+	hostKey struct {
+		Fingerprint string
+		KeyType     string
+	}
+)
+
+// VerifyHostKey performs a key exchange with the given host and port and
+// checks if the host key matches the expected sha256 fingerprint. The
+// fingerprint can be given in OpenSSH format (SHA256:base64), as plain
+// base64 or as hex. The actual fingerprint is returned along with the result.
+// No authentication is attempted.
+// @example
+// ```javascript
+// const ssh = require('nuclei/ssh');
+// const result = ssh.VerifyHostKey('acme.com', 22, 'SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8');
+// log(result.Matched, result.Fingerprint);
+// ```
+func VerifyHostKey(ctx context.Context, host string, port int, expectedSHA256 string) (VerifyHostKeyResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	key, err := memoizedgetHostKey(executionId, host, port)
+	if err != nil {
+		return VerifyHostKeyResponse{}, err
+	}
+	return VerifyHostKeyResponse{
+		Matched:     fingerprintMatches(key.Fingerprint, expectedSHA256),
+		Fingerprint: key.Fingerprint,
+		KeyType:     key.KeyType,
+	}, nil
+}
+
+// @memo
+func getHostKey(executionId string, host string, port int) (hostKey, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return hostKey{}, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, hostKeyTimeout)
+	if err != nil {
+		return hostKey{}, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var key hostKey
+	sshConfig := ssh.MakeSSHConfig()
+	sshConfig.Timeout = hostKeyTimeout
+	sshConfig.HostKeyCallback = func(_ string, _ net.Addr, publicKey ssh.PublicKey) error {
+		key.Fingerprint = ssh.FingerprintSHA256(publicKey)
+		key.KeyType = publicKey.Type()
+		// abort before authentication
+		return errHostKeyCollected
+	}
+	_, _, _, err = ssh.NewClientConn(conn, address, sshConfig)
+	if key.Fingerprint != "" {
+		return key, nil
+	}
+	return hostKey{}, err
+}
+
+// fingerprintMatches compares a SHA256:base64 fingerprint with the expected
+// fingerprint given in OpenSSH, base64 or hex (optionally colon separated) format
+func fingerprintMatches(fingerprint, expected string) bool {
+	actual, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(fingerprint, "SHA256:"))
+	if err != nil {
+		return false
+	}
+	expected = strings.TrimSpace(expected)
+	if len(expected) >= 7 && strings.EqualFold(expected[:7], "SHA256:") {
+		expected = expected[7:]
+	}
+	if digest, err := hex.DecodeString(strings.ReplaceAll(expected, ":", "")); err == nil && len(digest) == len(actual) {
+		return bytes.Equal(digest, actual)
+	}
+	digest, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(expected, "="))
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(digest, actual)
+}
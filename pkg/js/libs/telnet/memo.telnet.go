@@ -2,17 +2,26 @@
 package telnet
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisTelnet(executionId string, host string, port int) (IsTelnetResponse, error) {
-	hash := "isTelnet" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[IsTelnetResponse]("telnet.isTelnet", 3)
+}
+
+func memoizedisTelnet(ctx context.Context, executionId string, host string, port int) (IsTelnetResponse, error) {
+	hash := "telnet.isTelnet" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "telnet.isTelnet" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isTelnet(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsTelnetResponse, error) {
+			return isTelnet(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return IsTelnetResponse{}, err
@@ -2,7 +2,6 @@ package telnet
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -36,20 +35,15 @@ type (
 // ```
 func IsTelnet(ctx context.Context, host string, port int) (IsTelnetResponse, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedisTelnet(executionId, host, port)
+	return memoizedisTelnet(ctx, executionId, host, port)
 }
 
 // @memo
-func isTelnet(executionId string, host string, port int) (IsTelnetResponse, error) {
+func isTelnet(ctx context.Context, executionId string, host string, port int) (IsTelnetResponse, error) {
 	resp := IsTelnetResponse{}
 
 	timeout := 5 * time.Second
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return IsTelnetResponse{}, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
 	if err != nil {
 		return resp, err
 	}
@@ -2,17 +2,26 @@
 package vnc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 )
 
-func memoizedisVNC(executionId string, host string, port int) (IsVNCResponse, error) {
-	hash := "isVNC" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+func init() {
+	protocolstate.RegisterMemoized[IsVNCResponse]("vnc.isVNC", 3)
+}
+
+func memoizedisVNC(ctx context.Context, executionId string, host string, port int) (IsVNCResponse, error) {
+	hash := "vnc.isVNC" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "vnc.isVNC" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port)
 
 	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
-		return isVNC(executionId, host, port)
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (IsVNCResponse, error) {
+			return isVNC(ctx, executionId, host, port)
+		})
 	})
 	if err != nil {
 		return IsVNCResponse{}, err
@@ -2,7 +2,6 @@ package vnc
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -37,19 +36,15 @@ type (
 // ```
 func IsVNC(ctx context.Context, host string, port int) (IsVNCResponse, error) {
 	executionId := ctx.Value("executionId").(string)
-	return memoizedisVNC(executionId, host, port)
+	return memoizedisVNC(ctx, executionId, host, port)
 }
 
 // @memo
-func isVNC(executionId string, host string, port int) (IsVNCResponse, error) {
+func isVNC(ctx context.Context, executionId string, host string, port int) (IsVNCResponse, error) {
 	resp := IsVNCResponse{}
 
 	timeout := 5 * time.Second
-	dialer := protocolstate.GetDialersWithId(executionId)
-	if dialer == nil {
-		return IsVNCResponse{}, fmt.Errorf("dialers not initialized for %s", executionId)
-	}
-	conn, err := dialer.Fastdialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	conn, err := protocolstate.DialContext(ctx, executionId, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
 	if err != nil {
 		return resp, err
 	}
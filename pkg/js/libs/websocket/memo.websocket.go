@@ -0,0 +1,33 @@
+// Warning - This is generated code
+package websocket
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func init() {
+	protocolstate.RegisterMemoized[HandshakeResponse]("websocket.handshake", 5)
+}
+
+func memoizedhandshake(executionId string, host string, port int, path string, opts ConnectOptions) (HandshakeResponse, error) {
+	hash := "websocket.handshake" + ":" + fmt.Sprint(executionId) + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path) + ":" + fmt.Sprint(opts)
+	// execution id is not part of the on-disk key so results can be reused across runs
+	diskKey := "websocket.handshake" + ":" + fmt.Sprint(host) + ":" + fmt.Sprint(port) + ":" + fmt.Sprint(path) + ":" + fmt.Sprint(opts)
+
+	v, err, _ := protocolstate.Memoizer.Do(hash, func() (interface{}, error) {
+		return protocolstate.MemoizeOnDisk(executionId, diskKey, func() (HandshakeResponse, error) {
+			return handshake(executionId, host, port, path, opts)
+		})
+	})
+	if err != nil {
+		return HandshakeResponse{}, err
+	}
+	if value, ok := v.(HandshakeResponse); ok {
+		return value, nil
+	}
+
+	return HandshakeResponse{}, errors.New("could not convert cached result")
+}
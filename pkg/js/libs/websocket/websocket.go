@@ -0,0 +1,348 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+const (
+	// default timeout of the handshake and frame operations
+	defaultTimeout = 10 * time.Second
+	// guid used to compute Sec-WebSocket-Accept (rfc 6455)
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+var (
+	errNotAccepted = errors.New("websocket upgrade was not accepted")
+)
+
+type (
+	// ConnectOptions contains options for Handshake and Connect functions.
+	// @example
+	// ```javascript
+	// const websocket = require('nuclei/websocket');
+	// const conn = websocket.Connect('acme.com', 443, '/ws', { TLS: true, Subprotocols: ['graphql-ws'] });
+	// ```
+	ConnectOptions struct {
+		// TLS performs the handshake over tls (wss)
+		TLS bool
+		// Headers are added to the upgrade request (ex: Origin, Cookie)
+		Headers map[string]string
+		// Subprotocols are offered in Sec-WebSocket-Protocol ordered by preference
+		Subprotocols []string
+		// Timeout is the timeout of the handshake and frame operations in seconds (default: 10)
+		Timeout int
+	}
+
+	// HandshakeResponse is the result of the websocket upgrade handshake.
+	// this is returned by Handshake function.
+	// @example
+	// ```javascript
+	// const websocket = require('nuclei/websocket');
+	// const resp = websocket.Handshake('acme.com', 80, '/ws');
+	// log(toJSON(resp));
+	// ```
+	HandshakeResponse struct {
+		// Accepted is true if the server switched protocols with a valid Sec-WebSocket-Accept
+		Accepted bool
+		// StatusCode is the http status code of the upgrade response
+		StatusCode int
+		// Subprotocol is the subprotocol selected by the server (if any)
+		Subprotocol string
+		// Headers are the headers of the upgrade response
+		Headers map[string]string
+	}
+
+	// Conn is a websocket connection.
+	// this is returned by Connect function.
+	// @example
+	// ```javascript
+	// const websocket = require('nuclei/websocket');
+	// const conn = websocket.Connect('acme.com', 80, '/ws');
+	// if (conn.Accepted) { conn.Send('ping'); log(conn.Recv()); }
+	// ```
+	Conn struct {
+		// Accepted is true if the server switched protocols with a valid Sec-WebSocket-Accept
+		Accepted bool
+		// StatusCode is the http status code of the upgrade response
+		StatusCode int
+		// Subprotocol is the subprotocol selected by the server (if any)
+		Subprotocol string
+		// Headers are the headers of the upgrade response
+		Headers map[string]string
+
+		executionId string
+		conn        net.Conn
+		reader      io.Reader
+		timeout     time.Duration
+	}
+)
+
+// Handshake performs the websocket upgrade handshake with the given host,
+// port and path and returns whether the server accepted the upgrade along
+// with the negotiated subprotocol and response headers. The connection is
+// closed after the handshake and the result is memoized on target and path.
+// @example
+// ```javascript
+// const websocket = require('nuclei/websocket');
+// const resp = websocket.Handshake('acme.com', 443, '/ws', { TLS: true });
+// log(resp.Accepted, resp.Subprotocol);
+// ```
+func Handshake(ctx context.Context, host string, port int, path string, opts ConnectOptions) (HandshakeResponse, error) {
+	executionId := ctx.Value("executionId").(string)
+	return memoizedhandshake(executionId, host, port, path, opts)
+}
+
+// @memo
+func handshake(executionId string, host string, port int, path string, opts ConnectOptions) (HandshakeResponse, error) {
+	conn, err := connect(executionId, host, port, path, opts)
+	if err != nil {
+		return HandshakeResponse{}, err
+	}
+	_ = conn.Close()
+	return HandshakeResponse{
+		Accepted:    conn.Accepted,
+		StatusCode:  conn.StatusCode,
+		Subprotocol: conn.Subprotocol,
+		Headers:     conn.Headers,
+	}, nil
+}
+
+// Connect performs the websocket upgrade handshake with the given host,
+// port and path and returns the connection. If the upgrade is accepted
+// frames can be exchanged using Send and Recv. Unlike Handshake the
+// result is not memoized.
+// @example
+// ```javascript
+// const websocket = require('nuclei/websocket');
+// const conn = websocket.Connect('acme.com', 443, '/ws', { TLS: true, Headers: { Origin: 'https://acme.com' } });
+// if (conn.Accepted) { conn.Send('{"type":"ping"}'); log(conn.Recv()); }
+// conn.Close();
+// ```
+func Connect(ctx context.Context, host string, port int, path string, opts ConnectOptions) (*Conn, error) {
+	executionId := ctx.Value("executionId").(string)
+	return connect(executionId, host, port, path, opts)
+}
+
+// connect dials the target and performs the upgrade handshake
+func connect(executionId string, host string, port int, path string, opts ConnectOptions) (*Conn, error) {
+	if !protocolstate.IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	timeout := defaultTimeout
+	if opts.Timeout > 0 {
+		timeout = time.Duration(opts.Timeout) * time.Second
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := protocolstate.DialWithDeadline(executionId, "tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if opts.TLS {
+		config := &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}
+		if net.ParseIP(host) == nil {
+			config.ServerName = host
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	c, err := upgrade(executionId, conn, address, path, opts)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	c.executionId = executionId
+	c.timeout = timeout
+	if !c.Accepted {
+		_ = conn.Close()
+	}
+	return c, nil
+}
+
+// upgrade sends the upgrade request over conn and validates the response
+func upgrade(executionId string, conn net.Conn, address string, path string, opts ConnectOptions) (*Conn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	scheme := "http"
+	if opts.TLS {
+		scheme = "https"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	req, err := http.NewRequest(http.MethodGet, scheme+"://"+address+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range opts.Headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if len(opts.Subprotocols) > 0 {
+		req.Header.Set("Sec-WebSocket-Protocol", strings.Join(opts.Subprotocols, ", "))
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(utils.LimitConn(executionId, conn))
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, err
+	}
+	c := &Conn{StatusCode: resp.StatusCode, Headers: make(map[string]string, len(resp.Header))}
+	for name, values := range resp.Header {
+		c.Headers[name] = strings.Join(values, ", ")
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		_ = resp.Body.Close()
+		return c, nil
+	}
+	c.Accepted = strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") &&
+		resp.Header.Get("Sec-WebSocket-Accept") == acceptKey(key)
+	if !c.Accepted {
+		return c, nil
+	}
+	c.Subprotocol = resp.Header.Get("Sec-WebSocket-Protocol")
+	c.conn = conn
+	// frames sent right after the response may already be buffered
+	c.reader = reader
+	return c, nil
+}
+
+// acceptKey returns the expected Sec-WebSocket-Accept value of key
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// readWriter reads from the buffered reader and writes to the connection
+type readWriter struct {
+	io.Reader
+	io.Writer
+}
+
+// stream returns the frame stream of the connection
+func (c *Conn) stream() (io.ReadWriter, error) {
+	if !c.Accepted || c.conn == nil {
+		return nil, errNotAccepted
+	}
+	if err := c.conn.SetDeadline(protocolstate.GetDeadline(c.executionId, c.timeout)); err != nil {
+		return nil, err
+	}
+	return readWriter{Reader: c.reader, Writer: c.conn}, nil
+}
+
+// SetTimeout sets the timeout of frame operations in seconds.
+// @example
+// ```javascript
+// const websocket = require('nuclei/websocket');
+// const conn = websocket.Connect('acme.com', 80, '/ws');
+// conn.SetTimeout(5);
+// ```
+func (c *Conn) SetTimeout(value int) {
+	c.timeout = time.Duration(value) * time.Second
+}
+
+// Send sends data as a text frame.
+// @example
+// ```javascript
+// const websocket = require('nuclei/websocket');
+// const conn = websocket.Connect('acme.com', 80, '/ws');
+// conn.Send('hello');
+// ```
+func (c *Conn) Send(data string) error {
+	stream, err := c.stream()
+	if err != nil {
+		return err
+	}
+	return wsutil.WriteClientMessage(stream, ws.OpText, []byte(data))
+}
+
+// SendBinary sends data as a binary frame.
+// @example
+// ```javascript
+// const websocket = require('nuclei/websocket');
+// const conn = websocket.Connect('acme.com', 80, '/ws');
+// conn.SendBinary([0x01, 0x02]);
+// ```
+func (c *Conn) SendBinary(data []byte) error {
+	stream, err := c.stream()
+	if err != nil {
+		return err
+	}
+	return wsutil.WriteClientMessage(stream, ws.OpBinary, data)
+}
+
+// Recv receives the next text or binary message as a string.
+// Control frames (ping, pong) are handled transparently.
+// @example
+// ```javascript
+// const websocket = require('nuclei/websocket');
+// const conn = websocket.Connect('acme.com', 80, '/ws');
+// conn.Send('hello');
+// const message = conn.Recv();
+// ```
+func (c *Conn) Recv() (string, error) {
+	stream, err := c.stream()
+	if err != nil {
+		return "", err
+	}
+	data, _, err := wsutil.ReadServerData(stream)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Close closes the connection sending a close frame if the upgrade was accepted.
+// @example
+// ```javascript
+// const websocket = require('nuclei/websocket');
+// const conn = websocket.Connect('acme.com', 80, '/ws');
+// conn.Close();
+// ```
+func (c *Conn) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	if stream, err := c.stream(); err == nil {
+		_ = wsutil.WriteClientMessage(stream, ws.OpClose, ws.NewCloseFrameBody(ws.StatusNormalClosure, ""))
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// ProbeAllAddrs resolves every A and AAAA record of host and runs probe with
+// each address using at most concurrency goroutines (see Stream), so that a
+// service can be confirmed on every backend of a round-robin, cdn or anycast
+// hostname. The results are returned in the order of the resolved addresses.
+func ProbeAllAddrs[R any](executionId string, host string, concurrency int, probe func(ip string) R) ([]R, error) {
+	ips, err := protocolstate.ResolveAll(executionId, host)
+	if err != nil {
+		return nil, err
+	}
+	type indexed struct {
+		index  int
+		result R
+	}
+	indexes := make([]int, len(ips))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	results := make([]R, len(ips))
+	err = Stream(indexes, concurrency, func(i int) indexed {
+		return indexed{index: i, result: probe(ips[i])}
+	}, func(r indexed) error {
+		results[r.index] = r.result
+		return nil
+	})
+	return results, err
+}
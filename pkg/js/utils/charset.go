@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// DecodeCharset transcodes data from the given charset (ex: shift_jis, latin1,
+// windows-1252) to utf-8. data is returned as is when charset is empty or utf-8.
+// charset names are resolved using the whatwg encoding labels.
+func DecodeCharset(data []byte, charset string) (string, error) {
+	charset = strings.TrimSpace(charset)
+	if charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "utf8") {
+		return string(data), nil
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return "", fmt.Errorf("unsupported charset %q: %w", charset, err)
+	}
+	decoded, err := encoding.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("could not decode %s data: %w", charset, err)
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,34 @@
+package utils
+
+import "testing"
+
+func TestDecodeCharset(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		charset string
+		want    string
+	}{
+		// "220 ようこそ FTP" encoded in shift_jis
+		{name: "shift_jis", data: []byte("220 \x82\xe6\x82\xa4\x82\xb1\x82\xbb FTP\r\n"), charset: "Shift_JIS", want: "220 ようこそ FTP\r\n"},
+		{name: "sjis label", data: []byte("\x83\x8d\x83O\x83C\x83\x93:"), charset: "sjis", want: "ログイン:"},
+		{name: "latin1", data: []byte("Bienvenue \xe0 bord\xa0!"), charset: "latin1", want: "Bienvenue à bord !"},
+		{name: "utf-8", data: []byte("héllo"), charset: "utf-8", want: "héllo"},
+		{name: "empty charset", data: []byte("\xff\xfe"), want: "\xff\xfe"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DecodeCharset(test.data, test.charset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+
+	if _, err := DecodeCharset([]byte("data"), "no-such-charset"); err == nil {
+		t.Fatal("expected unsupported charset error")
+	}
+}
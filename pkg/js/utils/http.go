@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// NewHTTPClient returns a http client using the dialer of given execution.
+// Redirects are not followed and hosts reached by the client are checked
+// against the network policy of the execution. Each request is bounded by
+// timeout and the scan deadline of the execution.
+func NewHTTPClient(executionId string, timeout time.Duration) (*http.Client, error) {
+	dialer, err := protocolstate.GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if !protocolstate.IsHostAllowed(executionId, host) {
+			// host is not valid according to network policy
+			return nil, protocolstate.ErrHostDenied.Msgf(host)
+		}
+		// keep-alives are disabled, the connection deadline bounds the request
+		deadline := protocolstate.GetDeadline(executionId, timeout)
+		ctx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+		conn, err := dialer.Fastdialer.Dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return protocolstate.RecordConn(executionId, conn), nil
+	}
+	transport := &http.Transport{
+		DialContext:       dial,
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10},
+		DisableKeepAlives: true,
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+func TestHTTPClientScanDeadline(t *testing.T) {
+	executionId := jstest.Init(t)
+
+	// server accepting connections without ever answering
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+
+	client, err := NewHTTPClient(executionId, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	protocolstate.SetScanDeadline(executionId, time.Now().Add(200*time.Millisecond))
+	start := time.Now()
+	if _, err := client.Get("http://" + ln.Addr().String()); err == nil {
+		t.Fatal("expected request to a silent server to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected request to stop at the scan deadline, took %s", elapsed)
+	}
+}
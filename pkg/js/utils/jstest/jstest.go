@@ -0,0 +1,73 @@
+// Package jstest contains helpers shared by the tests of javascript libraries.
+package jstest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// Init initializes the protocol state of an execution named after the test
+// and closes it when the test ends. The default options are modified by the
+// given functions (ex: to set resolvers or another execution id) before
+// initialization. The execution id is returned.
+func Init(t testing.TB, modifiers ...func(options *types.Options)) string {
+	t.Helper()
+	options := types.DefaultOptions()
+	options.ExecutionId = strings.ReplaceAll(t.Name(), "/", "-")
+	for _, modify := range modifiers {
+		modify(options)
+	}
+	if err := protocolstate.Init(options); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { protocolstate.Close(options.ExecutionId) })
+	return options.ExecutionId
+}
+
+// Context is Init returning the context javascript library functions are called with
+func Context(t testing.TB, modifiers ...func(options *types.Options)) context.Context {
+	t.Helper()
+	return ExecutionContext(Init(t, modifiers...))
+}
+
+// ExecutionContext returns the context javascript library functions are
+// called with for the given execution
+func ExecutionContext(executionId string) context.Context {
+	return context.WithValue(context.Background(), "executionId", executionId) //nolint
+}
+
+// TLSConfig returns a server tls config with a self signed certificate
+// for localhost negotiating one of nextProtos (if any)
+func TLSConfig(t testing.TB, nextProtos ...string) *tls.Config {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		NextProtos:   nextProtos,
+	}
+}
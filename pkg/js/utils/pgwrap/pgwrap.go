@@ -23,29 +23,37 @@ type pgDial struct {
 }
 
 func (p *pgDial) Dial(network, address string) (net.Conn, error) {
-	dialers := protocolstate.GetDialersWithId(p.executionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", p.executionId)
+	dialers, err := protocolstate.GetDialersOrError(p.executionId)
+	if err != nil {
+		return nil, err
 	}
-	return dialers.Fastdialer.Dial(context.TODO(), network, address)
+	return p.record(dialers.Fastdialer.Dial(context.TODO(), network, address))
 }
 
 func (p *pgDial) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
-	dialers := protocolstate.GetDialersWithId(p.executionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", p.executionId)
+	dialers, err := protocolstate.GetDialersOrError(p.executionId)
+	if err != nil {
+		return nil, err
 	}
 	ctx, cancel := context.WithTimeoutCause(context.Background(), timeout, fastdialer.ErrDialTimeout)
 	defer cancel()
-	return dialers.Fastdialer.Dial(ctx, network, address)
+	return p.record(dialers.Fastdialer.Dial(ctx, network, address))
 }
 
 func (p *pgDial) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
-	dialers := protocolstate.GetDialersWithId(p.executionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", p.executionId)
+	dialers, err := protocolstate.GetDialersOrError(p.executionId)
+	if err != nil {
+		return nil, err
+	}
+	return p.record(dialers.Fastdialer.Dial(ctx, network, address))
+}
+
+// record records conn to the pcap file of the execution (if enabled)
+func (p *pgDial) record(conn net.Conn, err error) (net.Conn, error) {
+	if err != nil {
+		return nil, err
 	}
-	return dialers.Fastdialer.Dial(ctx, network, address)
+	return protocolstate.RecordConn(p.executionId, conn), nil
 }
 
 // Unfortunately lib/pq does not provide easy to customize or
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"net"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+)
+
+// DefaultMaxReadBytes is the default maximum number of bytes
+// read from a server response by javascript libraries
+const DefaultMaxReadBytes int64 = 8 * 1024 * 1024
+
+// ErrResponseTooLarge is returned when a server response exceeds MaxReadBytes
+var ErrResponseTooLarge = errors.New("response exceeds maximum read size")
+
+// MaxReadBytes returns the maximum number of bytes read from a server response
+// for the given execution. It is the response read size (-rsr) if configured,
+// DefaultMaxReadBytes otherwise.
+func MaxReadBytes(executionId string) int64 {
+	if n := protocolstate.GetResponseReadSize(executionId); n > 0 {
+		return int64(n)
+	}
+	return DefaultMaxReadBytes
+}
+
+// ReadAll reads from r until EOF or MaxReadBytes is reached.
+// If the response is larger than MaxReadBytes the truncated data
+// is returned along with ErrResponseTooLarge.
+func ReadAll(executionId string, r io.Reader) ([]byte, error) {
+	limit := MaxReadBytes(executionId)
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if int64(len(data)) > limit {
+		return data[:limit], ErrResponseTooLarge
+	}
+	return data, err
+}
+
+// LimitConn wraps given connection so that reads fail with ErrResponseTooLarge
+// once more than MaxReadBytes have been read from it. It is used to bound
+// reads performed by third party protocol implementations.
+func LimitConn(executionId string, conn net.Conn) net.Conn {
+	return &limitedConn{Conn: conn, remaining: MaxReadBytes(executionId)}
+}
+
+type limitedConn struct {
+	net.Conn
+	remaining int64
+}
+
+// Read reads from the underlying connection until the limit is reached
+func (c *limitedConn) Read(b []byte) (int, error) {
+	if c.remaining <= 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(b)) > c.remaining {
+		b = b[:c.remaining]
+	}
+	n, err := c.Conn.Read(b)
+	c.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils/jstest"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// streamListener starts a listener that writes size bytes to every accepted connection
+func streamListener(t *testing.T, size int) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_, _ = conn.Write(bytes.Repeat([]byte("A"), size))
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestReadAllResponseTooLarge(t *testing.T) {
+	executionId := jstest.Init(t, func(options *types.Options) { options.ResponseReadSize = 1024 })
+
+	conn, err := net.Dial("tcp", streamListener(t, 64*1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	data, err := ReadAll(executionId, conn)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got=%v", err)
+	}
+	if len(data) != 1024 {
+		t.Fatalf("expected truncated read of 1024 bytes, got=%d", len(data))
+	}
+}
+
+func TestReadAllWithinLimit(t *testing.T) {
+	executionId := jstest.Init(t, func(options *types.Options) { options.ResponseReadSize = 1024 })
+
+	conn, err := net.Dial("tcp", streamListener(t, 512))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	data, err := ReadAll(executionId, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 512 {
+		t.Fatalf("expected 512 bytes, got=%d", len(data))
+	}
+}
+
+func TestLimitConnResponseTooLarge(t *testing.T) {
+	executionId := jstest.Init(t, func(options *types.Options) { options.ResponseReadSize = 1024 })
+
+	conn, err := net.Dial("tcp", streamListener(t, 64*1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	data, err := io.ReadAll(LimitConn(executionId, conn))
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got=%v", err)
+	}
+	if len(data) != 1024 {
+		t.Fatalf("expected truncated read of 1024 bytes, got=%d", len(data))
+	}
+}
+
+func TestMaxReadBytesDefault(t *testing.T) {
+	executionId := jstest.Init(t, func(options *types.Options) { options.ResponseReadSize = 0 })
+	if got := MaxReadBytes(executionId); got != DefaultMaxReadBytes {
+		t.Fatalf("expected default limit without -rsr, got=%d", got)
+	}
+}
+
+func TestTeeConnTruncated(t *testing.T) {
+	conn, err := net.Dial("tcp", streamListener(t, 4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tee := NewTeeConn(conn, 1024)
+	defer func() { _ = tee.Close() }()
+
+	if _, err := tee.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(tee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 4096 {
+		t.Fatalf("expected reads to be unaffected by the capture limit, got=%d", len(data))
+	}
+	if !bytes.Equal(tee.Sent(), []byte("ping")) || !bytes.Equal(tee.Received(), data[:1024]) {
+		t.Fatalf("unexpected capture sent=%q received=%d bytes", tee.Sent(), len(tee.Received()))
+	}
+	if !tee.Truncated() {
+		t.Fatal("expected capture to be truncated")
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// ErrPanic is wrapped by errors returned for panics recovered in protocol libraries
+var ErrPanic = errors.New("recovered panic")
+
+// RecoverPanic recovers a panic of the calling function and assigns err an error
+// wrapping ErrPanic and classify (if not nil). It must be deferred directly
+// ex: defer utils.RecoverPanic(&err, ErrMalformedResponse)
+func RecoverPanic(err *error, classify error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	gologger.Verbose().Msgf("recovered panic in protocol library: %v\n%s", r, debug.Stack())
+	if classify != nil {
+		*err = fmt.Errorf("%w: %w: %v", classify, ErrPanic, r)
+		return
+	}
+	*err = fmt.Errorf("%w: %v", ErrPanic, r)
+}
+
+// WithRecover calls fn and converts a panic (ex: in a dependency parsing a
+// hostile response) into an error, see RecoverPanic
+func WithRecover[T any](classify error, fn func() (T, error)) (result T, err error) {
+	defer RecoverPanic(&err, classify)
+	return fn()
+}
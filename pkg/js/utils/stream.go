@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+)
+
+const (
+	// DefaultStreamConcurrency is the default number of concurrent probes run by Stream
+	DefaultStreamConcurrency = 10
+	// MaxStreamConcurrency is the maximum number of concurrent probes run by Stream
+	MaxStreamConcurrency = 100
+)
+
+// ErrCallbackRequired is returned by Stream when no callback is given
+var ErrCallbackRequired = errors.New("callback function is required")
+
+// Stream runs fn for every item using at most concurrency goroutines and
+// invokes callback with each result as soon as it completes.
+// The callback is always invoked on the goroutine calling Stream, so a
+// javascript callback can be invoked safely since the runtime is not safe
+// for concurrent use. If the callback returns an error (ex: a javascript
+// exception) no new items are started and the error is returned once the
+// running ones complete.
+func Stream[T any, R any](items []T, concurrency int, fn func(T) R, callback func(R) error) error {
+	if callback == nil {
+		return ErrCallbackRequired
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultStreamConcurrency
+	}
+	concurrency = min(concurrency, MaxStreamConcurrency, max(len(items), 1))
+
+	work := make(chan T)
+	results := make(chan R)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				results <- fn(item)
+			}
+		}()
+	}
+	go func() {
+		defer close(work)
+		for _, item := range items {
+			select {
+			case work <- item:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var err error
+	for result := range results {
+		if err != nil {
+			// drain results of the probes running when the callback failed
+			continue
+		}
+		if err = callback(result); err != nil {
+			close(stop)
+		}
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+	var running, peak atomic.Int32
+	probe := func(item int) int {
+		n := running.Add(1)
+		for {
+			current := peak.Load()
+			if n <= current || peak.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return item
+	}
+
+	seen := map[int]int{}
+	var inCallback atomic.Bool
+	err := Stream(items, 4, probe, func(result int) error {
+		if !inCallback.CompareAndSwap(false, true) {
+			t.Error("callback invoked concurrently")
+		}
+		defer inCallback.Store(false)
+		seen[result]++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != len(items) {
+		t.Fatalf("expected callback for %d items, got %d", len(items), len(seen))
+	}
+	for item, count := range seen {
+		if count != 1 {
+			t.Fatalf("expected single callback for item %d, got %d", item, count)
+		}
+	}
+	if got := peak.Load(); got > 4 || got < 1 {
+		t.Fatalf("expected at most 4 concurrent probes, got %d", got)
+	}
+}
+
+func TestStreamCallbackError(t *testing.T) {
+	items := make([]int, 100)
+	var probed atomic.Int32
+	errStop := errors.New("stop")
+	calls := 0
+	err := Stream(items, 2, func(item int) int {
+		probed.Add(1)
+		return item
+	}, func(result int) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected callback to stop after the error, got %d calls", calls)
+	}
+	if probed.Load() == int32(len(items)) {
+		t.Fatal("expected remaining items not to be probed")
+	}
+}
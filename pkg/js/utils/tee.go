@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"net"
+	"sync"
+)
+
+// DefaultMaxCaptureBytes is the default number of bytes recorded
+// per direction by a TeeConn
+const DefaultMaxCaptureBytes = 64 * 1024
+
+// TeeConn wraps a connection recording the bytes written to and read
+// from it. It is used to return the raw exchange of a detection for
+// debugging and should only be enabled on request since every byte
+// is copied.
+type TeeConn struct {
+	net.Conn
+
+	mu        sync.Mutex
+	limit     int
+	sent      []byte
+	received  []byte
+	truncated bool
+}
+
+// NewTeeConn returns conn recording up to limit bytes per direction.
+// values <= 0 use DefaultMaxCaptureBytes
+func NewTeeConn(conn net.Conn, limit int) *TeeConn {
+	if limit <= 0 {
+		limit = DefaultMaxCaptureBytes
+	}
+	return &TeeConn{Conn: conn, limit: limit}
+}
+
+// Read reads from the underlying connection and records the data read
+func (c *TeeConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.mu.Lock()
+		c.received = c.record(c.received, b[:n])
+		c.mu.Unlock()
+	}
+	return n, err
+}
+
+// Write writes to the underlying connection and records the data written
+func (c *TeeConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.mu.Lock()
+		c.sent = c.record(c.sent, b[:n])
+		c.mu.Unlock()
+	}
+	return n, err
+}
+
+// record appends data to buf up to the capture limit
+func (c *TeeConn) record(buf []byte, data []byte) []byte {
+	if remaining := c.limit - len(buf); len(data) > remaining {
+		data = data[:max(remaining, 0)]
+		c.truncated = true
+	}
+	return append(buf, data...)
+}
+
+// Sent returns a copy of the bytes written to the connection
+func (c *TeeConn) Sent() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.sent...)
+}
+
+// Received returns a copy of the bytes read from the connection
+func (c *TeeConn) Received() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.received...)
+}
+
+// Truncated returns true if any direction exceeded the capture limit
+func (c *TeeConn) Truncated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.truncated
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package protocolstate
+
+import (
+	"errors"
+	"syscall"
+)
+
+// IsConnectionRefused checks if err is returned for a refused connection (rst to syn)
+func IsConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsConnectionReset checks if err is returned for a connection reset by the peer
+func IsConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET)
+}
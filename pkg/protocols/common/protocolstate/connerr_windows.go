@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package protocolstate
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// wsaeconnreset is the winsock error returned for a connection reset by the peer
+	wsaeconnreset = syscall.Errno(10054)
+	// wsaeconnrefused is the winsock error returned for a refused connection
+	wsaeconnrefused = syscall.Errno(10061)
+)
+
+// IsConnectionRefused checks if err is returned for a refused connection (rst to syn)
+func IsConnectionRefused(err error) bool {
+	return errors.Is(err, wsaeconnrefused) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsConnectionReset checks if err is returned for a connection reset by the peer
+func IsConnectionReset(err error) bool {
+	return errors.Is(err, wsaeconnreset) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,207 @@
+package protocolstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// SetScanDeadline sets the global deadline of the scan for the given execution.
+// protocol helpers never wait past this deadline regardless of per-call timeouts.
+func SetScanDeadline(executionId string, deadline time.Time) {
+	dialers, ok := dialers.Get(executionId)
+	if ok && dialers != nil {
+		dialers.Lock()
+		dialers.ScanDeadline = deadline
+		dialers.Unlock()
+	}
+}
+
+// GetDeadline returns the deadline for an operation with given timeout
+// which is the smaller of now+timeout and the scan deadline of the execution (if any)
+func GetDeadline(executionId string, timeout time.Duration) time.Time {
+	deadline := time.Now().Add(timeout)
+	dialers, ok := dialers.Get(executionId)
+	if !ok || dialers == nil {
+		return deadline
+	}
+	dialers.Lock()
+	scanDeadline := dialers.ScanDeadline
+	dialers.Unlock()
+	if !scanDeadline.IsZero() && scanDeadline.Before(deadline) {
+		return scanDeadline
+	}
+	return deadline
+}
+
+// DialWithDeadline dials given address using the fastdialer of the execution.
+// The deadline derived from timeout and the scan deadline (see GetDeadline) is
+// applied to both the dial and the returned connection. Operations failing due
+// to the deadline return errors classified as context.DeadlineExceeded.
+func DialWithDeadline(executionId string, network, address string, timeout time.Duration) (net.Conn, error) {
+	return DialContextWithOptions(context.Background(), executionId, network, address, timeout, DialOptions{})
+}
+
+// DialContext is DialWithDeadline bounded by ctx as well. Once ctx is done the
+// dial is aborted and the returned connection is closed so that blocked reads
+// and writes fail right away (ex: probes losing a race to identify a service).
+func DialContext(ctx context.Context, executionId string, network, address string, timeout time.Duration) (net.Conn, error) {
+	return DialContextWithOptions(ctx, executionId, network, address, timeout, DialOptions{})
+}
+
+// DialWithOptions is DialWithDeadline with tcp level options (keep-alive, no-delay)
+// applied to the returned connection. If opts.IP is set it is dialed instead of
+// the host of address which is not resolved.
+// If opts.Interface is set the connection is bound to the address of the interface.
+// The exchanged bytes are recorded to the probe pcap file if enabled (-probe-pcap).
+// Unix sockets (opts.UnixSocket, unix:///path.sock addresses or the unix network)
+// are dialed directly when local file access is allowed.
+func DialWithOptions(executionId string, network, address string, timeout time.Duration, opts DialOptions) (net.Conn, error) {
+	return DialContextWithOptions(context.Background(), executionId, network, address, timeout, opts)
+}
+
+// DialContextWithOptions is DialWithOptions bounded by ctx (see DialContext)
+func DialContextWithOptions(ctx context.Context, executionId string, network, address string, timeout time.Duration, opts DialOptions) (net.Conn, error) {
+	dialer, err := GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	dial := dialer.Fastdialer.Dial
+	if path, ok := opts.unixSocket(network, address); ok {
+		if !isUnixSocketAllowed(dialer) {
+			return nil, ErrUnixSocketDenied
+		}
+		network, address = "unix", path
+		dial = (&net.Dialer{}).DialContext
+	} else if address, err = opts.DialAddress(address); err != nil {
+		return nil, err
+	} else if opts.Interface != "" {
+		if dialer.proxy {
+			return nil, ErrInterfaceProxy
+		}
+		if dial, address, err = interfaceDial(executionId, opts.Interface, network, address); err != nil {
+			return nil, err
+		}
+	}
+	finish := StartEvent(executionId, address, "dial")
+	deadline := GetDeadline(executionId, timeout)
+	if !time.Now().Before(deadline) {
+		err := fmt.Errorf("%w: scan deadline reached", context.DeadlineExceeded)
+		finish(err)
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		finish(err)
+		return nil, err
+	}
+	dialCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	conn, err := dial(dialCtx, network, address)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+		err = classifyDeadline(err)
+		finish(err)
+		return nil, err
+	}
+	finish(nil)
+	if err := opts.apply(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if dialer.pcap != nil {
+		conn = dialer.pcap.wrap(conn)
+	}
+	if err := WriteProxyHeader(conn, network, opts.ProxyProtocol); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	c := &deadlineConn{Conn: conn, ctx: ctx}
+	if ctx.Done() != nil {
+		c.stop = context.AfterFunc(ctx, func() { _ = conn.Close() })
+	}
+	return c, nil
+}
+
+// ResolvedIP returns the ip address dialed by the connection which is the
+// address the hostname was resolved to by the fastdialer (or the proxy address
+// if a proxy is used). An empty string is returned if it is not available
+func ResolvedIP(conn net.Conn) string {
+	if conn == nil || conn.RemoteAddr() == nil {
+		return ""
+	}
+	switch addr := conn.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		return addr.IP.String()
+	case *net.UDPAddr:
+		return addr.IP.String()
+	}
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// deadlineConn classifies deadline errors of underlying connection
+// and closes it once the context of the dial is done
+type deadlineConn struct {
+	net.Conn
+	ctx  context.Context
+	stop func() bool
+}
+
+// NetConn returns the underlying connection
+func (c *deadlineConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// Read reads data from the connection
+func (c *deadlineConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	return n, c.classify(err)
+}
+
+// Write writes data to the connection
+func (c *deadlineConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	return n, c.classify(err)
+}
+
+// Close closes the connection and stops watching the context of the dial
+func (c *deadlineConn) Close() error {
+	if c.stop != nil {
+		c.stop()
+	}
+	return c.Conn.Close()
+}
+
+// classify classifies deadline errors and errors caused by the
+// connection being closed once the context of the dial is done
+func (c *deadlineConn) classify(err error) error {
+	if err != nil && c.ctx.Err() != nil && !errors.Is(err, c.ctx.Err()) {
+		return fmt.Errorf("%w: %w", c.ctx.Err(), err)
+	}
+	return classifyDeadline(err)
+}
+
+// classifyDeadline wraps timeout errors with context.DeadlineExceeded
+func classifyDeadline(err error) error {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, os.ErrDeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package protocolstate
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+func initTestDialers(t *testing.T, modifiers ...func(options *types.Options)) string {
+	t.Helper()
+	options := types.DefaultOptions()
+	options.ExecutionId = t.Name()
+	for _, modify := range modifiers {
+		modify(options)
+	}
+	if err := Init(options); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Close(options.ExecutionId) })
+	return options.ExecutionId
+}
+
+// silentListener accepts connections but never writes to them
+func silentListener(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		close(done)
+		_ = ln.Close()
+	})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				<-done
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestDialWithDeadlineScanDeadline(t *testing.T) {
+	executionId := initTestDialers(t)
+	address := silentListener(t)
+
+	SetScanDeadline(executionId, time.Now().Add(200*time.Millisecond))
+	conn, err := DialWithDeadline(executionId, "tcp", address, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	start := time.Now()
+	_, err = conn.Read(make([]byte, 1))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got=%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("probe was not cut short by scan deadline, took=%v", elapsed)
+	}
+}
+
+func TestDialWithDeadlineExpired(t *testing.T) {
+	executionId := initTestDialers(t)
+	address := silentListener(t)
+
+	SetScanDeadline(executionId, time.Now().Add(-time.Second))
+	_, err := DialWithDeadline(executionId, "tcp", address, 10*time.Second)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got=%v", err)
+	}
+}
+
+func TestDialContextCancelled(t *testing.T) {
+	executionId := initTestDialers(t)
+	address := silentListener(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn, err := DialContext(ctx, executionId, "tcp", address, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = conn.Read(make([]byte, 1))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got=%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("read was not cut short by cancellation, took=%v", elapsed)
+	}
+
+	// dials with a cancelled context fail right away
+	if _, err := DialContext(ctx, executionId, "tcp", address, 10*time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got=%v", err)
+	}
+}
+
+func TestGetDeadline(t *testing.T) {
+	executionId := initTestDialers(t)
+
+	// without scan deadline per-call timeout is used
+	if deadline := GetDeadline(executionId, time.Second); time.Until(deadline) > time.Second {
+		t.Fatalf("unexpected deadline %v", deadline)
+	}
+	scanDeadline := time.Now().Add(100 * time.Millisecond)
+	SetScanDeadline(executionId, scanDeadline)
+	if deadline := GetDeadline(executionId, time.Minute); !deadline.Equal(scanDeadline) {
+		t.Fatalf("expected scan deadline %v, got=%v", scanDeadline, deadline)
+	}
+}
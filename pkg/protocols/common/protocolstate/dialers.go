@@ -2,22 +2,49 @@ package protocolstate
 
 import (
 	"sync"
+	"time"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/networkpolicy"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/ratelimit"
 	"github.com/projectdiscovery/rawhttp"
+	"github.com/projectdiscovery/retryabledns"
 	"github.com/projectdiscovery/retryablehttp-go"
 	mapsutil "github.com/projectdiscovery/utils/maps"
 )
 
 type Dialers struct {
 	Fastdialer                 *fastdialer.Dialer
+	Resolver                   *retryabledns.Client
 	RawHTTPClient              *rawhttp.Client
 	DefaultHTTPClient          *retryablehttp.Client
 	HTTPClientPool             *mapsutil.SyncLockMap[string, *retryablehttp.Client]
 	NetworkPolicy              *networkpolicy.NetworkPolicy
 	LocalFileAccessAllowed     bool
 	RestrictLocalNetworkAccess bool
+	RateLimiter                *ratelimit.Limiter
+	Timeouts                   *types.Timeouts
+	ScanDeadline               time.Time
+	EventSink                  EventSink
+
+	// proxy is set if connections of the fastdialer use a proxy (-proxy)
+	proxy bool
+
+	// pcap records the connections of javascript probes (-probe-pcap)
+	pcap *pcapRecorder
+
+	// random generates probe transaction ids (seeded by -probe-seed)
+	random *probeRandom
+
+	// threads is the template concurrency of the execution (-c)
+	threads int
+
+	// responseReadSize is the maximum response size read by javascript libraries (-rsr)
+	responseReadSize int
+
+	// memoCacheDir is the on-disk cache of memoized javascript library results (-js-cache-dir)
+	memoCacheDir string
 
 	sync.Mutex
 }
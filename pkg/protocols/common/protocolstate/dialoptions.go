@@ -0,0 +1,109 @@
+package protocolstate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ErrMSSUnsupported is returned by ReadMSS on platforms not exposing TCP_MAXSEG
+var ErrMSSUnsupported = errors.New("reading the tcp mss is unsupported on this platform")
+
+// DialOptions contains tcp level options applied to connections
+// returned by DialWithOptions. The zero value keeps the dialer defaults.
+type DialOptions struct {
+	// KeepAlive enables SO_KEEPALIVE with the given idle time and probe interval.
+	// a negative value disables keep-alive probes
+	KeepAlive time.Duration
+	// NoDelay sets TCP_NODELAY (nil keeps the default which is enabled)
+	NoDelay *bool
+	// IP is dialed instead of the host of the address (ex: resolved by a previous
+	// dns step). the hostname is kept for sni and virtual host purposes
+	IP string
+	// Interface is the name of the network interface (ex: eth1) connections are
+	// bound to using its address. the host of the address is resolved by the
+	// fastdialer and dialed directly (proxies and -lpr are not used)
+	Interface string
+	// UnixSocket is the path of a unix socket dialed instead of the address
+	// (requires local file access)
+	UnixSocket string
+	// ProxyProtocol is the haproxy PROXY protocol header sent right after
+	// the connection is established (zero value sends no header)
+	ProxyProtocol ProxyProtocol
+}
+
+// String returns a stable representation of options (used as memoization key)
+func (o DialOptions) String() string {
+	noDelay := "default"
+	if o.NoDelay != nil {
+		noDelay = fmt.Sprint(*o.NoDelay)
+	}
+	return fmt.Sprintf("keepalive=%s,nodelay=%s,ip=%s,iface=%s,unix=%s,proxy=%s", o.KeepAlive, noDelay, o.IP, o.Interface, o.UnixSocket, o.ProxyProtocol)
+}
+
+// IsZero checks if options keep all the dialer defaults
+func (o DialOptions) IsZero() bool {
+	return o.KeepAlive == 0 && o.NoDelay == nil && o.IP == "" && o.Interface == "" && o.UnixSocket == "" && o.ProxyProtocol == ProxyProtocol{}
+}
+
+// DialAddress returns the address to dial which is address with its
+// host replaced by IP (if any)
+func (o DialOptions) DialAddress(address string) (string, error) {
+	if o.IP == "" {
+		return address, nil
+	}
+	ip := net.ParseIP(o.IP)
+	if ip == nil {
+		return "", fmt.Errorf("invalid ip address %q", o.IP)
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(ip.String(), port), nil
+}
+
+// apply applies the options to the tcp connection underlying conn.
+// connections that are not tcp (ex: udp) are left untouched
+func (o DialOptions) apply(conn net.Conn) error {
+	if o.IsZero() {
+		return nil
+	}
+	tcpConn, ok := UnwrapTCPConn(conn)
+	if !ok {
+		return nil
+	}
+	switch {
+	case o.KeepAlive > 0:
+		if err := tcpConn.SetKeepAliveConfig(net.KeepAliveConfig{Enable: true, Idle: o.KeepAlive, Interval: o.KeepAlive}); err != nil {
+			return err
+		}
+	case o.KeepAlive < 0:
+		if err := tcpConn.SetKeepAlive(false); err != nil {
+			return err
+		}
+	}
+	if o.NoDelay != nil {
+		if err := tcpConn.SetNoDelay(*o.NoDelay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnwrapTCPConn returns the *net.TCPConn underlying conn by unwrapping
+// connections implementing NetConn() (ex: tls and deadline connections)
+func UnwrapTCPConn(conn net.Conn) (*net.TCPConn, bool) {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *net.TCPConn:
+			return c, true
+		case interface{ NetConn() net.Conn }:
+			conn = c.NetConn()
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
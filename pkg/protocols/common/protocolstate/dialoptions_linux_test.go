@@ -0,0 +1,95 @@
+//go:build linux
+
+package protocolstate
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// sockopt reads an integer socket option of conn
+func sockopt(t *testing.T, conn *net.TCPConn, level, name int) int {
+	t.Helper()
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value int
+	var optErr error
+	if err := raw.Control(func(fd uintptr) {
+		value, optErr = syscall.GetsockoptInt(int(fd), level, name)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if optErr != nil {
+		t.Fatal(optErr)
+	}
+	return value
+}
+
+func TestDialWithOptions(t *testing.T) {
+	executionId := initTestDialers(t)
+	address := silentListener(t)
+
+	noDelay := false
+	conn, err := DialWithOptions(executionId, "tcp", address, 2*time.Second, DialOptions{KeepAlive: 7 * time.Second, NoDelay: &noDelay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	tcpConn, ok := UnwrapTCPConn(conn)
+	if !ok {
+		t.Fatalf("expected *net.TCPConn underlying %T", conn)
+	}
+	if got := sockopt(t, tcpConn, syscall.SOL_SOCKET, syscall.SO_KEEPALIVE); got != 1 {
+		t.Fatalf("expected SO_KEEPALIVE to be enabled, got=%d", got)
+	}
+	if got := sockopt(t, tcpConn, syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL); got != 7 {
+		t.Fatalf("expected TCP_KEEPINTVL=7, got=%d", got)
+	}
+	if got := sockopt(t, tcpConn, syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE); got != 7 {
+		t.Fatalf("expected TCP_KEEPIDLE=7, got=%d", got)
+	}
+	if got := sockopt(t, tcpConn, syscall.IPPROTO_TCP, syscall.TCP_NODELAY); got != 0 {
+		t.Fatalf("expected TCP_NODELAY to be disabled, got=%d", got)
+	}
+
+	conn, err = DialWithOptions(executionId, "tcp", address, 2*time.Second, DialOptions{KeepAlive: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	tcpConn, _ = UnwrapTCPConn(conn)
+	if got := sockopt(t, tcpConn, syscall.SOL_SOCKET, syscall.SO_KEEPALIVE); got != 0 {
+		t.Fatalf("expected SO_KEEPALIVE to be disabled, got=%d", got)
+	}
+	if got := sockopt(t, tcpConn, syscall.IPPROTO_TCP, syscall.TCP_NODELAY); got != 1 {
+		t.Fatalf("expected default TCP_NODELAY to be enabled, got=%d", got)
+	}
+}
+
+func TestDialWithOptionsInterface(t *testing.T) {
+	executionId := initTestDialers(t)
+	address := silentListener(t)
+
+	conn, err := DialWithOptions(executionId, "tcp", address, 2*time.Second, DialOptions{Interface: "lo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	local, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok || !local.IP.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Fatalf("expected connection bound to the address of lo, got %v", conn.LocalAddr())
+	}
+
+	// an ipv4 target cannot be reached with an ipv6 network
+	if _, err := DialWithOptions(executionId, "tcp6", address, 2*time.Second, DialOptions{Interface: "lo"}); !errors.Is(err, ErrInterfaceNoAddress) {
+		t.Fatalf("expected ErrInterfaceNoAddress, got %v", err)
+	}
+	if _, err := DialWithOptions(executionId, "tcp", address, 2*time.Second, DialOptions{Interface: "missing0"}); err == nil {
+		t.Fatal("expected error for unknown interface")
+	}
+}
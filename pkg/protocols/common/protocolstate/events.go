@@ -0,0 +1,77 @@
+package protocolstate
+
+import (
+	"time"
+)
+
+const (
+	// OutcomeSuccess is the outcome of a phase completed without error
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of a phase completed with an error
+	OutcomeFailure = "failure"
+)
+
+// Event is a low level event emitted by protocol helpers and libraries
+// (ex: dial or handshake of a probe) for debugging purposes
+type Event struct {
+	ExecutionId string
+	// Target is the address of the probe (host:port)
+	Target string
+	// Phase is the name of the completed phase (ex: dial, rdp.detect)
+	Phase    string
+	Duration time.Duration
+	// Outcome is OutcomeSuccess or OutcomeFailure
+	Outcome string
+	// Error is the error of a failed phase
+	Error error
+}
+
+// EventSink receives events of an execution. It is called synchronously
+// from the probing goroutine and must not block
+type EventSink func(event Event)
+
+// SetEventSink sets the event sink of the given execution (nil disables events)
+func SetEventSink(executionId string, sink EventSink) {
+	dialers, ok := dialers.Get(executionId)
+	if ok && dialers != nil {
+		dialers.Lock()
+		dialers.EventSink = sink
+		dialers.Unlock()
+	}
+}
+
+// getEventSink returns the event sink of the given execution (if any)
+func getEventSink(executionId string) EventSink {
+	dialers, ok := dialers.Get(executionId)
+	if !ok || dialers == nil {
+		return nil
+	}
+	dialers.Lock()
+	defer dialers.Unlock()
+	return dialers.EventSink
+}
+
+// StartEvent starts a phase of a probe and returns a function to be called
+// with the error of the phase once it completes which emits the event to
+// the event sink of the execution. It is a no-op if no sink is set.
+func StartEvent(executionId string, target string, phase string) func(err error) {
+	sink := getEventSink(executionId)
+	if sink == nil {
+		return func(error) {}
+	}
+	start := time.Now()
+	return func(err error) {
+		event := Event{
+			ExecutionId: executionId,
+			Target:      target,
+			Phase:       phase,
+			Duration:    time.Since(start),
+			Outcome:     OutcomeSuccess,
+		}
+		if err != nil {
+			event.Outcome = OutcomeFailure
+			event.Error = err
+		}
+		sink(event)
+	}
+}
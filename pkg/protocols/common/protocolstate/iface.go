@@ -0,0 +1,93 @@
+package protocolstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+var (
+	// ErrInterfaceNoAddress is returned when a dial is pinned to an interface
+	// without an address usable to reach the target (down or other address family)
+	ErrInterfaceNoAddress = errors.New("interface has no usable address")
+
+	// ErrInterfaceProxy is returned when a dial is pinned to an interface while
+	// a proxy is configured since the proxy would not be used
+	ErrInterfaceProxy = errors.New("dials pinned to an interface cannot use a proxy")
+)
+
+// dialFunc dials address on network (ex: (*net.Dialer).DialContext)
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// interfaceDial returns the dial function binding connections to the address
+// of interface name along with the ip:port address to dial. The host of
+// address is resolved with the fastdialer of the execution and the first
+// address reachable from the interface is used.
+func interfaceDial(executionId string, name string, network string, address string) (dialFunc, string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not find interface %s: %w", name, err)
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return nil, "", fmt.Errorf("%w: %s is down", ErrInterfaceNoAddress, name)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not get addresses of interface %s: %w", name, err)
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, "", err
+	}
+	ips, err := ResolveAll(executionId, host)
+	if err != nil {
+		return nil, "", err
+	}
+	err = fmt.Errorf("%w: %s has no address for %s", ErrInterfaceNoAddress, name, host)
+	for _, value := range ips {
+		ip := net.ParseIP(value)
+		if (ip.To4() != nil && strings.HasSuffix(network, "6")) || (ip.To4() == nil && strings.HasSuffix(network, "4")) {
+			continue
+		}
+		local, localErr := interfaceAddr(name, addrs, ip)
+		if localErr != nil {
+			err = localErr
+			continue
+		}
+		if !IsHostAllowed(executionId, ip.String()) {
+			// resolved address is not valid according to network policy
+			return nil, "", ErrHostDenied.Msgf(ip.String())
+		}
+		// the zone is required to bind ipv6 link-local addresses
+		var laddr net.Addr = &net.TCPAddr{IP: local, Zone: name}
+		if strings.HasPrefix(network, "udp") {
+			laddr = &net.UDPAddr{IP: local, Zone: name}
+		}
+		return (&net.Dialer{LocalAddr: laddr}).DialContext, net.JoinHostPort(ip.String(), port), nil
+	}
+	return nil, "", err
+}
+
+// interfaceAddr returns the first address of addrs (addresses of interface name)
+// of the same family as ip. ipv6 link-local addresses are only used for
+// link-local targets since they cannot reach other networks.
+func interfaceAddr(name string, addrs []net.Addr, ip net.IP) (net.IP, error) {
+	ipv4 := ip.To4() != nil
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || (ipnet.IP.To4() != nil) != ipv4 {
+			continue
+		}
+		if !ipv4 && ipnet.IP.IsLinkLocalUnicast() && !ip.IsLinkLocalUnicast() {
+			continue
+		}
+		return ipnet.IP, nil
+	}
+	family := "ipv4"
+	if !ipv4 {
+		family = "ipv6"
+	}
+	return nil, fmt.Errorf("%w: %s has no %s address to reach %s", ErrInterfaceNoAddress, name, family, ip)
+}
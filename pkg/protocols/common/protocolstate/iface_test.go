@@ -0,0 +1,35 @@
+package protocolstate
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestInterfaceAddr(t *testing.T) {
+	addrs := []net.Addr{
+		&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("192.0.2.10").To4(), Mask: net.CIDRMask(24, 32)},
+	}
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{target: "198.51.100.1", want: "192.0.2.10"},
+		{target: "fe80::2", want: "fe80::1"},
+		// link-local addresses cannot reach global targets
+		{target: "2001:db8::1", want: ""},
+	}
+	for _, test := range tests {
+		ip, err := interfaceAddr("eth1", addrs, net.ParseIP(test.target))
+		if test.want == "" {
+			if !errors.Is(err, ErrInterfaceNoAddress) {
+				t.Errorf("interfaceAddr(%s): expected ErrInterfaceNoAddress, got %v", test.target, err)
+			}
+			continue
+		}
+		if err != nil || ip.String() != test.want {
+			t.Errorf("interfaceAddr(%s) = %v, %v, want %s", test.target, ip, err, test.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/Mzack9999/goja"
 	"github.com/Mzack9999/goja/parser"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/ratelimit"
 )
 
 // NewJSRuntime returns a new javascript runtime
@@ -18,3 +19,29 @@ func NewJSRuntime() *goja.Runtime {
 	}
 	return vm
 }
+
+// SetRateLimiter sets the rate limiter used by javascript libraries
+// that perform multiple requests within a single call
+func SetRateLimiter(executionId string, limiter *ratelimit.Limiter) {
+	dialers, ok := dialers.Get(executionId)
+	if ok && dialers != nil {
+		dialers.Lock()
+		dialers.RateLimiter = limiter
+		dialers.Unlock()
+	}
+}
+
+// RateLimitTake takes a token from the rate limiter of given execution
+// it is a no-op if no rate limiter is set
+func RateLimitTake(executionId string) {
+	dialers, ok := dialers.Get(executionId)
+	if !ok || dialers == nil {
+		return
+	}
+	dialers.Lock()
+	limiter := dialers.RateLimiter
+	dialers.Unlock()
+	if limiter != nil {
+		limiter.Take()
+	}
+}
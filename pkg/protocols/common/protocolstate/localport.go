@@ -0,0 +1,100 @@
+package protocolstate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+)
+
+// ErrLocalPortRangeExhausted is returned when every port of the configured
+// local port range is already in use
+var ErrLocalPortRangeExhausted = errors.New("local port range exhausted")
+
+// localPortRange binds outbound tcp sockets to a local port within [min, max]
+type localPortRange struct {
+	min, max int
+	// ip is the source ip to bind to (optional)
+	ip net.IP
+	// next is used to rotate the first port tried by each dial
+	next atomic.Uint32
+}
+
+// parseLocalPortRange parses a port range in min-max format (a single port is also accepted)
+func parseLocalPortRange(value string) (*localPortRange, error) {
+	minValue, maxValue, found := strings.Cut(strings.TrimSpace(value), "-")
+	if !found {
+		maxValue = minValue
+	}
+	minPort, err := strconv.Atoi(strings.TrimSpace(minValue))
+	if err != nil {
+		return nil, fmt.Errorf("invalid local port range %q: %w", value, err)
+	}
+	maxPort, err := strconv.Atoi(strings.TrimSpace(maxValue))
+	if err != nil {
+		return nil, fmt.Errorf("invalid local port range %q: %w", value, err)
+	}
+	if minPort < 1 || maxPort > 65535 || minPort > maxPort {
+		return nil, fmt.Errorf("invalid local port range %q: expected min-max within 1-65535", value)
+	}
+	return &localPortRange{min: minPort, max: maxPort}, nil
+}
+
+// apply configures dialer to bind tcp sockets within the port range.
+// the local ip of the dialer (if any) is bound by the range itself since
+// a socket cannot be bound twice
+func (r *localPortRange) apply(dialer *net.Dialer) {
+	if tcpAddr, ok := dialer.LocalAddr.(*net.TCPAddr); ok && tcpAddr != nil {
+		r.ip = tcpAddr.IP
+		dialer.LocalAddr = nil
+	}
+	dialer.Control = r.control
+}
+
+// control is used as net.Dialer.Control and binds the socket before connect
+func (r *localPortRange) control(network, address string, c syscall.RawConn) error {
+	if !strings.HasPrefix(network, "tcp") {
+		return nil
+	}
+	var bindErr error
+	if err := c.Control(func(fd uintptr) {
+		bindErr = r.bind(fd, network)
+	}); err != nil {
+		return err
+	}
+	return bindErr
+}
+
+// bind binds the socket to the first free port of the range
+func (r *localPortRange) bind(fd uintptr, network string) error {
+	ipv6 := network == "tcp6"
+	if r.ip != nil && (r.ip.To4() == nil) != ipv6 {
+		return fmt.Errorf("source ip %s cannot be used for %s connections", r.ip, network)
+	}
+	size := r.max - r.min + 1
+	start := int(r.next.Add(1)-1) % size
+	for i := 0; i < size; i++ {
+		port := r.min + (start+i)%size
+		var sa syscall.Sockaddr
+		if ipv6 {
+			sa6 := &syscall.SockaddrInet6{Port: port}
+			copy(sa6.Addr[:], r.ip.To16())
+			sa = sa6
+		} else {
+			sa4 := &syscall.SockaddrInet4{Port: port}
+			copy(sa4.Addr[:], r.ip.To4())
+			sa = sa4
+		}
+		err := bindSocket(fd, sa)
+		if err == nil {
+			return nil
+		}
+		if !isAddrInUse(err) {
+			return fmt.Errorf("could not bind local port %d: %w", port, err)
+		}
+	}
+	return fmt.Errorf("%w: no free port in %d-%d", ErrLocalPortRangeExhausted, r.min, r.max)
+}
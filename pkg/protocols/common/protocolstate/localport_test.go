@@ -0,0 +1,82 @@
+package protocolstate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// freePort returns a local port that is not in use
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestLocalPortRange(t *testing.T) {
+	address := silentListener(t)
+	minPort := freePort(t)
+	maxPort := minPort + 9
+	if maxPort > 65535 {
+		minPort, maxPort = 65526, 65535
+	}
+	executionId := initTestDialers(t, func(options *types.Options) {
+		options.LocalPortRange = fmt.Sprintf("%d-%d", minPort, maxPort)
+	})
+
+	for i := 0; i < 3; i++ {
+		conn, err := DialWithDeadline(executionId, "tcp", address, 2*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		port := conn.LocalAddr().(*net.TCPAddr).Port
+		_ = conn.Close()
+		if port < minPort || port > maxPort {
+			t.Fatalf("local port %d is not within %d-%d", port, minPort, maxPort)
+		}
+	}
+}
+
+func TestLocalPortRangeExhausted(t *testing.T) {
+	address := silentListener(t)
+	port := freePort(t)
+	executionId := initTestDialers(t, func(options *types.Options) {
+		options.LocalPortRange = fmt.Sprintf("%d-%d", port, port)
+	})
+
+	conn, err := DialWithDeadline(executionId, "tcp", address, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	if got := conn.LocalAddr().(*net.TCPAddr).Port; got != port {
+		t.Fatalf("expected local port %d, got=%d", port, got)
+	}
+
+	// the only port of the range is held by the first connection
+	_, err = DialWithDeadline(executionId, "tcp", address, 2*time.Second)
+	if !errors.Is(err, ErrLocalPortRangeExhausted) {
+		t.Fatalf("expected ErrLocalPortRangeExhausted, got=%v", err)
+	}
+}
+
+func TestParseLocalPortRange(t *testing.T) {
+	for _, value := range []string{"40000-40010", "40000", " 1-65535 "} {
+		if _, err := parseLocalPortRange(value); err != nil {
+			t.Fatalf("expected %q to be valid, got=%v", value, err)
+		}
+	}
+	for _, value := range []string{"", "abc", "0-10", "10-5", "1-65536", "10-"} {
+		if _, err := parseLocalPortRange(value); err == nil {
+			t.Fatalf("expected %q to be invalid", value)
+		}
+	}
+}
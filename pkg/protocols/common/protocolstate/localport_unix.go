@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package protocolstate
+
+import (
+	"errors"
+	"syscall"
+)
+
+// bindSocket binds the given socket to sa
+func bindSocket(fd uintptr, sa syscall.Sockaddr) error {
+	return syscall.Bind(int(fd), sa)
+}
+
+// isAddrInUse checks if err is returned for an address already in use
+func isAddrInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE)
+}
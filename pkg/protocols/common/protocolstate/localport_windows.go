@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package protocolstate
+
+import (
+	"errors"
+	"syscall"
+)
+
+// wsaeaddrinuse is the winsock error returned for an address already in use
+const wsaeaddrinuse = syscall.Errno(10048)
+
+// bindSocket binds the given socket to sa
+func bindSocket(fd uintptr, sa syscall.Sockaddr) error {
+	return syscall.Bind(syscall.Handle(fd), sa)
+}
+
+// isAddrInUse checks if err is returned for an address already in use
+func isAddrInUse(err error) bool {
+	return errors.Is(err, wsaeaddrinuse) || errors.Is(err, syscall.EADDRINUSE)
+}
@@ -0,0 +1,74 @@
+package protocolstate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// memoEntry is a single result persisted in the on-disk memo cache.
+// keys may contain credentials so only their digest is stored
+type memoEntry[T any] struct {
+	Digest string `json:"digest"`
+	Value  T      `json:"value"`
+}
+
+// MemoizeOnDisk returns the result of fn for the given key from the on-disk
+// memo cache of the execution (if enabled). On a cache miss or an unreadable entry
+// fn is executed and its result is written through to the cache. Failed calls are
+// never cached.
+func MemoizeOnDisk[T any](executionId string, key string, fn func() (T, error)) (T, error) {
+	dialers, ok := dialers.Get(executionId)
+	if !ok || dialers == nil || dialers.memoCacheDir == "" {
+		return fn()
+	}
+	dir := dialers.memoCacheDir
+
+	sum := sha256.Sum256([]byte(key))
+	digest := hex.EncodeToString(sum[:])
+	path := filepath.Join(dir, digest+".json")
+	if data, err := os.ReadFile(path); err == nil {
+		var entry memoEntry[T]
+		if err := json.Unmarshal(data, &entry); err == nil && entry.Digest == digest {
+			return entry.Value, nil
+		}
+		// corrupted entries are replaced by the live result
+		gologger.Verbose().Msgf("ignoring invalid memo cache entry %s", path)
+	}
+
+	value, err := fn()
+	if err != nil {
+		return value, err
+	}
+	if err := writeMemoEntry(dir, path, memoEntry[T]{Digest: digest, Value: value}); err != nil {
+		gologger.Verbose().Msgf("could not write memo cache entry %s: %s", path, err)
+	}
+	return value, nil
+}
+
+// writeMemoEntry atomically writes the entry to the given path
+func writeMemoEntry[T any](dir, path string, entry memoEntry[T]) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".memo-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,182 @@
+package protocolstate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// rdpAuthResponse mirrors the shape of rdp.CheckRDPAuthResponse
+type rdpAuthResponse struct {
+	PluginInfo *plugins.ServiceRDP
+	Auth       bool
+}
+
+// initTestMemoCache initializes an execution with the on-disk cache in dir
+func initTestMemoCache(t *testing.T, dir string, modifiers ...func(options *types.Options)) string {
+	t.Helper()
+	return initTestDialers(t, append([]func(options *types.Options){func(options *types.Options) {
+		options.JsMemoCacheDir = dir
+	}}, modifiers...)...)
+}
+
+func testRDPResponse() rdpAuthResponse {
+	return rdpAuthResponse{
+		PluginInfo: &plugins.ServiceRDP{
+			OSFingerprint:       "Windows Server 2016 or 2019",
+			OSVersion:           "10.0.17763",
+			TargetName:          "ACME",
+			NetBIOSComputerName: "DC01",
+			DNSDomainName:       "acme.local",
+		},
+		Auth: true,
+	}
+}
+
+func TestMemoizeOnDiskWriteThrough(t *testing.T) {
+	dir := t.TempDir()
+	executionId := initTestMemoCache(t, dir)
+
+	calls := 0
+	value, err := MemoizeOnDisk(executionId, "rdp.checkRDPAuth:acme.com:3389", func() (rdpAuthResponse, error) {
+		calls++
+		return testRDPResponse(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || !reflect.DeepEqual(value, testRDPResponse()) {
+		t.Fatalf("unexpected result %+v after %d calls", value, calls)
+	}
+	entries, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 cache entry, got %d", len(entries))
+	}
+
+	// failed calls are not cached
+	_, err = MemoizeOnDisk(executionId, "rdp.checkRDPAuth:acme.com:3390", func() (rdpAuthResponse, error) {
+		return rdpAuthResponse{}, errors.New("connection refused")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	entries, _ = filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(entries) != 1 {
+		t.Fatalf("expected failed call not to be cached, got %d entries", len(entries))
+	}
+}
+
+func TestMemoizeOnDiskReadBack(t *testing.T) {
+	dir := t.TempDir()
+	executionId := initTestMemoCache(t, dir)
+
+	key := "rdp.checkRDPAuth:acme.com:3389"
+	if _, err := MemoizeOnDisk(executionId, key, func() (rdpAuthResponse, error) {
+		return testRDPResponse(), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a restart with a new execution using the same directory
+	Close(executionId)
+	executionId = initTestMemoCache(t, dir, func(options *types.Options) { options.ExecutionId += "/restart" })
+	value, err := MemoizeOnDisk(executionId, key, func() (rdpAuthResponse, error) {
+		t.Fatal("live probe executed despite cached result")
+		return rdpAuthResponse{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(value, testRDPResponse()) {
+		t.Fatalf("unexpected cached result %+v", value)
+	}
+}
+
+func TestMemoizeOnDiskCorruption(t *testing.T) {
+	dir := t.TempDir()
+	executionId := initTestMemoCache(t, dir)
+
+	key := "rdp.checkRDPAuth:acme.com:3389"
+	if _, err := MemoizeOnDisk(executionId, key, func() (rdpAuthResponse, error) {
+		return testRDPResponse(), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 cache entry, got %d", len(entries))
+	}
+
+	for _, corrupted := range []string{`{"digest":"`, `{"digest":"0000","value":{}}`, `{"value":{"Auth":"yes"}}`} {
+		if err := os.WriteFile(entries[0], []byte(corrupted), 0600); err != nil {
+			t.Fatal(err)
+		}
+		calls := 0
+		value, err := MemoizeOnDisk(executionId, key, func() (rdpAuthResponse, error) {
+			calls++
+			return testRDPResponse(), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 1 || !reflect.DeepEqual(value, testRDPResponse()) {
+			t.Fatalf("expected live probe for %q, got %+v after %d calls", corrupted, value, calls)
+		}
+	}
+
+	// entry is repaired by the live result
+	value, err := MemoizeOnDisk(executionId, key, func() (rdpAuthResponse, error) {
+		t.Fatal("live probe executed despite repaired entry")
+		return rdpAuthResponse{}, nil
+	})
+	if err != nil || !reflect.DeepEqual(value, testRDPResponse()) {
+		t.Fatalf("unexpected result %+v: %v", value, err)
+	}
+}
+
+func TestMemoizeOnDiskDisabled(t *testing.T) {
+	executionId := initTestDialers(t)
+	calls := 0
+	for i := 0; i < 2; i++ {
+		if _, err := MemoizeOnDisk(executionId, "rdp.isRDP:acme.com:3389", func() (rdpAuthResponse, error) {
+			calls++
+			return testRDPResponse(), nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected disabled cache to call through, got %d calls", calls)
+	}
+}
+
+func TestMemoizeOnDiskPerExecution(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	firstId := initTestMemoCache(t, first)
+	secondId := initTestMemoCache(t, second, func(options *types.Options) { options.ExecutionId += "/second" })
+
+	key := "rdp.checkRDPAuth:acme.com:3389"
+	for _, executionId := range []string{firstId, secondId} {
+		if _, err := MemoizeOnDisk(executionId, key, func() (rdpAuthResponse, error) {
+			return testRDPResponse(), nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// each execution writes to its own directory
+	for _, dir := range []string{first, second} {
+		entries, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		if len(entries) != 1 {
+			t.Fatalf("expected 1 cache entry in %s, got %d", dir, len(entries))
+		}
+	}
+}
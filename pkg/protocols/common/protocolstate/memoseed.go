@@ -0,0 +1,53 @@
+package protocolstate
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// memoFunctions contains the memoized functions registered by the generated code
+var memoFunctions sync.Map
+
+// memoFunction is the signature of a memoized function
+type memoFunction struct {
+	result reflect.Type
+	params int
+}
+
+// RegisterMemoized registers a memoized function with its result type and
+// number of parameters so that its results can be pre-seeded with SeedMemo.
+// It is called by the generated memo code of the javascript libraries.
+func RegisterMemoized[T any](function string, params int) {
+	memoFunctions.Store(function, memoFunction{result: reflect.TypeFor[T](), params: params})
+}
+
+// SeedMemo inserts value as the memoized result of function (ex: rdp.isRDP)
+// called with args, given in the order of the function parameters including
+// the execution id. Subsequent calls with the same arguments return value
+// without executing the function (ex: results of a prior external scan).
+// value must have the result type of function and an already memoized
+// result is kept.
+func SeedMemo(function string, value interface{}, args ...interface{}) error {
+	registered, ok := memoFunctions.Load(function)
+	if !ok {
+		return fmt.Errorf("%s is not a memoized function", function)
+	}
+	signature := registered.(memoFunction)
+	if len(args) != signature.params {
+		return fmt.Errorf("%s expects %d arguments, got %d", function, signature.params, len(args))
+	}
+	if got := reflect.TypeOf(value); got != signature.result {
+		return fmt.Errorf("%s returns %v, got %v", function, signature.result, got)
+	}
+
+	// same key as the generated memo code
+	hash := function
+	for _, arg := range args {
+		hash += ":" + fmt.Sprint(arg)
+	}
+	_, _, _ = Memoizer.Do(hash, func() (interface{}, error) {
+		return value, nil
+	})
+	return nil
+}
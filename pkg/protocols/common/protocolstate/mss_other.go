@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package protocolstate
+
+import "net"
+
+// ReadMSS returns ErrMSSUnsupported since TCP_MAXSEG is not exposed on this platform
+func ReadMSS(conn *net.TCPConn) (int, error) {
+	return 0, ErrMSSUnsupported
+}
@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package protocolstate
+
+import (
+	"net"
+	"syscall"
+)
+
+// ReadMSS returns the maximum segment size (TCP_MAXSEG) of conn
+func ReadMSS(conn *net.TCPConn) (int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var mss int
+	var optErr error
+	if err := raw.Control(func(fd uintptr) {
+		mss, optErr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_MAXSEG)
+	}); err != nil {
+		return 0, err
+	}
+	return mss, optErr
+}
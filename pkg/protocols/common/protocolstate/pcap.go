@@ -0,0 +1,315 @@
+package protocolstate
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// pcapLinkTypeRaw is the link type of packets starting with the ip header
+	pcapLinkTypeRaw = 101
+	// pcapSnapLen is the maximum length of recorded packets
+	pcapSnapLen = 65535
+	// pcapMaxSegment is the maximum payload of a synthetic packet so that
+	// the ip and tcp headers fit within the snap length
+	pcapMaxSegment = pcapSnapLen - 60
+
+	tcpFin = 0x01
+	tcpSyn = 0x02
+	tcpPsh = 0x08
+	tcpAck = 0x10
+)
+
+// pcapRecorder writes the bytes exchanged by connections of an execution to
+// a pcap file. Packets are synthetic: real handshakes, retransmissions and
+// segmentation are not captured, only the payloads in the order they were
+// read and written with ip and tcp/udp headers built from the connection addresses.
+type pcapRecorder struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// newPcapRecorder creates the pcap file at path
+func newPcapRecorder(path string) (*pcapRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	header := binary.LittleEndian.AppendUint32(nil, 0xa1b2c3d4)
+	header = binary.LittleEndian.AppendUint16(header, 2)
+	header = binary.LittleEndian.AppendUint16(header, 4)
+	header = binary.LittleEndian.AppendUint32(header, 0) // thiszone
+	header = binary.LittleEndian.AppendUint32(header, 0) // sigfigs
+	header = binary.LittleEndian.AppendUint32(header, pcapSnapLen)
+	header = binary.LittleEndian.AppendUint32(header, pcapLinkTypeRaw)
+	if _, err := file.Write(header); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &pcapRecorder{file: file}, nil
+}
+
+// writePacket writes a packet record timestamped with the current time.
+// recording is best effort and write errors are ignored
+func (r *pcapRecorder) writePacket(packet []byte) {
+	now := time.Now()
+	record := binary.LittleEndian.AppendUint32(nil, uint32(now.Unix()))
+	record = binary.LittleEndian.AppendUint32(record, uint32(now.Nanosecond()/1000))
+	record = binary.LittleEndian.AppendUint32(record, uint32(len(packet)))
+	record = binary.LittleEndian.AppendUint32(record, uint32(len(packet)))
+	record = append(record, packet...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = r.file.Write(record)
+}
+
+// Close closes the pcap file
+func (r *pcapRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// wrap returns conn recording its payloads. connections without ip
+// addresses (ex: unix sockets) are returned as is.
+func (r *pcapRecorder) wrap(conn net.Conn) net.Conn {
+	c := &pcapConn{Conn: conn, recorder: r}
+	switch local := conn.LocalAddr().(type) {
+	case *net.TCPAddr:
+		remote, ok := conn.RemoteAddr().(*net.TCPAddr)
+		if !ok {
+			return conn
+		}
+		c.local, c.remote = pcapEndpoint{ip: local.IP, port: local.Port}, pcapEndpoint{ip: remote.IP, port: remote.Port}
+	case *net.UDPAddr:
+		remote, ok := conn.RemoteAddr().(*net.UDPAddr)
+		if !ok {
+			return conn
+		}
+		c.local, c.remote, c.udp = pcapEndpoint{ip: local.IP, port: local.Port}, pcapEndpoint{ip: remote.IP, port: remote.Port}, true
+	default:
+		return conn
+	}
+	if !c.udp {
+		// synthetic three way handshake so that analyzers follow the stream
+		c.segment(false, tcpSyn, nil)
+		c.segment(true, tcpSyn|tcpAck, nil)
+		c.seq, c.ack = 1, 1
+		c.segment(false, tcpAck, nil)
+	}
+	return c
+}
+
+// RecordConn returns conn recording its payloads to the pcap file of the
+// execution (-probe-pcap) or conn itself if recording is disabled.
+// Connections returned by DialWithOptions are already recorded.
+func RecordConn(executionId string, conn net.Conn) net.Conn {
+	dialers, ok := dialers.Get(executionId)
+	if !ok || dialers == nil || dialers.pcap == nil {
+		return conn
+	}
+	return dialers.pcap.wrap(conn)
+}
+
+// DialTLSWithConfig dials address with the fastdialer of the execution and
+// performs a tls handshake with config. When recording is enabled (-probe-pcap)
+// the tcp connection is recorded before the handshake so that the tls records
+// are part of the capture, in which case the fastdialer fallback to ztls on
+// handshake errors is not available.
+func DialTLSWithConfig(ctx context.Context, executionId string, network, address string, config *tls.Config) (net.Conn, error) {
+	dialers, err := GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	if dialers.pcap == nil {
+		return dialers.Fastdialer.DialTLSWithConfig(ctx, network, address, config)
+	}
+	conn, err := dialers.Fastdialer.Dial(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(dialers.pcap.wrap(conn), config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = tlsConn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// pcapEndpoint is the address of a side of a recorded connection
+type pcapEndpoint struct {
+	ip   net.IP
+	port int
+}
+
+// pcapConn records the payloads of a connection to a pcapRecorder
+type pcapConn struct {
+	net.Conn
+	recorder      *pcapRecorder
+	udp           bool
+	local, remote pcapEndpoint
+
+	mu sync.Mutex
+	// seq and ack are the next sequence numbers of the local and remote side
+	seq, ack  uint32
+	closeOnce sync.Once
+}
+
+// NetConn returns the underlying connection
+func (c *pcapConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// Read reads from the underlying connection and records the data read
+func (c *pcapConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.record(true, b[:n])
+	}
+	return n, err
+}
+
+// Write writes to the underlying connection and records the data written
+func (c *pcapConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.record(false, b[:n])
+	}
+	return n, err
+}
+
+// Close closes the underlying connection and records a fin for tcp
+func (c *pcapConn) Close() error {
+	if !c.udp {
+		c.closeOnce.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.segment(false, tcpFin|tcpAck, nil)
+		})
+	}
+	return c.Conn.Close()
+}
+
+// record writes data as packets split at pcapMaxSegment
+func (c *pcapConn) record(inbound bool, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(data) > 0 {
+		payload := data[:min(len(data), pcapMaxSegment)]
+		data = data[len(payload):]
+		if c.udp {
+			c.datagram(inbound, payload)
+			continue
+		}
+		c.segment(inbound, tcpPsh|tcpAck, payload)
+		if inbound {
+			c.ack += uint32(len(payload))
+		} else {
+			c.seq += uint32(len(payload))
+		}
+	}
+}
+
+// segment records a tcp segment with the current sequence numbers
+func (c *pcapConn) segment(inbound bool, flags byte, payload []byte) {
+	src, dst, seq, ack := c.local, c.remote, c.seq, c.ack
+	if inbound {
+		src, dst, seq, ack = c.remote, c.local, c.ack, c.seq
+	}
+	if flags&tcpAck == 0 {
+		ack = 0
+	}
+	header := binary.BigEndian.AppendUint16(nil, uint16(src.port))
+	header = binary.BigEndian.AppendUint16(header, uint16(dst.port))
+	header = binary.BigEndian.AppendUint32(header, seq)
+	header = binary.BigEndian.AppendUint32(header, ack)
+	header = append(header, 5<<4, flags)
+	header = binary.BigEndian.AppendUint16(header, 0xffff) // window
+	header = append(header, 0, 0, 0, 0)                    // checksum and urgent pointer
+	c.recorder.writePacket(ipPacket(src.ip, dst.ip, 6, append(header, payload...), 16))
+}
+
+// datagram records an udp datagram
+func (c *pcapConn) datagram(inbound bool, payload []byte) {
+	src, dst := c.local, c.remote
+	if inbound {
+		src, dst = c.remote, c.local
+	}
+	c.recorder.datagram(src, dst, payload)
+}
+
+// datagram records an udp datagram from src to dst
+func (r *pcapRecorder) datagram(src, dst pcapEndpoint, payload []byte) {
+	header := binary.BigEndian.AppendUint16(nil, uint16(src.port))
+	header = binary.BigEndian.AppendUint16(header, uint16(dst.port))
+	header = binary.BigEndian.AppendUint16(header, uint16(8+len(payload)))
+	header = append(header, 0, 0) // checksum
+	r.writePacket(ipPacket(src.ip, dst.ip, 17, append(header, payload...), 6))
+}
+
+// ipPacket returns an ipv4 (or ipv6 if any address is ipv6) packet carrying
+// the transport segment whose checksum (at checksumOffset) is filled in
+func ipPacket(src, dst net.IP, protocol byte, segment []byte, checksumOffset int) []byte {
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		pseudo := append(append(append([]byte{}, src4...), dst4...), 0, protocol)
+		pseudo = binary.BigEndian.AppendUint16(pseudo, uint16(len(segment)))
+		putChecksum(segment[checksumOffset:], protocol, checksum(pseudo, segment))
+
+		header := []byte{0x45, 0}
+		header = binary.BigEndian.AppendUint16(header, uint16(20+len(segment)))
+		header = append(header, 0, 0, 0x40, 0, 64, protocol, 0, 0) // id, dont fragment, ttl
+		header = append(append(header, src4...), dst4...)
+		binary.BigEndian.PutUint16(header[10:], checksum(header))
+		return append(header, segment...)
+	}
+	src16, dst16 := src.To16(), dst.To16()
+	pseudo := append(append([]byte{}, src16...), dst16...)
+	pseudo = binary.BigEndian.AppendUint32(pseudo, uint32(len(segment)))
+	pseudo = append(pseudo, 0, 0, 0, protocol)
+	putChecksum(segment[checksumOffset:], protocol, checksum(pseudo, segment))
+
+	header := []byte{0x60, 0, 0, 0}
+	header = binary.BigEndian.AppendUint16(header, uint16(len(segment)))
+	header = append(header, protocol, 64)
+	header = append(append(header, src16...), dst16...)
+	return append(header, segment...)
+}
+
+// putChecksum writes the transport checksum to b. a zero udp checksum
+// means no checksum and is sent as 0xffff
+func putChecksum(b []byte, protocol byte, sum uint16) {
+	if protocol == 17 && sum == 0 {
+		sum = 0xffff
+	}
+	binary.BigEndian.PutUint16(b, sum)
+}
+
+// checksum returns the internet checksum (rfc 1071) of the concatenated data
+func checksum(data ...[]byte) uint16 {
+	var sum uint32
+	var odd bool
+	var last byte
+	for _, chunk := range data {
+		for _, b := range chunk {
+			if odd {
+				sum += uint32(last)<<8 | uint32(b)
+			} else {
+				last = b
+			}
+			odd = !odd
+		}
+	}
+	if odd {
+		sum += uint32(last) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
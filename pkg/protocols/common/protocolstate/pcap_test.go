@@ -0,0 +1,234 @@
+package protocolstate
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// pcapPacket is a packet read from a pcap file
+type pcapPacket struct {
+	udp              bool
+	srcPort, dstPort int
+	flags            byte
+	payload          []byte
+}
+
+// readPcap parses the ipv4 tcp and udp packets of a pcap file written by pcapRecorder
+func readPcap(t *testing.T, path string) []pcapPacket {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) < 24 || binary.LittleEndian.Uint32(data) != 0xa1b2c3d4 {
+		t.Fatalf("invalid pcap header %x", data[:min(len(data), 24)])
+	}
+	if linkType := binary.LittleEndian.Uint32(data[20:]); linkType != pcapLinkTypeRaw {
+		t.Fatalf("expected raw link type, got %d", linkType)
+	}
+	var packets []pcapPacket
+	for data = data[24:]; len(data) > 0; {
+		if len(data) < 16 {
+			t.Fatalf("truncated record header")
+		}
+		length := int(binary.LittleEndian.Uint32(data[8:]))
+		if len(data) < 16+length {
+			t.Fatalf("truncated record")
+		}
+		packet := data[16 : 16+length]
+		data = data[16+length:]
+
+		protocol := packet[9]
+		if packet[0]>>4 != 4 || (protocol != 6 && protocol != 17) {
+			t.Fatalf("expected ipv4 tcp or udp packet, got %x", packet)
+		}
+		if sum := checksum(packet[:20]); sum != 0 {
+			t.Fatalf("invalid ip checksum %x", sum)
+		}
+		segment := packet[20:]
+		pseudo := append(append([]byte{}, packet[12:20]...), 0, protocol)
+		pseudo = binary.BigEndian.AppendUint16(pseudo, uint16(len(segment)))
+		if sum := checksum(pseudo, segment); sum != 0 {
+			t.Fatalf("invalid transport checksum %x", sum)
+		}
+		if protocol == 17 {
+			packets = append(packets, pcapPacket{
+				udp:     true,
+				srcPort: int(binary.BigEndian.Uint16(segment)),
+				dstPort: int(binary.BigEndian.Uint16(segment[2:])),
+				payload: segment[8:],
+			})
+			continue
+		}
+		packets = append(packets, pcapPacket{
+			srcPort: int(binary.BigEndian.Uint16(segment)),
+			dstPort: int(binary.BigEndian.Uint16(segment[2:])),
+			flags:   segment[13],
+			payload: segment[20:],
+		})
+	}
+	return packets
+}
+
+func TestDialRecordsPcap(t *testing.T) {
+	probePcap := filepath.Join(t.TempDir(), "probes.pcap")
+	executionId := initTestDialers(t, func(options *types.Options) { options.ProbePcap = probePcap })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		buff := make([]byte, 5)
+		if _, err := io.ReadFull(conn, buff); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("world"))
+	}()
+
+	conn, err := DialWithDeadline(executionId, "tcp", ln.Addr().String(), 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	reply := make([]byte, 5)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := UnwrapTCPConn(conn); !ok {
+		t.Fatalf("expected recorded connection to unwrap to *net.TCPConn")
+	}
+	_ = conn.Close()
+	// closing the dialers flushes and closes the pcap file
+	Close(executionId)
+
+	packets := readPcap(t, probePcap)
+	if len(packets) != 6 {
+		t.Fatalf("expected handshake, 2 payloads and fin, got %d packets", len(packets))
+	}
+	if packets[0].flags != tcpSyn || packets[1].flags != tcpSyn|tcpAck || packets[5].flags != tcpFin|tcpAck {
+		t.Fatalf("unexpected synthetic handshake or fin: %+v", packets)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	if sent := packets[3]; sent.dstPort != port || !bytes.Equal(sent.payload, []byte("hello")) {
+		t.Fatalf("expected hello sent to %d, got %+v", port, sent)
+	}
+	if received := packets[4]; received.srcPort != port || !bytes.Equal(received.payload, []byte("world")) {
+		t.Fatalf("expected world received from %d, got %+v", port, received)
+	}
+}
+
+func TestDialWithoutPcap(t *testing.T) {
+	executionId := initTestDialers(t)
+	address := silentListener(t)
+
+	conn, err := DialWithDeadline(executionId, "tcp", address, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	for c := conn; c != nil; {
+		if _, ok := c.(*pcapConn); ok {
+			t.Fatalf("expected connection not to be recorded without -probe-pcap")
+		}
+		wrapper, ok := c.(interface{ NetConn() net.Conn })
+		if !ok {
+			break
+		}
+		c = wrapper.NetConn()
+	}
+}
+
+func TestDialTLSRecordsPcap(t *testing.T) {
+	probePcap := filepath.Join(t.TempDir(), "probes.pcap")
+	executionId := initTestDialers(t, func(options *types.Options) { options.ProbePcap = probePcap })
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := server.Listener.Addr().String()
+
+	conn, err := DialTLSWithConfig(context.Background(), executionId, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+	Close(executionId)
+
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+	for _, packet := range readPcap(t, probePcap) {
+		// tls handshake record sent by the client
+		if packet.dstPort == port && len(packet.payload) > 0 && packet.payload[0] == 0x16 {
+			return
+		}
+	}
+	t.Fatalf("expected tls client hello to be recorded")
+}
+
+func TestListenUDPRecordsPcap(t *testing.T) {
+	probePcap := filepath.Join(t.TempDir(), "probes.pcap")
+	executionId := initTestDialers(t, func(options *types.Options) { options.ProbePcap = probePcap })
+
+	server, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+	go func() {
+		buff := make([]byte, 16)
+		n, addr, err := server.ReadFromUDP(buff)
+		if err != nil {
+			return
+		}
+		_, _ = server.WriteToUDP(append([]byte("re:"), buff[:n]...), addr)
+	}()
+
+	conn, err := ListenUDP(executionId, "127.0.0.1:0", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverAddr := server.LocalAddr().(*net.UDPAddr)
+	if _, err := conn.WriteToUDP([]byte("ping"), serverAddr); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buff := make([]byte, 16)
+	if _, _, err := conn.ReadFromUDP(buff); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := conn.PacketConn().(*net.UDPConn); ok {
+		t.Fatalf("expected recorded socket not to expose *net.UDPConn")
+	}
+	_ = conn.Close()
+	Close(executionId)
+
+	packets := readPcap(t, probePcap)
+	if len(packets) != 2 {
+		t.Fatalf("expected 2 datagrams, got %d packets", len(packets))
+	}
+	if sent := packets[0]; !sent.udp || sent.dstPort != serverAddr.Port || !bytes.Equal(sent.payload, []byte("ping")) {
+		t.Fatalf("expected ping sent to %d, got %+v", serverAddr.Port, sent)
+	}
+	if received := packets[1]; !received.udp || received.srcPort != serverAddr.Port || !bytes.Equal(received.payload, []byte("re:ping")) {
+		t.Fatalf("expected reply received from %d, got %+v", serverAddr.Port, received)
+	}
+}
@@ -0,0 +1,116 @@
+package protocolstate
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// proxyV2Signature is the signature starting PROXY protocol v2 headers
+var proxyV2Signature = []byte{0x0d, 0x0a, 0x0d, 0x0a, 0x00, 0x0d, 0x0a, 0x51, 0x55, 0x49, 0x54, 0x0a}
+
+// ProxyProtocol contains the haproxy PROXY protocol header sent before
+// any protocol data to backends expecting it (ex: behind load balancers).
+// The zero value sends no header.
+type ProxyProtocol struct {
+	// Version is the version of the header (1 for text, 2 for binary)
+	Version int
+	// Source is the announced client address as ip:port (default: local address of the connection)
+	Source string
+	// Destination is the announced server address as ip:port (default: remote address of the connection)
+	Destination string
+}
+
+// String returns a stable representation of the header options
+func (p ProxyProtocol) String() string {
+	if p.Version == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("v%d:%s>%s", p.Version, p.Source, p.Destination)
+}
+
+// WriteProxyHeader writes the PROXY protocol header described by p to conn.
+// Nothing is written if p is the zero value
+func WriteProxyHeader(conn net.Conn, network string, p ProxyProtocol) error {
+	if p.Version == 0 {
+		return nil
+	}
+	header, err := p.header(network, conn.LocalAddr(), conn.RemoteAddr())
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(header)
+	return err
+}
+
+// header returns the encoded header using local and remote as default addresses
+func (p ProxyProtocol) header(network string, local, remote net.Addr) ([]byte, error) {
+	source, err := proxyAddress(p.Source, local)
+	if err != nil {
+		return nil, err
+	}
+	destination, err := proxyAddress(p.Destination, remote)
+	if err != nil {
+		return nil, err
+	}
+	ipv4 := source.IP.To4() != nil
+	if ipv4 != (destination.IP.To4() != nil) {
+		return nil, fmt.Errorf("proxy protocol source %s and destination %s address families differ", source, destination)
+	}
+	udp := network == "udp" || network == "udp4" || network == "udp6"
+
+	switch p.Version {
+	case 1:
+		if udp {
+			return nil, fmt.Errorf("%w: proxy protocol v1 does not support %s", ErrUnsupportedTransport, network)
+		}
+		family := "TCP6"
+		if ipv4 {
+			family = "TCP4"
+		}
+		return fmt.Appendf(nil, "PROXY %s %s %s %d %d\r\n", family, source.IP, destination.IP, source.Port, destination.Port), nil
+	case 2:
+		// version 2 with PROXY command followed by the address family and transport
+		header := append([]byte{}, proxyV2Signature...)
+		header = append(header, 0x21)
+		family, sourceIP, destinationIP := byte(0x20), source.IP.To16(), destination.IP.To16()
+		if ipv4 {
+			family, sourceIP, destinationIP = 0x10, source.IP.To4(), destination.IP.To4()
+		}
+		if udp {
+			family |= 0x02
+		} else {
+			family |= 0x01
+		}
+		header = append(header, family)
+		header = binary.BigEndian.AppendUint16(header, uint16(2*len(sourceIP)+4))
+		header = append(header, sourceIP...)
+		header = append(header, destinationIP...)
+		header = binary.BigEndian.AppendUint16(header, uint16(source.Port))
+		header = binary.BigEndian.AppendUint16(header, uint16(destination.Port))
+		return header, nil
+	default:
+		return nil, fmt.Errorf("invalid proxy protocol version %d", p.Version)
+	}
+}
+
+// proxyAddress parses address (ip:port) or falls back to the connection address
+func proxyAddress(address string, fallback net.Addr) (*net.TCPAddr, error) {
+	if address == "" {
+		if fallback == nil {
+			return nil, fmt.Errorf("proxy protocol address is not available")
+		}
+		address = fallback.String()
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy protocol address %q: %w", address, err)
+	}
+	ip := net.ParseIP(host)
+	portNum, err := strconv.Atoi(port)
+	if ip == nil || err != nil || portNum < 0 || portNum > 65535 {
+		return nil, fmt.Errorf("invalid proxy protocol address %q", address)
+	}
+	return &net.TCPAddr{IP: ip, Port: portNum}, nil
+}
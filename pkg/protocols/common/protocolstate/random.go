@@ -0,0 +1,38 @@
+package protocolstate
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math/rand/v2"
+	"sync"
+)
+
+// probeRandom is a seeded random generator shared by the probes of an execution
+type probeRandom struct {
+	mu  sync.Mutex
+	rng *rand.ChaCha8
+}
+
+// newProbeRandom returns a generator seeded with seed or nil if seed is 0
+func newProbeRandom(seed int) *probeRandom {
+	if seed == 0 {
+		return nil
+	}
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], uint64(seed))
+	return &probeRandom{rng: rand.NewChaCha8(key)}
+}
+
+// RandomBytes fills b with random bytes used by probes (ex: transaction ids).
+// If the execution has a probe seed (-probe-seed) the bytes are generated
+// deterministically from it so that runs issuing the same probes in the same
+// order produce identical traffic, otherwise crypto/rand is used.
+func RandomBytes(executionId string, b []byte) {
+	if dialers, ok := dialers.Get(executionId); ok && dialers != nil && dialers.random != nil {
+		dialers.random.mu.Lock()
+		_, _ = dialers.random.rng.Read(b)
+		dialers.random.mu.Unlock()
+		return
+	}
+	_, _ = cryptorand.Read(b)
+}
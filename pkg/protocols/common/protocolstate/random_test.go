@@ -0,0 +1,48 @@
+package protocolstate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+// transactionIds initializes an execution with seed and returns the
+// transaction ids generated by a sequence of probes
+func transactionIds(t *testing.T, executionId string, seed int) [][]byte {
+	t.Helper()
+	options := types.DefaultOptions()
+	options.ExecutionId = executionId
+	options.ProbeSeed = seed
+	if err := Init(options); err != nil {
+		t.Fatal(err)
+	}
+	defer Close(executionId)
+
+	var ids [][]byte
+	for _, size := range []int{4, 8, 19, 8} {
+		id := make([]byte, size)
+		RandomBytes(executionId, id)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestRandomBytesSeeded(t *testing.T) {
+	first := transactionIds(t, t.Name()+"-first", 42)
+	second := transactionIds(t, t.Name()+"-second", 42)
+	for i := range first {
+		if !bytes.Equal(first[i], second[i]) {
+			t.Fatalf("transaction id %d differs between seeded executions: %x != %x", i, first[i], second[i])
+		}
+	}
+
+	other := transactionIds(t, t.Name()+"-other", 43)
+	if bytes.Equal(first[0], other[0]) && bytes.Equal(first[1], other[1]) {
+		t.Fatalf("transaction ids of different seeds are equal: %x", first[0])
+	}
+	unseeded := transactionIds(t, t.Name()+"-unseeded", 0)
+	if bytes.Equal(first[1], unseeded[1]) {
+		t.Fatalf("transaction id of unseeded execution matches seeded one: %x", first[1])
+	}
+}
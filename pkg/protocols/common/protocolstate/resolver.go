@@ -0,0 +1,80 @@
+package protocolstate
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/projectdiscovery/fastdialer/fastdialer"
+	"github.com/projectdiscovery/retryabledns"
+)
+
+// resolvConf is the system resolver configuration used with -system-resolvers
+const resolvConf = "/etc/resolv.conf"
+
+// newResolver returns the dns client used for record lookups of javascript
+// libraries. It queries the same resolvers as the fastdialer of the execution.
+func newResolver(opts fastdialer.Options, proxy string) (*retryabledns.Client, error) {
+	var resolvers []string
+	// system resolvers are tried first like the fastdialer does
+	if opts.ResolversFile {
+		if config, err := dns.ClientConfigFromFile(resolvConf); err == nil {
+			for _, server := range config.Servers {
+				resolvers = append(resolvers, net.JoinHostPort(server, config.Port))
+			}
+		}
+	}
+	resolvers = append(resolvers, opts.BaseResolvers...)
+	return retryabledns.NewWithOptions(retryabledns.Options{
+		BaseResolvers: resolvers,
+		MaxRetries:    max(opts.MaxRetries, 1),
+		Timeout:       2 * time.Second,
+		Proxy:         proxy,
+	})
+}
+
+// ResolveAll returns every A and AAAA record of host resolved with the
+// fastdialer of the execution (ipv4 addresses first, in the order of the
+// records). An ip address is returned as is.
+func ResolveAll(executionId string, host string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}, nil
+	}
+	dialer, err := GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	data, err := dialer.Fastdialer.GetDNSData(host)
+	if err != nil {
+		return nil, err
+	}
+	var ips []string
+	seen := make(map[string]struct{})
+	for _, ip := range append(append([]string{}, data.A...), data.AAAA...) {
+		if _, ok := seen[ip]; ok {
+			continue
+		}
+		seen[ip] = struct{}{}
+		ips = append(ips, ip)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("could not resolve %s", host)
+	}
+	return ips, nil
+}
+
+// CachedDNSData returns the dns answers the fastdialer of the execution cached
+// for host (the records it used to dial) without sending any query. false is
+// returned if host was not resolved by the fastdialer during the execution.
+func CachedDNSData(executionId string, host string) (*retryabledns.DNSData, bool, error) {
+	dialer, err := GetDialersOrError(executionId)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := dialer.Fastdialer.GetDNSDataFromCache(host)
+	if err != nil || data == nil {
+		return nil, false, nil
+	}
+	return data, true, nil
+}
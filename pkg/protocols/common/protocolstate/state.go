@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"os"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
@@ -44,6 +45,68 @@ func GetDialersWithId(id string) *Dialers {
 	return dialers
 }
 
+// ErrDialersNotInitialized is returned when protocol helpers are used for an
+// execution whose dialers were never initialized (or were already closed)
+var ErrDialersNotInitialized = errors.New("dialers not initialized")
+
+// DialersNotInitializedError is the typed error returned by GetDialersOrError
+type DialersNotInitializedError struct {
+	ExecutionId string
+}
+
+// Error returns the error message along with the required initialization step
+func (e *DialersNotInitializedError) Error() string {
+	return fmt.Sprintf("%s for execution id %q: call protocolstate.Init(options) with options.ExecutionId set to %q before use (this is done automatically by the nuclei engine and the sdk)", ErrDialersNotInitialized, e.ExecutionId, e.ExecutionId)
+}
+
+// Is allows matching the error with errors.Is(err, ErrDialersNotInitialized)
+func (e *DialersNotInitializedError) Is(target error) bool {
+	return target == ErrDialersNotInitialized
+}
+
+// GetDialersOrError returns the dialers of the given execution or a
+// *DialersNotInitializedError if they are not initialized
+func GetDialersOrError(id string) (*Dialers, error) {
+	dialers := GetDialersWithId(id)
+	if dialers == nil {
+		return nil, &DialersNotInitializedError{ExecutionId: id}
+	}
+	return dialers, nil
+}
+
+// GetTimeouts returns the timeout variants configured for the given execution
+// if dialers are not initialized, default timeouts are returned
+func GetTimeouts(id string) *types.Timeouts {
+	dialers, ok := dialers.Get(id)
+	if !ok || dialers == nil || dialers.Timeouts == nil {
+		timeouts := &types.Timeouts{}
+		timeouts.ApplyDefaults()
+		return timeouts
+	}
+	return dialers.Timeouts
+}
+
+// GetThreads returns the template concurrency (-c) configured for the given
+// execution, javascript libraries probing concurrently use it as an upper bound.
+// if dialers are not initialized, 0 is returned
+func GetThreads(id string) int {
+	dialers, ok := dialers.Get(id)
+	if !ok || dialers == nil {
+		return 0
+	}
+	return dialers.threads
+}
+
+// GetResponseReadSize returns the maximum response size (-rsr) configured
+// for the given execution. if it is not configured, 0 is returned
+func GetResponseReadSize(id string) int {
+	dialers, ok := dialers.Get(id)
+	if !ok || dialers == nil {
+		return 0
+	}
+	return dialers.responseReadSize
+}
+
 func ShouldInit(id string) bool {
 	dialer, ok := dialers.Get(id)
 	if !ok {
@@ -57,6 +120,11 @@ func Init(options *types.Options) error {
 	if GetDialersWithId(options.ExecutionId) != nil {
 		return nil
 	}
+	if options.JsMemoCacheDir != "" {
+		if err := os.MkdirAll(options.JsMemoCacheDir, 0700); err != nil {
+			return err
+		}
+	}
 
 	return initDialers(options)
 }
@@ -128,6 +196,20 @@ func initDialers(options *types.Options) error {
 			},
 		}
 	}
+	if options.LocalPortRange != "" {
+		portRange, err := parseLocalPortRange(options.LocalPortRange)
+		if err != nil {
+			return err
+		}
+		if opts.Dialer == nil {
+			opts.Dialer = &net.Dialer{
+				Timeout:   opts.DialerTimeout,
+				KeepAlive: opts.DialerKeepAlive,
+				DualStack: true,
+			}
+		}
+		portRange.apply(opts.Dialer)
+	}
 	if options.AliveSocksProxy != "" {
 		proxyURL, err := url.Parse(options.AliveSocksProxy)
 		if err != nil {
@@ -171,14 +253,33 @@ func initDialers(options *types.Options) error {
 	if err != nil {
 		return errors.Wrap(err, "could not create dialer")
 	}
+	resolver, err := newResolver(opts, options.AliveSocksProxy)
+	if err != nil {
+		return errors.Wrap(err, "could not create resolver")
+	}
 
 	networkPolicy, _ := networkpolicy.New(*npOptions)
 
+	var recorder *pcapRecorder
+	if options.ProbePcap != "" {
+		if recorder, err = newPcapRecorder(options.ProbePcap); err != nil {
+			return errors.Wrap(err, "could not create probe pcap file")
+		}
+	}
+
 	dialersInstance := &Dialers{
 		Fastdialer:             dialer,
+		Resolver:               resolver,
 		NetworkPolicy:          networkPolicy,
 		HTTPClientPool:         mapsutil.NewSyncLockMap[string, *retryablehttp.Client](),
 		LocalFileAccessAllowed: options.AllowLocalFileAccess,
+		Timeouts:               options.GetTimeouts(),
+		proxy:                  options.AliveSocksProxy != "",
+		pcap:                   recorder,
+		random:                 newProbeRandom(options.ProbeSeed),
+		threads:                options.TemplateThreads,
+		responseReadSize:       options.ResponseReadSize,
+		memoCacheDir:           options.JsMemoCacheDir,
 	}
 
 	_ = dialers.Set(options.ExecutionId, dialersInstance)
@@ -267,6 +368,12 @@ func Close(executionId string) {
 
 	if dialersInstance != nil {
 		dialersInstance.Fastdialer.Close()
+		if dialersInstance.Resolver != nil {
+			dialersInstance.Resolver.Close()
+		}
+		if dialersInstance.pcap != nil {
+			_ = dialersInstance.pcap.Close()
+		}
 	}
 
 	dialers.Delete(executionId)
@@ -0,0 +1,41 @@
+package protocolstate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetDialersOrError(t *testing.T) {
+	executionId := t.Name()
+
+	_, err := GetDialersOrError(executionId)
+	var typedErr *DialersNotInitializedError
+	if !errors.As(err, &typedErr) {
+		t.Fatalf("expected *DialersNotInitializedError, got=%v", err)
+	}
+	if typedErr.ExecutionId != executionId {
+		t.Fatalf("unexpected execution id %q", typedErr.ExecutionId)
+	}
+	if !errors.Is(err, ErrDialersNotInitialized) {
+		t.Fatalf("expected error to match ErrDialersNotInitialized, got=%v", err)
+	}
+	if !strings.Contains(err.Error(), "protocolstate.Init") || !strings.Contains(err.Error(), executionId) {
+		t.Fatalf("expected error to reference the initialization step, got=%v", err)
+	}
+
+	// helpers return the same typed error
+	if _, err := DialWithDeadline(executionId, "tcp", "127.0.0.1:1", time.Second); !errors.As(err, &typedErr) {
+		t.Fatalf("expected *DialersNotInitializedError from DialWithDeadline, got=%v", err)
+	}
+	if _, err := ListenUDP(executionId, "127.0.0.1:0", false); !errors.As(err, &typedErr) {
+		t.Fatalf("expected *DialersNotInitializedError from ListenUDP, got=%v", err)
+	}
+
+	initTestDialers(t)
+	dialers, err := GetDialersOrError(executionId)
+	if err != nil || dialers == nil {
+		t.Fatalf("expected initialized dialers, got=%v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package protocolstate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnsupportedTransport is returned when a library is asked to use a
+// transport it does not support (ex: udp for a tcp only protocol)
+var ErrUnsupportedTransport = errors.New("unsupported transport")
+
+// CheckTransport returns ErrUnsupportedTransport if network is not one of the
+// supported transports, before anything is dialed
+func CheckTransport(network string, supported ...string) error {
+	if slices.Contains(supported, network) {
+		return nil
+	}
+	return fmt.Errorf("%w %q (supported: %s)", ErrUnsupportedTransport, network, strings.Join(supported, ", "))
+}
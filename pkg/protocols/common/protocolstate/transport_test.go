@@ -0,0 +1,27 @@
+package protocolstate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckTransport(t *testing.T) {
+	tests := []struct {
+		network   string
+		supported []string
+		wantErr   bool
+	}{
+		{network: "tcp", supported: []string{"tcp", "udp"}},
+		{network: "udp", supported: []string{"tcp", "udp"}},
+		{network: "udp", supported: []string{"tcp"}, wantErr: true},
+		{network: "tcp", supported: []string{"udp"}, wantErr: true},
+		{network: "tcp4", supported: []string{"tcp"}, wantErr: true},
+		{network: "", supported: []string{"tcp"}, wantErr: true},
+	}
+	for _, test := range tests {
+		err := CheckTransport(test.network, test.supported...)
+		if test.wantErr != errors.Is(err, ErrUnsupportedTransport) || (!test.wantErr && err != nil) {
+			t.Errorf("CheckTransport(%q, %v) = %v, want error %v", test.network, test.supported, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package protocolstate
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// ListenUDP creates an unconnected udp socket bound to laddr for the given execution.
+// Unlike dialing with fastdialer, the returned socket can send and receive datagrams
+// to/from any address which is required by discovery protocols (dhcp, mdns, ssdp etc).
+// If broadcast is true SO_BROADCAST is enabled on the socket. An ipv6 laddr
+// (ex: [::]:0) creates an ipv6 socket.
+//
+// Binding to privileged ports (ex: dhcp client port 68) requires root privileges
+// (or CAP_NET_BIND_SERVICE on linux) and sending broadcast datagrams may require
+// CAP_NET_RAW / administrator privileges on some platforms.
+func ListenUDP(executionId string, laddr string, broadcast bool) (*UDPConn, error) {
+	return listenUDP(executionId, laddr, broadcast, "")
+}
+
+// ListenUDPInterface is like ListenUDP but the socket is bound to the network
+// interface name (ex: eth0) so that datagrams are only sent and received through
+// it. On linux SO_BINDTODEVICE is used which also receives broadcast datagrams,
+// on other platforms the socket is bound to the ipv4 address of the interface.
+func ListenUDPInterface(executionId string, name string, port int, broadcast bool) (*UDPConn, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not find interface %s: %w", name, err)
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return nil, fmt.Errorf("%w: %s is down", ErrInterfaceNoAddress, name)
+	}
+	laddr, device, err := interfaceListenAddr(iface, port)
+	if err != nil {
+		return nil, err
+	}
+	return listenUDP(executionId, laddr, broadcast, device)
+}
+
+// listenUDP creates the udp socket of ListenUDP bound to device (if any)
+func listenUDP(executionId string, laddr string, broadcast bool, device string) (*UDPConn, error) {
+	dialers, err := GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	lc := net.ListenConfig{}
+	if broadcast || device != "" {
+		lc.Control = func(network, address string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				if broadcast {
+					sockErr = setBroadcast(fd)
+				}
+				if sockErr == nil && device != "" {
+					sockErr = bindToDevice(fd, device)
+				}
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		}
+	}
+	network := "udp4"
+	if host, _, err := net.SplitHostPort(laddr); err == nil {
+		if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+			network = "udp6"
+		}
+	}
+	conn, err := lc.ListenPacket(context.Background(), network, laddr)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, ok := conn.(*net.UDPConn)
+	if !ok {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected packet conn type %T", conn)
+	}
+	return &UDPConn{UDPConn: udpConn, recorder: dialers.pcap}, nil
+}
+
+// UDPConn is an unconnected udp socket of an execution recording the
+// datagrams it sends and receives to the pcap file (-probe-pcap) if enabled
+type UDPConn struct {
+	*net.UDPConn
+	recorder *pcapRecorder
+}
+
+// ReadFromUDP reads a datagram and records it
+func (c *UDPConn) ReadFromUDP(b []byte) (int, *net.UDPAddr, error) {
+	n, addr, err := c.UDPConn.ReadFromUDP(b)
+	if n > 0 && addr != nil {
+		c.record(addr, true, b[:n])
+	}
+	return n, addr, err
+}
+
+// WriteToUDP writes a datagram to addr and records it
+func (c *UDPConn) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	n, err := c.UDPConn.WriteToUDP(b, addr)
+	if n > 0 {
+		c.record(addr, false, b[:n])
+	}
+	return n, err
+}
+
+// ReadFrom reads a datagram and records it
+func (c *UDPConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, err := c.ReadFromUDP(b)
+	if addr == nil {
+		return n, nil, err
+	}
+	return n, addr, err
+}
+
+// WriteTo writes a datagram to addr and records it
+func (c *UDPConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return c.UDPConn.WriteTo(b, addr)
+	}
+	return c.WriteToUDP(b, udpAddr)
+}
+
+// PacketConn returns the socket for libraries reading and writing datagrams
+// on their own (ex: quic). While recording only the net.PacketConn methods are
+// exposed so that datagrams cannot bypass the recorder (ex: batch reads).
+func (c *UDPConn) PacketConn() net.PacketConn {
+	if c.recorder == nil {
+		return c.UDPConn
+	}
+	return struct{ net.PacketConn }{c}
+}
+
+// record writes a datagram exchanged with remote to the pcap file (if enabled)
+func (c *UDPConn) record(remote *net.UDPAddr, inbound bool, data []byte) {
+	if c.recorder == nil {
+		return
+	}
+	local := pcapEndpoint{ip: net.IPv4zero}
+	if addr, ok := c.LocalAddr().(*net.UDPAddr); ok {
+		local = pcapEndpoint{ip: addr.IP, port: addr.Port}
+	}
+	src, dst := local, pcapEndpoint{ip: remote.IP, port: remote.Port}
+	if inbound {
+		src, dst = dst, src
+	}
+	c.recorder.datagram(src, dst, data[:min(len(data), pcapMaxSegment)])
+}
+
+// ResolveUDPTarget returns the udp address to send datagrams for host to.
+// If host is empty defaultIP (ex: the multicast group of a discovery
+// protocol) is used, otherwise host is checked against the network policy
+// and resolved to its first ipv4 address with the fastdialer of the execution.
+func ResolveUDPTarget(executionId string, host string, defaultIP net.IP, port int) (*net.UDPAddr, error) {
+	if host == "" {
+		return &net.UDPAddr{IP: defaultIP, Port: port}, nil
+	}
+	if !IsHostAllowed(executionId, host) {
+		// host is not valid according to network policy
+		return nil, ErrHostDenied.Msgf(host)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return &net.UDPAddr{IP: ip, Port: port}, nil
+	}
+	dialer, err := GetDialersOrError(executionId)
+	if err != nil {
+		return nil, err
+	}
+	dnsData, err := dialer.Fastdialer.GetDNSData(host)
+	if err != nil {
+		return nil, err
+	}
+	if len(dnsData.A) == 0 {
+		return nil, fmt.Errorf("could not resolve %s", host)
+	}
+	return &net.UDPAddr{IP: net.ParseIP(dnsData.A[0]), Port: port}, nil
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package protocolstate
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// interfaceListenAddr returns the wildcard address on port along with the
+// device the socket is bound to (SO_BINDTODEVICE) which, unlike binding to
+// the interface address, works for interfaces without an address yet and
+// receives broadcast datagrams
+func interfaceListenAddr(iface *net.Interface, port int) (string, string, error) {
+	return fmt.Sprintf("0.0.0.0:%d", port), iface.Name, nil
+}
+
+// bindToDevice binds the given socket to the network interface name (SO_BINDTODEVICE)
+func bindToDevice(fd uintptr, name string) error {
+	return syscall.BindToDevice(int(fd), name)
+}
@@ -0,0 +1,68 @@
+//go:build linux
+
+package protocolstate
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// loopbackInterface returns the name of the loopback interface
+func loopbackInterface(t *testing.T) string {
+	t.Helper()
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 && iface.Flags&net.FlagUp != 0 {
+			return iface.Name
+		}
+	}
+	t.Skip("no loopback interface")
+	return ""
+}
+
+func TestListenUDPInterface(t *testing.T) {
+	executionId := initTestDialers(t)
+	name := loopbackInterface(t)
+
+	server, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+	go func() {
+		buff := make([]byte, 16)
+		n, addr, err := server.ReadFromUDP(buff)
+		if err != nil {
+			return
+		}
+		_, _ = server.WriteToUDP(buff[:n], addr)
+	}()
+
+	conn, err := ListenUDPInterface(executionId, name, 0, true)
+	if errors.Is(err, syscall.EPERM) {
+		t.Skip("SO_BINDTODEVICE requires CAP_NET_RAW")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.WriteToUDP([]byte("ping"), server.LocalAddr().(*net.UDPAddr)); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buff := make([]byte, 16)
+	if n, _, err := conn.ReadFromUDP(buff); err != nil || string(buff[:n]) != "ping" {
+		t.Fatalf("expected echoed ping, got %q err=%v", buff[:n], err)
+	}
+
+	if _, err := ListenUDPInterface(executionId, "nuclei-missing0", 0, false); err == nil {
+		t.Fatal("expected error for unknown interface")
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !linux
+
+package protocolstate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// interfaceListenAddr returns the first ipv4 address of iface on port since
+// SO_BINDTODEVICE is not available on this platform
+func interfaceListenAddr(iface *net.Interface, port int) (string, string, error) {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", "", fmt.Errorf("could not get addresses of interface %s: %w", iface.Name, err)
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+			return net.JoinHostPort(ipnet.IP.String(), strconv.Itoa(port)), "", nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: %s has no ipv4 address", ErrInterfaceNoAddress, iface.Name)
+}
+
+// bindToDevice is never called on this platform since interfaceListenAddr
+// binds to the address of the interface instead
+func bindToDevice(fd uintptr, name string) error {
+	return errors.New("binding to a device is unsupported on this platform")
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package protocolstate
+
+import "syscall"
+
+// setBroadcast enables SO_BROADCAST on the given socket
+func setBroadcast(fd uintptr) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_BROADCAST, 1)
+}
@@ -0,0 +1,45 @@
+package protocolstate
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnixSocketDenied is returned when a unix socket is dialed
+// without local file access being allowed (-lfa)
+var ErrUnixSocketDenied = errors.New("unix socket dial requires local file access (-lfa)")
+
+// unixScheme is the prefix of unix socket addresses (ex: unix:///var/run/app.sock)
+const unixScheme = "unix://"
+
+// ParseUnixAddress returns the socket path of a unix socket address
+// in unix:///path/to/app.sock format
+func ParseUnixAddress(address string) (string, bool) {
+	if !strings.HasPrefix(address, unixScheme) {
+		return "", false
+	}
+	path := strings.TrimPrefix(address, unixScheme)
+	return path, path != ""
+}
+
+// unixSocket returns the unix socket path to dial (if any) which is
+// opts.UnixSocket, a unix:// address or the address of a unix network
+func (o DialOptions) unixSocket(network, address string) (string, bool) {
+	if o.UnixSocket != "" {
+		return o.UnixSocket, true
+	}
+	if path, ok := ParseUnixAddress(address); ok {
+		return path, true
+	}
+	if network == "unix" && address != "" {
+		return address, true
+	}
+	return "", false
+}
+
+// isUnixSocketAllowed checks if unix sockets can be dialed by the execution
+func isUnixSocketAllowed(dialers *Dialers) bool {
+	dialers.Lock()
+	defer dialers.Unlock()
+	return dialers.LocalFileAccessAllowed
+}
@@ -3,7 +3,6 @@ package engine
 import (
 	"context"
 	"crypto/tls"
-	"fmt"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
@@ -20,9 +19,9 @@ import (
 
 // newHttpClient creates a new http client for headless communication with a timeout
 func newHttpClient(options *types.Options) (*http.Client, error) {
-	dialers := protocolstate.GetDialersWithId(options.ExecutionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", options.ExecutionId)
+	dialers, err := protocolstate.GetDialersOrError(options.ExecutionId)
+	if err != nil {
+		return nil, err
 	}
 	// Set the base TLS configuration definition
 	tlsConfig := &tls.Config{
@@ -36,7 +35,6 @@ func newHttpClient(options *types.Options) (*http.Client, error) {
 	}
 
 	// Add the client certificate authentication to the request if it's configured
-	var err error
 	tlsConfig, err = utils.AddConfiguredClientCertToRequest(tlsConfig, options)
 	if err != nil {
 		return nil, err
@@ -141,9 +141,9 @@ func (c *Configuration) HasStandardOptions() bool {
 
 // GetRawHTTP returns the rawhttp request client
 func GetRawHTTP(options *protocols.ExecutorOptions) *rawhttp.Client {
-	dialers := protocolstate.GetDialersWithId(options.Options.ExecutionId)
-	if dialers == nil {
-		panic("dialers not initialized for execution id: " + options.Options.ExecutionId)
+	dialers, err := protocolstate.GetDialersOrError(options.Options.ExecutionId)
+	if err != nil {
+		panic(err)
 	}
 
 	// Lock the dialers to avoid a race when setting RawHTTPClient
@@ -170,9 +170,9 @@ func GetRawHTTP(options *protocols.ExecutorOptions) *rawhttp.Client {
 // Get creates or gets a client for the protocol based on custom configuration
 func Get(options *types.Options, configuration *Configuration) (*retryablehttp.Client, error) {
 	if configuration.HasStandardOptions() {
-		dialers := protocolstate.GetDialersWithId(options.ExecutionId)
-		if dialers == nil {
-			return nil, fmt.Errorf("dialers not initialized for %s", options.ExecutionId)
+		dialers, err := protocolstate.GetDialersOrError(options.ExecutionId)
+		if err != nil {
+			return nil, err
 		}
 		return dialers.DefaultHTTPClient, nil
 	}
@@ -184,9 +184,9 @@ func Get(options *types.Options, configuration *Configuration) (*retryablehttp.C
 func wrappedGet(options *types.Options, configuration *Configuration) (*retryablehttp.Client, error) {
 	var err error
 
-	dialers := protocolstate.GetDialersWithId(options.ExecutionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", options.ExecutionId)
+	dialers, err := protocolstate.GetDialersOrError(options.ExecutionId)
+	if err != nil {
+		return nil, err
 	}
 
 	hash := configuration.Hash()
@@ -102,6 +102,9 @@ type Request struct {
 // Compile compiles the request generators preparing any requests possible.
 func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	request.options = options
+	// libraries performing multiple requests per call (ex: net.ScanPorts)
+	// share the rate limiter of this execution
+	protocolstate.SetRateLimiter(options.Options.ExecutionId, options.RateLimiter)
 
 	var err error
 	if len(request.Payloads) > 0 {
@@ -613,9 +616,9 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 
 // generateEventData generates event data for the request
 func (request *Request) generateEventData(input *contextargs.Context, values map[string]interface{}, matched string) map[string]interface{} {
-	dialers := protocolstate.GetDialersWithId(request.options.Options.ExecutionId)
-	if dialers == nil {
-		panic(fmt.Sprintf("dialers not initialized for %s", request.options.Options.ExecutionId))
+	dialers, err := protocolstate.GetDialersOrError(request.options.Options.ExecutionId)
+	if err != nil {
+		panic(err)
 	}
 
 	data := make(map[string]interface{})
@@ -1,8 +1,6 @@
 package networkclientpool
 
 import (
-	"fmt"
-
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
@@ -28,9 +26,9 @@ func Get(options *types.Options, configuration *Configuration /*TODO review unus
 	if configuration != nil && configuration.CustomDialer != nil {
 		return configuration.CustomDialer, nil
 	}
-	dialers := protocolstate.GetDialersWithId(options.ExecutionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", options.ExecutionId)
+	dialers, err := protocolstate.GetDialersOrError(options.ExecutionId)
+	if err != nil {
+		return nil, err
 	}
 	return dialers.Fastdialer, nil
 }
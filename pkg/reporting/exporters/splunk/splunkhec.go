@@ -49,9 +49,9 @@ type Exporter struct {
 func New(option *Options) (*Exporter, error) {
 	var ei *Exporter
 
-	dialers := protocolstate.GetDialersWithId(option.ExecutionId)
-	if dialers == nil {
-		return nil, fmt.Errorf("dialers not initialized for %s", option.ExecutionId)
+	dialers, err := protocolstate.GetDialersOrError(option.ExecutionId)
+	if err != nil {
+		return nil, err
 	}
 
 	var client *http.Client
@@ -216,6 +216,12 @@ type Options struct {
 	ShowVarDump bool
 	// VarDumpLimit limits the number of characters displayed in var dump
 	VarDumpLimit int
+	// ProbeSeed seeds the random generator of probe transaction ids
+	// (ex: dhcp xid) for reproducible runs. 0 uses crypto/rand
+	ProbeSeed int
+	// ProbePcap is the file the bytes exchanged by javascript protocol probes are
+	// recorded to (pcap with synthetic tcp/udp framing). empty disables recording
+	ProbePcap string
 	// No-Color disables the colored output.
 	NoColor bool
 	// UpdateTemplates updates the templates installed at startup (also used by cloud to update datasources)
@@ -301,6 +307,8 @@ type Options struct {
 	Interface string
 	// SourceIP sets custom source IP address for network requests
 	SourceIP string
+	// LocalPortRange restricts the local ports used by outbound tcp connections (ex: 40000-40999)
+	LocalPortRange string
 	// AttackType overrides template level attack-type configuration
 	AttackType string
 	// ResponseReadSize is the maximum size of response to read
@@ -413,6 +421,9 @@ type Options struct {
 	TeamID string
 	// JsConcurrency is the number of concurrent js routines to run
 	JsConcurrency int
+	// JsMemoCacheDir is the directory used to persist memoized javascript
+	// library results across runs (disabled if empty)
+	JsMemoCacheDir string
 	// SecretsFile is file containing secrets for nuclei
 	SecretsFile goflags.StringSlice
 	// PreFetchSecrets pre-fetches the secrets from the auth provider
@@ -555,6 +566,8 @@ func (options *Options) Copy() *Options {
 		VerboseVerbose:                 options.VerboseVerbose,
 		ShowVarDump:                    options.ShowVarDump,
 		VarDumpLimit:                   options.VarDumpLimit,
+		ProbeSeed:                      options.ProbeSeed,
+		ProbePcap:                      options.ProbePcap,
 		NoColor:                        options.NoColor,
 		UpdateTemplates:                options.UpdateTemplates,
 		JSONL:                          options.JSONL,
@@ -597,6 +610,7 @@ func (options *Options) Copy() *Options {
 		DialerKeepAlive:                options.DialerKeepAlive,
 		Interface:                      options.Interface,
 		SourceIP:                       options.SourceIP,
+		LocalPortRange:                 options.LocalPortRange,
 		AttackType:                     options.AttackType,
 		ResponseReadSize:               options.ResponseReadSize,
 		ResponseSaveSize:               options.ResponseSaveSize,
@@ -653,6 +667,7 @@ func (options *Options) Copy() *Options {
 		ScanUploadFile:                 options.ScanUploadFile,
 		TeamID:                         options.TeamID,
 		JsConcurrency:                  options.JsConcurrency,
+		JsMemoCacheDir:                 options.JsMemoCacheDir,
 		SecretsFile:                    options.SecretsFile,
 		PreFetchSecrets:                options.PreFetchSecrets,
 		FormatUseRequiredOnly:          options.FormatUseRequiredOnly,